@@ -52,7 +52,29 @@ func main() {
 	// openIM clear msg --userID=xxx --beginSeq=100 --limit=10
 	// openIM clear msg --superGroupID=xxx --beginSeq=100 --limit=10
 	// openIM clear msg --clearAll
-	msgUtilsCmd.AddCommand(&getCmd.Command, &fixCmd.Command, &clearCmd.Command)
+	inspectCmd := cmd.NewInspectCmd()
+	inspectCmd.AddCommand(cmd.NewDeadLetterCmd().InspectDeadLetterCmd())
+	inspectCmd.AddConfigFlag()
+	inspectCmd.AddLimitFlag()
+	// openIM inspect deadLetter --config_folder_path=xxx --limit=10
+
+	replayCmd := cmd.NewReplayCmd()
+	replayCmd.AddCommand(cmd.NewDeadLetterCmd().ReplayDeadLetterCmd())
+	replayCmd.AddConfigFlag()
+	// openIM replay deadLetter --config_folder_path=xxx
+
+	migrateCmd := cmd.NewMigrateCmd()
+	migrateCmd.AddCommand(cmd.NewMsgLayoutCmd().MigrateMsgLayoutCmd())
+	migrateCmd.AddConfigFlag()
+	// openIM migrate msgLayout --config_folder_path=xxx
+
+	backfillCmd := cmd.NewBackfillCmd()
+	backfillCmd.AddCommand(cmd.NewChatLogCmd().BackfillChatLogCmd())
+	backfillCmd.AddConfigFlag()
+	// openIM backfill chatLog --config_folder_path=xxx
+
+	msgUtilsCmd.AddCommand(&getCmd.Command, &fixCmd.Command, &clearCmd.Command, &inspectCmd.Command, &replayCmd.Command, &migrateCmd.Command,
+		&backfillCmd.Command)
 	if err := msgUtilsCmd.Execute(); err != nil {
 		panic(err)
 	}
@@ -21,6 +21,7 @@ import (
 	"github.com/OpenIMSDK/tools/a2r"
 
 	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcext"
 )
 
 type ConversationApi rpcclient.Conversation
@@ -48,3 +49,7 @@ func (o *ConversationApi) SetConversations(c *gin.Context) {
 func (o *ConversationApi) GetConversationOfflinePushUserIDs(c *gin.Context) {
 	a2r.Call(conversation.ConversationClient.GetConversationOfflinePushUserIDs, o.Client, c)
 }
+
+func (o *ConversationApi) SetConversationSnooze(c *gin.Context) {
+	a2r.Call(rpcext.ConversationExtClient.SetConversationSnooze, o.ExtClient, c)
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/OpenIMSDK/tools/a2r"
 
 	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcext"
 
 	"github.com/gin-gonic/gin"
 )
@@ -144,3 +145,115 @@ func (o *GroupApi) GetGroups(c *gin.Context) {
 func (o *GroupApi) GetGroupMemberUserIDs(c *gin.Context) {
 	a2r.Call(group.GroupClient.GetGroupMemberUserIDs, o.Client, c)
 }
+
+func (o *GroupApi) CreateGroupInviteLink(c *gin.Context) {
+	a2r.Call(rpcext.GroupExtClient.CreateGroupInviteLink, o.ExtClient, c)
+}
+
+func (o *GroupApi) GetGroupInviteLinks(c *gin.Context) {
+	a2r.Call(rpcext.GroupExtClient.GetGroupInviteLinks, o.ExtClient, c)
+}
+
+func (o *GroupApi) RevokeGroupInviteLink(c *gin.Context) {
+	a2r.Call(rpcext.GroupExtClient.RevokeGroupInviteLink, o.ExtClient, c)
+}
+
+func (o *GroupApi) RedeemGroupInviteLink(c *gin.Context) {
+	a2r.Call(rpcext.GroupExtClient.RedeemGroupInviteLink, o.ExtClient, c)
+}
+
+func (o *GroupApi) SetGroupRole(c *gin.Context) {
+	a2r.Call(rpcext.GroupExtClient.SetGroupRole, o.ExtClient, c)
+}
+
+func (o *GroupApi) DeleteGroupRole(c *gin.Context) {
+	a2r.Call(rpcext.GroupExtClient.DeleteGroupRole, o.ExtClient, c)
+}
+
+func (o *GroupApi) GetGroupRoles(c *gin.Context) {
+	a2r.Call(rpcext.GroupExtClient.GetGroupRoles, o.ExtClient, c)
+}
+
+func (o *GroupApi) GetGroupAuditLogs(c *gin.Context) {
+	a2r.Call(rpcext.GroupExtClient.GetGroupAuditLogs, o.ExtClient, c)
+}
+
+func (o *GroupApi) SetGroupDirectory(c *gin.Context) {
+	a2r.Call(rpcext.GroupExtClient.SetGroupDirectory, o.ExtClient, c)
+}
+
+func (o *GroupApi) SearchGroupDirectory(c *gin.Context) {
+	a2r.Call(rpcext.GroupExtClient.SearchGroupDirectory, o.ExtClient, c)
+}
+
+func (o *GroupApi) JoinDirectoryGroup(c *gin.Context) {
+	a2r.Call(rpcext.GroupExtClient.JoinDirectoryGroup, o.ExtClient, c)
+}
+
+func (o *GroupApi) QuitAllGroups(c *gin.Context) {
+	a2r.Call(rpcext.GroupExtClient.QuitAllGroups, o.ExtClient, c)
+}
+
+func (o *GroupApi) ImportGroupMembers(c *gin.Context) {
+	a2r.Call(rpcext.GroupExtClient.ImportGroupMembers, o.ExtClient, c)
+}
+
+func (o *GroupApi) ExportGroupMembers(c *gin.Context) {
+	a2r.Call(rpcext.GroupExtClient.ExportGroupMembers, o.ExtClient, c)
+}
+
+func (o *GroupApi) CreateGroupAnnouncement(c *gin.Context) {
+	a2r.Call(rpcext.GroupExtClient.CreateGroupAnnouncement, o.ExtClient, c)
+}
+
+func (o *GroupApi) UpdateGroupAnnouncement(c *gin.Context) {
+	a2r.Call(rpcext.GroupExtClient.UpdateGroupAnnouncement, o.ExtClient, c)
+}
+
+func (o *GroupApi) DeleteGroupAnnouncement(c *gin.Context) {
+	a2r.Call(rpcext.GroupExtClient.DeleteGroupAnnouncement, o.ExtClient, c)
+}
+
+func (o *GroupApi) GetGroupAnnouncements(c *gin.Context) {
+	a2r.Call(rpcext.GroupExtClient.GetGroupAnnouncements, o.ExtClient, c)
+}
+
+func (o *GroupApi) AckGroupAnnouncement(c *gin.Context) {
+	a2r.Call(rpcext.GroupExtClient.AckGroupAnnouncement, o.ExtClient, c)
+}
+
+func (o *GroupApi) GetGroupAnnouncementUnreadMembers(c *gin.Context) {
+	a2r.Call(rpcext.GroupExtClient.GetGroupAnnouncementUnreadMembers, o.ExtClient, c)
+}
+
+func (o *GroupApi) SetGroupMemberTag(c *gin.Context) {
+	a2r.Call(rpcext.GroupExtClient.SetGroupMemberTag, o.ExtClient, c)
+}
+
+func (o *GroupApi) DeleteGroupMemberTag(c *gin.Context) {
+	a2r.Call(rpcext.GroupExtClient.DeleteGroupMemberTag, o.ExtClient, c)
+}
+
+func (o *GroupApi) GetGroupMemberTags(c *gin.Context) {
+	a2r.Call(rpcext.GroupExtClient.GetGroupMemberTags, o.ExtClient, c)
+}
+
+func (o *GroupApi) CreateGroupMuteSchedule(c *gin.Context) {
+	a2r.Call(rpcext.GroupExtClient.CreateGroupMuteSchedule, o.ExtClient, c)
+}
+
+func (o *GroupApi) DeleteGroupMuteSchedule(c *gin.Context) {
+	a2r.Call(rpcext.GroupExtClient.DeleteGroupMuteSchedule, o.ExtClient, c)
+}
+
+func (o *GroupApi) GetGroupMuteSchedules(c *gin.Context) {
+	a2r.Call(rpcext.GroupExtClient.GetGroupMuteSchedules, o.ExtClient, c)
+}
+
+func (o *GroupApi) SetGroupJoinRule(c *gin.Context) {
+	a2r.Call(rpcext.GroupExtClient.SetGroupJoinRule, o.ExtClient, c)
+}
+
+func (o *GroupApi) GetGroupJoinRule(c *gin.Context) {
+	a2r.Call(rpcext.GroupExtClient.GetGroupJoinRule, o.ExtClient, c)
+}
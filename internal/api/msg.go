@@ -34,6 +34,7 @@ import (
 
 	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
 	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcext"
 )
 
 type MessageApi struct {
@@ -107,6 +108,46 @@ func (m *MessageApi) GetSeq(c *gin.Context) {
 	a2r.Call(msg.MsgClient.GetMaxSeq, m.Client, c)
 }
 
+func (m *MessageApi) CreateExportJob(c *gin.Context) {
+	a2r.Call(rpcext.MsgExtClient.CreateExportJob, m.ExtClient, c)
+}
+
+func (m *MessageApi) GetExportJob(c *gin.Context) {
+	a2r.Call(rpcext.MsgExtClient.GetExportJob, m.ExtClient, c)
+}
+
+func (m *MessageApi) SetLegalHold(c *gin.Context) {
+	a2r.Call(rpcext.MsgExtClient.SetLegalHold, m.ExtClient, c)
+}
+
+func (m *MessageApi) ReleaseLegalHold(c *gin.Context) {
+	a2r.Call(rpcext.MsgExtClient.ReleaseLegalHold, m.ExtClient, c)
+}
+
+func (m *MessageApi) SearchLegalHolds(c *gin.Context) {
+	a2r.Call(rpcext.MsgExtClient.SearchLegalHolds, m.ExtClient, c)
+}
+
+func (m *MessageApi) SearchLegalHoldLogs(c *gin.Context) {
+	a2r.Call(rpcext.MsgExtClient.SearchLegalHoldLogs, m.ExtClient, c)
+}
+
+func (m *MessageApi) SetRetentionPolicy(c *gin.Context) {
+	a2r.Call(rpcext.MsgExtClient.SetRetentionPolicy, m.ExtClient, c)
+}
+
+func (m *MessageApi) DeleteRetentionPolicy(c *gin.Context) {
+	a2r.Call(rpcext.MsgExtClient.DeleteRetentionPolicy, m.ExtClient, c)
+}
+
+func (m *MessageApi) SearchRetentionPolicies(c *gin.Context) {
+	a2r.Call(rpcext.MsgExtClient.SearchRetentionPolicies, m.ExtClient, c)
+}
+
+func (m *MessageApi) GetEffectiveRetentionPolicy(c *gin.Context) {
+	a2r.Call(rpcext.MsgExtClient.GetEffectiveRetentionPolicy, m.ExtClient, c)
+}
+
 func (m *MessageApi) PullMsgBySeqs(c *gin.Context) {
 	a2r.Call(msg.MsgClient.PullMessageBySeqs, m.Client, c)
 }
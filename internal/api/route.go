@@ -59,6 +59,7 @@ func NewGinRouter(discov discoveryregistry.SvcDiscoveryRegistry, rdb redis.Unive
 	conversationRpc := rpcclient.NewConversation(discov)
 	authRpc := rpcclient.NewAuth(discov)
 	thirdRpc := rpcclient.NewThird(discov)
+	pushRpc := rpcclient.NewPush(discov)
 
 	u := NewUserApi(*userRpc)
 	m := NewMessageApi(messageRpc, userRpc)
@@ -77,6 +78,8 @@ func NewGinRouter(discov discoveryregistry.SvcDiscoveryRegistry, rdb redis.Unive
 		userRouterGroup.POST("/subscribe_users_status", ParseToken, u.SubscriberStatus)
 		userRouterGroup.POST("/get_users_status", ParseToken, u.GetUserStatus)
 		userRouterGroup.POST("/get_subscribe_users_status", ParseToken, u.GetSubscribeUsersStatus)
+		userRouterGroup.POST("/set_do_not_disturb", ParseToken, u.SetDoNotDisturb)
+		userRouterGroup.POST("/get_do_not_disturb", ParseToken, u.GetDoNotDisturb)
 	}
 	// friend routing group
 	friendRouterGroup := r.Group("/friend", ParseToken)
@@ -126,6 +129,34 @@ func NewGinRouter(discov discoveryregistry.SvcDiscoveryRegistry, rdb redis.Unive
 		groupRouterGroup.POST("/get_group_abstract_info", g.GetGroupAbstractInfo)
 		groupRouterGroup.POST("/get_groups", g.GetGroups)
 		groupRouterGroup.POST("/get_group_member_user_id", g.GetGroupMemberUserIDs)
+		groupRouterGroup.POST("/create_invite_link", g.CreateGroupInviteLink)
+		groupRouterGroup.POST("/get_invite_links", g.GetGroupInviteLinks)
+		groupRouterGroup.POST("/revoke_invite_link", g.RevokeGroupInviteLink)
+		groupRouterGroup.POST("/redeem_invite_link", g.RedeemGroupInviteLink)
+		groupRouterGroup.POST("/set_group_role", g.SetGroupRole)
+		groupRouterGroup.POST("/delete_group_role", g.DeleteGroupRole)
+		groupRouterGroup.POST("/get_group_roles", g.GetGroupRoles)
+		groupRouterGroup.POST("/get_group_audit_logs", g.GetGroupAuditLogs)
+		groupRouterGroup.POST("/set_group_directory", g.SetGroupDirectory)
+		groupRouterGroup.POST("/search_group_directory", g.SearchGroupDirectory)
+		groupRouterGroup.POST("/join_directory_group", g.JoinDirectoryGroup)
+		groupRouterGroup.POST("/quit_all_groups", g.QuitAllGroups)
+		groupRouterGroup.POST("/import_group_members", g.ImportGroupMembers)
+		groupRouterGroup.POST("/export_group_members", g.ExportGroupMembers)
+		groupRouterGroup.POST("/create_group_announcement", g.CreateGroupAnnouncement)
+		groupRouterGroup.POST("/update_group_announcement", g.UpdateGroupAnnouncement)
+		groupRouterGroup.POST("/delete_group_announcement", g.DeleteGroupAnnouncement)
+		groupRouterGroup.POST("/get_group_announcements", g.GetGroupAnnouncements)
+		groupRouterGroup.POST("/ack_group_announcement", g.AckGroupAnnouncement)
+		groupRouterGroup.POST("/get_group_announcement_unread_members", g.GetGroupAnnouncementUnreadMembers)
+		groupRouterGroup.POST("/set_group_member_tag", g.SetGroupMemberTag)
+		groupRouterGroup.POST("/delete_group_member_tag", g.DeleteGroupMemberTag)
+		groupRouterGroup.POST("/get_group_member_tags", g.GetGroupMemberTags)
+		groupRouterGroup.POST("/create_group_mute_schedule", g.CreateGroupMuteSchedule)
+		groupRouterGroup.POST("/delete_group_mute_schedule", g.DeleteGroupMuteSchedule)
+		groupRouterGroup.POST("/get_group_mute_schedules", g.GetGroupMuteSchedules)
+		groupRouterGroup.POST("/set_group_join_rule", g.SetGroupJoinRule)
+		groupRouterGroup.POST("/get_group_join_rule", g.GetGroupJoinRule)
 	}
 	superGroupRouterGroup := r.Group("/super_group", ParseToken)
 	{
@@ -146,6 +177,7 @@ func NewGinRouter(discov discoveryregistry.SvcDiscoveryRegistry, rdb redis.Unive
 		thirdGroup.GET("/prometheus", GetPrometheus)
 		t := NewThirdApi(*thirdRpc)
 		thirdGroup.POST("/fcm_update_token", t.FcmUpdateToken)
+		thirdGroup.POST("/voip_update_token", t.VoipUpdateToken)
 		thirdGroup.POST("/set_app_badge", t.SetAppBadge)
 
 		logs := thirdGroup.Group("/logs")
@@ -153,6 +185,9 @@ func NewGinRouter(discov discoveryregistry.SvcDiscoveryRegistry, rdb redis.Unive
 		logs.POST("/delete", t.DeleteLogs)
 		logs.POST("/search", t.SearchLogs)
 
+		p := NewPushApi(*pushRpc)
+		thirdGroup.POST("/push/search", p.SearchPushRecords)
+
 		objectGroup := r.Group("/object", ParseToken)
 
 		objectGroup.POST("/part_limit", t.PartLimit)
@@ -186,6 +221,16 @@ func NewGinRouter(discov discoveryregistry.SvcDiscoveryRegistry, rdb redis.Unive
 		msgGroup.POST("/batch_send_msg", m.BatchSendMsg)
 		msgGroup.POST("/check_msg_is_send_success", m.CheckMsgIsSendSuccess)
 		msgGroup.POST("/get_server_time", m.GetServerTime)
+		msgGroup.POST("/create_export_job", m.CreateExportJob)
+		msgGroup.POST("/get_export_job", m.GetExportJob)
+		msgGroup.POST("/set_legal_hold", m.SetLegalHold)
+		msgGroup.POST("/release_legal_hold", m.ReleaseLegalHold)
+		msgGroup.POST("/search_legal_holds", m.SearchLegalHolds)
+		msgGroup.POST("/search_legal_hold_logs", m.SearchLegalHoldLogs)
+		msgGroup.POST("/set_retention_policy", m.SetRetentionPolicy)
+		msgGroup.POST("/delete_retention_policy", m.DeleteRetentionPolicy)
+		msgGroup.POST("/search_retention_policies", m.SearchRetentionPolicies)
+		msgGroup.POST("/get_effective_retention_policy", m.GetEffectiveRetentionPolicy)
 	}
 	// Conversation
 	conversationGroup := r.Group("/conversation", ParseToken)
@@ -196,6 +241,7 @@ func NewGinRouter(discov discoveryregistry.SvcDiscoveryRegistry, rdb redis.Unive
 		conversationGroup.POST("/get_conversations", c.GetConversations)
 		conversationGroup.POST("/set_conversations", c.SetConversations)
 		conversationGroup.POST("/get_conversation_offline_push_user_ids", c.GetConversationOfflinePushUserIDs)
+		conversationGroup.POST("/set_conversation_snooze", c.SetConversationSnooze)
 	}
 
 	statisticsGroup := r.Group("/statistics", ParseToken)
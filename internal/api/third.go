@@ -27,6 +27,7 @@ import (
 	"github.com/OpenIMSDK/tools/mcontext"
 
 	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcext"
 )
 
 type ThirdApi rpcclient.Third
@@ -39,6 +40,10 @@ func (o *ThirdApi) FcmUpdateToken(c *gin.Context) {
 	a2r.Call(third.ThirdClient.FcmUpdateToken, o.Client, c)
 }
 
+func (o *ThirdApi) VoipUpdateToken(c *gin.Context) {
+	a2r.Call(rpcext.ThirdExtClient.VoipUpdateToken, o.ExtClient, c)
+}
+
 func (o *ThirdApi) SetAppBadge(c *gin.Context) {
 	a2r.Call(third.ThirdClient.SetAppBadge, o.Client, c)
 }
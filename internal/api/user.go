@@ -27,6 +27,7 @@ import (
 
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
 	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcext"
 )
 
 type UserApi rpcclient.User
@@ -199,3 +200,13 @@ func (u *UserApi) GetUserStatus(c *gin.Context) {
 func (u *UserApi) GetSubscribeUsersStatus(c *gin.Context) {
 	a2r.Call(user.UserClient.GetSubscribeUsersStatus, u.Client, c)
 }
+
+// SetDoNotDisturb Set the quiet hours of the user.
+func (u *UserApi) SetDoNotDisturb(c *gin.Context) {
+	a2r.Call(rpcext.UserExtClient.SetDoNotDisturb, u.ExtClient, c)
+}
+
+// GetDoNotDisturb Get the quiet hours of the users, only the users themselves and app managers may read them.
+func (u *UserApi) GetDoNotDisturb(c *gin.Context) {
+	a2r.Call(rpcext.UserExtClient.GetDoNotDisturb, u.ExtClient, c)
+}
@@ -0,0 +1,69 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msgtransfer
+
+import "sync"
+
+// conversationQueue holds the messages a worker has yet to handle, grouped by conversation.
+// Conversations take turns, so that a busy conversation cannot hold back the others sharing the worker.
+type conversationQueue struct {
+	lock    sync.Mutex
+	cond    *sync.Cond
+	msgs    map[string][]*ContextMsg // uniqueKey -> messages in arrival order
+	turns   []string                 // uniqueKeys with messages, in the order they take their turn
+	pending int
+}
+
+func newConversationQueue() *conversationQueue {
+	q := &conversationQueue{msgs: make(map[string][]*ContextMsg)}
+	q.cond = sync.NewCond(&q.lock)
+	return q
+}
+
+// push queues msgs of the conversation and returns how many messages the queue holds.
+func (q *conversationQueue) push(uniqueKey string, msgs []*ContextMsg) int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if _, ok := q.msgs[uniqueKey]; !ok {
+		q.turns = append(q.turns, uniqueKey)
+	}
+	q.msgs[uniqueKey] = append(q.msgs[uniqueKey], msgs...)
+	q.pending += len(msgs)
+	q.cond.Signal()
+	return q.pending
+}
+
+// pop waits for the conversation whose turn it is and takes up to maxBatchSize of its messages, all of them
+// when maxBatchSize is not positive. A conversation with messages left goes to the end of the turns.
+func (q *conversationQueue) pop(maxBatchSize int) (uniqueKey string, batch []*ContextMsg, pending int) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	for len(q.turns) == 0 {
+		q.cond.Wait()
+	}
+	uniqueKey = q.turns[0]
+	q.turns = q.turns[1:]
+	msgs := q.msgs[uniqueKey]
+	if maxBatchSize > 0 && len(msgs) > maxBatchSize {
+		batch = msgs[:maxBatchSize:maxBatchSize]
+		q.msgs[uniqueKey] = msgs[maxBatchSize:]
+		q.turns = append(q.turns, uniqueKey)
+	} else {
+		batch = msgs
+		delete(q.msgs, uniqueKey)
+	}
+	q.pending -= len(batch)
+	return uniqueKey, batch, q.pending
+}
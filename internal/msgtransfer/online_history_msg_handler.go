@@ -16,16 +16,17 @@ package msgtransfer
 
 import (
 	"context"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
 
 	"github.com/OpenIMSDK/tools/errs"
 
-	"github.com/IBM/sarama"
 	"github.com/go-redis/redis"
 	"google.golang.org/protobuf/proto"
 
@@ -37,7 +38,10 @@ import (
 
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+	unrelationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/unrelation"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/kafka"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/mq"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/prommetrics"
 	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
 )
 
@@ -45,18 +49,12 @@ const (
 	ConsumerMsgs   = 3
 	SourceMessages = 4
 	MongoMessages  = 5
-	ChannelNum     = 100
+	ChannelNum     = 100 // default number of workers
 )
 
-type MsgChannelValue struct {
-	uniqueKey  string
-	ctx        context.Context
-	ctxMsgList []*ContextMsg
-}
-
 type TriggerChannelValue struct {
 	ctx      context.Context
-	cMsgList []*sarama.ConsumerMessage
+	cMsgList []*mq.Message
 }
 
 type Cmd2Value struct {
@@ -69,9 +67,15 @@ type ContextMsg struct {
 }
 
 type OnlineHistoryRedisConsumerHandler struct {
-	historyConsumerGroup *kafka.MConsumerGroup
-	chArrays             [ChannelNum]chan Cmd2Value
+	historyConsumerGroup mq.ConsumerGroup
+	queues               []*conversationQueue
 	msgDistributionCh    chan Cmd2Value
+	maxBatchSize         int
+
+	pendingMsgs    int64 // atomic
+	maxPendingMsgs int64
+	pauseLock      sync.Mutex
+	paused         bool
 
 	singleMsgSuccessCount      uint64
 	singleMsgFailedCount       uint64
@@ -83,6 +87,15 @@ type OnlineHistoryRedisConsumerHandler struct {
 	groupRpcClient        *rpcclient.GroupRpcClient
 }
 
+// clampMaxBatchSize a batch never exceeds the messages of one msg doc, BatchInsertChat2Cache refuses a larger one.
+func clampMaxBatchSize(maxBatchSize int) int {
+	limit := int(unrelationtb.MsgDocModel{}.GetSingleGocMsgNum())
+	if maxBatchSize <= 0 || maxBatchSize > limit {
+		return limit
+	}
+	return maxBatchSize
+}
+
 func NewOnlineHistoryRedisConsumerHandler(
 	database controller.CommonMsgDatabase,
 	conversationRpcClient *rpcclient.ConversationRpcClient,
@@ -90,19 +103,23 @@ func NewOnlineHistoryRedisConsumerHandler(
 ) *OnlineHistoryRedisConsumerHandler {
 	var och OnlineHistoryRedisConsumerHandler
 	och.msgDatabase = database
-	och.msgDistributionCh = make(chan Cmd2Value) // no buffer channel
-	go och.MessagesDistributionHandle()
-	for i := 0; i < ChannelNum; i++ {
-		och.chArrays[i] = make(chan Cmd2Value, 50)
-		go och.Run(i)
-	}
 	och.conversationRpcClient = conversationRpcClient
 	och.groupRpcClient = groupRpcClient
-	och.historyConsumerGroup = kafka.NewMConsumerGroup(&kafka.MConsumerGroupConfig{
-		KafkaVersion:   sarama.V2_0_0_0,
-		OffsetsInitial: sarama.OffsetNewest, IsReturnErr: false,
-	}, []string{config.Config.Kafka.LatestMsgToRedis.Topic},
-		config.Config.Kafka.Addr, config.Config.Kafka.ConsumerGroupID.MsgToRedis)
+	och.historyConsumerGroup = mq.NewConsumerGroup([]string{config.Config.Kafka.LatestMsgToRedis.Topic},
+		config.Config.Kafka.ConsumerGroupID.MsgToRedis)
+	och.maxBatchSize = clampMaxBatchSize(config.Config.MsgTransfer.MaxBatchSize)
+	och.maxPendingMsgs = int64(config.Config.MsgTransfer.MaxPendingMsgs)
+	workerNum := config.Config.MsgTransfer.WorkerNum
+	if workerNum <= 0 {
+		workerNum = ChannelNum
+	}
+	och.queues = make([]*conversationQueue, workerNum)
+	for i := 0; i < workerNum; i++ {
+		och.queues[i] = newConversationQueue()
+		go och.Run(i)
+	}
+	och.msgDistributionCh = make(chan Cmd2Value) // no buffer channel
+	go och.MessagesDistributionHandle()
 	// statistics.NewStatistics(&och.singleMsgSuccessCount, config.Config.ModuleName.MsgTransferName, fmt.Sprintf("%d
 	// second singleMsgCount insert to mongo", constant.StatisticsTimeInterval), constant.StatisticsTimeInterval)
 	return &och
@@ -110,63 +127,79 @@ func NewOnlineHistoryRedisConsumerHandler(
 
 func (och *OnlineHistoryRedisConsumerHandler) Run(channelID int) {
 	for {
-		select {
-		case cmd := <-och.chArrays[channelID]:
-			switch cmd.Cmd {
-			case SourceMessages:
-				msgChannelValue := cmd.Value.(MsgChannelValue)
-				ctxMsgList := msgChannelValue.ctxMsgList
-				ctx := msgChannelValue.ctx
-				log.ZDebug(
-					ctx,
-					"msg arrived channel",
-					"channel id",
-					channelID,
-					"msgList length",
-					len(ctxMsgList),
-					"uniqueKey",
-					msgChannelValue.uniqueKey,
-				)
-				storageMsgList, notStorageMsgList, storageNotificationList, notStorageNotificationList, modifyMsgList := och.getPushStorageMsgList(
-					ctxMsgList,
-				)
-				log.ZDebug(
-					ctx,
-					"msg lens",
-					"storageMsgList",
-					len(storageMsgList),
-					"notStorageMsgList",
-					len(notStorageMsgList),
-					"storageNotificationList",
-					len(storageNotificationList),
-					"notStorageNotificationList",
-					len(notStorageNotificationList),
-					"modifyMsgList",
-					len(modifyMsgList),
-				)
-				conversationIDMsg := msgprocessor.GetChatConversationIDByMsg(ctxMsgList[0].message)
-				conversationIDNotification := msgprocessor.GetNotificationConversationIDByMsg(ctxMsgList[0].message)
-				och.handleMsg(ctx, msgChannelValue.uniqueKey, conversationIDMsg, storageMsgList, notStorageMsgList)
-				och.handleNotification(
-					ctx,
-					msgChannelValue.uniqueKey,
-					conversationIDNotification,
-					storageNotificationList,
-					notStorageNotificationList,
-				)
-				if err := och.msgDatabase.MsgToModifyMQ(ctx, msgChannelValue.uniqueKey, conversationIDNotification, modifyMsgList); err != nil {
-					log.ZError(
-						ctx,
-						"msg to modify mq error",
-						err,
-						"uniqueKey",
-						msgChannelValue.uniqueKey,
-						"modifyMsgList",
-						modifyMsgList,
-					)
-				}
-			}
+		uniqueKey, ctxMsgList, depth := och.queues[channelID].pop(och.maxBatchSize)
+		prommetrics.MsgTransferQueueDepthGauge.WithLabelValues(strconv.Itoa(channelID)).Set(float64(depth))
+		ctx := withAggregationCtx(mcontext.WithTriggerIDContext(context.Background(), utils.OperationIDGenerator()), ctxMsgList)
+		log.ZDebug(
+			ctx,
+			"msg arrived channel",
+			"channel id",
+			channelID,
+			"msgList length",
+			len(ctxMsgList),
+			"uniqueKey",
+			uniqueKey,
+		)
+		storageMsgList, notStorageMsgList, storageNotificationList, notStorageNotificationList, modifyMsgList := och.getPushStorageMsgList(
+			ctxMsgList,
+		)
+		log.ZDebug(
+			ctx,
+			"msg lens",
+			"storageMsgList",
+			len(storageMsgList),
+			"notStorageMsgList",
+			len(notStorageMsgList),
+			"storageNotificationList",
+			len(storageNotificationList),
+			"notStorageNotificationList",
+			len(notStorageNotificationList),
+			"modifyMsgList",
+			len(modifyMsgList),
+		)
+		conversationIDMsg := msgprocessor.GetChatConversationIDByMsg(ctxMsgList[0].message)
+		conversationIDNotification := msgprocessor.GetNotificationConversationIDByMsg(ctxMsgList[0].message)
+		och.handleMsg(ctx, uniqueKey, conversationIDMsg, storageMsgList, notStorageMsgList)
+		och.handleNotification(
+			ctx,
+			uniqueKey,
+			conversationIDNotification,
+			storageNotificationList,
+			notStorageNotificationList,
+		)
+		if err := och.msgDatabase.MsgToModifyMQ(ctx, uniqueKey, conversationIDNotification, modifyMsgList); err != nil {
+			log.ZError(
+				ctx,
+				"msg to modify mq error",
+				err,
+				"uniqueKey",
+				uniqueKey,
+				"modifyMsgList",
+				modifyMsgList,
+			)
 		}
+		och.addPendingMsgs(-len(ctxMsgList))
+	}
+}
+
+// addPendingMsgs counts the messages waiting in the workers. Consuming pauses once maxPendingMsgs wait and resumes
+// when half of them are handled, so that memory stays bounded while the workers fall behind.
+func (och *OnlineHistoryRedisConsumerHandler) addPendingMsgs(n int) {
+	atomic.AddInt64(&och.pendingMsgs, int64(n))
+	och.pauseLock.Lock()
+	defer och.pauseLock.Unlock()
+	pending := atomic.LoadInt64(&och.pendingMsgs)
+	switch {
+	case !och.paused && pending >= och.maxPendingMsgs:
+		och.paused = true
+		och.historyConsumerGroup.Pause()
+		prommetrics.MsgTransferConsumerPausedGauge.Set(1)
+		log.ZWarn(context.Background(), "too many pending msgs, consumer paused", nil, "pending", pending)
+	case och.paused && pending <= och.maxPendingMsgs/2:
+		och.paused = false
+		och.historyConsumerGroup.Resume()
+		prommetrics.MsgTransferConsumerPausedGauge.Set(0)
+		log.ZInfo(context.Background(), "pending msgs drained, consumer resumed", "pending", pending)
 	}
 }
 
@@ -249,14 +282,59 @@ func (och *OnlineHistoryRedisConsumerHandler) handleNotification(
 				"storageList",
 				storageList,
 			)
+			och.toDeadLetter(ctx, key, conversationID, storageList, err)
 			return
 		}
-		log.ZDebug(ctx, "success to next topic", "conversationID", conversationID)
-		och.msgDatabase.MsgToMongoMQ(ctx, key, conversationID, storageList, lastSeq)
+		log.ZDebug(ctx, "success to next topic", "conversationID", conversationID, "lastSeq", lastSeq)
+		storageList = och.toMongoTopic(ctx, key, conversationID, storageList)
 		och.toPushTopic(ctx, key, conversationID, storageList)
 	}
 }
 
+// toMongoTopic sends msgs in runs of consecutive seqs, as a re-delivered message keeps its earlier seq
+// a batch may have gaps or the same message twice. It returns msgs sorted by seq without the repeats.
+func (och *OnlineHistoryRedisConsumerHandler) toMongoTopic(
+	ctx context.Context,
+	key, conversationID string,
+	msgs []*sdkws.MsgData,
+) []*sdkws.MsgData {
+	msgs = distinctSeqMsgs(msgs)
+	for _, run := range splitSeqRuns(msgs) {
+		if err := och.msgDatabase.MsgToMongoMQ(ctx, key, conversationID, run, run[0].Seq-1); err != nil {
+			log.ZError(ctx, "msg to mongo mq error", err, "conversationID", conversationID, "firstSeq", run[0].Seq)
+		}
+	}
+	return msgs
+}
+
+// distinctSeqMsgs sorts msgs by seq and keeps the first message of each seq.
+func distinctSeqMsgs(msgs []*sdkws.MsgData) []*sdkws.MsgData {
+	sorted := make([]*sdkws.MsgData, len(msgs))
+	copy(sorted, msgs)
+	sort.Stable(msgprocessor.MsgBySeq(sorted))
+	res := make([]*sdkws.MsgData, 0, len(sorted))
+	for _, msg := range sorted {
+		if len(res) > 0 && msg.Seq == res[len(res)-1].Seq {
+			continue
+		}
+		res = append(res, msg)
+	}
+	return res
+}
+
+// splitSeqRuns splits msgs sorted by distinct seqs into runs of consecutive seqs.
+func splitSeqRuns(msgs []*sdkws.MsgData) [][]*sdkws.MsgData {
+	var runs [][]*sdkws.MsgData
+	start := 0
+	for i := 1; i <= len(msgs); i++ {
+		if i == len(msgs) || msgs[i].Seq != msgs[i-1].Seq+1 {
+			runs = append(runs, msgs[start:i])
+			start = i
+		}
+	}
+	return runs
+}
+
 func (och *OnlineHistoryRedisConsumerHandler) toPushTopic(
 	ctx context.Context,
 	key, conversationID string,
@@ -267,6 +345,13 @@ func (och *OnlineHistoryRedisConsumerHandler) toPushTopic(
 	}
 }
 
+// toDeadLetter keeps a batch that could not be cached, so that it can be replayed instead of being lost.
+func (och *OnlineHistoryRedisConsumerHandler) toDeadLetter(ctx context.Context, key, conversationID string, msgs []*sdkws.MsgData, cause error) {
+	if err := och.msgDatabase.MsgToDeadLetterMQ(ctx, key, kafka.DeadLetterStageRedis, conversationID, msgs, 0, cause); err != nil {
+		log.ZError(ctx, "msg to dead letter mq error, the batch is lost", err, "conversationID", conversationID, "msgs", msgs)
+	}
+}
+
 func (och *OnlineHistoryRedisConsumerHandler) handleMsg(
 	ctx context.Context,
 	key, conversationID string,
@@ -280,6 +365,7 @@ func (och *OnlineHistoryRedisConsumerHandler) handleMsg(
 			och.singleMsgFailedCountMutex.Lock()
 			och.singleMsgFailedCount += uint64(len(storageList))
 			och.singleMsgFailedCountMutex.Unlock()
+			och.toDeadLetter(ctx, key, conversationID, storageList, err)
 			return
 		}
 		if isNewConversation {
@@ -314,14 +400,15 @@ func (och *OnlineHistoryRedisConsumerHandler) handleMsg(
 		och.singleMsgSuccessCountMutex.Lock()
 		och.singleMsgSuccessCount += uint64(len(storageList))
 		och.singleMsgSuccessCountMutex.Unlock()
-		och.msgDatabase.MsgToMongoMQ(ctx, key, conversationID, storageList, lastSeq)
+		log.ZDebug(ctx, "success to mongo topic", "conversationID", conversationID, "lastSeq", lastSeq)
+		storageList = och.toMongoTopic(ctx, key, conversationID, storageList)
 		och.toPushTopic(ctx, key, conversationID, storageList)
 	}
 }
 
 func (och *OnlineHistoryRedisConsumerHandler) MessagesDistributionHandle() {
 	for {
-		aggregationMsgs := make(map[string][]*ContextMsg, ChannelNum)
+		aggregationMsgs := make(map[string][]*ContextMsg, len(och.queues))
 		select {
 		case cmd := <-och.msgDistributionCh:
 			switch cmd.Cmd {
@@ -376,10 +463,9 @@ func (och *OnlineHistoryRedisConsumerHandler) MessagesDistributionHandle() {
 				for uniqueKey, v := range aggregationMsgs {
 					if len(v) >= 0 {
 						hashCode := utils.GetHashCode(uniqueKey)
-						channelID := hashCode % ChannelNum
-						newCtx := withAggregationCtx(ctx, v)
+						channelID := int(hashCode % uint32(len(och.queues)))
 						log.ZDebug(
-							newCtx,
+							ctx,
 							"generate channelID",
 							"hashCode",
 							hashCode,
@@ -388,7 +474,10 @@ func (och *OnlineHistoryRedisConsumerHandler) MessagesDistributionHandle() {
 							"uniqueKey",
 							uniqueKey,
 						)
-						och.chArrays[channelID] <- Cmd2Value{Cmd: SourceMessages, Value: MsgChannelValue{uniqueKey: uniqueKey, ctxMsgList: v, ctx: newCtx}}
+						// queuing never blocks, a busy worker holds back neither the other workers nor the consumer
+						och.addPendingMsgs(len(v))
+						depth := och.queues[channelID].push(uniqueKey, v)
+						prommetrics.MsgTransferQueueDepthGauge.WithLabelValues(strconv.Itoa(channelID)).Set(float64(depth))
 					}
 				}
 			}
@@ -410,14 +499,9 @@ func withAggregationCtx(ctx context.Context, values []*ContextMsg) context.Conte
 	return mcontext.SetOperationID(ctx, allMessageOperationID)
 }
 
-func (och *OnlineHistoryRedisConsumerHandler) Setup(_ sarama.ConsumerGroupSession) error { return nil }
-func (och *OnlineHistoryRedisConsumerHandler) Cleanup(_ sarama.ConsumerGroupSession) error {
-	return nil
-}
-
 func (och *OnlineHistoryRedisConsumerHandler) ConsumeClaim(
-	sess sarama.ConsumerGroupSession,
-	claim sarama.ConsumerGroupClaim,
+	sess mq.ConsumerGroupSession,
+	claim mq.ConsumerGroupClaim,
 ) error { // a instance in the consumer group
 	for {
 		if sess == nil {
@@ -429,10 +513,16 @@ func (och *OnlineHistoryRedisConsumerHandler) ConsumeClaim(
 	}
 	log.ZDebug(context.Background(), "online new session msg come", "highWaterMarkOffset",
 		claim.HighWaterMarkOffset(), "topic", claim.Topic(), "partition", claim.Partition())
+	// a rebalance starts the claims unpaused
+	och.pauseLock.Lock()
+	if och.paused {
+		och.historyConsumerGroup.Pause()
+	}
+	och.pauseLock.Unlock()
 
 	split := 1000
 	rwLock := new(sync.RWMutex)
-	messages := make([]*sarama.ConsumerMessage, 0, 1000)
+	messages := make([]*mq.Message, 0, 1000)
 	ticker := time.NewTicker(time.Millisecond * 100)
 
 	go func() {
@@ -444,7 +534,7 @@ func (och *OnlineHistoryRedisConsumerHandler) ConsumeClaim(
 				}
 
 				rwLock.Lock()
-				buffer := make([]*sarama.ConsumerMessage, 0, len(messages))
+				buffer := make([]*mq.Message, 0, len(messages))
 				buffer = append(buffer, messages...)
 
 				// reuse slice, set cap to 0
@@ -481,7 +571,7 @@ func (och *OnlineHistoryRedisConsumerHandler) ConsumeClaim(
 		messages = append(messages, msg)
 		rwLock.Unlock()
 
-		sess.MarkMessage(msg, "")
+		sess.MarkMessage(msg)
 	}
 
 	return nil
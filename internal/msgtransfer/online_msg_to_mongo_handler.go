@@ -17,7 +17,6 @@ package msgtransfer
 import (
 	"context"
 
-	"github.com/IBM/sarama"
 	"google.golang.org/protobuf/proto"
 
 	pbmsg "github.com/OpenIMSDK/protocol/msg"
@@ -26,21 +25,19 @@ import (
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
 	kfk "github.com/openimsdk/open-im-server/v3/pkg/common/kafka"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/mq"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/prommetrics"
 )
 
 type OnlineHistoryMongoConsumerHandler struct {
-	historyConsumerGroup *kfk.MConsumerGroup
+	historyConsumerGroup mq.ConsumerGroup
 	msgDatabase          controller.CommonMsgDatabase
 }
 
 func NewOnlineHistoryMongoConsumerHandler(database controller.CommonMsgDatabase) *OnlineHistoryMongoConsumerHandler {
 	mc := &OnlineHistoryMongoConsumerHandler{
-		historyConsumerGroup: kfk.NewMConsumerGroup(&kfk.MConsumerGroupConfig{
-			KafkaVersion:   sarama.V2_0_0_0,
-			OffsetsInitial: sarama.OffsetNewest, IsReturnErr: false,
-		}, []string{config.Config.Kafka.MsgToMongo.Topic},
-			config.Config.Kafka.Addr, config.Config.Kafka.ConsumerGroupID.MsgToMongo),
+		historyConsumerGroup: mq.NewConsumerGroup([]string{config.Config.Kafka.MsgToMongo.Topic},
+			config.Config.Kafka.ConsumerGroupID.MsgToMongo),
 		msgDatabase: database,
 	}
 	return mc
@@ -48,9 +45,9 @@ func NewOnlineHistoryMongoConsumerHandler(database controller.CommonMsgDatabase)
 
 func (mc *OnlineHistoryMongoConsumerHandler) handleChatWs2Mongo(
 	ctx context.Context,
-	cMsg *sarama.ConsumerMessage,
+	cMsg *mq.Message,
 	key string,
-	session sarama.ConsumerGroupSession,
+	session mq.ConsumerGroupSession,
 ) {
 	msg := cMsg.Value
 	msgFromMQ := pbmsg.MsgDataToMongoByMQ{}
@@ -76,9 +73,14 @@ func (mc *OnlineHistoryMongoConsumerHandler) handleChatWs2Mongo(
 			msgFromMQ.ConversationID,
 		)
 		prommetrics.MsgInsertMongoFailedCounter.Inc()
-	} else {
-		prommetrics.MsgInsertMongoSuccessCounter.Inc()
+		// the cached messages are the only copy until the dead letter is replayed, so they are kept
+		if err := mc.msgDatabase.MsgToDeadLetterMQ(ctx, key, kfk.DeadLetterStageMongo, msgFromMQ.ConversationID,
+			msgFromMQ.MsgData, msgFromMQ.LastSeq, err); err != nil {
+			log.ZError(ctx, "msg to dead letter mq error, the batch is lost", err, "conversationID", msgFromMQ.ConversationID)
+		}
+		return
 	}
+	prommetrics.MsgInsertMongoSuccessCounter.Inc()
 	var seqs []int64
 	for _, msg := range msgFromMQ.MsgData {
 		seqs = append(seqs, msg.Seq)
@@ -98,23 +100,20 @@ func (mc *OnlineHistoryMongoConsumerHandler) handleChatWs2Mongo(
 	mc.msgDatabase.DelUserDeleteMsgsList(ctx, msgFromMQ.ConversationID, seqs)
 }
 
-func (OnlineHistoryMongoConsumerHandler) Setup(_ sarama.ConsumerGroupSession) error   { return nil }
-func (OnlineHistoryMongoConsumerHandler) Cleanup(_ sarama.ConsumerGroupSession) error { return nil }
-
 func (mc *OnlineHistoryMongoConsumerHandler) ConsumeClaim(
-	sess sarama.ConsumerGroupSession,
-	claim sarama.ConsumerGroupClaim,
+	sess mq.ConsumerGroupSession,
+	claim mq.ConsumerGroupClaim,
 ) error { // a instance in the consumer group
 	log.ZDebug(context.Background(), "online new session msg come", "highWaterMarkOffset",
 		claim.HighWaterMarkOffset(), "topic", claim.Topic(), "partition", claim.Partition())
 	for msg := range claim.Messages() {
-		ctx := mc.historyConsumerGroup.GetContextFromMsg(msg)
+		ctx := mq.GetContextFromMsg(msg)
 		if len(msg.Value) != 0 {
 			mc.handleChatWs2Mongo(ctx, msg, string(msg.Key), sess)
 		} else {
 			log.ZError(ctx, "mongo msg get from kafka but is nil", nil, "conversationID", msg.Key)
 		}
-		sess.MarkMessage(msg, "")
+		sess.MarkMessage(msg)
 	}
 	return nil
 }
@@ -16,86 +16,106 @@ package msgtransfer
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/OpenIMSDK/protocol/constant"
 	pbmsg "github.com/OpenIMSDK/protocol/msg"
+	"github.com/OpenIMSDK/protocol/sdkws"
 	"github.com/OpenIMSDK/tools/log"
 	"github.com/OpenIMSDK/tools/utils"
 
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+	unrelationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/unrelation"
 	kfk "github.com/openimsdk/open-im-server/v3/pkg/common/kafka"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/mq"
 
-	"github.com/IBM/sarama"
 	"google.golang.org/protobuf/proto"
 )
 
+// PersistentConsumerHandler mirrors the msgs stored in mongo into the mysql chat logs,
+// together with the revokes and deletes sent to the modify topic.
 type PersistentConsumerHandler struct {
-	persistentConsumerGroup *kfk.MConsumerGroup
+	persistentConsumerGroup mq.ConsumerGroup
 	chatLogDatabase         controller.ChatLogDatabase
 }
 
 func NewPersistentConsumerHandler(database controller.ChatLogDatabase) *PersistentConsumerHandler {
 	return &PersistentConsumerHandler{
-		persistentConsumerGroup: kfk.NewMConsumerGroup(&kfk.MConsumerGroupConfig{
-			KafkaVersion:   sarama.V2_0_0_0,
-			OffsetsInitial: sarama.OffsetNewest, IsReturnErr: false,
-		}, []string{config.Config.Kafka.LatestMsgToRedis.Topic},
-			config.Config.Kafka.Addr, config.Config.Kafka.ConsumerGroupID.MsgToMySql),
+		persistentConsumerGroup: mq.NewConsumerGroup([]string{config.Config.Kafka.MsgToMongo.Topic, config.Config.Kafka.MsgToModify.Topic},
+			config.Config.Kafka.ConsumerGroupID.MsgToMySql),
 		chatLogDatabase: database,
 	}
 }
 
-func (pc *PersistentConsumerHandler) handleChatWs2Mysql(
-	ctx context.Context,
-	cMsg *sarama.ConsumerMessage,
-	msgKey string,
-	_ sarama.ConsumerGroupSession,
-) {
-	msg := cMsg.Value
-	var tag bool
-	msgFromMQ := pbmsg.MsgDataToMQ{}
-	err := proto.Unmarshal(msg, &msgFromMQ)
-	if err != nil {
+func (pc *PersistentConsumerHandler) handleChatWs2Mysql(ctx context.Context, cMsg *mq.Message) {
+	msgFromMQ := pbmsg.MsgDataToMongoByMQ{}
+	if err := proto.Unmarshal(cMsg.Value, &msgFromMQ); err != nil {
 		log.ZError(ctx, "msg_transfer Unmarshal msg err", err)
 		return
 	}
-
-	log.ZDebug(ctx, "handleChatWs2Mysql", "msg", msgFromMQ.MsgData)
 	// Control whether to store history messages (mysql)
-	isPersist := utils.GetSwitchFromOptions(msgFromMQ.MsgData.Options, constant.IsPersistent)
-	// Only process receiver data
-	if isPersist {
-		switch msgFromMQ.MsgData.SessionType {
-		case constant.SingleChatType, constant.NotificationChatType:
-			if msgKey == msgFromMQ.MsgData.RecvID {
-				tag = true
-			}
-		case constant.GroupChatType:
-			if msgKey == msgFromMQ.MsgData.SendID {
-				tag = true
-			}
-		case constant.SuperGroupChatType:
-			tag = true
+	var msgs []*sdkws.MsgData
+	for _, msg := range msgFromMQ.MsgData {
+		if utils.GetSwitchFromOptions(msg.Options, constant.IsPersistent) {
+			msgs = append(msgs, msg)
 		}
-		if tag {
-			log.ZInfo(ctx, "msg_transfer msg persisting", "msg", string(msg))
-			if err = pc.chatLogDatabase.CreateChatLog(&msgFromMQ); err != nil {
-				log.ZError(ctx, "Message insert failed", err, "msg", msgFromMQ.String())
-				return
+	}
+	if len(msgs) == 0 {
+		return
+	}
+	log.ZDebug(ctx, "msg_transfer msg persisting", "conversationID", msgFromMQ.ConversationID, "len", len(msgs))
+	if err := pc.chatLogDatabase.CreateChatLogs(ctx, msgFromMQ.ConversationID, msgs); err != nil {
+		log.ZError(ctx, "Message insert failed", err, "conversationID", msgFromMQ.ConversationID, "len", len(msgs))
+	}
+}
+
+func (pc *PersistentConsumerHandler) handleModify2Mysql(ctx context.Context, cMsg *mq.Message) {
+	modifyType := kfk.GetModifyType(cMsg.Headers)
+	if modifyType == "" {
+		return
+	}
+	msgFromMQ := pbmsg.MsgDataToModifyByMQ{}
+	if err := proto.Unmarshal(cMsg.Value, &msgFromMQ); err != nil {
+		log.ZError(ctx, "msg_transfer Unmarshal msg err", err)
+		return
+	}
+	if len(msgFromMQ.Messages) == 0 {
+		return
+	}
+	var err error
+	conversationID := msgFromMQ.ConversationID
+	switch modifyType {
+	case kfk.ModifyTypeRevoke:
+		for _, msg := range msgFromMQ.Messages {
+			var revoke unrelationtb.RevokeModel
+			if err = json.Unmarshal(msg.Content, &revoke); err != nil {
+				break
+			}
+			if err = pc.chatLogDatabase.RevokeChatLog(ctx, conversationID, msg.Seq, &revoke); err != nil {
+				break
 			}
 		}
+	case kfk.ModifyTypeDelete:
+		seqs := utils.Slice(msgFromMQ.Messages, func(msg *sdkws.MsgData) int64 { return msg.Seq })
+		err = pc.chatLogDatabase.DeleteChatLogs(ctx, conversationID, seqs)
+	case kfk.ModifyTypeDeleteBefore:
+		err = pc.chatLogDatabase.DeleteChatLogsBefore(ctx, conversationID, msgFromMQ.Messages[0].Seq)
+	default:
+		log.ZWarn(ctx, "unknown modify type", nil, "modifyType", modifyType, "conversationID", conversationID)
+		return
+	}
+	if err != nil {
+		log.ZError(ctx, "chat log modify failed", err, "modifyType", modifyType, "conversationID", conversationID)
 	}
 }
-func (PersistentConsumerHandler) Setup(_ sarama.ConsumerGroupSession) error   { return nil }
-func (PersistentConsumerHandler) Cleanup(_ sarama.ConsumerGroupSession) error { return nil }
 
 func (pc *PersistentConsumerHandler) ConsumeClaim(
-	sess sarama.ConsumerGroupSession,
-	claim sarama.ConsumerGroupClaim,
+	sess mq.ConsumerGroupSession,
+	claim mq.ConsumerGroupClaim,
 ) error {
 	for msg := range claim.Messages() {
-		ctx := pc.persistentConsumerGroup.GetContextFromMsg(msg)
+		ctx := mq.GetContextFromMsg(msg)
 		log.ZDebug(
 			ctx,
 			"kafka get info to mysql",
@@ -103,17 +123,20 @@ func (pc *PersistentConsumerHandler) ConsumeClaim(
 			msg.Topic,
 			"msgPartition",
 			msg.Partition,
-			"msg",
-			string(msg.Value),
 			"key",
 			string(msg.Key),
 		)
 		if len(msg.Value) != 0 {
-			pc.handleChatWs2Mysql(ctx, msg, string(msg.Key), sess)
+			switch msg.Topic {
+			case config.Config.Kafka.MsgToModify.Topic:
+				pc.handleModify2Mysql(ctx, msg)
+			default:
+				pc.handleChatWs2Mysql(ctx, msg)
+			}
 		} else {
 			log.ZError(ctx, "msg get from kafka but is nil", nil, "key", msg.Key)
 		}
-		sess.MarkMessage(msg, "")
+		sess.MarkMessage(msg)
 	}
 	return nil
 }
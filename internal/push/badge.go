@@ -0,0 +1,156 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package push
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	pbconversation "github.com/OpenIMSDK/protocol/conversation"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/utils"
+
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
+)
+
+// incrBadgeUnreadCount counts msg into the cached badges of its receivers.
+// Only users that already have a cached badge are touched, the others are recounted on their next offline push.
+func (p *Pusher) incrBadgeUnreadCount(ctx context.Context, msg *sdkws.MsgData, userIDs []string) {
+	if !utils.GetSwitchFromOptions(msg.Options, constant.IsUnreadCount) {
+		return
+	}
+	conversationID := msgprocessor.GetConversationIDByMsg(msg)
+	if msgprocessor.IsNotification(conversationID) {
+		return
+	}
+	badges, err := p.database.GetUserBadgeUnreadCountSums(ctx, utils.SliceSub(userIDs, []string{msg.SendID}))
+	if err != nil {
+		log.ZWarn(ctx, "GetUserBadgeUnreadCountSums failed", err, "conversationID", conversationID)
+		return
+	}
+	if len(badges) == 0 {
+		return
+	}
+	resp, err := p.conversationRpcClient.Client.GetConversationOfflinePushUserIDs(ctx,
+		&pbconversation.GetConversationOfflinePushUserIDsReq{ConversationID: conversationID, UserIDs: utils.Keys(badges)})
+	if err != nil {
+		log.ZWarn(ctx, "GetConversationOfflinePushUserIDs failed", err, "conversationID", conversationID)
+		return
+	}
+	if err := p.database.IncrUserBadgeUnreadCountSums(ctx, resp.UserIDs, 1); err != nil {
+		log.ZWarn(ctx, "IncrUserBadgeUnreadCountSums failed", err, "conversationID", conversationID)
+	}
+}
+
+// getBadgeUnreadCounts returns the badge of every user, recounting and caching the ones that are not cached.
+// Users whose badge cannot be counted are absent from the result.
+func (p *Pusher) getBadgeUnreadCounts(ctx context.Context, userIDs []string) map[string]int {
+	badges, err := p.database.GetUserBadgeUnreadCountSums(ctx, userIDs)
+	if err != nil {
+		log.ZWarn(ctx, "GetUserBadgeUnreadCountSums failed", err, "userIDs", userIDs)
+		badges = make(map[string]int)
+	}
+	for _, userID := range userIDs {
+		if _, ok := badges[userID]; ok {
+			continue
+		}
+		count, err := p.countUnread(ctx, userID)
+		if err != nil {
+			log.ZWarn(ctx, "count unread failed", err, "userID", userID)
+			continue
+		}
+		badges[userID] = count
+		if err := p.database.SetUserBadgeUnreadCountSum(ctx, userID, count); err != nil {
+			log.ZWarn(ctx, "SetUserBadgeUnreadCountSum failed", err, "userID", userID)
+		}
+	}
+	return badges
+}
+
+// countUnread sums the unread messages of the user over every conversation that counts toward the badge.
+func (p *Pusher) countUnread(ctx context.Context, userID string) (int, error) {
+	resp, err := p.conversationRpcClient.Client.GetAllConversations(ctx, &pbconversation.GetAllConversationsReq{OwnerUserID: userID})
+	if err != nil {
+		return 0, err
+	}
+	conversationMaxSeqMap := make(map[string]int64)
+	var conversationIDs []string
+	for _, conversation := range resp.Conversations {
+		if !msgprocessor.IsBadgeCounted(conversation.ConversationID, conversation.RecvMsgOpt) {
+			continue
+		}
+		conversationIDs = append(conversationIDs, conversation.ConversationID)
+		if conversation.MaxSeq != 0 {
+			conversationMaxSeqMap[conversation.ConversationID] = conversation.MaxSeq
+		}
+	}
+	if len(conversationIDs) == 0 {
+		return 0, nil
+	}
+	maxSeqs, err := p.database.GetMaxSeqs(ctx, conversationIDs)
+	if err != nil {
+		return 0, err
+	}
+	hasReadSeqs, err := p.database.GetHasReadSeqs(ctx, userID, conversationIDs)
+	if err != nil {
+		return 0, err
+	}
+	var count int64
+	for conversationID, maxSeq := range maxSeqs {
+		// the conversation max seq caps what the user can see, e.g. after leaving a group
+		if v, ok := conversationMaxSeqMap[conversationID]; ok && v < maxSeq {
+			maxSeq = v
+		}
+		if unread := maxSeq - hasReadSeqs[conversationID]; unread > 0 {
+			count += unread
+		}
+	}
+	return int(count), nil
+}
+
+// pushOfflineWithBadge pushes to userIDs, grouped by their badge when badge counting is on.
+func (p *Pusher) pushOfflineWithBadge(ctx context.Context, userIDs []string, title, content string, opts *offlinepush.Opts) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	if !config.Config.IOSPush.BadgeCount {
+		return p.offlinePusher.Push(ctx, userIDs, title, content, opts)
+	}
+	badges := p.getBadgeUnreadCounts(ctx, userIDs)
+	badgeUserIDs := make(map[int][]string)
+	var unknownUserIDs []string
+	for _, userID := range userIDs {
+		if badge, ok := badges[userID]; ok {
+			badgeUserIDs[badge] = append(badgeUserIDs[badge], userID)
+		} else {
+			unknownUserIDs = append(unknownUserIDs, userID)
+		}
+	}
+	for badge, ids := range badgeUserIDs {
+		badge := badge
+		badgeOpts := *opts
+		badgeOpts.Badge = &badge
+		if err := p.offlinePusher.Push(ctx, ids, title, content, &badgeOpts); err != nil {
+			return err
+		}
+	}
+	if len(unknownUserIDs) > 0 {
+		return p.offlinePusher.Push(ctx, unknownUserIDs, title, content, opts)
+	}
+	return nil
+}
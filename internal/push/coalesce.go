@@ -0,0 +1,155 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package push
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/OpenIMSDK/tools/utils"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
+)
+
+// coalesceFlushBatch the number of due windows taken from redis at a time.
+const coalesceFlushBatch = 1000
+
+// coalesceEnabled reports whether offline pushes of msg take part in coalescing.
+// Only super group messages are coalesced, signaling is always pushed at once.
+func (p *Pusher) coalesceEnabled(msg *sdkws.MsgData) bool {
+	coalesce := config.Config.Push.Coalesce
+	return coalesce.Enable && coalesce.Window > 0 &&
+		msg.SessionType == constant.SuperGroupChatType && msg.ContentType != constant.SignalingNotification
+}
+
+// coalesceOfflinePush counts msg in the coalescing window of every user and returns the users to push right now.
+// The first push of a window is delivered immediately, the following ones are only counted and, when the window
+// closes, collapse into one "N new messages" push. Mentioned users always get the push.
+func (p *Pusher) coalesceOfflinePush(ctx context.Context, msg *sdkws.MsgData, userIDs []string) []string {
+	if len(userIDs) == 0 || utils.IsContain(constant.AtAllString, msg.AtUserIDList) {
+		return userIDs
+	}
+	candidates := utils.SliceSub(userIDs, msg.AtUserIDList)
+	if len(candidates) == 0 {
+		return userIDs
+	}
+	conversationID := msgprocessor.GetConversationIDByMsg(msg)
+	window := time.Duration(config.Config.Push.Coalesce.Window) * time.Second
+	// the key outlives the window, so a window whose owner died is dropped instead of staying open forever
+	counts, err := p.database.IncrPushCoalesceCounts(ctx, conversationID, candidates, window*2)
+	if err != nil {
+		log.ZWarn(ctx, "IncrPushCoalesceCounts failed", err, "conversationID", conversationID)
+		return userIDs
+	}
+	var pushUserIDs []string
+	var opened []*cache.PushCoalesceWindow
+	for _, userID := range userIDs {
+		count, ok := counts[userID]
+		if !ok {
+			pushUserIDs = append(pushUserIDs, userID)
+		} else if count == 1 {
+			pushUserIDs = append(pushUserIDs, userID)
+			opened = append(opened, &cache.PushCoalesceWindow{ConversationID: conversationID, GroupID: msg.GroupID, UserID: userID})
+		}
+	}
+	// the windows are closed from redis by any push instance, see flushCoalescedPushes
+	if err := p.database.AddPushCoalesceWindows(ctx, opened, time.Now().Add(window)); err != nil {
+		log.ZWarn(ctx, "AddPushCoalesceWindows failed", err, "conversationID", conversationID)
+	}
+	log.ZDebug(ctx, "offline push coalesced", "conversationID", conversationID, "userIDs", len(userIDs), "pushUserIDs", len(pushUserIDs), "opened", len(opened))
+	return pushUserIDs
+}
+
+// flushCoalescedPushes closes the due coalescing windows once a second until the process exits.
+func (p *Pusher) flushCoalescedPushes() {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for range ticker.C {
+		ctx := mcontext.NewCtx(utils.GetSelfFuncName() + "_" + utils.OperationIDGenerator())
+		for {
+			windows, err := p.database.PopDuePushCoalesceWindows(ctx, time.Now(), coalesceFlushBatch)
+			if err != nil {
+				log.ZError(ctx, "PopDuePushCoalesceWindows failed", err)
+				break
+			}
+			type conversationGroup struct{ conversationID, groupID string }
+			userIDs := make(map[conversationGroup][]string)
+			for _, window := range windows {
+				key := conversationGroup{conversationID: window.ConversationID, groupID: window.GroupID}
+				userIDs[key] = append(userIDs[key], window.UserID)
+			}
+			for key, ids := range userIDs {
+				p.flushCoalescedPush(ctx, key.conversationID, key.groupID, ids)
+			}
+			if len(windows) < coalesceFlushBatch {
+				break
+			}
+		}
+	}
+}
+
+// flushCoalescedPush closes the windows of the users and sends one summary push to every user who got more
+// messages than the one already pushed. The summary shares the collapse key, so it replaces the first notification.
+func (p *Pusher) flushCoalescedPush(ctx context.Context, conversationID, groupID string, userIDs []string) {
+	counts, err := p.database.GetDelPushCoalesceCounts(ctx, conversationID, userIDs)
+	if err != nil {
+		log.ZError(ctx, "GetDelPushCoalesceCounts failed", err, "conversationID", conversationID)
+		return
+	}
+	countUserIDs := make(map[int64][]string)
+	for userID, count := range counts {
+		if count > 1 {
+			countUserIDs[count] = append(countUserIDs[count], userID)
+		}
+	}
+	if len(countUserIDs) == 0 {
+		return
+	}
+	if p.offlinePusher == nil {
+		log.ZWarn(ctx, "flush coalesced push", errNoOfflinePusher, "conversationID", conversationID)
+		return
+	}
+	groupName := constant.ContentType2PushContent[constant.GroupMsg]
+	if groupInfo, err := p.groupRpcClient.GetGroupInfoCache(ctx, groupID); err != nil {
+		log.ZWarn(ctx, "GetGroupInfoCache failed", err, "groupID", groupID)
+	} else if groupInfo.GroupName != "" {
+		groupName = groupInfo.GroupName
+	}
+	summary := &sdkws.MsgData{
+		GroupID:     groupID,
+		SessionType: constant.SuperGroupChatType,
+		ContentType: constant.Text,
+	}
+	for count, userIDs := range countUserIDs {
+		opts, err := p.GetOfflinePushOpts(summary)
+		if err != nil {
+			log.ZError(ctx, "GetOfflinePushOpts failed", err)
+			return
+		}
+		opts.CollapseKey = conversationID
+		pushUserIDs, silentUserIDs := p.filterDoNotDisturb(ctx, summary, userIDs)
+		content := fmt.Sprintf("%d new messages in %s", count, groupName)
+		if err := p.pushOffline(ctx, pushUserIDs, silentUserIDs, groupName, content, opts); err != nil {
+			log.ZError(ctx, "push coalesced summary failed", err, "conversationID", conversationID, "count", count, "userIDs", userIDs)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package push
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/utils"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
+)
+
+// doNotDisturbModeSilent pushes to disturbed users without sound, any other mode drops their pushes.
+const doNotDisturbModeSilent = "silent"
+
+// filterDoNotDisturb removes the users who are in their quiet hours or have snoozed the conversation of msg.
+// Depending on push.doNotDisturb.mode they are dropped or returned in silentUserIDs to be pushed without sound.
+// A failed lookup is logged and treated as not disturbed, so notifications are never lost because of it.
+func (p *Pusher) filterDoNotDisturb(ctx context.Context, msg *sdkws.MsgData, userIDs []string) (pushUserIDs, silentUserIDs []string) {
+	dndConfig := config.Config.Push.DoNotDisturb
+	if !dndConfig.Enable || len(userIDs) == 0 {
+		return userIDs, nil
+	}
+	if dndConfig.SignalBypass && msg.ContentType == constant.SignalingNotification {
+		return userIDs, nil
+	}
+	candidates := userIDs
+	if dndConfig.AtBypass && len(msg.AtUserIDList) > 0 {
+		if utils.IsContain(constant.AtAllString, msg.AtUserIDList) {
+			return userIDs, nil
+		}
+		candidates = utils.SliceSub(userIDs, msg.AtUserIDList)
+		if len(candidates) == 0 {
+			return userIDs, nil
+		}
+	}
+	now := time.Now()
+	disturbed := make(map[string]struct{})
+	dndMap, err := p.userRpcClient.GetDoNotDisturbMap(ctx, candidates)
+	if err != nil {
+		log.ZWarn(ctx, "GetDoNotDisturbMap failed", err, "userIDs", candidates)
+	}
+	for userID, dnd := range dndMap {
+		if dnd.InQuietHours(now) {
+			disturbed[userID] = struct{}{}
+		}
+	}
+	conversationID := msgprocessor.GetConversationIDByMsg(msg)
+	snoozedUserIDs, err := p.conversationRpcClient.GetSnoozedUserIDs(ctx, conversationID, candidates)
+	if err != nil {
+		log.ZWarn(ctx, "GetSnoozedUserIDs failed", err, "conversationID", conversationID, "userIDs", candidates)
+	}
+	for _, userID := range snoozedUserIDs {
+		disturbed[userID] = struct{}{}
+	}
+	if len(disturbed) == 0 {
+		return userIDs, nil
+	}
+	for _, userID := range userIDs {
+		if _, ok := disturbed[userID]; !ok {
+			pushUserIDs = append(pushUserIDs, userID)
+		} else if dndConfig.Mode == doNotDisturbModeSilent {
+			silentUserIDs = append(silentUserIDs, userID)
+		}
+	}
+	log.ZDebug(ctx, "do not disturb filtered", "conversationID", conversationID, "mode", dndConfig.Mode, "disturbed", len(disturbed), "silentUserIDs", silentUserIDs)
+	return pushUserIDs, silentUserIDs
+}
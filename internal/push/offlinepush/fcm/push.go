@@ -20,10 +20,10 @@ import (
 
 	firebase "firebase.google.com/go"
 	"firebase.google.com/go/messaging"
-	"github.com/redis/go-redis/v9"
 	"google.golang.org/api/option"
 
 	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/tools/errs"
 
 	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
@@ -57,6 +57,12 @@ func NewClient(cache cache.MsgModel) *Fcm {
 }
 
 func (f *Fcm) Push(ctx context.Context, userIDs []string, title, content string, opts *offlinepush.Opts) error {
+	_, err := f.PushWithResults(ctx, userIDs, title, content, opts)
+	return err
+}
+
+// PushWithResults a user is refused when it has no token or none of its tokens was accepted.
+func (f *Fcm) PushWithResults(ctx context.Context, userIDs []string, title, content string, opts *offlinepush.Opts) (map[string]error, error) {
 	// accounts->registrationToken
 	allTokens := make(map[string][]string, 0)
 	for _, account := range userIDs {
@@ -69,67 +75,69 @@ func (f *Fcm) Push(ctx context.Context, userIDs []string, title, content string,
 		}
 		allTokens[account] = personTokens
 	}
-	Success := 0
-	Fail := 0
 	notification := &messaging.Notification{}
 	notification.Body = content
 	notification.Title = title
-	var messages []*messaging.Message
+	var (
+		messages       []*messaging.Message
+		messageUserIDs []string
+		userErrs       = make(map[string]error)
+		pushed         = make(map[string]struct{})
+	)
+	sendAll := func() {
+		response, err := f.fcmMsgCli.SendAll(ctx, messages)
+		for i, userID := range messageUserIDs {
+			switch {
+			case err != nil:
+				userErrs[userID] = err
+			case response.Responses[i].Success:
+				pushed[userID] = struct{}{}
+			default:
+				userErrs[userID] = response.Responses[i].Error
+			}
+		}
+		messages = messages[0:0]
+		messageUserIDs = messageUserIDs[0:0]
+	}
 	for userID, personTokens := range allTokens {
+		if len(personTokens) == 0 {
+			userErrs[userID] = errs.ErrRecordNotFound.Wrap("fcm token not found")
+			continue
+		}
 		apns := &messaging.APNSConfig{Payload: &messaging.APNSPayload{Aps: &messaging.Aps{Sound: opts.IOSPushSound}}}
-		messageCount := len(messages)
-		if messageCount >= SinglePushCountLimit {
-			response, err := f.fcmMsgCli.SendAll(ctx, messages)
-			if err != nil {
-				Fail = Fail + messageCount
-			} else {
-				Success = Success + response.SuccessCount
-				Fail = Fail + response.FailureCount
-			}
-			messages = messages[0:0]
+		var android *messaging.AndroidConfig
+		if opts.Silent || opts.CollapseKey != "" {
+			android = &messaging.AndroidConfig{CollapseKey: opts.CollapseKey, Notification: &messaging.AndroidNotification{Tag: opts.CollapseKey}}
 		}
-		if opts.IOSBadgeCount {
-			unreadCountSum, err := f.cache.IncrUserBadgeUnreadCountSum(ctx, userID)
-			if err == nil {
-				apns.Payload.Aps.Badge = &unreadCountSum
-			} else {
-				// log.Error(operationID, "IncrUserBadgeUnreadCountSum redis err", err.Error(), uid)
-				Fail++
-				continue
-			}
-		} else {
-			unreadCountSum, err := f.cache.GetUserBadgeUnreadCountSum(ctx, userID)
-			if err == nil && unreadCountSum != 0 {
-				apns.Payload.Aps.Badge = &unreadCountSum
-			} else if err == redis.Nil || unreadCountSum == 0 {
-				zero := 1
-				apns.Payload.Aps.Badge = &zero
-			} else {
-				// log.Error(operationID, "GetUserBadgeUnreadCountSum redis err", err.Error(), uid)
-				Fail++
-				continue
-			}
+		if opts.Silent {
+			apns.Payload.Aps.Sound = ""
+			android.Notification.Priority = messaging.PriorityLow
+		}
+		if opts.CollapseKey != "" {
+			apns.Headers = map[string]string{"apns-collapse-id": opts.CollapseKey}
 		}
+		if len(messages) >= SinglePushCountLimit {
+			sendAll()
+		}
+		apns.Payload.Aps.Badge = opts.Badge
 		for _, token := range personTokens {
 			temp := &messaging.Message{
 				Data:         map[string]string{"ex": opts.Ex},
 				Token:        token,
 				Notification: notification,
 				APNS:         apns,
+				Android:      android,
 			}
 			messages = append(messages, temp)
+			messageUserIDs = append(messageUserIDs, userID)
 		}
 	}
-	messageCount := len(messages)
-	if messageCount > 0 {
-		response, err := f.fcmMsgCli.SendAll(ctx, messages)
-		if err != nil {
-			Fail = Fail + messageCount
-			// log.Info(operationID, "some token push err", err.Error(), messageCount)
-		} else {
-			Success = Success + response.SuccessCount
-			Fail = Fail + response.FailureCount
-		}
+	if len(messages) > 0 {
+		sendAll()
+	}
+	// one accepted token is enough
+	for userID := range pushed {
+		delete(userErrs, userID)
 	}
-	return nil
+	return userErrs, nil
 }
@@ -16,6 +16,7 @@ package getui
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
 )
@@ -183,3 +184,14 @@ func (pushReq *PushReq) setPushChannel(title string, body string) {
 		},
 	}
 }
+
+func (pushReq *PushReq) setBadge(badge int) {
+	autoBadge := strconv.Itoa(badge)
+	pushReq.PushChannel.Ios.AutoBadge = &autoBadge
+}
+
+func (pushReq *PushReq) setSilent() {
+	pushReq.PushChannel.Ios.Aps.Sound = ""
+	pushReq.PushChannel.Android.Ups.Options.HW.Sound = ""
+	pushReq.PushChannel.Android.Ups.Options.HW.Importance = "LOW"
+}
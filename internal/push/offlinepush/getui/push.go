@@ -80,6 +80,12 @@ func (g *Client) Push(ctx context.Context, userIDs []string, title, content stri
 	}
 	pushReq := newPushReq(title, content)
 	pushReq.setPushChannel(title, content)
+	if opts.Silent {
+		pushReq.setSilent()
+	}
+	if opts.Badge != nil {
+		pushReq.setBadge(*opts.Badge)
+	}
 	if len(userIDs) > 1 {
 		maxNum := 999
 		if len(userIDs) > maxNum {
@@ -15,6 +15,8 @@
 package body
 
 import (
+	"strconv"
+
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
 )
 
@@ -64,3 +66,11 @@ func (n *Notification) SetAndroidIntent() {
 func (n *Notification) IOSEnableMutableContent() {
 	n.IOS.MutableContent = true
 }
+
+func (n *Notification) SetSilent() {
+	n.IOS.Sound = ""
+}
+
+func (n *Notification) SetBadge(badge int) {
+	n.IOS.Badge = strconv.Itoa(badge)
+}
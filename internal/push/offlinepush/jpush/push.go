@@ -59,10 +59,17 @@ func (j *JPush) Push(ctx context.Context, userIDs []string, title, content strin
 	no.IOSEnableMutableContent()
 	no.SetExtras(extras)
 	no.SetAlert(title)
+	if opts.Silent {
+		no.SetSilent()
+	}
+	if opts.Badge != nil {
+		no.SetBadge(*opts.Badge)
+	}
 	var msg body.Message
 	msg.SetMsgContent(content)
 	var opt body.Options
 	opt.SetApnsProduction(config.Config.IOSPush.Production)
+	opt.SetApnsCollapseID(opts.CollapseKey)
 	var pushObj body.PushObj
 	pushObj.SetPlatform(&pf)
 	pushObj.SetAudience(&au)
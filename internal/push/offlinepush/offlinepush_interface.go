@@ -23,12 +23,28 @@ type OfflinePusher interface {
 	Push(ctx context.Context, userIDs []string, title, content string, opts *Opts) error
 }
 
+// UserResultPusher is implemented by the pushers whose vendor answers for every device token.
+type UserResultPusher interface {
+	OfflinePusher
+	// PushWithResults is Push also returning the error of every user the vendor refused,
+	// the users missing from userErrs were pushed.
+	PushWithResults(ctx context.Context, userIDs []string, title, content string, opts *Opts) (userErrs map[string]error, err error)
+}
+
 // Opts opts.
 type Opts struct {
 	Signal        *Signal
 	IOSPushSound  string
 	IOSBadgeCount bool
 	Ex            string
+	// ClientMsgID the message being pushed, used to audit the push
+	ClientMsgID string
+	// Silent deliver the notification without sound, e.g. during the receiver's quiet hours
+	Silent bool
+	// CollapseKey notifications with the same key replace each other on the device where the vendor supports it
+	CollapseKey string
+	// Badge the app icon badge of the receivers, nil when it is not counted
+	Badge *int
 }
 
 // Signal message id.
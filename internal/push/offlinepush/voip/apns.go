@@ -0,0 +1,153 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package voip
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+)
+
+const (
+	apnsProductionHost  = "https://api.push.apple.com"
+	apnsDevelopmentHost = "https://api.sandbox.push.apple.com"
+	// apns rejects provider tokens older than an hour and throttles ones refreshed more than every 20 minutes
+	apnsTokenRefresh = time.Minute * 50
+)
+
+// errApnsBadToken the device token is no longer valid and should be dropped.
+var errApnsBadToken = errors.New("apns device token is invalid")
+
+// apnsClient sends PushKit VoIP pushes over the APNs HTTP/2 API with token based authentication.
+type apnsClient struct {
+	host     string
+	keyID    string
+	teamID   string
+	topic    string
+	key      *ecdsa.PrivateKey
+	client   *http.Client
+	lock     sync.Mutex
+	token    string
+	issuedAt time.Time
+}
+
+func newApnsClient(keyFile, keyID, teamID, bundleID string, production bool) (*apnsClient, error) {
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errs.Wrap(errors.New("apns key file is not pem encoded"))
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	ecdsaKey, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, errs.Wrap(errors.New("apns key is not an ecdsa key"))
+	}
+	host := apnsDevelopmentHost
+	if production {
+		host = apnsProductionHost
+	}
+	return &apnsClient{
+		host:   host,
+		keyID:  keyID,
+		teamID: teamID,
+		topic:  bundleID + ".voip",
+		key:    ecdsaKey,
+		client: &http.Client{Timeout: time.Second * 10},
+	}, nil
+}
+
+// providerToken returns the ES256 signed JWT apns authenticates the provider with.
+func (a *apnsClient) providerToken() (string, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if a.token != "" && time.Since(a.issuedAt) < apnsTokenRefresh {
+		return a.token, nil
+	}
+	now := time.Now()
+	header, err := json.Marshal(map[string]string{"alg": "ES256", "kid": a.keyID})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]any{"iss": a.teamID, "iat": now.Unix()})
+	if err != nil {
+		return "", err
+	}
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	r, s, err := ecdsa.Sign(rand.Reader, a.key, digest[:])
+	if err != nil {
+		return "", err
+	}
+	// JWS wants the fixed size r || s form instead of ASN.1
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+	a.token = unsigned + "." + base64.RawURLEncoding.EncodeToString(signature)
+	a.issuedAt = now
+	return a.token, nil
+}
+
+func (a *apnsClient) push(ctx context.Context, deviceToken string, payload []byte, expiration time.Time) error {
+	token, err := a.providerToken()
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.host+"/3/device/"+deviceToken, bytes.NewReader(payload))
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	req.Header.Set("authorization", "bearer "+token)
+	req.Header.Set("apns-push-type", "voip")
+	req.Header.Set("apns-topic", a.topic)
+	req.Header.Set("apns-priority", "10")
+	req.Header.Set("apns-expiration", strconv.FormatInt(expiration.Unix(), 10))
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	var result struct {
+		Reason string `json:"reason"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&result)
+	if resp.StatusCode == http.StatusGone || result.Reason == "BadDeviceToken" {
+		return errApnsBadToken
+	}
+	return errs.Wrap(fmt.Errorf("apns status %d reason %s", resp.StatusCode, result.Reason))
+}
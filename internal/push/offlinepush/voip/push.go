@@ -0,0 +1,149 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package voip
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	firebase "firebase.google.com/go"
+	"firebase.google.com/go/messaging"
+	"google.golang.org/api/option"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+)
+
+// Voip pushes incoming calls so they ring even when the app is killed: a PushKit VoIP push on iOS
+// and a high priority FCM data message on Android, both sent to the tokens registered for calls.
+type Voip struct {
+	apns      *apnsClient
+	fcmMsgCli *messaging.Client
+	cache     cache.MsgModel
+}
+
+func NewClient(cache cache.MsgModel) *Voip {
+	v := &Voip{cache: cache}
+	apnsConf := config.Config.Push.Voip.Apns
+	if apnsConf.KeyID != "" && apnsConf.TeamID != "" && apnsConf.BundleID != "" {
+		keyFile := filepath.Join(config.GetProjectRoot(), "config", apnsConf.KeyFile)
+		apns, err := newApnsClient(keyFile, apnsConf.KeyID, apnsConf.TeamID, apnsConf.BundleID, config.Config.IOSPush.Production)
+		if err != nil {
+			log.ZError(context.Background(), "init apns voip client failed", err)
+		} else {
+			v.apns = apns
+		}
+	}
+	credentialsFilePath := filepath.Join(config.GetProjectRoot(), "config", config.Config.Push.Fcm.ServiceAccount)
+	fcmApp, err := firebase.NewApp(context.Background(), nil, option.WithCredentialsFile(credentialsFilePath))
+	if err == nil {
+		if v.fcmMsgCli, err = fcmApp.Messaging(context.Background()); err != nil {
+			log.ZError(context.Background(), "init fcm voip client failed", err)
+		}
+	}
+	return v
+}
+
+type callPayload struct {
+	ClientMsgID string `json:"clientMsgID"`
+	Title       string `json:"title"`
+	Content     string `json:"content"`
+	Ex          string `json:"ex"`
+}
+
+func (v *Voip) Push(ctx context.Context, userIDs []string, title, content string, opts *offlinepush.Opts) error {
+	_, err := v.PushWithResults(ctx, userIDs, title, content, opts)
+	return err
+}
+
+// PushWithResults a user is refused when it has no call token or none of its call pushes was accepted.
+func (v *Voip) PushWithResults(ctx context.Context, userIDs []string, title, content string, opts *offlinepush.Opts) (map[string]error, error) {
+	ttl := time.Duration(config.Config.Push.Voip.TTL) * time.Second
+	expiration := time.Now().Add(ttl)
+	call := callPayload{ClientMsgID: opts.Signal.ClientMsgID, Title: title, Content: content, Ex: opts.Ex}
+	// the aps dictionary stays empty, the app reports the call to CallKit itself
+	apnsPayload, err := json.Marshal(struct {
+		Aps map[string]any `json:"aps"`
+		callPayload
+	}{Aps: map[string]any{}, callPayload: call})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	var total, fail int
+	userErrs := make(map[string]error)
+	for _, userID := range userIDs {
+		var (
+			userErr error
+			pushed  bool
+		)
+		if v.apns != nil {
+			if token, err := v.cache.GetVoipToken(ctx, userID, constant.IOSPlatformID); err == nil {
+				total++
+				if err := v.apns.push(ctx, token, apnsPayload, expiration); err != nil {
+					fail++
+					userErr = err
+					log.ZWarn(ctx, "apns voip push failed", err, "userID", userID)
+					if err == errApnsBadToken {
+						_ = v.cache.DelVoipToken(ctx, userID, constant.IOSPlatformID)
+					}
+				} else {
+					pushed = true
+				}
+			}
+		}
+		if v.fcmMsgCli != nil {
+			if token, err := v.cache.GetVoipToken(ctx, userID, constant.AndroidPlatformID); err == nil {
+				total++
+				// a data message without notification wakes the app, which shows the call screen itself
+				_, err := v.fcmMsgCli.Send(ctx, &messaging.Message{
+					Token: token,
+					Data: map[string]string{
+						"type":        "call",
+						"clientMsgID": call.ClientMsgID,
+						"title":       call.Title,
+						"content":     call.Content,
+						"ex":          call.Ex,
+					},
+					Android: &messaging.AndroidConfig{Priority: "high", TTL: &ttl},
+				})
+				if err != nil {
+					fail++
+					userErr = err
+					log.ZWarn(ctx, "fcm voip push failed", err, "userID", userID)
+				} else {
+					pushed = true
+				}
+			}
+		}
+		if !pushed {
+			if userErr == nil {
+				userErr = errs.ErrRecordNotFound.Wrap("voip token not found")
+			}
+			userErrs[userID] = userErr
+		}
+	}
+	if fail > 0 {
+		return userErrs, errs.ErrInternalServer.Wrap(fmt.Sprintf("%d of %d call pushes failed", fail, total))
+	}
+	return userErrs, nil
+}
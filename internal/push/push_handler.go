@@ -17,7 +17,6 @@ package push
 import (
 	"context"
 
-	"github.com/IBM/sarama"
 	"google.golang.org/protobuf/proto"
 
 	"github.com/OpenIMSDK/protocol/constant"
@@ -27,21 +26,18 @@ import (
 	"github.com/OpenIMSDK/tools/utils"
 
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
-	kfk "github.com/openimsdk/open-im-server/v3/pkg/common/kafka"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/mq"
 )
 
 type ConsumerHandler struct {
-	pushConsumerGroup *kfk.MConsumerGroup
+	pushConsumerGroup mq.ConsumerGroup
 	pusher            *Pusher
 }
 
 func NewConsumerHandler(pusher *Pusher) *ConsumerHandler {
 	var consumerHandler ConsumerHandler
 	consumerHandler.pusher = pusher
-	consumerHandler.pushConsumerGroup = kfk.NewMConsumerGroup(&kfk.MConsumerGroupConfig{
-		KafkaVersion:   sarama.V2_0_0_0,
-		OffsetsInitial: sarama.OffsetNewest, IsReturnErr: false,
-	}, []string{config.Config.Kafka.MsgToPush.Topic}, config.Config.Kafka.Addr,
+	consumerHandler.pushConsumerGroup = mq.NewConsumerGroup([]string{config.Config.Kafka.MsgToPush.Topic},
 		config.Config.Kafka.ConsumerGroupID.MsgToPush)
 	return &consumerHandler
 }
@@ -83,15 +79,13 @@ func (c *ConsumerHandler) handleMs2PsChat(ctx context.Context, msg []byte) {
 		}
 	}
 }
-func (ConsumerHandler) Setup(_ sarama.ConsumerGroupSession) error   { return nil }
-func (ConsumerHandler) Cleanup(_ sarama.ConsumerGroupSession) error { return nil }
-func (c *ConsumerHandler) ConsumeClaim(sess sarama.ConsumerGroupSession,
-	claim sarama.ConsumerGroupClaim,
+func (c *ConsumerHandler) ConsumeClaim(sess mq.ConsumerGroupSession,
+	claim mq.ConsumerGroupClaim,
 ) error {
 	for msg := range claim.Messages() {
-		ctx := c.pushConsumerGroup.GetContextFromMsg(msg)
+		ctx := mq.GetContextFromMsg(msg)
 		c.handleMs2PsChat(ctx, msg.Value)
-		sess.MarkMessage(msg, "")
+		sess.MarkMessage(msg)
 	}
 	return nil
 }
@@ -0,0 +1,113 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package push
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/utils"
+
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+	unrelationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/unrelation"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcext"
+)
+
+// recordPusher records every push attempt of the wrapped vendor, one record per user. The push stays one batch,
+// a vendor answering per token gives every user its own outcome, the other vendors share the batch outcome.
+type recordPusher struct {
+	offlinepush.OfflinePusher
+	vendor   string
+	database controller.PushDatabase
+}
+
+func newRecordPusher(pusher offlinepush.OfflinePusher, vendor string, database controller.PushDatabase) offlinepush.OfflinePusher {
+	return &recordPusher{OfflinePusher: pusher, vendor: vendor, database: database}
+}
+
+func (r *recordPusher) Push(ctx context.Context, userIDs []string, title, content string, opts *offlinepush.Opts) error {
+	var (
+		start    = time.Now()
+		userErrs map[string]error
+		err      error
+	)
+	if pusher, ok := r.OfflinePusher.(offlinepush.UserResultPusher); ok {
+		userErrs, err = pusher.PushWithResults(ctx, userIDs, title, content, opts)
+	} else {
+		err = r.OfflinePusher.Push(ctx, userIDs, title, content, opts)
+	}
+	records := utils.Slice(userIDs, func(userID string) *unrelationtb.PushRecordModel {
+		userErr := err
+		if userErrs != nil {
+			userErr = userErrs[userID]
+		}
+		return newPushRecord(r.vendor, userID, opts.ClientMsgID, start, userErr)
+	})
+	if recordErr := r.database.CreatePushRecords(ctx, records); recordErr != nil {
+		log.ZWarn(ctx, "CreatePushRecords failed", recordErr, "vendor", r.vendor, "clientMsgID", opts.ClientMsgID)
+	}
+	return err
+}
+
+func newPushRecord(vendor, userID, clientMsgID string, start time.Time, err error) *unrelationtb.PushRecordModel {
+	record := &unrelationtb.PushRecordModel{
+		Vendor:      vendor,
+		UserID:      userID,
+		ClientMsgID: clientMsgID,
+		Latency:     time.Since(start).Milliseconds(),
+		CreateTime:  start,
+	}
+	if err != nil {
+		record.Code = errs.ServerInternalError
+		if codeErr, ok := errs.Unwrap(err).(errs.CodeError); ok {
+			record.Code = codeErr.Code()
+		}
+		record.ErrMsg = err.Error()
+	}
+	return record
+}
+
+func (r *pushServer) SearchPushRecords(ctx context.Context, req *rpcext.SearchPushRecordsReq) (*rpcext.SearchPushRecordsResp, error) {
+	if err := authverify.CheckAdmin(ctx); err != nil {
+		return nil, err
+	}
+	end := time.Now()
+	if req.EndTime != 0 {
+		end = time.UnixMilli(req.EndTime)
+	}
+	total, records, err := r.pusher.database.SearchPushRecords(ctx, req.UserID, req.ClientMsgID, time.UnixMilli(req.StartTime), end,
+		req.Pagination.PageNumber, req.Pagination.ShowNumber)
+	if err != nil {
+		return nil, err
+	}
+	return &rpcext.SearchPushRecordsResp{
+		Total: total,
+		Records: utils.Slice(records, func(record *unrelationtb.PushRecordModel) *rpcext.PushRecord {
+			return &rpcext.PushRecord{
+				Vendor:      record.Vendor,
+				UserID:      record.UserID,
+				ClientMsgID: record.ClientMsgID,
+				Code:        record.Code,
+				ErrMsg:      record.ErrMsg,
+				Latency:     record.Latency,
+				CreateTime:  record.CreateTime.UnixMilli(),
+			}
+		}),
+	}, nil
+}
@@ -0,0 +1,134 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package push
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/OpenIMSDK/tools/errs"
+
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+	unrelationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/unrelation"
+)
+
+type failingPusher struct {
+	mu     sync.Mutex
+	pushed [][]string
+	fail   map[string]bool
+}
+
+func (p *failingPusher) Push(ctx context.Context, userIDs []string, title, content string, opts *offlinepush.Opts) error {
+	p.mu.Lock()
+	p.pushed = append(p.pushed, userIDs)
+	p.mu.Unlock()
+	for _, userID := range userIDs {
+		if p.fail[userID] {
+			return errs.ErrArgs.Wrap("no token")
+		}
+	}
+	return nil
+}
+
+type pushRecordDatabase struct {
+	controller.PushDatabase
+	records []*unrelationtb.PushRecordModel
+}
+
+func (d *pushRecordDatabase) CreatePushRecords(ctx context.Context, records []*unrelationtb.PushRecordModel) error {
+	d.records = append(d.records, records...)
+	return nil
+}
+
+// resultPusher answers per user like a vendor reporting the outcome of every token.
+type resultPusher struct {
+	*failingPusher
+}
+
+func (p *resultPusher) PushWithResults(ctx context.Context, userIDs []string, title, content string, opts *offlinepush.Opts) (map[string]error, error) {
+	p.mu.Lock()
+	p.pushed = append(p.pushed, userIDs)
+	p.mu.Unlock()
+	userErrs := make(map[string]error)
+	for _, userID := range userIDs {
+		if p.fail[userID] {
+			userErrs[userID] = errs.ErrRecordNotFound.Wrap("fcm token not found")
+		}
+	}
+	return userErrs, nil
+}
+
+func TestRecordPusher(t *testing.T) {
+	type record struct {
+		userID string
+		code   int
+	}
+	tests := []struct {
+		name    string
+		fail    map[string]bool
+		perUser bool
+		wantErr bool
+		want    []record
+	}{
+		{
+			name:    "batch outcome shared by every user",
+			fail:    map[string]bool{"u2": true},
+			wantErr: true,
+			want:    []record{{"u1", errs.ArgsError}, {"u2", errs.ArgsError}, {"u3", errs.ArgsError}},
+		},
+		{
+			name: "batch success",
+			want: []record{{"u1", 0}, {"u2", 0}, {"u3", 0}},
+		},
+		{
+			name:    "outcome per user",
+			fail:    map[string]bool{"u2": true},
+			perUser: true,
+			want:    []record{{"u1", 0}, {"u2", errs.RecordNotFoundError}, {"u3", 0}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vendor := &failingPusher{fail: tt.fail}
+			var pusher offlinepush.OfflinePusher = vendor
+			if tt.perUser {
+				pusher = &resultPusher{vendor}
+			}
+			database := &pushRecordDatabase{}
+			recorder := newRecordPusher(pusher, "fcm", database)
+			err := recorder.Push(context.Background(), []string{"u1", "u2", "u3"}, "title", "content", &offlinepush.Opts{ClientMsgID: "m1"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Push() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if calls := vendor.pushed; len(calls) != 1 || len(calls[0]) != 3 {
+				t.Errorf("vendor calls = %v, want one batch of all the users", calls)
+			}
+			if len(database.records) != len(tt.want) {
+				t.Fatalf("got %d records, want %d", len(database.records), len(tt.want))
+			}
+			for i, want := range tt.want {
+				got := database.records[i]
+				if got.UserID != want.userID || got.Code != want.code || got.Vendor != "fcm" || got.ClientMsgID != "m1" {
+					t.Errorf("record %d = %+v, want user %s code %d", i, got, want.userID, want.code)
+				}
+				if (got.ErrMsg != "") != (want.code != 0) {
+					t.Errorf("record %d ErrMsg = %q, want it set only on failure", i, got.ErrMsg)
+				}
+			}
+		})
+	}
+}
@@ -25,10 +25,15 @@ import (
 	"github.com/OpenIMSDK/tools/discoveryregistry"
 	"github.com/OpenIMSDK/tools/log"
 
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush"
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/voip"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/localcache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/unrelation"
 	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcext"
 )
 
 type pushServer struct {
@@ -40,29 +45,54 @@ func Start(client discoveryregistry.SvcDiscoveryRegistry, server *grpc.Server) e
 	if err != nil {
 		return err
 	}
+	mongo, err := unrelation.NewMongo()
+	if err != nil {
+		return err
+	}
 	cacheModel := cache.NewMsgCacheModel(rdb)
+	database := controller.NewPushDatabase(cacheModel, unrelation.NewPushRecordMongoDriver(mongo.GetDatabase()))
 	offlinePusher := NewOfflinePusher(cacheModel)
-	database := controller.NewPushDatabase(cacheModel)
+	var callPusher offlinepush.OfflinePusher
+	if config.Config.Push.Voip.Enable {
+		callPusher = voip.NewClient(cacheModel)
+	}
+	if config.Config.Push.Audit.Enable {
+		if err := mongo.CreatePushRecordIndexes(config.Config.Push.Audit.Retention); err != nil {
+			return err
+		}
+		offlinePusher = newRecordPusher(offlinePusher, config.Config.Push.Enable, database)
+		if callPusher != nil {
+			callPusher = newRecordPusher(callPusher, "voip", database)
+		}
+	}
 	groupRpcClient := rpcclient.NewGroupRpcClient(client)
 	conversationRpcClient := rpcclient.NewConversationRpcClient(client)
 	msgRpcClient := rpcclient.NewMessageRpcClient(client)
+	userRpcClient := rpcclient.NewUserRpcClient(client)
 	pusher := NewPusher(
 		client,
 		offlinePusher,
+		callPusher,
 		database,
 		localcache.NewGroupLocalCache(&groupRpcClient),
 		localcache.NewConversationLocalCache(&conversationRpcClient),
 		&conversationRpcClient,
 		&groupRpcClient,
 		&msgRpcClient,
+		&userRpcClient,
 	)
+	if config.Config.Push.Coalesce.Enable {
+		go pusher.flushCoalescedPushes()
+	}
 	var wg sync.WaitGroup
 	wg.Add(2)
 	go func() {
 		defer wg.Done()
-		pbpush.RegisterPushMsgServiceServer(server, &pushServer{
+		s := &pushServer{
 			pusher: pusher,
-		})
+		}
+		pbpush.RegisterPushMsgServiceServer(server, s)
+		rpcext.RegisterPushExtServer(server, s)
 	}()
 	go func() {
 		defer wg.Done()
@@ -97,5 +127,8 @@ func (r *pushServer) DelUserPushToken(
 	if err = r.pusher.database.DelFcmToken(ctx, req.UserID, int(req.PlatformID)); err != nil {
 		return nil, err
 	}
+	if err = r.pusher.database.DelVoipToken(ctx, req.UserID, int(req.PlatformID)); err != nil {
+		return nil, err
+	}
 	return &pbpush.DelUserPushTokenResp{}, nil
 }
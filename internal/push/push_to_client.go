@@ -21,6 +21,7 @@ import (
 	"sync"
 
 	"golang.org/x/sync/errgroup"
+	"google.golang.org/protobuf/encoding/protowire"
 
 	"github.com/OpenIMSDK/protocol/constant"
 	"github.com/OpenIMSDK/protocol/conversation"
@@ -49,28 +50,33 @@ type Pusher struct {
 	database               controller.PushDatabase
 	discov                 discoveryregistry.SvcDiscoveryRegistry
 	offlinePusher          offlinepush.OfflinePusher
+	callPusher             offlinepush.OfflinePusher
 	groupLocalCache        *localcache.GroupLocalCache
 	conversationLocalCache *localcache.ConversationLocalCache
 	msgRpcClient           *rpcclient.MessageRpcClient
 	conversationRpcClient  *rpcclient.ConversationRpcClient
 	groupRpcClient         *rpcclient.GroupRpcClient
+	userRpcClient          *rpcclient.UserRpcClient
 }
 
 var errNoOfflinePusher = errors.New("no offlinePusher is configured")
 
-func NewPusher(discov discoveryregistry.SvcDiscoveryRegistry, offlinePusher offlinepush.OfflinePusher, database controller.PushDatabase,
+func NewPusher(discov discoveryregistry.SvcDiscoveryRegistry, offlinePusher, callPusher offlinepush.OfflinePusher, database controller.PushDatabase,
 	groupLocalCache *localcache.GroupLocalCache, conversationLocalCache *localcache.ConversationLocalCache,
 	conversationRpcClient *rpcclient.ConversationRpcClient, groupRpcClient *rpcclient.GroupRpcClient, msgRpcClient *rpcclient.MessageRpcClient,
+	userRpcClient *rpcclient.UserRpcClient,
 ) *Pusher {
 	return &Pusher{
 		discov:                 discov,
 		database:               database,
 		offlinePusher:          offlinePusher,
+		callPusher:             callPusher,
 		groupLocalCache:        groupLocalCache,
 		conversationLocalCache: conversationLocalCache,
 		msgRpcClient:           msgRpcClient,
 		conversationRpcClient:  conversationRpcClient,
 		groupRpcClient:         groupRpcClient,
+		userRpcClient:          userRpcClient,
 	}
 }
 
@@ -111,6 +117,8 @@ func (p *Pusher) Push2User(ctx context.Context, userIDs []string, msg *sdkws.Msg
 		return err
 	}
 
+	p.incrBadgeUnreadCount(ctx, msg, userIDs)
+
 	isOfflinePush := utils.GetSwitchFromOptions(msg.Options, constant.IsOfflinePush)
 	log.ZDebug(ctx, "push_result", "ws push result", wsResults, "sendData", msg, "isOfflinePush", isOfflinePush, "push_to_userID", userIDs)
 
@@ -204,6 +212,7 @@ func (p *Pusher) Push2SuperGroup(ctx context.Context, groupID string, msg *sdkws
 	}
 
 	log.ZDebug(ctx, "get conn and online push success", "result", wsResults, "msg", msg)
+	p.incrBadgeUnreadCount(ctx, msg, pushToUserIDs)
 	isOfflinePush := utils.GetSwitchFromOptions(msg.Options, constant.IsOfflinePush)
 	if isOfflinePush {
 		var (
@@ -333,26 +342,45 @@ func (p *Pusher) offlinePushMsg(ctx context.Context, conversationID string, msg
 	if err != nil {
 		return err
 	}
-	err = p.offlinePusher.Push(ctx, offlinePushUserIDs, title, content, opts)
-	if err != nil {
+	if p.callPusher != nil && opts.Signal.ClientMsgID != "" {
+		// call invites must ring, so they skip do-not-disturb and coalescing
+		if err := p.callPusher.Push(ctx, offlinePushUserIDs, title, content, opts); err != nil {
+			prommetrics.MsgOfflinePushFailedCounter.Inc()
+			return err
+		}
+		return nil
+	}
+	offlinePushUserIDs, silentUserIDs := p.filterDoNotDisturb(ctx, msg, offlinePushUserIDs)
+	if p.coalesceEnabled(msg) {
+		opts.CollapseKey = msgprocessor.GetConversationIDByMsg(msg)
+		offlinePushUserIDs = p.coalesceOfflinePush(ctx, msg, offlinePushUserIDs)
+		silentUserIDs = p.coalesceOfflinePush(ctx, msg, silentUserIDs)
+	}
+	return p.pushOffline(ctx, offlinePushUserIDs, silentUserIDs, title, content, opts)
+}
+
+// pushOffline pushes to offlinePushUserIDs with opts and to silentUserIDs with the same opts but no sound.
+func (p *Pusher) pushOffline(ctx context.Context, offlinePushUserIDs, silentUserIDs []string, title, content string, opts *offlinepush.Opts) error {
+	if err := p.pushOfflineWithBadge(ctx, offlinePushUserIDs, title, content, opts); err != nil {
 		prommetrics.MsgOfflinePushFailedCounter.Inc()
 		return err
 	}
+	if len(silentUserIDs) > 0 {
+		silentOpts := *opts
+		silentOpts.Silent = true
+		if err := p.pushOfflineWithBadge(ctx, silentUserIDs, title, content, &silentOpts); err != nil {
+			prommetrics.MsgOfflinePushFailedCounter.Inc()
+			return err
+		}
+	}
 	return nil
 }
 
 func (p *Pusher) GetOfflinePushOpts(msg *sdkws.MsgData) (opts *offlinepush.Opts, err error) {
-	opts = &offlinepush.Opts{Signal: &offlinepush.Signal{}}
-	// if msg.ContentType > constant.SignalingNotificationBegin && msg.ContentType < constant.SignalingNotificationEnd {
-	// 	req := &sdkws.SignalReq{}
-	// 	if err := proto.Unmarshal(msg.Content, req); err != nil {
-	// 		return nil, utils.Wrap(err, "")
-	// 	}
-	// 	switch req.Payload.(type) {
-	// 	case *sdkws.SignalReq_Invite, *sdkws.SignalReq_InviteInGroup:
-	// 		opts.Signal = &offlinepush.Signal{ClientMsgID: msg.ClientMsgID}
-	// 	}
-	// }
+	opts = &offlinepush.Opts{Signal: &offlinepush.Signal{}, ClientMsgID: msg.ClientMsgID}
+	if msg.ContentType == constant.SignalingNotification && isSignalInvite(msg.Content) {
+		opts.Signal = &offlinepush.Signal{ClientMsgID: msg.ClientMsgID}
+	}
 	if msg.OfflinePushInfo != nil {
 		opts.IOSBadgeCount = msg.OfflinePushInfo.IOSBadgeCount
 		opts.IOSPushSound = msg.OfflinePushInfo.IOSPushSound
@@ -361,6 +389,33 @@ func (p *Pusher) GetOfflinePushOpts(msg *sdkws.MsgData) (opts *offlinepush.Opts,
 	return opts, nil
 }
 
+// The oneof fields of sdkws.SignalReq which start a call.
+const (
+	signalReqInvite        protowire.Number = 1
+	signalReqInviteInGroup protowire.Number = 2
+)
+
+// isSignalInvite reports whether the content of a signaling message is an invite, the other signals
+// (cancel, accept, reject, hang up...) don't ring. The protocol in use doesn't ship sdkws.SignalReq,
+// so the oneof field set is read off the wire.
+func isSignalInvite(content []byte) bool {
+	for len(content) > 0 {
+		num, typ, n := protowire.ConsumeTag(content)
+		if n < 0 {
+			return false
+		}
+		if typ == protowire.BytesType && (num == signalReqInvite || num == signalReqInviteInGroup) {
+			return true
+		}
+		content = content[n:]
+		if n = protowire.ConsumeFieldValue(num, typ, content); n < 0 {
+			return false
+		}
+		content = content[n:]
+	}
+	return false
+}
+
 func (p *Pusher) getOfflinePushInfos(conversationID string, msg *sdkws.MsgData) (title, content string, opts *offlinepush.Opts, err error) {
 	if p.offlinePusher == nil {
 		err = errNoOfflinePusher
@@ -0,0 +1,98 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package push
+
+import (
+	"context"
+	"testing"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"google.golang.org/protobuf/encoding/protowire"
+	"gopkg.in/yaml.v3"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+)
+
+// signalReq encodes a sdkws.SignalReq with the oneof field num set to an empty message.
+func signalReq(num protowire.Number) []byte {
+	b := protowire.AppendTag(nil, num, protowire.BytesType)
+	inner := protowire.AppendTag(nil, 1, protowire.BytesType)
+	inner = protowire.AppendString(inner, "room")
+	return protowire.AppendBytes(b, inner)
+}
+
+func TestIsSignalInvite(t *testing.T) {
+	tests := []struct {
+		name    string
+		content []byte
+		want    bool
+	}{
+		{name: "invite", content: signalReq(1), want: true},
+		{name: "invite in group", content: signalReq(2), want: true},
+		{name: "cancel", content: signalReq(3), want: false},
+		{name: "accept", content: signalReq(4), want: false},
+		{name: "hung up", content: signalReq(5), want: false},
+		{name: "reject", content: signalReq(6), want: false},
+		{name: "empty", content: nil, want: false},
+		{name: "not protobuf", content: []byte(`{"invite":{}}`), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isSignalInvite(tt.content); got != tt.want {
+				t.Errorf("isSignalInvite() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOfflinePushMsgSignaling(t *testing.T) {
+	if err := yaml.Unmarshal([]byte("push:\n  doNotDisturb:\n    enable: false\n"), &config.Config); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		content  []byte
+		wantCall bool
+	}{
+		{name: "invite rings", content: signalReq(1), wantCall: true},
+		{name: "invite in group rings", content: signalReq(2), wantCall: true},
+		{name: "hung up does not ring", content: signalReq(5), wantCall: false},
+		{name: "cancel does not ring", content: signalReq(3), wantCall: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			offlinePusher, callPusher := &failingPusher{}, &failingPusher{}
+			p := &Pusher{offlinePusher: offlinePusher, callPusher: callPusher}
+			msg := &sdkws.MsgData{
+				SendID:      "u1",
+				RecvID:      "u2",
+				ClientMsgID: "m1",
+				SessionType: constant.SingleChatType,
+				ContentType: constant.SignalingNotification,
+				Content:     tt.content,
+			}
+			if err := p.offlinePushMsg(context.Background(), "si_u1_u2", msg, []string{"u2"}); err != nil {
+				t.Fatalf("offlinePushMsg() error = %v", err)
+			}
+			if got := len(callPusher.pushed) > 0; got != tt.wantCall {
+				t.Errorf("call pushed = %v, want %v", got, tt.wantCall)
+			}
+			if got := len(offlinePusher.pushed) > 0; got == tt.wantCall {
+				t.Errorf("offline pushed = %v, want %v", got, !tt.wantCall)
+			}
+		})
+	}
+}
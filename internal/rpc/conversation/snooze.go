@@ -0,0 +1,56 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversation
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcext"
+)
+
+func (c *conversationServer) SetConversationSnooze(ctx context.Context, req *rpcext.SetConversationSnoozeReq) (*rpcext.SetConversationSnoozeResp, error) {
+	if err := authverify.CheckAccessV3(ctx, req.OwnerUserID); err != nil {
+		return nil, err
+	}
+	conversations, err := c.conversationDatabase.FindConversations(ctx, req.OwnerUserID, []string{req.ConversationID})
+	if err != nil {
+		return nil, err
+	}
+	if len(conversations) == 0 {
+		return nil, errs.ErrRecordNotFound.Wrap("conversation not found")
+	}
+	if conversations[0].SnoozeUntil == req.SnoozeUntil {
+		return &rpcext.SetConversationSnoozeResp{}, nil
+	}
+	err = c.conversationDatabase.UpdateUsersConversationFiled(ctx, []string{req.OwnerUserID}, req.ConversationID, map[string]interface{}{"snooze_until": req.SnoozeUntil})
+	if err != nil {
+		return nil, err
+	}
+	_ = c.conversationNotificationSender.ConversationChangeNotification(ctx, req.OwnerUserID, []string{req.ConversationID})
+	return &rpcext.SetConversationSnoozeResp{}, nil
+}
+
+// GetSnoozedUserIDs returns the users among req.UserIDs whose snooze of the conversation has not expired yet.
+func (c *conversationServer) GetSnoozedUserIDs(ctx context.Context, req *rpcext.GetSnoozedUserIDsReq) (*rpcext.GetSnoozedUserIDsResp, error) {
+	userIDs, err := c.conversationDatabase.FindSnoozedUserIDs(ctx, req.ConversationID, req.UserIDs, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return &rpcext.GetSnoozedUserIDsResp{UserIDs: userIDs}, nil
+}
@@ -0,0 +1,316 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package group
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	pbconversation "github.com/OpenIMSDK/protocol/conversation"
+	pbgroup "github.com/OpenIMSDK/protocol/group"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/protocol/wrapperspb"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/OpenIMSDK/tools/utils"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcext"
+)
+
+// groupNotificationMaxLen the size of the notification column of the groups.
+const groupNotificationMaxLen = 255
+
+func (s *groupServer) announcementDB2Ext(announcement *relationtb.GroupAnnouncementModel, acknowledged bool) *rpcext.GroupAnnouncement {
+	return &rpcext.GroupAnnouncement{
+		AnnouncementID: announcement.AnnouncementID,
+		GroupID:        announcement.GroupID,
+		Content:        announcement.Content,
+		CreatorUserID:  announcement.CreatorUserID,
+		UpdaterUserID:  announcement.UpdaterUserID,
+		Pinned:         announcement.Pinned,
+		Acknowledged:   acknowledged,
+		CreateTime:     announcement.CreateTime.UnixMilli(),
+		UpdateTime:     announcement.UpdateTime.UnixMilli(),
+	}
+}
+
+func (s *groupServer) createGroupAnnouncement(ctx context.Context, groupID string, content string, pinned bool) (*relationtb.GroupAnnouncementModel, error) {
+	now := time.Now()
+	announcement := &relationtb.GroupAnnouncementModel{
+		GroupID:       groupID,
+		Content:       content,
+		CreatorUserID: mcontext.GetOpUserID(ctx),
+		UpdaterUserID: mcontext.GetOpUserID(ctx),
+		Pinned:        pinned,
+		CreateTime:    now,
+		UpdateTime:    now,
+	}
+	if err := s.announcementDatabase.CreateGroupAnnouncement(ctx, announcement); err != nil {
+		return nil, err
+	}
+	return announcement, nil
+}
+
+// publishGroupAnnouncement marks the group conversation of the members and notifies them.
+func (s *groupServer) publishGroupAnnouncement(ctx context.Context, tips *sdkws.GroupInfoSetAnnouncementTips, announcementID string) {
+	groupID := tips.Group.GroupID
+	go func() {
+		nctx := mcontext.NewCtx("@@@" + mcontext.GetOperationID(ctx))
+		conversation := &pbconversation.ConversationReq{
+			ConversationID:   msgprocessor.GetConversationIDBySessionType(constant.SuperGroupChatType, groupID),
+			ConversationType: constant.SuperGroupChatType,
+			GroupID:          groupID,
+		}
+		resp, err := s.GetGroupMemberUserIDs(nctx, &pbgroup.GetGroupMemberUserIDsReq{GroupID: groupID})
+		if err != nil {
+			log.ZWarn(ctx, "GetGroupMemberIDs", err)
+			return
+		}
+		conversation.GroupAtType = &wrapperspb.Int32Value{Value: constant.GroupNotification}
+		if err := s.conversationRpcClient.SetConversations(nctx, resp.UserIDs, conversation); err != nil {
+			log.ZWarn(ctx, "SetConversations", err, resp.UserIDs, conversation)
+		}
+	}()
+	s.Notification.GroupInfoSetAnnouncementNotification(ctx, tips, announcementID)
+}
+
+// setGroupNotification keeps the content in the group notification for the clients which don't know the announcements,
+// and publishes the announcement.
+func (s *groupServer) setGroupNotification(ctx context.Context, group *relationtb.GroupModel, announcement *relationtb.GroupAnnouncementModel) error {
+	content := []rune(announcement.Content)
+	if len(content) > groupNotificationMaxLen {
+		content = content[:groupNotificationMaxLen]
+	}
+	data := map[string]any{
+		"notification":             string(content),
+		"notification_update_time": time.Now(),
+		"notification_user_id":     mcontext.GetOpUserID(ctx),
+	}
+	if err := s.GroupDatabase.UpdateGroup(ctx, group.GroupID, data); err != nil {
+		return err
+	}
+	group, err := s.GroupDatabase.TakeGroup(ctx, group.GroupID)
+	if err != nil {
+		return err
+	}
+	owner, err := s.TakeGroupOwner(ctx, group.GroupID)
+	if err != nil {
+		return err
+	}
+	count, err := s.GroupDatabase.FindGroupMemberNum(ctx, group.GroupID)
+	if err != nil {
+		return err
+	}
+	tips := &sdkws.GroupInfoSetAnnouncementTips{Group: s.groupDB2PB(group, owner.UserID, count)}
+	if !authverify.IsAppManagerUid(ctx) {
+		opMember, err := s.TakeGroupMember(ctx, group.GroupID, mcontext.GetOpUserID(ctx))
+		if err != nil {
+			return err
+		}
+		tips.OpUser = s.groupMemberDB2PB(opMember, 0)
+	}
+	s.publishGroupAnnouncement(ctx, tips, announcement.AnnouncementID)
+	return nil
+}
+
+func (s *groupServer) CreateGroupAnnouncement(ctx context.Context, req *rpcext.CreateGroupAnnouncementReq) (*rpcext.CreateGroupAnnouncementResp, error) {
+	if err := req.Check(); err != nil {
+		return nil, errs.ErrArgs.Wrap(err.Error())
+	}
+	if err := s.checkGroupPermission(ctx, req.GroupID, authverify.GroupPermissionEditInfo); err != nil {
+		return nil, err
+	}
+	if req.Pinned {
+		if err := s.checkGroupPermission(ctx, req.GroupID, authverify.GroupPermissionPin); err != nil {
+			return nil, err
+		}
+	}
+	group, err := s.GroupDatabase.TakeGroup(ctx, req.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	if group.Status == constant.GroupStatusDismissed {
+		return nil, errs.ErrDismissedAlready.Wrap()
+	}
+	announcement, err := s.createGroupAnnouncement(ctx, req.GroupID, req.Content, req.Pinned)
+	if err != nil {
+		return nil, err
+	}
+	s.writeAuditLogs(ctx, s.newAuditLog(ctx, req.GroupID, relationtb.GroupAuditActionSetAnnouncement, "", nil, announcement))
+	if err := s.setGroupNotification(ctx, group, announcement); err != nil {
+		return nil, err
+	}
+	return &rpcext.CreateGroupAnnouncementResp{Announcement: s.announcementDB2Ext(announcement, false)}, nil
+}
+
+func (s *groupServer) UpdateGroupAnnouncement(ctx context.Context, req *rpcext.UpdateGroupAnnouncementReq) (*rpcext.UpdateGroupAnnouncementResp, error) {
+	if err := req.Check(); err != nil {
+		return nil, errs.ErrArgs.Wrap(err.Error())
+	}
+	announcement, err := s.announcementDatabase.TakeGroupAnnouncement(ctx, req.AnnouncementID)
+	if err != nil {
+		return nil, err
+	}
+	before := *announcement
+	data := map[string]any{
+		"updater_user_id": mcontext.GetOpUserID(ctx),
+		"update_time":     time.Now(),
+	}
+	contentChanged := req.Content != nil && *req.Content != announcement.Content
+	if contentChanged {
+		if err := s.checkGroupPermission(ctx, announcement.GroupID, authverify.GroupPermissionEditInfo); err != nil {
+			return nil, err
+		}
+		data["content"] = *req.Content
+		announcement.Content = *req.Content
+	}
+	if req.Pinned != nil && *req.Pinned != announcement.Pinned {
+		if err := s.checkGroupPermission(ctx, announcement.GroupID, authverify.GroupPermissionPin); err != nil {
+			return nil, err
+		}
+		data["pinned"] = *req.Pinned
+		announcement.Pinned = *req.Pinned
+	}
+	if len(data) == 2 {
+		return &rpcext.UpdateGroupAnnouncementResp{Announcement: s.announcementDB2Ext(announcement, false)}, nil
+	}
+	group, err := s.GroupDatabase.TakeGroup(ctx, announcement.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	if group.Status == constant.GroupStatusDismissed {
+		return nil, errs.ErrDismissedAlready.Wrap()
+	}
+	if err := s.announcementDatabase.UpdateGroupAnnouncement(ctx, announcement.AnnouncementID, data, contentChanged); err != nil {
+		return nil, err
+	}
+	announcement.UpdaterUserID = data["updater_user_id"].(string)
+	announcement.UpdateTime = data["update_time"].(time.Time)
+	s.writeAuditLogs(ctx, s.newAuditLog(ctx, announcement.GroupID, relationtb.GroupAuditActionSetAnnouncement, "", &before, announcement))
+	if contentChanged {
+		if err := s.setGroupNotification(ctx, group, announcement); err != nil {
+			return nil, err
+		}
+	}
+	return &rpcext.UpdateGroupAnnouncementResp{Announcement: s.announcementDB2Ext(announcement, false)}, nil
+}
+
+func (s *groupServer) DeleteGroupAnnouncement(ctx context.Context, req *rpcext.DeleteGroupAnnouncementReq) (*rpcext.DeleteGroupAnnouncementResp, error) {
+	if err := req.Check(); err != nil {
+		return nil, errs.ErrArgs.Wrap(err.Error())
+	}
+	announcement, err := s.announcementDatabase.TakeGroupAnnouncement(ctx, req.AnnouncementID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkGroupPermission(ctx, announcement.GroupID, authverify.GroupPermissionEditInfo); err != nil {
+		return nil, err
+	}
+	if err := s.announcementDatabase.DeleteGroupAnnouncement(ctx, req.AnnouncementID); err != nil {
+		return nil, err
+	}
+	s.writeAuditLogs(ctx, s.newAuditLog(ctx, announcement.GroupID, relationtb.GroupAuditActionSetAnnouncement, "", announcement, nil))
+	return &rpcext.DeleteGroupAnnouncementResp{}, nil
+}
+
+func (s *groupServer) GetGroupAnnouncements(ctx context.Context, req *rpcext.GetGroupAnnouncementsReq) (*rpcext.GetGroupAnnouncementsResp, error) {
+	if err := req.Check(); err != nil {
+		return nil, errs.ErrArgs.Wrap(err.Error())
+	}
+	opUserID := mcontext.GetOpUserID(ctx)
+	if !authverify.IsAppManagerUid(ctx) {
+		if _, err := s.GroupDatabase.TakeGroupMember(ctx, req.GroupID, opUserID); err != nil {
+			return nil, err
+		}
+	}
+	total, announcements, err := s.announcementDatabase.PageGroupAnnouncements(ctx, req.GroupID, req.Pagination.PageNumber, req.Pagination.ShowNumber)
+	if err != nil {
+		return nil, err
+	}
+	ackedIDs, err := s.announcementDatabase.FindAckedGroupAnnouncementIDs(ctx, opUserID,
+		utils.Slice(announcements, func(e *relationtb.GroupAnnouncementModel) string { return e.AnnouncementID }))
+	if err != nil {
+		return nil, err
+	}
+	ackedMap := utils.SliceSet(ackedIDs)
+	return &rpcext.GetGroupAnnouncementsResp{
+		Total: int64(total),
+		Announcements: utils.Slice(announcements, func(e *relationtb.GroupAnnouncementModel) *rpcext.GroupAnnouncement {
+			_, acked := ackedMap[e.AnnouncementID]
+			return s.announcementDB2Ext(e, acked)
+		}),
+	}, nil
+}
+
+func (s *groupServer) AckGroupAnnouncement(ctx context.Context, req *rpcext.AckGroupAnnouncementReq) (*rpcext.AckGroupAnnouncementResp, error) {
+	if err := req.Check(); err != nil {
+		return nil, errs.ErrArgs.Wrap(err.Error())
+	}
+	announcement, err := s.announcementDatabase.TakeGroupAnnouncement(ctx, req.AnnouncementID)
+	if err != nil {
+		return nil, err
+	}
+	opUserID := mcontext.GetOpUserID(ctx)
+	if _, err := s.GroupDatabase.TakeGroupMember(ctx, announcement.GroupID, opUserID); err != nil {
+		return nil, err
+	}
+	ack := &relationtb.GroupAnnouncementAckModel{
+		AnnouncementID: announcement.AnnouncementID,
+		UserID:         opUserID,
+		GroupID:        announcement.GroupID,
+		AckTime:        time.Now(),
+	}
+	if err := s.announcementDatabase.AckGroupAnnouncement(ctx, ack); err != nil {
+		return nil, err
+	}
+	return &rpcext.AckGroupAnnouncementResp{}, nil
+}
+
+func (s *groupServer) GetGroupAnnouncementUnreadMembers(ctx context.Context, req *rpcext.GetGroupAnnouncementUnreadMembersReq) (*rpcext.GetGroupAnnouncementUnreadMembersResp, error) {
+	if err := req.Check(); err != nil {
+		return nil, errs.ErrArgs.Wrap(err.Error())
+	}
+	announcement, err := s.announcementDatabase.TakeGroupAnnouncement(ctx, req.AnnouncementID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkGroupPermission(ctx, announcement.GroupID, authverify.GroupPermissionEditInfo); err != nil {
+		return nil, err
+	}
+	memberUserIDs, err := s.GroupDatabase.FindGroupMemberUserID(ctx, announcement.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	ackUserIDs, err := s.announcementDatabase.FindGroupAnnouncementAckUserIDs(ctx, req.AnnouncementID)
+	if err != nil {
+		return nil, err
+	}
+	ackMap := utils.SliceSet(ackUserIDs)
+	unread := make([]string, 0, len(memberUserIDs))
+	for _, userID := range memberUserIDs {
+		if _, ok := ackMap[userID]; !ok {
+			unread = append(unread, userID)
+		}
+	}
+	return &rpcext.GetGroupAnnouncementUnreadMembersResp{
+		Total:   int64(len(unread)),
+		UserIDs: utils.Paginate(unread, int(req.Pagination.PageNumber), int(req.Pagination.ShowNumber)),
+	}, nil
+}
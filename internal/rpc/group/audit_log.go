@@ -0,0 +1,123 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package group
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/OpenIMSDK/tools/utils"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcext"
+)
+
+// groupAuditValues the group columns which can be changed by SetGroupInfo, keyed like UpdateGroupInfoMap.
+func groupAuditValues(group *relationtb.GroupModel) map[string]any {
+	return map[string]any{
+		"name":                     group.GroupName,
+		"notification":             group.Notification,
+		"notification_update_time": group.NotificationUpdateTime,
+		"notification_user_id":     group.NotificationUserID,
+		"introduction":             group.Introduction,
+		"face_url":                 group.FaceURL,
+		"need_verification":        group.NeedVerification,
+		"look_member_info":         group.LookMemberInfo,
+		"apply_member_friend":      group.ApplyMemberFriend,
+		"ex":                       group.Ex,
+		"status":                   group.Status,
+	}
+}
+
+// groupMemberAuditValues keyed like UpdateGroupMemberMap.
+func groupMemberAuditValues(member *relationtb.GroupMemberModel) map[string]any {
+	return map[string]any{
+		"nickname":            member.Nickname,
+		"user_group_face_url": member.FaceURL,
+		"role_level":          member.RoleLevel,
+		"ex":                  member.Ex,
+		"mute_end_time":       member.MuteEndTime,
+	}
+}
+
+// pickAuditValues the values of the keys which are changed.
+func pickAuditValues(values map[string]any, changed map[string]any) map[string]any {
+	m := make(map[string]any, len(changed))
+	for k := range changed {
+		if v, ok := values[k]; ok {
+			m[k] = v
+		}
+	}
+	return m
+}
+
+func (s *groupServer) newAuditLog(ctx context.Context, groupID string, action string, targetUserID string, before, after any) *relationtb.GroupAuditLogModel {
+	auditLog := &relationtb.GroupAuditLogModel{
+		GroupID:        groupID,
+		Action:         action,
+		OperatorUserID: mcontext.GetOpUserID(ctx),
+		TargetUserID:   targetUserID,
+		CreateTime:     time.Now(),
+	}
+	if before != nil {
+		data, _ := json.Marshal(before)
+		auditLog.Before = string(data)
+	}
+	if after != nil {
+		data, _ := json.Marshal(after)
+		auditLog.After = string(data)
+	}
+	return auditLog
+}
+
+// writeAuditLogs the change is already done, a failed write is only logged.
+func (s *groupServer) writeAuditLogs(ctx context.Context, logs ...*relationtb.GroupAuditLogModel) {
+	if err := s.auditLogDatabase.CreateGroupAuditLogs(ctx, logs); err != nil {
+		log.ZError(ctx, "CreateGroupAuditLogs failed", err, "logs", logs)
+	}
+}
+
+func (s *groupServer) GetGroupAuditLogs(ctx context.Context, req *rpcext.GetGroupAuditLogsReq) (*rpcext.GetGroupAuditLogsResp, error) {
+	if err := req.Check(); err != nil {
+		return nil, errs.ErrArgs.Wrap(err.Error())
+	}
+	if err := s.checkGroupPermission(ctx, req.GroupID, authverify.GroupPermissionViewAuditLog); err != nil {
+		return nil, err
+	}
+	total, logs, err := s.auditLogDatabase.PageGroupAuditLogs(ctx, req.GroupID, req.Action, req.TargetUserID, req.Pagination.PageNumber, req.Pagination.ShowNumber)
+	if err != nil {
+		return nil, err
+	}
+	return &rpcext.GetGroupAuditLogsResp{
+		Total: int64(total),
+		Logs: utils.Slice(logs, func(auditLog *relationtb.GroupAuditLogModel) *rpcext.GroupAuditLog {
+			return &rpcext.GroupAuditLog{
+				ID:             auditLog.ID,
+				GroupID:        auditLog.GroupID,
+				Action:         auditLog.Action,
+				OperatorUserID: auditLog.OperatorUserID,
+				TargetUserID:   auditLog.TargetUserID,
+				Before:         auditLog.Before,
+				After:          auditLog.After,
+				CreateTime:     auditLog.CreateTime.UnixMilli(),
+			}
+		}),
+	}, nil
+}
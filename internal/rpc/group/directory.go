@@ -0,0 +1,147 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package group
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	pbgroup "github.com/OpenIMSDK/protocol/group"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/OpenIMSDK/tools/utils"
+
+	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcext"
+)
+
+func (s *groupServer) SetGroupDirectory(ctx context.Context, req *rpcext.SetGroupDirectoryReq) (*rpcext.SetGroupDirectoryResp, error) {
+	if err := req.Check(); err != nil {
+		return nil, errs.ErrArgs.Wrap(err.Error())
+	}
+	if err := s.checkGroupOwner(ctx, req.GroupID); err != nil {
+		return nil, err
+	}
+	group, err := s.GroupDatabase.TakeGroup(ctx, req.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	if group.Status == constant.GroupStatusDismissed {
+		return nil, errs.ErrDismissedAlready.Wrap()
+	}
+	var before *relationtb.GroupDirectoryModel
+	if entry, err := s.directoryDatabase.TakeGroupDirectory(ctx, req.GroupID); err == nil {
+		before = entry
+	} else if !relationtb.IsNotFound(err) {
+		return nil, err
+	}
+	if !req.Discoverable {
+		if before == nil {
+			return &rpcext.SetGroupDirectoryResp{}, nil
+		}
+		if err := s.directoryDatabase.DeleteGroupDirectory(ctx, req.GroupID); err != nil {
+			return nil, err
+		}
+		s.writeAuditLogs(ctx, s.newAuditLog(ctx, req.GroupID, relationtb.GroupAuditActionSetDirectory, "", before, nil))
+		return &rpcext.SetGroupDirectoryResp{}, nil
+	}
+	entry := &relationtb.GroupDirectoryModel{
+		GroupID:     req.GroupID,
+		Category:    req.Category,
+		Description: req.Description,
+		CreateTime:  time.Now(),
+	}
+	entry.SetTags(utils.Distinct(req.Tags))
+	if before != nil {
+		entry.ActiveTime = before.ActiveTime
+		entry.CreateTime = before.CreateTime
+	}
+	if err := s.directoryDatabase.SetGroupDirectory(ctx, entry); err != nil {
+		return nil, err
+	}
+	s.writeAuditLogs(ctx, s.newAuditLog(ctx, req.GroupID, relationtb.GroupAuditActionSetDirectory, "", before, entry))
+	return &rpcext.SetGroupDirectoryResp{}, nil
+}
+
+func (s *groupServer) SearchGroupDirectory(ctx context.Context, req *rpcext.SearchGroupDirectoryReq) (*rpcext.SearchGroupDirectoryResp, error) {
+	if err := req.Check(); err != nil {
+		return nil, errs.ErrArgs.Wrap(err.Error())
+	}
+	total, entries, err := s.directoryDatabase.SearchGroupDirectory(ctx, req.Keyword, req.Category, req.Tag, req.OrderBy, req.Pagination.PageNumber, req.Pagination.ShowNumber)
+	if err != nil {
+		return nil, err
+	}
+	resp := &rpcext.SearchGroupDirectoryResp{Total: int64(total)}
+	if len(entries) == 0 {
+		return resp, nil
+	}
+	groupIDs := utils.Slice(entries, func(e *relationtb.GroupDirectoryModel) string { return e.GroupID })
+	groups, err := s.GroupDatabase.FindGroup(ctx, groupIDs)
+	if err != nil {
+		return nil, err
+	}
+	groupMap := utils.SliceToMap(groups, func(e *relationtb.GroupModel) string { return e.GroupID })
+	memberNumMap, err := s.GroupDatabase.MapGroupMemberNum(ctx, groupIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		group, ok := groupMap[entry.GroupID]
+		if !ok {
+			continue
+		}
+		resp.Groups = append(resp.Groups, &rpcext.GroupDirectoryEntry{
+			GroupID:          group.GroupID,
+			GroupName:        group.GroupName,
+			FaceURL:          group.FaceURL,
+			Introduction:     group.Introduction,
+			Category:         entry.Category,
+			Tags:             entry.GetTags(),
+			Description:      entry.Description,
+			MemberCount:      memberNumMap[group.GroupID],
+			NeedVerification: group.NeedVerification,
+			ActiveTime:       entry.ActiveTime.UnixMilli(),
+		})
+	}
+	return resp, nil
+}
+
+// JoinDirectoryGroup joins through the usual JoinGroup flow, so NeedVerification and the join rules of the group decide whether an approval is needed.
+func (s *groupServer) JoinDirectoryGroup(ctx context.Context, req *rpcext.JoinDirectoryGroupReq) (*rpcext.JoinDirectoryGroupResp, error) {
+	if err := req.Check(); err != nil {
+		return nil, errs.ErrArgs.Wrap(err.Error())
+	}
+	if _, err := s.directoryDatabase.TakeGroupDirectory(ctx, req.GroupID); err != nil {
+		if relationtb.IsNotFound(err) {
+			return nil, errs.ErrGroupIDNotFound.Wrap("group is not in the directory")
+		}
+		return nil, err
+	}
+	group, err := s.GroupDatabase.TakeGroup(ctx, req.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	pending, err := s.joinGroup(ctx, &pbgroup.JoinGroupReq{
+		GroupID:       req.GroupID,
+		ReqMessage:    req.ReqMessage,
+		JoinSource:    constant.JoinBySearch,
+		InviterUserID: mcontext.GetOpUserID(ctx),
+	}, group, group.NeedVerification == constant.Directly, "")
+	if err != nil {
+		return nil, err
+	}
+	return &rpcext.JoinDirectoryGroupResp{Pending: pending}, nil
+}
@@ -29,9 +29,6 @@ import (
 	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
 	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
 
-	pbconversation "github.com/OpenIMSDK/protocol/conversation"
-	"github.com/OpenIMSDK/protocol/wrapperspb"
-
 	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient/notification"
 
 	"github.com/OpenIMSDK/tools/mw/specialerror"
@@ -55,6 +52,7 @@ import (
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/relation"
 	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/unrelation"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcext"
 )
 
 func Start(client discoveryregistry.SvcDiscoveryRegistry, server *grpc.Server) error {
@@ -89,7 +87,47 @@ func Start(client discoveryregistry.SvcDiscoveryRegistry, server *grpc.Server) e
 	})
 	gs.conversationRpcClient = conversationRpcClient
 	gs.msgRpcClient = msgRpcClient
+	gs.friendRpcClient = rpcclient.NewFriendRpcClient(client)
+	gs.inviteLinkDatabase, err = controller.InitGroupInviteLinkDatabase(db)
+	if err != nil {
+		return err
+	}
+	gs.roleDatabase, err = controller.InitGroupRoleDatabase(db, rdb)
+	if err != nil {
+		return err
+	}
+	gs.permission = authverify.NewGroupPermissionChecker(gs.roleDatabase.GetGroupRolePermissions)
+	gs.auditLogDatabase, err = controller.InitGroupAuditLogDatabase(db)
+	if err != nil {
+		return err
+	}
+	gs.directoryDatabase, err = controller.InitGroupDirectoryDatabase(db)
+	if err != nil {
+		return err
+	}
+	gs.announcementDatabase, err = controller.InitGroupAnnouncementDatabase(db)
+	if err != nil {
+		return err
+	}
+	gs.memberTagDatabase, err = controller.InitGroupMemberTagDatabase(db)
+	if err != nil {
+		return err
+	}
+	gs.muteScheduleDatabase, err = controller.InitGroupMuteScheduleDatabase(db, rdb)
+	if err != nil {
+		return err
+	}
+	gs.joinRuleDatabase, err = controller.InitGroupJoinRuleDatabase(db)
+	if err != nil {
+		return err
+	}
+	o, err := controller.NewObjectStorage(rdb)
+	if err != nil {
+		return err
+	}
+	gs.s3Database = controller.NewS3Database(rdb, o, relation.NewObjectInfo(db))
 	pbgroup.RegisterGroupServer(server, &gs)
+	rpcext.RegisterGroupExtServer(server, &gs)
 	//pbgroup.RegisterGroupServer(server, &groupServer{
 	//	GroupDatabase: database,
 	//	User:          userRpcClient,
@@ -112,6 +150,17 @@ type groupServer struct {
 	Notification          *notification.GroupNotificationSender
 	conversationRpcClient rpcclient.ConversationRpcClient
 	msgRpcClient          rpcclient.MessageRpcClient
+	inviteLinkDatabase    controller.GroupInviteLinkDatabase
+	roleDatabase          controller.GroupRoleDatabase
+	permission            *authverify.GroupPermissionChecker
+	auditLogDatabase      controller.GroupAuditLogDatabase
+	directoryDatabase     controller.GroupDirectoryDatabase
+	s3Database            controller.S3Database
+	announcementDatabase  controller.GroupAnnouncementDatabase
+	memberTagDatabase     controller.GroupMemberTagDatabase
+	muteScheduleDatabase  controller.GroupMuteScheduleDatabase
+	joinRuleDatabase      controller.GroupJoinRuleDatabase
+	friendRpcClient       rpcclient.FriendRpcClient
 }
 
 func (s *groupServer) NotificationUserInfoUpdate(ctx context.Context, req *pbgroup.NotificationUserInfoUpdateReq) (*pbgroup.NotificationUserInfoUpdateResp, error) {
@@ -387,7 +436,7 @@ func (s *groupServer) InviteUserToGroup(ctx context.Context, req *pbgroup.Invite
 	}
 	if group.NeedVerification == constant.AllNeedVerification {
 		if !authverify.IsAppManagerUid(ctx) {
-			if !(groupMember.RoleLevel == constant.GroupOwner || groupMember.RoleLevel == constant.GroupAdmin) {
+			if s.permission.Check(ctx, req.GroupID, groupMember.RoleLevel, authverify.GroupPermissionApproveJoin) != nil {
 				var requests []*relationtb.GroupRequestModel
 				for _, userID := range req.InvitedUserIDs {
 					requests = append(requests, &relationtb.GroupRequestModel{
@@ -521,6 +570,9 @@ func (s *groupServer) KickGroupMember(ctx context.Context, req *pbgroup.KickGrou
 		if err := s.GroupDatabase.DeleteSuperGroupMember(ctx, req.GroupID, req.KickedUserIDs); err != nil {
 			return nil, err
 		}
+		s.writeAuditLogs(ctx, utils.Slice(req.KickedUserIDs, func(userID string) *relationtb.GroupAuditLogModel {
+			return s.newAuditLog(ctx, req.GroupID, relationtb.GroupAuditActionKickMember, userID, nil, nil)
+		})...)
 		go func() {
 			for _, userID := range req.KickedUserIDs {
 				s.Notification.SuperGroupNotification(ctx, userID, userID)
@@ -546,16 +598,8 @@ func (s *groupServer) KickGroupMember(ctx context.Context, req *pbgroup.KickGrou
 				if opMember == nil {
 					return nil, errs.ErrNoPermission.Wrap("opUserID no in group")
 				}
-				switch opMember.RoleLevel {
-				case constant.GroupOwner:
-				case constant.GroupAdmin:
-					if member.RoleLevel == constant.GroupOwner || member.RoleLevel == constant.GroupAdmin {
-						return nil, errs.ErrNoPermission.Wrap("group admins cannot remove the group owner and other admins")
-					}
-				case constant.GroupOrdinaryUsers:
-					return nil, errs.ErrNoPermission.Wrap("opUserID no permission")
-				default:
-					return nil, errs.ErrNoPermission.Wrap("opUserID roleLevel unknown")
+				if err := s.permission.CheckOn(ctx, req.GroupID, opMember.RoleLevel, member.RoleLevel, authverify.GroupPermissionKick); err != nil {
+					return nil, err
 				}
 			}
 		}
@@ -570,6 +614,9 @@ func (s *groupServer) KickGroupMember(ctx context.Context, req *pbgroup.KickGrou
 		if err := s.GroupDatabase.DeleteGroupMember(ctx, group.GroupID, req.KickedUserIDs); err != nil {
 			return nil, err
 		}
+		s.writeAuditLogs(ctx, utils.Slice(req.KickedUserIDs, func(userID string) *relationtb.GroupAuditLogModel {
+			return s.newAuditLog(ctx, req.GroupID, relationtb.GroupAuditActionKickMember, userID, groupMemberAuditValues(memberMap[userID]), nil)
+		})...)
 		tips := &sdkws.MemberKickedTips{
 			Group: &sdkws.GroupInfo{
 				GroupID:      group.GroupID,
@@ -734,14 +781,8 @@ func (s *groupServer) GroupApplicationResponse(ctx context.Context, req *pbgroup
 	if !utils.Contain(req.HandleResult, constant.GroupResponseAgree, constant.GroupResponseRefuse) {
 		return nil, errs.ErrArgs.Wrap("HandleResult unknown")
 	}
-	if !authverify.IsAppManagerUid(ctx) {
-		groupMember, err := s.GroupDatabase.TakeGroupMember(ctx, req.GroupID, mcontext.GetOpUserID(ctx))
-		if err != nil {
-			return nil, err
-		}
-		if !(groupMember.RoleLevel == constant.GroupOwner || groupMember.RoleLevel == constant.GroupAdmin) {
-			return nil, errs.ErrNoPermission.Wrap("no group owner or admin")
-		}
+	if err := s.checkGroupPermission(ctx, req.GroupID, authverify.GroupPermissionApproveJoin); err != nil {
+		return nil, err
 	}
 	group, err := s.GroupDatabase.TakeGroup(ctx, req.GroupID)
 	if err != nil {
@@ -786,6 +827,9 @@ func (s *groupServer) GroupApplicationResponse(ctx context.Context, req *pbgroup
 	if err := s.GroupDatabase.HandlerGroupRequest(ctx, req.GroupID, req.FromUserID, req.HandledMsg, req.HandleResult, member); err != nil {
 		return nil, err
 	}
+	s.writeAuditLogs(ctx, s.newAuditLog(ctx, req.GroupID, relationtb.GroupAuditActionApplicationResponse, req.FromUserID,
+		map[string]any{"handle_result": groupRequest.HandleResult},
+		map[string]any{"handle_result": req.HandleResult, "handled_msg": req.HandledMsg}))
 	switch req.HandleResult {
 	case constant.GroupResponseAgree:
 		if err := s.conversationRpcClient.GroupChatFirstCreateConversation(ctx, req.GroupID, []string{req.FromUserID}); err != nil {
@@ -805,28 +849,49 @@ func (s *groupServer) GroupApplicationResponse(ctx context.Context, req *pbgroup
 
 func (s *groupServer) JoinGroup(ctx context.Context, req *pbgroup.JoinGroupReq) (resp *pbgroup.JoinGroupResp, err error) {
 	defer log.ZInfo(ctx, "JoinGroup.Return")
-	user, err := s.User.GetUserInfo(ctx, req.InviterUserID)
+	group, err := s.GroupDatabase.TakeGroup(ctx, req.GroupID)
 	if err != nil {
 		return nil, err
 	}
-	group, err := s.GroupDatabase.TakeGroup(ctx, req.GroupID)
-	if err != nil {
+	log.ZInfo(ctx, "JoinGroup.groupInfo", "group", group, "eq", group.NeedVerification == constant.Directly)
+	if _, err := s.joinGroup(ctx, req, group, group.NeedVerification == constant.Directly, ""); err != nil {
 		return nil, err
 	}
+	return &pbgroup.JoinGroupResp{}, nil
+}
+
+// joinGroup adds req.InviterUserID to the group if directly or the request matches an auto-approval rule of the group,
+// otherwise it creates a group request for the admins and returns pending.
+// inviterUserID is who invited the applicant, empty if nobody did.
+func (s *groupServer) joinGroup(ctx context.Context, req *pbgroup.JoinGroupReq, group *relationtb.GroupModel, directly bool, inviterUserID string) (pending bool, err error) {
+	user, err := s.User.GetUserInfo(ctx, req.InviterUserID)
+	if err != nil {
+		return false, err
+	}
 	if group.Status == constant.GroupStatusDismissed {
-		return nil, errs.ErrDismissedAlready.Wrap()
+		return false, errs.ErrDismissedAlready.Wrap()
 	}
 	_, err = s.GroupDatabase.TakeGroupMember(ctx, req.GroupID, req.InviterUserID)
 	if err == nil {
-		return nil, errs.ErrArgs.Wrap("already in group")
+		return false, errs.ErrArgs.Wrap("already in group")
 	} else if !s.IsNotFound(err) && utils.Unwrap(err) != errs.ErrRecordNotFound {
-		return nil, err
+		return false, err
 	}
-	log.ZInfo(ctx, "JoinGroup.groupInfo", "group", group, "eq", group.NeedVerification == constant.Directly)
-	resp = &pbgroup.JoinGroupResp{}
-	if group.NeedVerification == constant.Directly {
+	if !directly && group.GroupType != constant.SuperGroup {
+		rule, err := s.matchGroupJoinRule(ctx, req, user, inviterUserID)
+		if err != nil {
+			return false, err
+		}
+		if rule != "" {
+			log.ZInfo(ctx, "join request approved by rule", "groupID", req.GroupID, "userID", req.InviterUserID, "rule", rule)
+			s.writeAuditLogs(ctx, s.newAuditLog(ctx, req.GroupID, relationtb.GroupAuditActionApplicationResponse, req.InviterUserID,
+				nil, map[string]any{"handle_result": constant.GroupResponseAgree, "rule": rule}))
+			directly = true
+		}
+	}
+	if directly {
 		if group.GroupType == constant.SuperGroup {
-			return nil, errs.ErrGroupTypeNotSupport.Wrap()
+			return false, errs.ErrGroupTypeNotSupport.Wrap()
 		}
 		groupMember := &relationtb.GroupMemberModel{
 			GroupID:        group.GroupID,
@@ -838,30 +903,31 @@ func (s *groupServer) JoinGroup(ctx context.Context, req *pbgroup.JoinGroupReq)
 			MuteEndTime:    time.UnixMilli(0),
 		}
 		if err := CallbackBeforeMemberJoinGroup(ctx, groupMember, group.Ex); err != nil {
-			return nil, err
+			return false, err
 		}
 		if err := s.GroupDatabase.CreateGroup(ctx, nil, []*relationtb.GroupMemberModel{groupMember}); err != nil {
-			return nil, err
+			return false, err
 		}
 		if err := s.conversationRpcClient.GroupChatFirstCreateConversation(ctx, req.GroupID, []string{req.InviterUserID}); err != nil {
-			return nil, err
+			return false, err
 		}
 		s.Notification.MemberEnterNotification(ctx, req.GroupID, req.InviterUserID)
-		return resp, nil
+		return false, nil
 	}
 	groupRequest := relationtb.GroupRequestModel{
-		UserID:      req.InviterUserID,
-		ReqMsg:      req.ReqMessage,
-		GroupID:     req.GroupID,
-		JoinSource:  req.JoinSource,
-		ReqTime:     time.Now(),
-		HandledTime: time.Unix(0, 0),
+		UserID:        req.InviterUserID,
+		ReqMsg:        req.ReqMessage,
+		GroupID:       req.GroupID,
+		JoinSource:    req.JoinSource,
+		InviterUserID: inviterUserID,
+		ReqTime:       time.Now(),
+		HandledTime:   time.Unix(0, 0),
 	}
 	if err := s.GroupDatabase.CreateGroupRequest(ctx, []*relationtb.GroupRequestModel{&groupRequest}); err != nil {
-		return nil, err
+		return false, err
 	}
 	s.Notification.JoinGroupApplicationNotification(ctx, req)
-	return resp, nil
+	return true, nil
 }
 
 func (s *groupServer) QuitGroup(ctx context.Context, req *pbgroup.QuitGroupReq) (*pbgroup.QuitGroupResp, error) {
@@ -887,8 +953,10 @@ func (s *groupServer) QuitGroup(ctx context.Context, req *pbgroup.QuitGroupReq)
 		if err != nil {
 			return nil, err
 		}
-		if info.RoleLevel == constant.GroupOwner {
-			return nil, errs.ErrNoPermission.Wrap("group owner can't quit")
+		if info.RoleLevel == constant.GroupOwner && group.Status != constant.GroupStatusDismissed {
+			if err := s.succeedGroupOwner(ctx, group, info); err != nil {
+				return nil, err
+			}
 		}
 		err = s.GroupDatabase.DeleteGroupMember(ctx, req.GroupID, []string{req.UserID})
 		if err != nil {
@@ -920,8 +988,8 @@ func (s *groupServer) SetGroupInfo(ctx context.Context, req *pbgroup.SetGroupInf
 		if err != nil {
 			return nil, err
 		}
-		if !(opMember.RoleLevel == constant.GroupOwner || opMember.RoleLevel == constant.GroupAdmin) {
-			return nil, errs.ErrNoPermission.Wrap("no group owner or admin")
+		if err := s.permission.Check(ctx, req.GroupInfoForSet.GroupID, opMember.RoleLevel, authverify.GroupPermissionEditInfo); err != nil {
+			return nil, err
 		}
 	}
 	group, err := s.GroupDatabase.TakeGroup(ctx, req.GroupInfoForSet.GroupID)
@@ -944,9 +1012,11 @@ func (s *groupServer) SetGroupInfo(ctx context.Context, req *pbgroup.SetGroupInf
 	if len(data) == 0 {
 		return resp, nil
 	}
+	before := pickAuditValues(groupAuditValues(group), data)
 	if err := s.GroupDatabase.UpdateGroup(ctx, group.GroupID, data); err != nil {
 		return nil, err
 	}
+	s.writeAuditLogs(ctx, s.newAuditLog(ctx, group.GroupID, relationtb.GroupAuditActionSetGroupInfo, "", before, data))
 	group, err = s.GroupDatabase.TakeGroup(ctx, req.GroupInfoForSet.GroupID)
 	if err != nil {
 		return nil, err
@@ -961,25 +1031,12 @@ func (s *groupServer) SetGroupInfo(ctx context.Context, req *pbgroup.SetGroupInf
 	}
 	var num int
 	if req.GroupInfoForSet.Notification != "" {
-		go func() {
-			nctx := mcontext.NewCtx("@@@" + mcontext.GetOperationID(ctx))
-			conversation := &pbconversation.ConversationReq{
-				ConversationID:   msgprocessor.GetConversationIDBySessionType(constant.SuperGroupChatType, req.GroupInfoForSet.GroupID),
-				ConversationType: constant.SuperGroupChatType,
-				GroupID:          req.GroupInfoForSet.GroupID,
-			}
-			resp, err := s.GetGroupMemberUserIDs(nctx, &pbgroup.GetGroupMemberUserIDsReq{GroupID: req.GroupInfoForSet.GroupID})
-			if err != nil {
-				log.ZWarn(ctx, "GetGroupMemberIDs", err)
-				return
-			}
-			conversation.GroupAtType = &wrapperspb.Int32Value{Value: constant.GroupNotification}
-			if err := s.conversationRpcClient.SetConversations(nctx, resp.UserIDs, conversation); err != nil {
-				log.ZWarn(ctx, "SetConversations", err, resp.UserIDs, conversation)
-			}
-		}()
+		announcement, err := s.createGroupAnnouncement(ctx, group.GroupID, req.GroupInfoForSet.Notification, false)
+		if err != nil {
+			return nil, err
+		}
 		num++
-		s.Notification.GroupInfoSetAnnouncementNotification(ctx, &sdkws.GroupInfoSetAnnouncementTips{Group: tips.Group, OpUser: tips.OpUser})
+		s.publishGroupAnnouncement(ctx, &sdkws.GroupInfoSetAnnouncementTips{Group: tips.Group, OpUser: tips.OpUser}, announcement.AnnouncementID)
 	}
 	switch len(data) - num {
 	case 0:
@@ -1028,13 +1085,28 @@ func (s *groupServer) TransferGroupOwner(ctx context.Context, req *pbgroup.Trans
 			return nil, errs.ErrNoPermission.Wrap("no permission transfer group owner")
 		}
 	}
-	if err := s.GroupDatabase.TransferGroupOwner(ctx, req.GroupID, req.OldOwnerUserID, req.NewOwnerUserID, newOwner.RoleLevel); err != nil {
+	if err := s.transferGroupOwner(ctx, req.GroupID, oldOwner, newOwner); err != nil {
 		return nil, err
 	}
-	s.Notification.GroupOwnerTransferredNotification(ctx, req)
 	return resp, nil
 }
 
+// transferGroupOwner the old owner gets the role level of the new owner.
+func (s *groupServer) transferGroupOwner(ctx context.Context, groupID string, oldOwner, newOwner *relationtb.GroupMemberModel) error {
+	if err := s.GroupDatabase.TransferGroupOwner(ctx, groupID, oldOwner.UserID, newOwner.UserID, newOwner.RoleLevel); err != nil {
+		return err
+	}
+	s.writeAuditLogs(ctx, s.newAuditLog(ctx, groupID, relationtb.GroupAuditActionTransferOwner, newOwner.UserID,
+		map[string]any{"owner_user_id": oldOwner.UserID, "role_level": newOwner.RoleLevel},
+		map[string]any{"owner_user_id": newOwner.UserID, "role_level": constant.GroupOwner}))
+	s.Notification.GroupOwnerTransferredNotification(ctx, &pbgroup.TransferGroupOwnerReq{
+		GroupID:        groupID,
+		OldOwnerUserID: oldOwner.UserID,
+		NewOwnerUserID: newOwner.UserID,
+	})
+	return nil
+}
+
 func (s *groupServer) GetGroups(ctx context.Context, req *pbgroup.GetGroupsReq) (*pbgroup.GetGroupsResp, error) {
 	resp := &pbgroup.GetGroupsResp{}
 	var (
@@ -1180,6 +1252,9 @@ func (s *groupServer) DismissGroup(ctx context.Context, req *pbgroup.DismissGrou
 	if err := s.GroupDatabase.DismissGroup(ctx, req.GroupID, req.DeleteMember); err != nil {
 		return nil, err
 	}
+	s.writeAuditLogs(ctx, s.newAuditLog(ctx, req.GroupID, relationtb.GroupAuditActionDismissGroup, "",
+		map[string]any{"status": group.Status},
+		map[string]any{"status": constant.GroupStatusDismissed, "delete_member": req.DeleteMember}))
 	if group.GroupType == constant.SuperGroup {
 		if err := s.GroupDatabase.DeleteSuperGroup(ctx, group.GroupID); err != nil {
 			return nil, err
@@ -1218,23 +1293,16 @@ func (s *groupServer) MuteGroupMember(ctx context.Context, req *pbgroup.MuteGrou
 		if err != nil {
 			return nil, err
 		}
-		switch member.RoleLevel {
-		case constant.GroupOwner:
-			return nil, errs.ErrNoPermission.Wrap("set group owner mute")
-		case constant.GroupAdmin:
-			if opMember.RoleLevel != constant.GroupOwner {
-				return nil, errs.ErrNoPermission.Wrap("set group admin mute")
-			}
-		case constant.GroupOrdinaryUsers:
-			if !(opMember.RoleLevel == constant.GroupAdmin || opMember.RoleLevel == constant.GroupOwner) {
-				return nil, errs.ErrNoPermission.Wrap("set group ordinary users mute")
-			}
+		if err := s.permission.CheckOn(ctx, req.GroupID, opMember.RoleLevel, member.RoleLevel, authverify.GroupPermissionMute); err != nil {
+			return nil, err
 		}
 	}
 	data := UpdateGroupMemberMutedTimeMap(time.Now().Add(time.Second * time.Duration(req.MutedSeconds)))
 	if err := s.GroupDatabase.UpdateGroupMember(ctx, member.GroupID, member.UserID, data); err != nil {
 		return nil, err
 	}
+	s.writeAuditLogs(ctx, s.newAuditLog(ctx, member.GroupID, relationtb.GroupAuditActionMuteMember, member.UserID,
+		pickAuditValues(groupMemberAuditValues(member), data), data))
 	s.Notification.GroupMemberMutedNotification(ctx, req.GroupID, req.UserID, req.MutedSeconds)
 	return resp, nil
 }
@@ -1266,47 +1334,42 @@ func (s *groupServer) CancelMuteGroupMember(ctx context.Context, req *pbgroup.Ca
 		if err != nil {
 			return nil, err
 		}
-		switch member.RoleLevel {
-		case constant.GroupOwner:
-			return nil, errs.ErrNoPermission.Wrap("set group owner mute")
-		case constant.GroupAdmin:
-			if opMember.RoleLevel != constant.GroupOwner {
-				return nil, errs.ErrNoPermission.Wrap("set group admin mute")
-			}
-		case constant.GroupOrdinaryUsers:
-			if !(opMember.RoleLevel == constant.GroupAdmin || opMember.RoleLevel == constant.GroupOwner) {
-				return nil, errs.ErrNoPermission.Wrap("set group ordinary users mute")
-			}
+		if err := s.permission.CheckOn(ctx, req.GroupID, opMember.RoleLevel, member.RoleLevel, authverify.GroupPermissionMute); err != nil {
+			return nil, err
 		}
 	}
 	data := UpdateGroupMemberMutedTimeMap(time.Unix(0, 0))
 	if err := s.GroupDatabase.UpdateGroupMember(ctx, member.GroupID, member.UserID, data); err != nil {
 		return nil, err
 	}
+	s.writeAuditLogs(ctx, s.newAuditLog(ctx, member.GroupID, relationtb.GroupAuditActionCancelMuteMember, member.UserID,
+		pickAuditValues(groupMemberAuditValues(member), data), data))
 	s.Notification.GroupMemberCancelMutedNotification(ctx, req.GroupID, req.UserID)
 	return resp, nil
 }
 
 func (s *groupServer) MuteGroup(ctx context.Context, req *pbgroup.MuteGroupReq) (*pbgroup.MuteGroupResp, error) {
 	resp := &pbgroup.MuteGroupResp{}
-	if err := s.CheckGroupAdmin(ctx, req.GroupID); err != nil {
+	if err := s.checkGroupPermission(ctx, req.GroupID, authverify.GroupPermissionMute); err != nil {
 		return nil, err
 	}
 	if err := s.GroupDatabase.UpdateGroup(ctx, req.GroupID, UpdateGroupStatusMap(constant.GroupStatusMuted)); err != nil {
 		return nil, err
 	}
+	s.writeAuditLogs(ctx, s.newAuditLog(ctx, req.GroupID, relationtb.GroupAuditActionMuteGroup, "", nil, UpdateGroupStatusMap(constant.GroupStatusMuted)))
 	s.Notification.GroupMutedNotification(ctx, req.GroupID)
 	return resp, nil
 }
 
 func (s *groupServer) CancelMuteGroup(ctx context.Context, req *pbgroup.CancelMuteGroupReq) (*pbgroup.CancelMuteGroupResp, error) {
 	resp := &pbgroup.CancelMuteGroupResp{}
-	if err := s.CheckGroupAdmin(ctx, req.GroupID); err != nil {
+	if err := s.checkGroupPermission(ctx, req.GroupID, authverify.GroupPermissionMute); err != nil {
 		return nil, err
 	}
 	if err := s.GroupDatabase.UpdateGroup(ctx, req.GroupID, UpdateGroupStatusMap(constant.GroupOk)); err != nil {
 		return nil, err
 	}
+	s.writeAuditLogs(ctx, s.newAuditLog(ctx, req.GroupID, relationtb.GroupAuditActionCancelMuteGroup, "", nil, UpdateGroupStatusMap(constant.GroupOk)))
 	s.Notification.GroupCancelMutedNotification(ctx, req.GroupID)
 	return resp, nil
 }
@@ -1355,7 +1418,9 @@ func (s *groupServer) SetGroupMemberInfo(ctx context.Context, req *pbgroup.SetGr
 				switch member.RoleLevel.Value {
 				case constant.GroupOrdinaryUsers, constant.GroupAdmin:
 				default:
-					return nil, errs.ErrArgs.Wrap("invalid role level")
+					if !authverify.IsGroupCustomRole(member.RoleLevel.Value) {
+						return nil, errs.ErrArgs.Wrap("invalid role level")
+					}
 				}
 			}
 			opMember, ok := memberMap[[...]string{member.GroupID, opUserID}]
@@ -1388,7 +1453,7 @@ func (s *groupServer) SetGroupMemberInfo(ctx context.Context, req *pbgroup.SetGr
 			case constant.GroupOrdinaryUsers:
 				return nil, errs.ErrNoPermission.Wrap("ordinary users can not change other role level")
 			case constant.GroupAdmin:
-				if dbMember.RoleLevel != constant.GroupOrdinaryUsers {
+				if dbMember.RoleLevel >= constant.GroupAdmin {
 					return nil, errs.ErrNoPermission.Wrap("admin can not change other role level")
 				}
 				if member.RoleLevel != nil {
@@ -1398,6 +1463,8 @@ func (s *groupServer) SetGroupMemberInfo(ctx context.Context, req *pbgroup.SetGr
 				//if member.RoleLevel != nil && member.RoleLevel.Value == constant.GroupOwner {
 				//	return nil, errs.ErrNoPermission.Wrap("owner only one")
 				//}
+			default:
+				return nil, errs.ErrNoPermission.Wrap("custom roles can not change other member info")
 			}
 		}
 	}
@@ -1408,6 +1475,11 @@ func (s *groupServer) SetGroupMemberInfo(ctx context.Context, req *pbgroup.SetGr
 		if memberMap[[...]string{member.GroupID, member.UserID}].RoleLevel == constant.GroupOwner {
 			return nil, errs.ErrArgs.Wrap(fmt.Sprintf("group %s user %s is owner", member.GroupID, member.UserID))
 		}
+		if authverify.IsGroupCustomRole(member.RoleLevel.Value) {
+			if _, err := s.roleDatabase.TakeGroupRole(ctx, member.GroupID, member.RoleLevel.Value); err != nil {
+				return nil, err
+			}
+		}
 	}
 	for i := 0; i < len(req.Members); i++ {
 		if err := CallbackBeforeSetGroupMemberInfo(ctx, req.Members[i]); err != nil {
@@ -1423,6 +1495,11 @@ func (s *groupServer) SetGroupMemberInfo(ctx context.Context, req *pbgroup.SetGr
 	})); err != nil {
 		return nil, err
 	}
+	s.writeAuditLogs(ctx, utils.Slice(req.Members, func(e *pbgroup.SetGroupMemberInfo) *relationtb.GroupAuditLogModel {
+		data := UpdateGroupMemberMap(e)
+		before := pickAuditValues(groupMemberAuditValues(memberMap[[...]string{e.GroupID, e.UserID}]), data)
+		return s.newAuditLog(ctx, e.GroupID, relationtb.GroupAuditActionSetMemberInfo, e.UserID, before, data)
+	})...)
 	for _, member := range req.Members {
 		if member.RoleLevel != nil {
 			switch member.RoleLevel.Value {
@@ -1432,7 +1509,8 @@ func (s *groupServer) SetGroupMemberInfo(ctx context.Context, req *pbgroup.SetGr
 				s.Notification.GroupMemberSetToOrdinaryUserNotification(ctx, member.GroupID, member.UserID)
 			}
 		}
-		if member.Nickname != nil || member.FaceURL != nil || member.Ex != nil {
+		if member.Nickname != nil || member.FaceURL != nil || member.Ex != nil ||
+			(member.RoleLevel != nil && authverify.IsGroupCustomRole(member.RoleLevel.Value)) {
 			log.ZDebug(ctx, "setGroupMemberInfo notification", "member", member.UserID)
 			if err := s.Notification.GroupMemberInfoSetNotification(ctx, member.GroupID, member.UserID); err != nil {
 				log.ZError(ctx, "setGroupMemberInfo notification failed", err, "member", member.UserID, "groupID", member.GroupID)
@@ -0,0 +1,148 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package group
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	pbgroup "github.com/OpenIMSDK/protocol/group"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/OpenIMSDK/tools/utils"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcext"
+)
+
+func (s *groupServer) inviteLinkDB2Ext(link *relationtb.GroupInviteLinkModel) *rpcext.GroupInviteLink {
+	return &rpcext.GroupInviteLink{
+		Code:            link.Code,
+		GroupID:         link.GroupID,
+		CreatorUserID:   link.CreatorUserID,
+		MaxUses:         link.MaxUses,
+		UsedCount:       link.UsedCount,
+		RequireApproval: link.RequireApproval,
+		Revoked:         link.Revoked,
+		ExpireTime:      link.ExpireTime.UnixMilli(),
+		CreateTime:      link.CreateTime.UnixMilli(),
+	}
+}
+
+func (s *groupServer) CreateGroupInviteLink(ctx context.Context, req *rpcext.CreateGroupInviteLinkReq) (*rpcext.CreateGroupInviteLinkResp, error) {
+	if err := req.Check(); err != nil {
+		return nil, errs.ErrArgs.Wrap(err.Error())
+	}
+	if err := s.checkGroupPermission(ctx, req.GroupID, authverify.GroupPermissionApproveJoin); err != nil {
+		return nil, err
+	}
+	group, err := s.GroupDatabase.TakeGroup(ctx, req.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	if group.Status == constant.GroupStatusDismissed {
+		return nil, errs.ErrDismissedAlready.Wrap()
+	}
+	now := time.Now()
+	expireTime := time.UnixMilli(req.ExpireTime)
+	if !expireTime.After(now) {
+		return nil, errs.ErrArgs.Wrap("expireTime is in the past")
+	}
+	link := &relationtb.GroupInviteLinkModel{
+		GroupID:         req.GroupID,
+		CreatorUserID:   mcontext.GetOpUserID(ctx),
+		MaxUses:         req.MaxUses,
+		RequireApproval: req.RequireApproval,
+		ExpireTime:      expireTime,
+		CreateTime:      now,
+	}
+	if err := s.inviteLinkDatabase.CreateGroupInviteLink(ctx, link); err != nil {
+		return nil, err
+	}
+	return &rpcext.CreateGroupInviteLinkResp{Link: s.inviteLinkDB2Ext(link)}, nil
+}
+
+func (s *groupServer) GetGroupInviteLinks(ctx context.Context, req *rpcext.GetGroupInviteLinksReq) (*rpcext.GetGroupInviteLinksResp, error) {
+	if err := req.Check(); err != nil {
+		return nil, errs.ErrArgs.Wrap(err.Error())
+	}
+	if err := s.checkGroupPermission(ctx, req.GroupID, authverify.GroupPermissionApproveJoin); err != nil {
+		return nil, err
+	}
+	total, links, err := s.inviteLinkDatabase.PageGroupInviteLinks(ctx, req.GroupID, req.Valid, req.Pagination.PageNumber, req.Pagination.ShowNumber)
+	if err != nil {
+		return nil, err
+	}
+	return &rpcext.GetGroupInviteLinksResp{
+		Total: int64(total),
+		Links: utils.Slice(links, s.inviteLinkDB2Ext),
+	}, nil
+}
+
+func (s *groupServer) RevokeGroupInviteLink(ctx context.Context, req *rpcext.RevokeGroupInviteLinkReq) (*rpcext.RevokeGroupInviteLinkResp, error) {
+	if err := req.Check(); err != nil {
+		return nil, errs.ErrArgs.Wrap(err.Error())
+	}
+	link, err := s.inviteLinkDatabase.TakeGroupInviteLink(ctx, req.Code)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkGroupPermission(ctx, link.GroupID, authverify.GroupPermissionApproveJoin); err != nil {
+		return nil, err
+	}
+	if err := s.inviteLinkDatabase.RevokeGroupInviteLink(ctx, req.Code); err != nil {
+		return nil, err
+	}
+	return &rpcext.RevokeGroupInviteLinkResp{}, nil
+}
+
+// RedeemGroupInviteLink joins the group through the usual JoinGroup flow, the link decides whether an approval is needed.
+func (s *groupServer) RedeemGroupInviteLink(ctx context.Context, req *rpcext.RedeemGroupInviteLinkReq) (*rpcext.RedeemGroupInviteLinkResp, error) {
+	if err := req.Check(); err != nil {
+		return nil, errs.ErrArgs.Wrap(err.Error())
+	}
+	if req.UserID == "" {
+		req.UserID = mcontext.GetOpUserID(ctx)
+	} else if err := authverify.CheckAccessV3(ctx, req.UserID); err != nil {
+		return nil, err
+	}
+	link, err := s.inviteLinkDatabase.UseGroupInviteLink(ctx, req.Code)
+	if err != nil {
+		return nil, err
+	}
+	join := func() (bool, error) {
+		group, err := s.GroupDatabase.TakeGroup(ctx, link.GroupID)
+		if err != nil {
+			return false, err
+		}
+		return s.joinGroup(ctx, &pbgroup.JoinGroupReq{
+			GroupID:       link.GroupID,
+			ReqMessage:    req.ReqMessage,
+			JoinSource:    relationtb.GroupJoinByInviteLink,
+			InviterUserID: req.UserID,
+		}, group, !link.RequireApproval, link.CreatorUserID)
+	}
+	pending, err := join()
+	if err != nil {
+		if err := s.inviteLinkDatabase.UnuseGroupInviteLink(ctx, req.Code); err != nil {
+			log.ZError(ctx, "UnuseGroupInviteLink failed", err, "code", req.Code)
+		}
+		return nil, err
+	}
+	return &rpcext.RedeemGroupInviteLinkResp{GroupID: link.GroupID, Pending: pending}, nil
+}
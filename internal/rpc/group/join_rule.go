@@ -0,0 +1,231 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package group
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	pbgroup "github.com/OpenIMSDK/protocol/group"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/OpenIMSDK/tools/utils"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcext"
+)
+
+// The auto-approval rules a join request can match.
+const (
+	groupJoinRuleAnswers      = "answers"
+	groupJoinRuleEx           = "ex"
+	groupJoinRuleAdminInvited = "adminInvited"
+	groupJoinRuleFriends      = "friends"
+)
+
+// joinPatternCacheSize bounds the compiled answer and ex patterns kept in memory.
+const joinPatternCacheSize = 1024
+
+var joinPatterns = struct {
+	sync.Mutex
+	m map[string]*regexp.Regexp
+}{m: make(map[string]*regexp.Regexp)}
+
+// compileJoinPattern compiles the pattern anchored at both ends, the whole value has to match it.
+// The compiled patterns are cached, so a join doesn't compile the rule of the group again.
+func compileJoinPattern(pattern string) (*regexp.Regexp, error) {
+	joinPatterns.Lock()
+	defer joinPatterns.Unlock()
+	if re, ok := joinPatterns.m[pattern]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		return nil, err
+	}
+	if len(joinPatterns.m) >= joinPatternCacheSize {
+		joinPatterns.m = make(map[string]*regexp.Regexp)
+	}
+	joinPatterns.m[pattern] = re
+	return re, nil
+}
+
+// matchJoinPattern an empty pattern matches any value.
+func matchJoinPattern(pattern string, value string) bool {
+	if pattern == "" {
+		return true
+	}
+	re, err := compileJoinPattern(pattern)
+	return err == nil && re.MatchString(value)
+}
+
+func joinRuleDB2PB(rule *relationtb.GroupJoinRuleModel) *rpcext.GroupJoinRule {
+	return &rpcext.GroupJoinRule{
+		GroupID: rule.GroupID,
+		Questions: utils.Slice(rule.GetQuestions(), func(e *relationtb.GroupJoinQuestion) *rpcext.GroupJoinQuestion {
+			return &rpcext.GroupJoinQuestion{Question: e.Question, AnswerPattern: e.AnswerPattern}
+		}),
+		ApproveByAnswers:    rule.ApproveByAnswers,
+		ExPattern:           rule.ExPattern,
+		ApproveAdminInvited: rule.ApproveAdminInvited,
+		MinFriendMembers:    rule.MinFriendMembers,
+		UpdateUserID:        rule.UpdateUserID,
+		UpdateTime:          rule.UpdateTime.UnixMilli(),
+	}
+}
+
+func (s *groupServer) SetGroupJoinRule(ctx context.Context, req *rpcext.SetGroupJoinRuleReq) (*rpcext.SetGroupJoinRuleResp, error) {
+	if err := req.Check(); err != nil {
+		return nil, errs.ErrArgs.Wrap(err.Error())
+	}
+	for _, question := range req.Questions {
+		if _, err := compileJoinPattern(question.AnswerPattern); err != nil {
+			return nil, errs.ErrArgs.Wrap("invalid answerPattern " + question.AnswerPattern)
+		}
+	}
+	if _, err := compileJoinPattern(req.ExPattern); err != nil {
+		return nil, errs.ErrArgs.Wrap("invalid exPattern " + req.ExPattern)
+	}
+	if err := s.checkGroupOwner(ctx, req.GroupID); err != nil {
+		return nil, err
+	}
+	group, err := s.GroupDatabase.TakeGroup(ctx, req.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	if group.Status == constant.GroupStatusDismissed {
+		return nil, errs.ErrDismissedAlready.Wrap()
+	}
+	var before *rpcext.GroupJoinRule
+	if rule, err := s.joinRuleDatabase.TakeGroupJoinRule(ctx, req.GroupID); err == nil {
+		before = joinRuleDB2PB(rule)
+	} else if !relationtb.IsNotFound(err) {
+		return nil, err
+	}
+	if len(req.Questions) == 0 && req.ExPattern == "" && !req.ApproveAdminInvited && req.MinFriendMembers == 0 {
+		if err := s.joinRuleDatabase.DeleteGroupJoinRule(ctx, req.GroupID); err != nil {
+			return nil, err
+		}
+		s.writeAuditLogs(ctx, s.newAuditLog(ctx, req.GroupID, relationtb.GroupAuditActionSetJoinRule, "", before, nil))
+		return &rpcext.SetGroupJoinRuleResp{}, nil
+	}
+	rule := &relationtb.GroupJoinRuleModel{
+		GroupID:             req.GroupID,
+		ApproveByAnswers:    req.ApproveByAnswers,
+		ExPattern:           req.ExPattern,
+		ApproveAdminInvited: req.ApproveAdminInvited,
+		MinFriendMembers:    req.MinFriendMembers,
+		UpdateUserID:        mcontext.GetOpUserID(ctx),
+		UpdateTime:          time.Now(),
+	}
+	rule.SetQuestions(utils.Slice(req.Questions, func(e *rpcext.GroupJoinQuestion) *relationtb.GroupJoinQuestion {
+		return &relationtb.GroupJoinQuestion{Question: e.Question, AnswerPattern: e.AnswerPattern}
+	}))
+	if err := s.joinRuleDatabase.SetGroupJoinRule(ctx, rule); err != nil {
+		return nil, err
+	}
+	s.writeAuditLogs(ctx, s.newAuditLog(ctx, req.GroupID, relationtb.GroupAuditActionSetJoinRule, "", before, joinRuleDB2PB(rule)))
+	return &rpcext.SetGroupJoinRuleResp{}, nil
+}
+
+func (s *groupServer) GetGroupJoinRule(ctx context.Context, req *rpcext.GetGroupJoinRuleReq) (*rpcext.GetGroupJoinRuleResp, error) {
+	if err := req.Check(); err != nil {
+		return nil, errs.ErrArgs.Wrap(err.Error())
+	}
+	rule, err := s.joinRuleDatabase.TakeGroupJoinRule(ctx, req.GroupID)
+	if err != nil {
+		if relationtb.IsNotFound(err) {
+			return &rpcext.GetGroupJoinRuleResp{Rule: &rpcext.GroupJoinRule{GroupID: req.GroupID, Questions: []*rpcext.GroupJoinQuestion{}}}, nil
+		}
+		return nil, err
+	}
+	resp := &rpcext.GetGroupJoinRuleResp{Rule: joinRuleDB2PB(rule)}
+	if s.checkGroupPermission(ctx, req.GroupID, authverify.GroupPermissionApproveJoin) != nil {
+		resp.Rule = &rpcext.GroupJoinRule{
+			GroupID: rule.GroupID,
+			Questions: utils.Slice(resp.Rule.Questions, func(e *rpcext.GroupJoinQuestion) *rpcext.GroupJoinQuestion {
+				return &rpcext.GroupJoinQuestion{Question: e.Question}
+			}),
+		}
+	}
+	return resp, nil
+}
+
+// matchGroupJoinRule checks the answers to the join questions of the group,
+// it returns the first auto-approval rule the request matches, empty if the request needs an approval.
+// inviterUserID is the creator of the invite link the applicant comes from.
+func (s *groupServer) matchGroupJoinRule(ctx context.Context, req *pbgroup.JoinGroupReq, user *sdkws.UserInfo, inviterUserID string) (string, error) {
+	rule, err := s.joinRuleDatabase.TakeGroupJoinRule(ctx, req.GroupID)
+	if err != nil {
+		if relationtb.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	questions := rule.GetQuestions()
+	if len(questions) > 0 {
+		var answers []string
+		if err := json.Unmarshal([]byte(req.ReqMessage), &answers); err != nil || len(answers) != len(questions) {
+			return "", errs.ErrArgs.Wrap("reqMessage must be a json array holding the answers to the join questions")
+		}
+		if rule.ApproveByAnswers && matchGroupJoinAnswers(questions, answers) {
+			return groupJoinRuleAnswers, nil
+		}
+	}
+	if rule.ExPattern != "" && matchJoinPattern(rule.ExPattern, user.Ex) {
+		return groupJoinRuleEx, nil
+	}
+	if rule.ApproveAdminInvited && inviterUserID != "" {
+		inviter, err := s.GroupDatabase.TakeGroupMember(ctx, req.GroupID, inviterUserID)
+		if err == nil {
+			if s.permission.Check(ctx, req.GroupID, inviter.RoleLevel, authverify.GroupPermissionApproveJoin) == nil {
+				return groupJoinRuleAdminInvited, nil
+			}
+		} else if !s.IsNotFound(err) {
+			return "", err
+		}
+	}
+	if rule.MinFriendMembers > 0 {
+		friendIDs, err := s.friendRpcClient.GetFriendIDs(ctx, user.UserID)
+		if err != nil {
+			return "", err
+		}
+		if len(friendIDs) >= int(rule.MinFriendMembers) {
+			memberUserIDs, err := s.GroupDatabase.FindGroupMemberUserID(ctx, req.GroupID)
+			if err != nil {
+				return "", err
+			}
+			if len(utils.IntersectString(friendIDs, memberUserIDs)) >= int(rule.MinFriendMembers) {
+				return groupJoinRuleFriends, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+func matchGroupJoinAnswers(questions []*relationtb.GroupJoinQuestion, answers []string) bool {
+	for i, question := range questions {
+		if !matchJoinPattern(question.AnswerPattern, answers[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,144 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package group
+
+import (
+	"context"
+	"testing"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	pbgroup "github.com/OpenIMSDK/protocol/group"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/errs"
+	"gorm.io/gorm"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+type joinRuleDatabase struct {
+	controller.GroupJoinRuleDatabase
+	rules map[string]*relationtb.GroupJoinRuleModel
+}
+
+func (d *joinRuleDatabase) TakeGroupJoinRule(ctx context.Context, groupID string) (*relationtb.GroupJoinRuleModel, error) {
+	rule, ok := d.rules[groupID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return rule, nil
+}
+
+type joinGroupDatabase struct {
+	controller.GroupDatabase
+	members map[string]*relationtb.GroupMemberModel
+}
+
+func (d *joinGroupDatabase) TakeGroupMember(ctx context.Context, groupID string, userID string) (*relationtb.GroupMemberModel, error) {
+	member, ok := d.members[userID]
+	if !ok {
+		return nil, errs.ErrRecordNotFound.Wrap()
+	}
+	return member, nil
+}
+
+func TestMatchJoinPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		value   string
+		want    bool
+	}{
+		{pattern: "", value: "anything", want: true},
+		{pattern: "yes", value: "yes", want: true},
+		{pattern: "yes", value: "yes please", want: false},
+		{pattern: "yes", value: "oh yes", want: false},
+		{pattern: "yes|y", value: "y", want: true},
+		{pattern: "yes|y", value: "yesterday", want: false},
+		{pattern: "(?i)blue", value: "BLUE", want: true},
+		{pattern: `.*"vip":true.*`, value: `{"vip":true}`, want: true},
+		{pattern: "[", value: "[", want: false},
+	}
+	for _, tt := range tests {
+		if got := matchJoinPattern(tt.pattern, tt.value); got != tt.want {
+			t.Errorf("matchJoinPattern(%q, %q) = %v, want %v", tt.pattern, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestMatchGroupJoinRule(t *testing.T) {
+	withAnswers := &relationtb.GroupJoinRuleModel{GroupID: "answers", ApproveByAnswers: true, ExPattern: "vip"}
+	withAnswers.SetQuestions([]*relationtb.GroupJoinQuestion{
+		{Question: "favourite colour", AnswerPattern: "(?i)blue|green"},
+		{Question: "anything to add"},
+	})
+	s := &groupServer{
+		joinRuleDatabase: &joinRuleDatabase{rules: map[string]*relationtb.GroupJoinRuleModel{
+			"answers": withAnswers,
+			"ex":      {GroupID: "ex", ExPattern: "vip|svip"},
+			"invited": {GroupID: "invited", ApproveAdminInvited: true},
+		}},
+		GroupDatabase: &joinGroupDatabase{members: map[string]*relationtb.GroupMemberModel{
+			"owner":     {UserID: "owner", RoleLevel: constant.GroupOwner},
+			"admin":     {UserID: "admin", RoleLevel: constant.GroupAdmin},
+			"approver":  {UserID: "approver", RoleLevel: 30},
+			"moderator": {UserID: "moderator", RoleLevel: 40},
+			"member":    {UserID: "member", RoleLevel: constant.GroupOrdinaryUsers},
+		}},
+		permission: authverify.NewGroupPermissionChecker(func(ctx context.Context, groupID string, roleLevel int32) (int64, error) {
+			if roleLevel == 30 {
+				return authverify.GroupPermissionApproveJoin, nil
+			}
+			return authverify.GroupPermissionMute, nil
+		}),
+	}
+	tests := []struct {
+		name       string
+		groupID    string
+		reqMessage string
+		ex         string
+		inviter    string
+		want       string
+		wantErr    bool
+	}{
+		{name: "no rule", groupID: "none", want: ""},
+		{name: "answers match", groupID: "answers", reqMessage: `["Blue", "hi"]`, want: groupJoinRuleAnswers},
+		{name: "answer contains the pattern", groupID: "answers", reqMessage: `["not blue", ""]`, want: ""},
+		{name: "answers fail but ex matches", groupID: "answers", reqMessage: `["red", ""]`, ex: "vip", want: groupJoinRuleEx},
+		{name: "missing answers", groupID: "answers", reqMessage: `["blue"]`, wantErr: true},
+		{name: "answers not json", groupID: "answers", reqMessage: "blue", wantErr: true},
+		{name: "ex matches", groupID: "ex", ex: "svip", want: groupJoinRuleEx},
+		{name: "ex contains the pattern", groupID: "ex", ex: "not a vip", want: ""},
+		{name: "invited by the owner", groupID: "invited", inviter: "owner", want: groupJoinRuleAdminInvited},
+		{name: "invited by an admin", groupID: "invited", inviter: "admin", want: groupJoinRuleAdminInvited},
+		{name: "invited by a role approving joins", groupID: "invited", inviter: "approver", want: groupJoinRuleAdminInvited},
+		{name: "invited by a role not approving joins", groupID: "invited", inviter: "moderator", want: ""},
+		{name: "invited by a member", groupID: "invited", inviter: "member", want: ""},
+		{name: "invited by someone who left", groupID: "invited", inviter: "gone", want: ""},
+		{name: "not invited", groupID: "invited", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &pbgroup.JoinGroupReq{GroupID: tt.groupID, ReqMessage: tt.reqMessage}
+			got, err := s.matchGroupJoinRule(context.Background(), req, &sdkws.UserInfo{UserID: "u1", Ex: tt.ex}, tt.inviter)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("matchGroupJoinRule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("matchGroupJoinRule() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,490 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package group
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	pbuser "github.com/OpenIMSDK/protocol/user"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/OpenIMSDK/tools/utils"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/convert"
+	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcext"
+)
+
+const (
+	groupMemberImportMaxSize   = 10 << 20
+	groupMemberImportMaxRows   = 50000
+	groupMemberImportBatchSize = 500
+	// groupMemberImportNotifyNum members listed in one invited or kicked notification.
+	groupMemberImportNotifyNum = 200
+	groupMemberExportExpire    = 24 * time.Hour
+)
+
+// groupMemberImportRow invalid is the reason the row can't be parsed.
+type groupMemberImportRow struct {
+	Row       int32  `json:"-"`
+	UserID    string `json:"userID"`
+	Action    string `json:"action"`
+	RoleLevel int32  `json:"roleLevel"`
+	Nickname  string `json:"nickname"`
+	invalid   string
+}
+
+// groupMemberImportPlan the rows which passed the validation.
+type groupMemberImportPlan struct {
+	add     []*groupMemberImportRow
+	remove  []*groupMemberImportRow
+	members map[string]*relationtb.GroupMemberModel
+}
+
+func parseGroupMemberCSV(data []byte) ([]*groupMemberImportRow, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, errs.ErrArgs.Wrap("invalid csv " + err.Error())
+	}
+	if len(records) == 0 {
+		return nil, errs.ErrArgs.Wrap("csv is empty")
+	}
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["userid"]; !ok {
+		return nil, errs.ErrArgs.Wrap("csv has no userID column")
+	}
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	rows := make([]*groupMemberImportRow, 0, len(records)-1)
+	for i, record := range records[1:] {
+		row := &groupMemberImportRow{
+			Row:      int32(i + 1),
+			UserID:   field(record, "userid"),
+			Action:   field(record, "action"),
+			Nickname: field(record, "nickname"),
+		}
+		if roleLevel := field(record, "rolelevel"); roleLevel != "" {
+			level, err := strconv.ParseInt(roleLevel, 10, 32)
+			if err != nil {
+				row.invalid = "invalid roleLevel " + roleLevel
+			}
+			row.RoleLevel = int32(level)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func parseGroupMemberJSON(data []byte) ([]*groupMemberImportRow, error) {
+	var file struct {
+		Members []*groupMemberImportRow `json:"members"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, errs.ErrArgs.Wrap("invalid json " + err.Error())
+	}
+	for i, row := range file.Members {
+		if row == nil {
+			row = &groupMemberImportRow{invalid: "member is null"}
+			file.Members[i] = row
+		}
+		row.Row = int32(i + 1)
+	}
+	return file.Members, nil
+}
+
+// readGroupMemberImport only app managers can import the objects uploaded by other users.
+func (s *groupServer) readGroupMemberImport(ctx context.Context, objectName string) ([]*groupMemberImportRow, error) {
+	obj, reader, err := s.s3Database.GetObject(ctx, objectName)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	if !authverify.IsAppManagerUid(ctx) && obj.UserID != mcontext.GetOpUserID(ctx) {
+		return nil, errs.ErrNoPermission.Wrap("object not uploaded by the operator")
+	}
+	if obj.Size > groupMemberImportMaxSize {
+		return nil, errs.ErrArgs.Wrap("object is too large")
+	}
+	data, err := io.ReadAll(io.LimitReader(reader, groupMemberImportMaxSize+1))
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	if len(data) > groupMemberImportMaxSize {
+		return nil, errs.ErrArgs.Wrap("object is too large")
+	}
+	var rows []*groupMemberImportRow
+	if strings.Contains(obj.ContentType, "json") || strings.EqualFold(path.Ext(obj.Name), ".json") {
+		rows, err = parseGroupMemberJSON(data)
+	} else {
+		rows, err = parseGroupMemberCSV(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) > groupMemberImportMaxRows {
+		return nil, errs.ErrArgs.Wrap(fmt.Sprintf("more than %d members", groupMemberImportMaxRows))
+	}
+	return rows, nil
+}
+
+func addGroupMemberImportFailure(resp *rpcext.ImportGroupMembersResp, row *groupMemberImportRow, reason string) {
+	resp.Failures = append(resp.Failures, &rpcext.GroupMemberImportFailure{Row: row.Row, UserID: row.UserID, Reason: reason})
+}
+
+// prepareGroupMemberImport plans the import against the current members and drops the rows of unknown users.
+func (s *groupServer) prepareGroupMemberImport(ctx context.Context, group *relationtb.GroupModel, rows []*groupMemberImportRow, resp *rpcext.ImportGroupMembersResp) (*groupMemberImportPlan, error) {
+	members, err := s.FindGroupMember(ctx, []string{group.GroupID}, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	memberMap := utils.SliceToMap(members, func(e *relationtb.GroupMemberModel) string { return e.UserID })
+	var opRoleLevel int32
+	if authverify.IsAppManagerUid(ctx) {
+		opRoleLevel = constant.GroupOwner
+	} else if op, ok := memberMap[mcontext.GetOpUserID(ctx)]; ok {
+		opRoleLevel = op.RoleLevel
+	}
+	roles, err := s.roleDatabase.FindGroupRoles(ctx, group.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	roleMap := utils.SliceToMap(roles, func(e *relationtb.GroupRoleModel) int32 { return e.RoleLevel })
+	plan := planGroupMemberImport(rows, memberMap, roleMap, opRoleLevel, resp)
+	add := plan.add
+	plan.add = nil
+	for pageNumber := 1; ; pageNumber++ {
+		batch := utils.Paginate(add, pageNumber, groupMemberImportBatchSize)
+		if len(batch) == 0 {
+			break
+		}
+		users, err := s.User.Client.GetDesignateUsers(ctx, &pbuser.GetDesignateUsersReq{
+			UserIDs: utils.Slice(batch, func(e *groupMemberImportRow) string { return e.UserID }),
+		})
+		if err != nil {
+			return nil, err
+		}
+		userMap := utils.SliceToMap(users.UsersInfo, func(e *sdkws.UserInfo) string { return e.UserID })
+		for _, row := range batch {
+			if _, ok := userMap[row.UserID]; !ok {
+				addGroupMemberImportFailure(resp, row, "user not found")
+				continue
+			}
+			plan.add = append(plan.add, row)
+		}
+	}
+	return plan, nil
+}
+
+// planGroupMemberImport the operator only adds and removes members ranking below it, like kicking does,
+// the owner itself can't be removed. An app manager imports with the role level of the owner.
+// The rows that fail are added to resp.
+func planGroupMemberImport(rows []*groupMemberImportRow, members map[string]*relationtb.GroupMemberModel,
+	roleMap map[int32]*relationtb.GroupRoleModel, opRoleLevel int32, resp *rpcext.ImportGroupMembersResp,
+) *groupMemberImportPlan {
+	plan := &groupMemberImportPlan{members: members}
+	seen := make(map[string]struct{}, len(rows))
+	for _, row := range rows {
+		if row.invalid != "" {
+			addGroupMemberImportFailure(resp, row, row.invalid)
+			continue
+		}
+		if row.UserID == "" {
+			addGroupMemberImportFailure(resp, row, "userID is empty")
+			continue
+		}
+		if _, ok := seen[row.UserID]; ok {
+			addGroupMemberImportFailure(resp, row, "userID duplicate")
+			continue
+		}
+		seen[row.UserID] = struct{}{}
+		member := plan.members[row.UserID]
+		switch strings.ToLower(row.Action) {
+		case "", rpcext.GroupMemberImportActionAdd:
+			if member != nil {
+				addGroupMemberImportFailure(resp, row, "already in group")
+				continue
+			}
+			switch {
+			case row.RoleLevel == 0:
+				row.RoleLevel = constant.GroupOrdinaryUsers
+			case row.RoleLevel == constant.GroupOrdinaryUsers, row.RoleLevel == constant.GroupAdmin:
+			case authverify.IsGroupCustomRole(row.RoleLevel):
+				if _, ok := roleMap[row.RoleLevel]; !ok {
+					addGroupMemberImportFailure(resp, row, "group role not found")
+					continue
+				}
+			default:
+				addGroupMemberImportFailure(resp, row, "invalid roleLevel")
+				continue
+			}
+			if !authverify.GroupRoleOutranks(opRoleLevel, row.RoleLevel) {
+				addGroupMemberImportFailure(resp, row, "roleLevel must be below the operator")
+				continue
+			}
+			plan.add = append(plan.add, row)
+		case rpcext.GroupMemberImportActionRemove:
+			if member == nil {
+				addGroupMemberImportFailure(resp, row, "not in group")
+				continue
+			}
+			if member.RoleLevel == constant.GroupOwner {
+				addGroupMemberImportFailure(resp, row, "group owner can't be removed")
+				continue
+			}
+			if !authverify.GroupRoleOutranks(opRoleLevel, member.RoleLevel) {
+				addGroupMemberImportFailure(resp, row, "member must rank below the operator")
+				continue
+			}
+			plan.remove = append(plan.remove, row)
+		default:
+			addGroupMemberImportFailure(resp, row, "invalid action")
+		}
+	}
+	return plan
+}
+
+// addImportedGroupMembers a failed batch fails all its rows, the batches before are kept.
+func (s *groupServer) addImportedGroupMembers(ctx context.Context, group *relationtb.GroupModel, rows []*groupMemberImportRow, resp *rpcext.ImportGroupMembersResp) []string {
+	opUserID := mcontext.GetOpUserID(ctx)
+	var addedUserIDs []string
+	for pageNumber := 1; ; pageNumber++ {
+		batch := utils.Paginate(rows, pageNumber, groupMemberImportBatchSize)
+		if len(batch) == 0 {
+			break
+		}
+		members := make([]*relationtb.GroupMemberModel, 0, len(batch))
+		accepted := make([]*groupMemberImportRow, 0, len(batch))
+		for _, row := range batch {
+			member := &relationtb.GroupMemberModel{
+				GroupID:        group.GroupID,
+				UserID:         row.UserID,
+				Nickname:       row.Nickname,
+				RoleLevel:      row.RoleLevel,
+				OperatorUserID: opUserID,
+				InviterUserID:  opUserID,
+				JoinSource:     constant.JoinByInvitation,
+				JoinTime:       time.Now(),
+				MuteEndTime:    time.UnixMilli(0),
+			}
+			if err := CallbackBeforeMemberJoinGroup(ctx, member, group.Ex); err != nil {
+				addGroupMemberImportFailure(resp, row, err.Error())
+				continue
+			}
+			members = append(members, member)
+			accepted = append(accepted, row)
+		}
+		if len(members) == 0 {
+			continue
+		}
+		if err := s.GroupDatabase.CreateGroup(ctx, nil, members); err != nil {
+			log.ZError(ctx, "import group members failed", err, "groupID", group.GroupID, "num", len(members))
+			for _, row := range accepted {
+				addGroupMemberImportFailure(resp, row, err.Error())
+			}
+			continue
+		}
+		userIDs := utils.Slice(members, func(e *relationtb.GroupMemberModel) string { return e.UserID })
+		if err := s.conversationRpcClient.GroupChatFirstCreateConversation(ctx, group.GroupID, userIDs); err != nil {
+			log.ZError(ctx, "GroupChatFirstCreateConversation failed", err, "groupID", group.GroupID, "userIDs", userIDs)
+		}
+		addedUserIDs = append(addedUserIDs, userIDs...)
+	}
+	return addedUserIDs
+}
+
+func (s *groupServer) removeImportedGroupMembers(ctx context.Context, group *relationtb.GroupModel, rows []*groupMemberImportRow, resp *rpcext.ImportGroupMembersResp) []string {
+	var removedUserIDs []string
+	for pageNumber := 1; ; pageNumber++ {
+		batch := utils.Paginate(rows, pageNumber, groupMemberImportBatchSize)
+		if len(batch) == 0 {
+			break
+		}
+		userIDs := utils.Slice(batch, func(e *groupMemberImportRow) string { return e.UserID })
+		if err := s.GroupDatabase.DeleteGroupMember(ctx, group.GroupID, userIDs); err != nil {
+			log.ZError(ctx, "remove imported group members failed", err, "groupID", group.GroupID, "num", len(userIDs))
+			for _, row := range batch {
+				addGroupMemberImportFailure(resp, row, err.Error())
+			}
+			continue
+		}
+		if err := s.deleteMemberAndSetConversationSeq(ctx, group.GroupID, userIDs); err != nil {
+			log.ZError(ctx, "deleteMemberAndSetConversationSeq failed", err, "groupID", group.GroupID, "userIDs", userIDs)
+		}
+		removedUserIDs = append(removedUserIDs, userIDs...)
+	}
+	return removedUserIDs
+}
+
+// auditImportedGroupMembers one audit log per batch of added or removed members, so each log stays small.
+func (s *groupServer) auditImportedGroupMembers(ctx context.Context, group *relationtb.GroupModel, objectName string, addedUserIDs, removedUserIDs []string) {
+	var logs []*relationtb.GroupAuditLogModel
+	addLogs := func(key string, allUserIDs []string) {
+		for pageNumber := 1; ; pageNumber++ {
+			userIDs := utils.Paginate(allUserIDs, pageNumber, groupMemberImportBatchSize)
+			if len(userIDs) == 0 {
+				return
+			}
+			logs = append(logs, s.newAuditLog(ctx, group.GroupID, relationtb.GroupAuditActionImportMembers, "", nil,
+				map[string]any{"object_name": objectName, "batch": pageNumber, key: userIDs}))
+		}
+	}
+	addLogs("added_user_ids", addedUserIDs)
+	addLogs("removed_user_ids", removedUserIDs)
+	s.writeAuditLogs(ctx, logs...)
+}
+
+// notifyImportedGroupMembers the added and the removed members are announced groupMemberImportNotifyNum at a time.
+func (s *groupServer) notifyImportedGroupMembers(ctx context.Context, group *relationtb.GroupModel, addedUserIDs []string, removed []*relationtb.GroupMemberModel) {
+	for pageNumber := 1; ; pageNumber++ {
+		userIDs := utils.Paginate(addedUserIDs, pageNumber, groupMemberImportNotifyNum)
+		if len(userIDs) == 0 {
+			break
+		}
+		s.Notification.MemberInvitedNotification(ctx, group.GroupID, "", userIDs)
+	}
+	if len(removed) == 0 {
+		return
+	}
+	owner, err := s.TakeGroupOwner(ctx, group.GroupID)
+	if err != nil {
+		log.ZError(ctx, "TakeGroupOwner failed", err, "groupID", group.GroupID)
+		return
+	}
+	num, err := s.GroupDatabase.FindGroupMemberNum(ctx, group.GroupID)
+	if err != nil {
+		log.ZError(ctx, "FindGroupMemberNum failed", err, "groupID", group.GroupID)
+		return
+	}
+	for pageNumber := 1; ; pageNumber++ {
+		members := utils.Paginate(removed, pageNumber, groupMemberImportNotifyNum)
+		if len(members) == 0 {
+			break
+		}
+		s.Notification.MemberKickedNotification(ctx, &sdkws.MemberKickedTips{
+			Group:          s.groupDB2PB(group, owner.UserID, num),
+			KickedUserList: utils.Slice(members, convert.Db2PbGroupMember),
+		})
+	}
+}
+
+func (s *groupServer) ImportGroupMembers(ctx context.Context, req *rpcext.ImportGroupMembersReq) (*rpcext.ImportGroupMembersResp, error) {
+	if err := req.Check(); err != nil {
+		return nil, errs.ErrArgs.Wrap(err.Error())
+	}
+	if err := s.checkGroupPermission(ctx, req.GroupID, groupPermissionManageMembers); err != nil {
+		return nil, err
+	}
+	group, err := s.GroupDatabase.TakeGroup(ctx, req.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	if group.Status == constant.GroupStatusDismissed {
+		return nil, errs.ErrDismissedAlready.Wrap()
+	}
+	if group.GroupType == constant.SuperGroup {
+		return nil, errs.ErrArgs.Wrap("members of super group can't be imported")
+	}
+	rows, err := s.readGroupMemberImport(ctx, req.ObjectName)
+	if err != nil {
+		return nil, err
+	}
+	resp := &rpcext.ImportGroupMembersResp{Total: int32(len(rows)), Failures: []*rpcext.GroupMemberImportFailure{}}
+	plan, err := s.prepareGroupMemberImport(ctx, group, rows, resp)
+	if err != nil {
+		return nil, err
+	}
+	if req.DryRun {
+		resp.Added = int32(len(plan.add))
+		resp.Removed = int32(len(plan.remove))
+	} else {
+		addedUserIDs := s.addImportedGroupMembers(ctx, group, plan.add, resp)
+		removedUserIDs := s.removeImportedGroupMembers(ctx, group, plan.remove, resp)
+		resp.Added = int32(len(addedUserIDs))
+		resp.Removed = int32(len(removedUserIDs))
+		if len(addedUserIDs) > 0 || len(removedUserIDs) > 0 {
+			s.auditImportedGroupMembers(ctx, group, req.ObjectName, addedUserIDs, removedUserIDs)
+			s.notifyImportedGroupMembers(ctx, group, addedUserIDs, utils.Slice(removedUserIDs, func(userID string) *relationtb.GroupMemberModel {
+				return plan.members[userID]
+			}))
+		}
+	}
+	sort.SliceStable(resp.Failures, func(i, j int) bool { return resp.Failures[i].Row < resp.Failures[j].Row })
+	return resp, nil
+}
+
+func (s *groupServer) ExportGroupMembers(ctx context.Context, req *rpcext.ExportGroupMembersReq) (*rpcext.ExportGroupMembersResp, error) {
+	if err := req.Check(); err != nil {
+		return nil, errs.ErrArgs.Wrap(err.Error())
+	}
+	if err := s.checkGroupPermission(ctx, req.GroupID, groupPermissionManageMembers); err != nil {
+		return nil, err
+	}
+	members, err := s.FindGroupMember(ctx, []string{req.GroupID}, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(members, func(i, j int) bool { return members[i].JoinTime.Before(members[j].JoinTime) })
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	_ = writer.Write([]string{"userID", "roleLevel", "nickname", "joinTime"})
+	for _, member := range members {
+		_ = writer.Write([]string{member.UserID, strconv.Itoa(int(member.RoleLevel)), member.Nickname, member.JoinTime.Format(time.RFC3339)})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, errs.Wrap(err)
+	}
+	opUserID := mcontext.GetOpUserID(ctx)
+	name := path.Join(opUserID, "group_member_export", req.GroupID+"_"+strconv.FormatInt(time.Now().UnixMilli(), 10)+".csv")
+	obj := &relationtb.ObjectModel{
+		Name:        name,
+		UserID:      opUserID,
+		ContentType: "text/csv",
+		Cause:       "group_member_export",
+		CreateTime:  time.Now(),
+	}
+	if err := s.s3Database.UploadObject(ctx, obj, buf.Bytes()); err != nil {
+		return nil, err
+	}
+	expireTime, url, err := s.s3Database.AccessURL(ctx, name, groupMemberExportExpire, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &rpcext.ExportGroupMembersResp{URL: url, ExpireTime: expireTime.UnixMilli()}, nil
+}
@@ -0,0 +1,153 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package group
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/OpenIMSDK/protocol/constant"
+
+	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcext"
+)
+
+func TestParseGroupMemberCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []groupMemberImportRow
+		wantErr bool
+	}{
+		{
+			name: "all columns with bom",
+			data: "\xef\xbb\xbfUserID, Action, RoleLevel, Nickname\nu1,add,60,Alice\nu2,remove,,\n",
+			want: []groupMemberImportRow{
+				{Row: 1, UserID: "u1", Action: "add", RoleLevel: 60, Nickname: "Alice"},
+				{Row: 2, UserID: "u2", Action: "remove"},
+			},
+		},
+		{
+			name: "columns in any order and short rows",
+			data: "nickname,userID\nBob,u1\nCarol\n",
+			want: []groupMemberImportRow{
+				{Row: 1, UserID: "u1", Nickname: "Bob"},
+				{Row: 2, Nickname: "Carol"},
+			},
+		},
+		{
+			name: "invalid role level",
+			data: "userID,roleLevel\nu1,admin\n",
+			want: []groupMemberImportRow{{Row: 1, UserID: "u1", invalid: "invalid roleLevel admin"}},
+		},
+		{name: "header only", data: "userID\n", want: []groupMemberImportRow{}},
+		{name: "no user id column", data: "nickname\nBob\n", wantErr: true},
+		{name: "empty", data: "", wantErr: true},
+		{name: "broken quote", data: "userID\n\"u1\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := parseGroupMemberCSV([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseGroupMemberCSV() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			got := make([]groupMemberImportRow, 0, len(rows))
+			for _, row := range rows {
+				got = append(got, *row)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseGroupMemberCSV() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlanGroupMemberImport(t *testing.T) {
+	members := map[string]*relationtb.GroupMemberModel{
+		"owner":  {UserID: "owner", RoleLevel: constant.GroupOwner},
+		"admin":  {UserID: "admin", RoleLevel: constant.GroupAdmin},
+		"member": {UserID: "member", RoleLevel: constant.GroupOrdinaryUsers},
+		"mod":    {UserID: "mod", RoleLevel: 30},
+		"senior": {UserID: "senior", RoleLevel: 50},
+	}
+	roleMap := map[int32]*relationtb.GroupRoleModel{30: {RoleLevel: 30}, 50: {RoleLevel: 50}}
+	tests := []struct {
+		name        string
+		row         groupMemberImportRow
+		opRoleLevel int32
+		wantAdd     bool
+		wantRemove  bool
+		wantReason  string
+	}{
+		{name: "add by default", row: groupMemberImportRow{UserID: "u1"}, opRoleLevel: constant.GroupAdmin, wantAdd: true},
+		{name: "add admin by owner", row: groupMemberImportRow{UserID: "u1", RoleLevel: constant.GroupAdmin}, opRoleLevel: constant.GroupOwner, wantAdd: true},
+		{name: "add admin by admin", row: groupMemberImportRow{UserID: "u1", RoleLevel: constant.GroupAdmin}, opRoleLevel: constant.GroupAdmin, wantReason: "roleLevel must be below the operator"},
+		{name: "add custom role", row: groupMemberImportRow{UserID: "u1", Action: "ADD", RoleLevel: 30}, opRoleLevel: constant.GroupAdmin, wantAdd: true},
+		{name: "add lower custom role by custom role", row: groupMemberImportRow{UserID: "u1", RoleLevel: 30}, opRoleLevel: 50, wantAdd: true},
+		{name: "add higher custom role by custom role", row: groupMemberImportRow{UserID: "u1", RoleLevel: 50}, opRoleLevel: 30, wantReason: "roleLevel must be below the operator"},
+		{name: "add same custom role by custom role", row: groupMemberImportRow{UserID: "u1", RoleLevel: 30}, opRoleLevel: 30, wantReason: "roleLevel must be below the operator"},
+		{name: "add ordinary by custom role", row: groupMemberImportRow{UserID: "u1"}, opRoleLevel: 30, wantAdd: true},
+		{name: "add unknown custom role", row: groupMemberImportRow{UserID: "u1", RoleLevel: 31}, opRoleLevel: constant.GroupOwner, wantReason: "group role not found"},
+		{name: "add as owner", row: groupMemberImportRow{UserID: "u1", RoleLevel: constant.GroupOwner}, opRoleLevel: constant.GroupOwner, wantReason: "invalid roleLevel"},
+		{name: "add member", row: groupMemberImportRow{UserID: "member"}, opRoleLevel: constant.GroupAdmin, wantReason: "already in group"},
+		{name: "remove member", row: groupMemberImportRow{UserID: "member", Action: "remove"}, opRoleLevel: constant.GroupAdmin, wantRemove: true},
+		{name: "remove member by custom role", row: groupMemberImportRow{UserID: "member", Action: "remove"}, opRoleLevel: 30, wantRemove: true},
+		{name: "remove higher custom role by custom role", row: groupMemberImportRow{UserID: "senior", Action: "remove"}, opRoleLevel: 30, wantReason: "member must rank below the operator"},
+		{name: "remove same custom role by custom role", row: groupMemberImportRow{UserID: "mod", Action: "remove"}, opRoleLevel: 30, wantReason: "member must rank below the operator"},
+		{name: "remove custom role by admin", row: groupMemberImportRow{UserID: "senior", Action: "remove"}, opRoleLevel: constant.GroupAdmin, wantRemove: true},
+		{name: "remove admin by admin", row: groupMemberImportRow{UserID: "admin", Action: "remove"}, opRoleLevel: constant.GroupAdmin, wantReason: "member must rank below the operator"},
+		{name: "remove admin by owner", row: groupMemberImportRow{UserID: "admin", Action: "remove"}, opRoleLevel: constant.GroupOwner, wantRemove: true},
+		{name: "remove owner", row: groupMemberImportRow{UserID: "owner", Action: "remove"}, opRoleLevel: constant.GroupOwner, wantReason: "group owner can't be removed"},
+		{name: "remove stranger", row: groupMemberImportRow{UserID: "u1", Action: "remove"}, opRoleLevel: constant.GroupAdmin, wantReason: "not in group"},
+		{name: "unknown action", row: groupMemberImportRow{UserID: "u1", Action: "kick"}, opRoleLevel: constant.GroupAdmin, wantReason: "invalid action"},
+		{name: "empty user id", row: groupMemberImportRow{}, opRoleLevel: constant.GroupAdmin, wantReason: "userID is empty"},
+		{name: "invalid row", row: groupMemberImportRow{UserID: "u1", invalid: "invalid roleLevel x"}, opRoleLevel: constant.GroupAdmin, wantReason: "invalid roleLevel x"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			row := tt.row
+			resp := &rpcext.ImportGroupMembersResp{}
+			plan := planGroupMemberImport([]*groupMemberImportRow{&row}, members, roleMap, tt.opRoleLevel, resp)
+			if got := len(plan.add) == 1; got != tt.wantAdd {
+				t.Errorf("add = %v, want %v", got, tt.wantAdd)
+			}
+			if got := len(plan.remove) == 1; got != tt.wantRemove {
+				t.Errorf("remove = %v, want %v", got, tt.wantRemove)
+			}
+			var reason string
+			if len(resp.Failures) > 0 {
+				reason = resp.Failures[0].Reason
+			}
+			if reason != tt.wantReason {
+				t.Errorf("failure = %q, want %q", reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestPlanGroupMemberImportDuplicate(t *testing.T) {
+	rows := []*groupMemberImportRow{{Row: 1, UserID: "u1"}, {Row: 2, UserID: "u1", Action: "remove"}}
+	resp := &rpcext.ImportGroupMembersResp{}
+	plan := planGroupMemberImport(rows, map[string]*relationtb.GroupMemberModel{}, nil, constant.GroupAdmin, resp)
+	if len(plan.add) != 1 || plan.add[0].RoleLevel != constant.GroupOrdinaryUsers {
+		t.Fatalf("add = %+v, want u1 as ordinary member", plan.add)
+	}
+	if len(resp.Failures) != 1 || resp.Failures[0].Row != 2 || resp.Failures[0].Reason != "userID duplicate" {
+		t.Errorf("failures = %+v, want row 2 duplicate", resp.Failures)
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package group
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/OpenIMSDK/tools/utils"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcext"
+)
+
+func (s *groupServer) SetGroupMemberTag(ctx context.Context, req *rpcext.SetGroupMemberTagReq) (*rpcext.SetGroupMemberTagResp, error) {
+	if err := req.Check(); err != nil {
+		return nil, errs.ErrArgs.Wrap(err.Error())
+	}
+	if err := s.checkGroupPermission(ctx, req.GroupID, authverify.GroupPermissionEditInfo); err != nil {
+		return nil, err
+	}
+	group, err := s.GroupDatabase.TakeGroup(ctx, req.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	if group.Status == constant.GroupStatusDismissed {
+		return nil, errs.ErrDismissedAlready.Wrap()
+	}
+	userIDs := utils.Distinct(req.UserIDs)
+	members, err := s.FindGroupMember(ctx, []string{req.GroupID}, userIDs, nil)
+	if err != nil {
+		return nil, err
+	}
+	if ids := utils.Single(userIDs, utils.Slice(members, func(e *relationtb.GroupMemberModel) string { return e.UserID })); len(ids) > 0 {
+		return nil, errs.ErrArgs.Wrap("user not in group " + strings.Join(ids, ","))
+	}
+	before, err := s.memberTagDatabase.FindGroupMemberTags(ctx, req.GroupID, []string{req.Tag})
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	memberTags := utils.Slice(userIDs, func(userID string) *relationtb.GroupMemberTagModel {
+		return &relationtb.GroupMemberTagModel{GroupID: req.GroupID, Tag: req.Tag, UserID: userID, CreateTime: now}
+	})
+	if err := s.memberTagDatabase.SetGroupMemberTag(ctx, req.GroupID, req.Tag, memberTags); err != nil {
+		return nil, err
+	}
+	s.writeAuditLogs(ctx, s.newAuditLog(ctx, req.GroupID, relationtb.GroupAuditActionSetMemberTag, "",
+		map[string]any{"tag": req.Tag, "user_ids": utils.Slice(before, func(e *relationtb.GroupMemberTagModel) string { return e.UserID })},
+		map[string]any{"tag": req.Tag, "user_ids": userIDs}))
+	return &rpcext.SetGroupMemberTagResp{}, nil
+}
+
+func (s *groupServer) DeleteGroupMemberTag(ctx context.Context, req *rpcext.DeleteGroupMemberTagReq) (*rpcext.DeleteGroupMemberTagResp, error) {
+	if err := req.Check(); err != nil {
+		return nil, errs.ErrArgs.Wrap(err.Error())
+	}
+	if err := s.checkGroupPermission(ctx, req.GroupID, authverify.GroupPermissionEditInfo); err != nil {
+		return nil, err
+	}
+	before, err := s.memberTagDatabase.FindGroupMemberTags(ctx, req.GroupID, []string{req.Tag})
+	if err != nil {
+		return nil, err
+	}
+	if len(before) == 0 {
+		return nil, errs.ErrRecordNotFound.Wrap("tag not found")
+	}
+	if err := s.memberTagDatabase.DeleteGroupMemberTag(ctx, req.GroupID, req.Tag); err != nil {
+		return nil, err
+	}
+	s.writeAuditLogs(ctx, s.newAuditLog(ctx, req.GroupID, relationtb.GroupAuditActionSetMemberTag, "",
+		map[string]any{"tag": req.Tag, "user_ids": utils.Slice(before, func(e *relationtb.GroupMemberTagModel) string { return e.UserID })}, nil))
+	return &rpcext.DeleteGroupMemberTagResp{}, nil
+}
+
+// GetGroupMemberTags the members who left the group are dropped from the tags.
+func (s *groupServer) GetGroupMemberTags(ctx context.Context, req *rpcext.GetGroupMemberTagsReq) (*rpcext.GetGroupMemberTagsResp, error) {
+	if err := req.Check(); err != nil {
+		return nil, errs.ErrArgs.Wrap(err.Error())
+	}
+	if !authverify.IsAppManagerUid(ctx) {
+		if _, err := s.GroupDatabase.TakeGroupMember(ctx, req.GroupID, mcontext.GetOpUserID(ctx)); err != nil {
+			return nil, err
+		}
+	}
+	memberTags, err := s.memberTagDatabase.FindGroupMemberTags(ctx, req.GroupID, req.Tags)
+	if err != nil {
+		return nil, err
+	}
+	memberUserIDs, err := s.GroupDatabase.FindGroupMemberUserID(ctx, req.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	memberMap := utils.SliceSet(memberUserIDs)
+	resp := &rpcext.GetGroupMemberTagsResp{Tags: []*rpcext.GroupMemberTag{}}
+	tagMap := make(map[string]*rpcext.GroupMemberTag)
+	for _, memberTag := range memberTags {
+		if _, ok := memberMap[memberTag.UserID]; !ok {
+			continue
+		}
+		tag, ok := tagMap[memberTag.Tag]
+		if !ok {
+			tag = &rpcext.GroupMemberTag{Tag: memberTag.Tag}
+			tagMap[memberTag.Tag] = tag
+			resp.Tags = append(resp.Tags, tag)
+		}
+		tag.UserIDs = append(tag.UserIDs, memberTag.UserID)
+	}
+	return resp, nil
+}
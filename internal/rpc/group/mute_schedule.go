@@ -0,0 +1,167 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package group
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/OpenIMSDK/tools/utils"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcext"
+)
+
+const maxGroupMuteSchedules = 20
+
+func muteScheduleDB2PB(schedule *relationtb.GroupMuteScheduleModel) *rpcext.GroupMuteSchedule {
+	res := &rpcext.GroupMuteSchedule{
+		ScheduleID:    schedule.ID,
+		GroupID:       schedule.GroupID,
+		Type:          schedule.Type,
+		StartMinute:   schedule.StartMinute,
+		EndMinute:     schedule.EndMinute,
+		TimeZone:      schedule.TimeZone,
+		Active:        schedule.Active,
+		CreatorUserID: schedule.CreatorUserID,
+		CreateTime:    schedule.CreateTime.UnixMilli(),
+	}
+	if schedule.Type == relationtb.GroupMuteScheduleOnce {
+		res.StartTime = schedule.StartTime.UnixMilli()
+		res.EndTime = schedule.EndTime.UnixMilli()
+	}
+	return res
+}
+
+func anyMuteScheduleActive(schedules []*relationtb.GroupMuteScheduleModel) bool {
+	for _, schedule := range schedules {
+		if schedule.Active {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateGroupMuteSchedule a window covering the current time mutes the group at once,
+// the crontask announces the later boundaries.
+func (s *groupServer) CreateGroupMuteSchedule(ctx context.Context, req *rpcext.CreateGroupMuteScheduleReq) (*rpcext.CreateGroupMuteScheduleResp, error) {
+	if err := req.Check(); err != nil {
+		return nil, errs.ErrArgs.Wrap(err.Error())
+	}
+	if err := s.checkGroupPermission(ctx, req.GroupID, authverify.GroupPermissionMute); err != nil {
+		return nil, err
+	}
+	group, err := s.GroupDatabase.TakeGroup(ctx, req.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	if group.Status == constant.GroupStatusDismissed {
+		return nil, errs.ErrDismissedAlready.Wrap()
+	}
+	schedules, err := s.muteScheduleDatabase.FindGroupMuteSchedules(ctx, req.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	if len(schedules) >= maxGroupMuteSchedules {
+		return nil, errs.ErrArgs.Wrap("too many mute schedules in the group")
+	}
+	now := time.Now()
+	schedule := &relationtb.GroupMuteScheduleModel{
+		GroupID:       req.GroupID,
+		Type:          req.Type,
+		CreatorUserID: mcontext.GetOpUserID(ctx),
+		CreateTime:    now,
+	}
+	if req.Type == rpcext.GroupMuteScheduleDaily {
+		schedule.StartMinute = req.StartMinute
+		schedule.EndMinute = req.EndMinute
+		schedule.TimeZone = req.TimeZone
+	} else {
+		schedule.StartTime = time.UnixMilli(req.StartTime)
+		schedule.EndTime = time.UnixMilli(req.EndTime)
+	}
+	schedule.Active = schedule.Covers(now)
+	if err := s.muteScheduleDatabase.CreateGroupMuteSchedule(ctx, schedule); err != nil {
+		return nil, err
+	}
+	s.writeAuditLogs(ctx, s.newAuditLog(ctx, req.GroupID, relationtb.GroupAuditActionSetMuteSchedule, "", nil, muteScheduleDB2PB(schedule)))
+	if schedule.Active && !anyMuteScheduleActive(schedules) && group.Status != constant.GroupStatusMuted {
+		s.Notification.GroupMutedNotification(ctx, req.GroupID)
+	}
+	return &rpcext.CreateGroupMuteScheduleResp{Schedule: muteScheduleDB2PB(schedule)}, nil
+}
+
+func (s *groupServer) DeleteGroupMuteSchedule(ctx context.Context, req *rpcext.DeleteGroupMuteScheduleReq) (*rpcext.DeleteGroupMuteScheduleResp, error) {
+	if err := req.Check(); err != nil {
+		return nil, errs.ErrArgs.Wrap(err.Error())
+	}
+	schedule, err := s.muteScheduleDatabase.TakeGroupMuteSchedule(ctx, req.ScheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkGroupPermission(ctx, schedule.GroupID, authverify.GroupPermissionMute); err != nil {
+		return nil, err
+	}
+	if err := s.muteScheduleDatabase.DeleteGroupMuteSchedules(ctx, schedule.GroupID, []string{schedule.ID}); err != nil {
+		return nil, err
+	}
+	s.writeAuditLogs(ctx, s.newAuditLog(ctx, schedule.GroupID, relationtb.GroupAuditActionSetMuteSchedule, "", muteScheduleDB2PB(schedule), nil))
+	if !schedule.Active {
+		return &rpcext.DeleteGroupMuteScheduleResp{}, nil
+	}
+	schedules, err := s.muteScheduleDatabase.FindGroupMuteSchedules(ctx, schedule.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	if anyMuteScheduleActive(schedules) {
+		return &rpcext.DeleteGroupMuteScheduleResp{}, nil
+	}
+	group, err := s.GroupDatabase.TakeGroup(ctx, schedule.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	if group.Status != constant.GroupStatusMuted {
+		s.Notification.GroupCancelMutedNotification(ctx, schedule.GroupID)
+	}
+	return &rpcext.DeleteGroupMuteScheduleResp{}, nil
+}
+
+func (s *groupServer) GetGroupMuteSchedules(ctx context.Context, req *rpcext.GetGroupMuteSchedulesReq) (*rpcext.GetGroupMuteSchedulesResp, error) {
+	if err := req.Check(); err != nil {
+		return nil, errs.ErrArgs.Wrap(err.Error())
+	}
+	if !authverify.IsAppManagerUid(ctx) {
+		if _, err := s.GroupDatabase.TakeGroupMember(ctx, req.GroupID, mcontext.GetOpUserID(ctx)); err != nil {
+			return nil, err
+		}
+	}
+	schedules, err := s.muteScheduleDatabase.FindGroupMuteSchedules(ctx, req.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	resp := &rpcext.GetGroupMuteSchedulesResp{Schedules: utils.Slice(schedules, muteScheduleDB2PB)}
+	for _, schedule := range schedules {
+		if schedule.Covers(now) {
+			resp.Muted = true
+			break
+		}
+	}
+	return resp, nil
+}
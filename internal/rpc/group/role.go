@@ -0,0 +1,132 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package group
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/OpenIMSDK/tools/utils"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcext"
+)
+
+// groupPermissionManageMembers importing and exporting the member list both adds and removes members.
+const groupPermissionManageMembers = authverify.GroupPermissionKick | authverify.GroupPermissionApproveJoin
+
+// checkGroupPermission the operator must be an app manager or a member holding the permission.
+func (s *groupServer) checkGroupPermission(ctx context.Context, groupID string, permission int64) error {
+	if authverify.IsAppManagerUid(ctx) {
+		return nil
+	}
+	opMember, err := s.GroupDatabase.TakeGroupMember(ctx, groupID, mcontext.GetOpUserID(ctx))
+	if err != nil {
+		return err
+	}
+	return s.permission.Check(ctx, groupID, opMember.RoleLevel, permission)
+}
+
+func (s *groupServer) checkGroupOwner(ctx context.Context, groupID string) error {
+	if authverify.IsAppManagerUid(ctx) {
+		return nil
+	}
+	opMember, err := s.GroupDatabase.TakeGroupMember(ctx, groupID, mcontext.GetOpUserID(ctx))
+	if err != nil {
+		return err
+	}
+	if opMember.RoleLevel != constant.GroupOwner {
+		return errs.ErrNoPermission.Wrap("no group owner")
+	}
+	return nil
+}
+
+func (s *groupServer) SetGroupRole(ctx context.Context, req *rpcext.SetGroupRoleReq) (*rpcext.SetGroupRoleResp, error) {
+	if err := req.Check(); err != nil {
+		return nil, errs.ErrArgs.Wrap(err.Error())
+	}
+	if err := s.checkGroupOwner(ctx, req.GroupID); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	role := &relationtb.GroupRoleModel{
+		GroupID:     req.GroupID,
+		RoleLevel:   req.RoleLevel,
+		Name:        req.Name,
+		Permissions: req.Permissions,
+		CreateTime:  now,
+	}
+	if old, err := s.roleDatabase.TakeGroupRole(ctx, req.GroupID, req.RoleLevel); err == nil {
+		role.CreateTime = old.CreateTime
+	} else if !relationtb.IsNotFound(err) {
+		return nil, err
+	}
+	if err := s.roleDatabase.SetGroupRole(ctx, role); err != nil {
+		return nil, err
+	}
+	return &rpcext.SetGroupRoleResp{}, nil
+}
+
+// DeleteGroupRole the members holding the role have to be moved to another role first.
+func (s *groupServer) DeleteGroupRole(ctx context.Context, req *rpcext.DeleteGroupRoleReq) (*rpcext.DeleteGroupRoleResp, error) {
+	if err := req.Check(); err != nil {
+		return nil, errs.ErrArgs.Wrap(err.Error())
+	}
+	if err := s.checkGroupOwner(ctx, req.GroupID); err != nil {
+		return nil, err
+	}
+	members, err := s.GroupDatabase.FindGroupMember(ctx, []string{req.GroupID}, nil, []int32{req.RoleLevel})
+	if err != nil {
+		return nil, err
+	}
+	if len(members) > 0 {
+		return nil, errs.ErrArgs.Wrap("group role is still held by members")
+	}
+	if err := s.roleDatabase.DeleteGroupRole(ctx, req.GroupID, req.RoleLevel); err != nil {
+		return nil, err
+	}
+	return &rpcext.DeleteGroupRoleResp{}, nil
+}
+
+func (s *groupServer) GetGroupRoles(ctx context.Context, req *rpcext.GetGroupRolesReq) (*rpcext.GetGroupRolesResp, error) {
+	if err := req.Check(); err != nil {
+		return nil, errs.ErrArgs.Wrap(err.Error())
+	}
+	if !authverify.IsAppManagerUid(ctx) {
+		if _, err := s.GroupDatabase.TakeGroupMember(ctx, req.GroupID, mcontext.GetOpUserID(ctx)); err != nil {
+			return nil, err
+		}
+	}
+	roles, err := s.roleDatabase.FindGroupRoles(ctx, req.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	return &rpcext.GetGroupRolesResp{
+		Roles: utils.Slice(roles, func(role *relationtb.GroupRoleModel) *rpcext.GroupRole {
+			return &rpcext.GroupRole{
+				GroupID:     role.GroupID,
+				RoleLevel:   role.RoleLevel,
+				Name:        role.Name,
+				Permissions: role.Permissions,
+				CreateTime:  role.CreateTime.UnixMilli(),
+				UpdateTime:  role.UpdateTime.UnixMilli(),
+			}
+		}),
+	}, nil
+}
@@ -0,0 +1,112 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package group
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	pbgroup "github.com/OpenIMSDK/protocol/group"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/utils"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcext"
+)
+
+// The values of config groupOwnerSuccession, the owner of a group can't quit while it is empty.
+const (
+	groupOwnerSuccessionOldestAdmin  = "oldestAdmin"
+	groupOwnerSuccessionOldestMember = "oldestMember"
+	groupOwnerSuccessionDismiss      = "dismiss"
+)
+
+// succeedGroupOwner runs before the owner leaves the group, the ownership goes to the successor of the
+// configured policy. oldestAdmin falls back to the oldest member without admins, the group is dismissed
+// when no member is left.
+func (s *groupServer) succeedGroupOwner(ctx context.Context, group *relationtb.GroupModel, owner *relationtb.GroupMemberModel) error {
+	policy := config.Config.GroupOwnerSuccession
+	var successor *relationtb.GroupMemberModel
+	switch policy {
+	case groupOwnerSuccessionOldestAdmin, groupOwnerSuccessionOldestMember:
+		if policy == groupOwnerSuccessionOldestAdmin {
+			admins, err := s.FindGroupMember(ctx, []string{group.GroupID}, nil, []int32{constant.GroupAdmin})
+			if err != nil {
+				return err
+			}
+			successor = oldestGroupMember(admins, owner.UserID)
+		}
+		if successor == nil {
+			members, err := s.FindGroupMember(ctx, []string{group.GroupID}, nil, nil)
+			if err != nil {
+				return err
+			}
+			successor = oldestGroupMember(members, owner.UserID)
+		}
+	case groupOwnerSuccessionDismiss:
+	default:
+		return errs.ErrNoPermission.Wrap("group owner can't quit")
+	}
+	if successor == nil {
+		log.ZInfo(ctx, "group owner left, dismiss group", "groupID", group.GroupID, "ownerUserID", owner.UserID, "policy", policy)
+		_, err := s.DismissGroup(ctx, &pbgroup.DismissGroupReq{GroupID: group.GroupID})
+		return err
+	}
+	log.ZInfo(ctx, "group owner left, transfer group owner", "groupID", group.GroupID, "ownerUserID", owner.UserID, "newOwnerUserID", successor.UserID, "policy", policy)
+	return s.transferGroupOwner(ctx, group.GroupID, owner, successor)
+}
+
+// oldestGroupMember the member who joined first, except the leaving owner.
+func oldestGroupMember(members []*relationtb.GroupMemberModel, ownerUserID string) *relationtb.GroupMemberModel {
+	var oldest *relationtb.GroupMemberModel
+	for _, member := range members {
+		if member.UserID == ownerUserID {
+			continue
+		}
+		if oldest == nil || member.JoinTime.Before(oldest.JoinTime) ||
+			(member.JoinTime.Equal(oldest.JoinTime) && member.UserID < oldest.UserID) {
+			oldest = member
+		}
+	}
+	return oldest
+}
+
+// QuitAllGroups removes the user from all joined groups, it is used when the account of the user is deleted.
+// The owned groups go to the successors of the configured policy.
+func (s *groupServer) QuitAllGroups(ctx context.Context, req *rpcext.QuitAllGroupsReq) (*rpcext.QuitAllGroupsResp, error) {
+	if err := authverify.CheckAdmin(ctx); err != nil {
+		return nil, err
+	}
+	members, err := s.FindGroupMember(ctx, nil, []string{req.UserID}, nil)
+	if err != nil {
+		return nil, err
+	}
+	superGroupIDs, err := s.GroupDatabase.FindJoinSuperGroup(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	groupIDs := utils.Distinct(append(utils.Slice(members, func(e *relationtb.GroupMemberModel) string { return e.GroupID }), superGroupIDs...))
+	resp := &rpcext.QuitAllGroupsResp{}
+	for _, groupID := range groupIDs {
+		if _, err := s.QuitGroup(ctx, &pbgroup.QuitGroupReq{GroupID: groupID, UserID: req.UserID}); err != nil {
+			log.ZError(ctx, "QuitGroup failed", err, "groupID", groupID, "userID", req.UserID)
+			resp.FailedGroupIDs = append(resp.FailedGroupIDs, groupID)
+		}
+	}
+	return resp, nil
+}
@@ -0,0 +1,73 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package group
+
+import (
+	"testing"
+	"time"
+
+	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+func TestOldestGroupMember(t *testing.T) {
+	base := time.Date(2023, 10, 1, 8, 0, 0, 0, time.UTC)
+	member := func(userID string, joinedAfter time.Duration) *relationtb.GroupMemberModel {
+		return &relationtb.GroupMemberModel{UserID: userID, JoinTime: base.Add(joinedAfter)}
+	}
+	tests := []struct {
+		name    string
+		members []*relationtb.GroupMemberModel
+		want    string
+	}{
+		{name: "no members", want: ""},
+		{name: "only the owner", members: []*relationtb.GroupMemberModel{member("owner", 0)}, want: ""},
+		{
+			name:    "earliest join wins",
+			members: []*relationtb.GroupMemberModel{member("u1", time.Hour), member("u2", time.Minute), member("u3", 2*time.Hour)},
+			want:    "u2",
+		},
+		{
+			name:    "owner skipped although oldest",
+			members: []*relationtb.GroupMemberModel{member("owner", 0), member("u2", time.Hour), member("u1", 2*time.Hour)},
+			want:    "u2",
+		},
+		{
+			name:    "same join time goes to the smallest user id",
+			members: []*relationtb.GroupMemberModel{member("u3", time.Hour), member("u1", time.Hour), member("u2", time.Hour)},
+			want:    "u1",
+		},
+		{
+			name:    "join time before user id",
+			members: []*relationtb.GroupMemberModel{member("a", time.Hour), member("z", time.Minute), member("b", time.Minute)},
+			want:    "b",
+		},
+		{
+			name:    "same join time as the skipped owner",
+			members: []*relationtb.GroupMemberModel{member("a", 0), member("owner", 0), member("b", 0)},
+			want:    "a",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			if successor := oldestGroupMember(tt.members, "owner"); successor != nil {
+				got = successor.UserID
+			}
+			if got != tt.want {
+				t.Errorf("oldestGroupMember() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
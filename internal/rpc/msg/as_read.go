@@ -20,10 +20,13 @@ import (
 	"github.com/redis/go-redis/v9"
 
 	"github.com/OpenIMSDK/protocol/constant"
+	pbconversation "github.com/OpenIMSDK/protocol/conversation"
 	"github.com/OpenIMSDK/protocol/msg"
 	"github.com/OpenIMSDK/protocol/sdkws"
 	"github.com/OpenIMSDK/tools/errs"
 	"github.com/OpenIMSDK/tools/log"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
 )
 
 func (m *msgServer) GetConversationsHasReadAndMaxSeq(ctx context.Context, req *msg.GetConversationsHasReadAndMaxSeqReq) (resp *msg.GetConversationsHasReadAndMaxSeqResp, err error) {
@@ -81,6 +84,10 @@ func (m *msgServer) SetConversationHasReadSeq(
 	if err := m.MsgDatabase.SetHasReadSeq(ctx, req.UserID, req.ConversationID, req.HasReadSeq); err != nil {
 		return nil, err
 	}
+	// the previous has read seq is unknown here, so let the badge be recomputed on the next push
+	if err := m.MsgDatabase.DelUserBadgeUnreadCountSum(ctx, req.UserID); err != nil {
+		log.ZWarn(ctx, "del user badge unread count sum failed", err, "userID", req.UserID)
+	}
 	if err = m.sendMarkAsReadNotification(ctx, req.ConversationID, constant.SingleChatType, req.UserID,
 		req.UserID, nil, req.HasReadSeq); err != nil {
 		return
@@ -119,6 +126,7 @@ func (m *msgServer) MarkMsgsAsRead(
 		if err != nil {
 			return
 		}
+		m.decrBadgeUnreadCount(ctx, req.UserID, conversation, hasReadSeq-currentHasReadSeq)
 	}
 	if err = m.sendMarkAsReadNotification(ctx, req.ConversationID, conversation.ConversationType, req.UserID,
 		m.conversationAndGetRecvID(conversation, req.UserID), req.Seqs, hasReadSeq); err != nil {
@@ -144,7 +152,16 @@ func (m *msgServer) MarkConversationAsRead(
 	log.ZDebug(ctx, "MarkConversationAsRead", "hasReadSeq", hasReadSeq,
 		"req.HasReadSeq", req.HasReadSeq)
 	if conversation.ConversationType == constant.SingleChatType {
-		for i := hasReadSeq + 1; i <= req.HasReadSeq; i++ {
+		// the read state of archived messages is only kept by the has read seq
+		archivedMaxSeq, err := m.MsgDatabase.GetArchivedMaxSeq(ctx, req.ConversationID)
+		if err != nil {
+			return nil, err
+		}
+		begin := hasReadSeq + 1
+		if archivedMaxSeq >= begin {
+			begin = archivedMaxSeq + 1
+		}
+		for i := begin; i <= req.HasReadSeq; i++ {
 			seqs = append(seqs, i)
 		}
 
@@ -159,6 +176,7 @@ func (m *msgServer) MarkConversationAsRead(
 			if err != nil {
 				return nil, err
 			}
+			m.decrBadgeUnreadCount(ctx, req.UserID, conversation, req.HasReadSeq-hasReadSeq)
 			hasReadSeq = req.HasReadSeq
 		}
 		if err = m.sendMarkAsReadNotification(ctx, req.ConversationID, conversation.ConversationType, req.UserID,
@@ -172,6 +190,7 @@ func (m *msgServer) MarkConversationAsRead(
 			if err != nil {
 				return nil, err
 			}
+			m.decrBadgeUnreadCount(ctx, req.UserID, conversation, req.HasReadSeq-hasReadSeq)
 			hasReadSeq = req.HasReadSeq
 		}
 		if err = m.sendMarkAsReadNotification(ctx, req.ConversationID, constant.SingleChatType, req.UserID,
@@ -184,6 +203,17 @@ func (m *msgServer) MarkConversationAsRead(
 	return &msg.MarkConversationAsReadResp{}, nil
 }
 
+// decrBadgeUnreadCount takes the messages that were just read off the user's cached badge.
+// A failure only leaves the badge stale until it expires, so it is logged and not returned.
+func (m *msgServer) decrBadgeUnreadCount(ctx context.Context, userID string, conversation *pbconversation.Conversation, readCount int64) {
+	if readCount <= 0 || !msgprocessor.IsBadgeCounted(conversation.ConversationID, conversation.RecvMsgOpt) {
+		return
+	}
+	if err := m.MsgDatabase.IncrUserBadgeUnreadCountSums(ctx, []string{userID}, -int(readCount)); err != nil {
+		log.ZWarn(ctx, "decr user badge unread count sum failed", err, "userID", userID, "readCount", readCount)
+	}
+}
+
 func (m *msgServer) sendMarkAsReadNotification(
 	ctx context.Context,
 	conversationID string,
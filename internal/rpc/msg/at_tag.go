@@ -0,0 +1,70 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msg
+
+import (
+	"context"
+	"strings"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/utils"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcext"
+)
+
+// expandAtTags replaces the tag mentions in AtUserIDList with the members carrying the tags. It runs before the
+// message goes to the queue, so setConversationAtInfo marks the conversation of the members as @me and the push
+// treats them as mentioned. The list is kept as it is if the tags can't be resolved. Tags only mean something in
+// groups, the sender's permission to use them is checked in messageVerification like @all.
+func (m *msgServer) expandAtTags(ctx context.Context, msg *sdkws.MsgData) {
+	if msg.SessionType != constant.SuperGroupChatType && msg.SessionType != constant.GroupChatType {
+		return
+	}
+	var tags []string
+	atUserIDs := make([]string, 0, len(msg.AtUserIDList))
+	for _, atUserID := range msg.AtUserIDList {
+		if tag := strings.TrimPrefix(atUserID, rpcext.GroupMemberTagAtPrefix); tag != atUserID {
+			tags = append(tags, tag)
+			continue
+		}
+		atUserIDs = append(atUserIDs, atUserID)
+	}
+	if len(tags) == 0 {
+		return
+	}
+	tagUserIDs, err := m.Group.GetGroupMemberTagUserIDs(ctx, msg.GroupID, tags)
+	if err != nil {
+		log.ZWarn(ctx, "GetGroupMemberTagUserIDs failed", err, "groupID", msg.GroupID, "tags", tags)
+		return
+	}
+	for _, userID := range tagUserIDs {
+		if userID != msg.SendID {
+			atUserIDs = append(atUserIDs, userID)
+		}
+	}
+	msg.AtUserIDList = utils.Distinct(atUserIDs)
+}
+
+// hasAtTag reports whether any of the mentions is a tag mention.
+func hasAtTag(atUserIDs []string) bool {
+	for _, atUserID := range atUserIDs {
+		if strings.HasPrefix(atUserID, rpcext.GroupMemberTagAtPrefix) {
+			return true
+		}
+	}
+	return false
+}
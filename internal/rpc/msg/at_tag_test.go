@@ -0,0 +1,128 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msg
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"google.golang.org/grpc"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcext"
+)
+
+type memberTagClient struct {
+	rpcext.GroupExtClient
+	tags  map[string][]string
+	fail  bool
+	calls int
+}
+
+func (c *memberTagClient) GetGroupMemberTags(ctx context.Context, in *rpcext.GetGroupMemberTagsReq, opts ...grpc.CallOption) (*rpcext.GetGroupMemberTagsResp, error) {
+	c.calls++
+	if c.fail {
+		return nil, errors.New("group rpc unavailable")
+	}
+	resp := &rpcext.GetGroupMemberTagsResp{}
+	for _, tag := range in.Tags {
+		if userIDs, ok := c.tags[tag]; ok {
+			resp.Tags = append(resp.Tags, &rpcext.GroupMemberTag{Tag: tag, UserIDs: userIDs})
+		}
+	}
+	return resp, nil
+}
+
+func TestExpandAtTags(t *testing.T) {
+	tag := func(name string) string { return rpcext.GroupMemberTagAtPrefix + name }
+	tests := []struct {
+		name        string
+		sessionType int32
+		atUserIDs   []string
+		fail        bool
+		want        []string
+		wantCalls   int
+	}{
+		{
+			name:        "no tags",
+			sessionType: constant.SuperGroupChatType,
+			atUserIDs:   []string{"u1", "u2"},
+			want:        []string{"u1", "u2"},
+		},
+		{
+			name:        "tag expanded",
+			sessionType: constant.SuperGroupChatType,
+			atUserIDs:   []string{tag("dev")},
+			want:        []string{"u1", "u2"},
+			wantCalls:   1,
+		},
+		{
+			name:        "sender excluded",
+			sessionType: constant.SuperGroupChatType,
+			atUserIDs:   []string{tag("ops")},
+			want:        []string{"u3"},
+			wantCalls:   1,
+		},
+		{
+			name:        "duplicates removed",
+			sessionType: constant.GroupChatType,
+			atUserIDs:   []string{"u2", tag("dev"), tag("ops"), "u2"},
+			want:        []string{"u2", "u1", "u3"},
+			wantCalls:   1,
+		},
+		{
+			name:        "unknown tag dropped",
+			sessionType: constant.SuperGroupChatType,
+			atUserIDs:   []string{"u1", tag("gone")},
+			want:        []string{"u1"},
+			wantCalls:   1,
+		},
+		{
+			name:        "lookup failed",
+			sessionType: constant.SuperGroupChatType,
+			atUserIDs:   []string{"u1", tag("dev")},
+			fail:        true,
+			want:        []string{"u1", tag("dev")},
+			wantCalls:   1,
+		},
+		{
+			name:        "not a group",
+			sessionType: constant.SingleChatType,
+			atUserIDs:   []string{tag("dev")},
+			want:        []string{tag("dev")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &memberTagClient{
+				tags: map[string][]string{"dev": {"u1", "u2"}, "ops": {"sender", "u3"}},
+				fail: tt.fail,
+			}
+			m := &msgServer{Group: &rpcclient.GroupRpcClient{ExtClient: client}}
+			msg := &sdkws.MsgData{SendID: "sender", GroupID: "g1", SessionType: tt.sessionType, AtUserIDList: tt.atUserIDs}
+			m.expandAtTags(context.Background(), msg)
+			if !reflect.DeepEqual(msg.AtUserIDList, tt.want) {
+				t.Errorf("AtUserIDList = %v, want %v", msg.AtUserIDList, tt.want)
+			}
+			if client.calls != tt.wantCalls {
+				t.Errorf("GetGroupMemberTags called %d times, want %d", client.calls, tt.wantCalls)
+			}
+		})
+	}
+}
@@ -78,6 +78,16 @@ func (m *msgServer) DeleteMsgs(ctx context.Context, req *msg.DeleteMsgsReq) (*ms
 		return nil, err
 	}
 	isSyncSelf, isSyncOther := m.validateDeleteSyncOpt(req.DeleteSyncOpt)
+	if isSyncOther {
+		held, err := m.heldConversationIDs(ctx, []string{req.ConversationID})
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := held[req.ConversationID]; ok {
+			log.ZInfo(ctx, "conversation is under legal hold, only delete for the user", "conversationID", req.ConversationID, "seqs", req.Seqs)
+			isSyncSelf, isSyncOther = true, false
+		}
+	}
 	if isSyncOther {
 		if err := m.MsgDatabase.DeleteMsgsPhysicalBySeqs(ctx, req.ConversationID, req.Seqs); err != nil {
 			return nil, err
@@ -111,7 +121,15 @@ func (m *msgServer) DeleteMsgPhysicalBySeq(
 	ctx context.Context,
 	req *msg.DeleteMsgPhysicalBySeqReq,
 ) (*msg.DeleteMsgPhysicalBySeqResp, error) {
-	err := m.MsgDatabase.DeleteMsgsPhysicalBySeqs(ctx, req.ConversationID, req.Seqs)
+	held, err := m.heldConversationIDs(ctx, []string{req.ConversationID})
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := held[req.ConversationID]; ok {
+		log.ZWarn(ctx, "conversation is under legal hold, skip physical delete", nil, "conversationID", req.ConversationID, "seqs", req.Seqs)
+		return &msg.DeleteMsgPhysicalBySeqResp{}, nil
+	}
+	err = m.MsgDatabase.DeleteMsgsPhysicalBySeqs(ctx, req.ConversationID, req.Seqs)
 	if err != nil {
 		return nil, err
 	}
@@ -126,7 +144,15 @@ func (m *msgServer) DeleteMsgPhysical(
 		return nil, err
 	}
 	remainTime := utils.GetCurrentTimestampBySecond() - req.Timestamp
+	held, err := m.heldConversationIDs(ctx, req.ConversationIDs)
+	if err != nil {
+		return nil, err
+	}
 	for _, conversationID := range req.ConversationIDs {
+		if _, ok := held[conversationID]; ok {
+			log.ZWarn(ctx, "conversation is under legal hold, skip physical delete", nil, "conversationID", conversationID)
+			continue
+		}
 		if err := m.MsgDatabase.DeleteConversationMsgsAndSetMinSeq(ctx, conversationID, remainTime); err != nil {
 			log.ZWarn(
 				ctx,
@@ -164,9 +190,24 @@ func (m *msgServer) clearConversation(
 	if err != nil {
 		return err
 	}
+	// held conversations are only hidden from the user through del_list, their minSeq stays unchanged
+	held, err := m.heldConversationIDs(ctx, existConversationIDs)
+	if err != nil {
+		return err
+	}
+	minSeqs := m.getMinSeqs(maxSeqs)
+	for conversationID := range held {
+		if maxSeq, ok := maxSeqs[conversationID]; ok {
+			log.ZInfo(ctx, "conversation is under legal hold, only delete for the user", "conversationID", conversationID, "maxSeq", maxSeq)
+			if err := m.MsgDatabase.DeleteUserConversationMsgs(ctx, userID, conversationID, maxSeq); err != nil {
+				return err
+			}
+		}
+		delete(minSeqs, conversationID)
+	}
 	isSyncSelf, isSyncOther := m.validateDeleteSyncOpt(deleteSyncOpt)
 	if !isSyncOther {
-		if err := m.MsgDatabase.SetUserConversationsMinSeqs(ctx, userID, m.getMinSeqs(maxSeqs)); err != nil {
+		if err := m.MsgDatabase.SetUserConversationsMinSeqs(ctx, userID, minSeqs); err != nil {
 			return err
 		}
 		// notification 2 self
@@ -182,11 +223,15 @@ func (m *msgServer) clearConversation(
 			)
 		}
 	} else {
-		if err := m.MsgDatabase.SetMinSeqs(ctx, m.getMinSeqs(maxSeqs)); err != nil {
+		if err := m.MsgDatabase.SetMinSeqs(ctx, minSeqs); err != nil {
 			return err
 		}
 		for _, conversation := range existConversations {
 			tips := &sdkws.ClearConversationTips{UserID: userID, ConversationIDs: []string{conversation.ConversationID}}
+			if _, ok := held[conversation.ConversationID]; ok {
+				m.notificationSender.NotificationWithSesstionType(ctx, userID, userID, constant.ClearConversationNotification, constant.SingleChatType, tips)
+				continue
+			}
 			m.notificationSender.NotificationWithSesstionType(ctx, userID, m.conversationAndGetRecvID(conversation, userID), constant.ClearConversationNotification, conversation.ConversationType, tips)
 		}
 	}
@@ -0,0 +1,227 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msg
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/mcontext"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcext"
+)
+
+const (
+	exportPullNum = 100
+	// exportMaxMsgNum caps a single export so the transcript fits in memory.
+	exportMaxMsgNum = 100000
+	exportExpire    = time.Hour * 24 * 7
+	// exportStaleTime marks a running job as failed once it has not reported progress for this long,
+	// e.g. because the msg rpc that ran it restarted.
+	exportStaleTime = time.Minute * 10
+)
+
+func (m *msgServer) CreateExportJob(ctx context.Context, req *rpcext.CreateExportJobReq) (*rpcext.CreateExportJobResp, error) {
+	if err := req.Check(); err != nil {
+		return nil, errs.ErrArgs.Wrap(err.Error())
+	}
+	if err := authverify.CheckAccessV3(ctx, req.UserID); err != nil {
+		return nil, err
+	}
+	if msgprocessor.IsNotification(req.ConversationID) {
+		return nil, errs.ErrArgs.Wrap("notification conversation can not be exported")
+	}
+	conversation, err := m.Conversation.GetConversation(ctx, req.UserID, req.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+	maxSeq, err := m.MsgDatabase.GetMaxSeq(ctx, req.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+	// conversation.MaxSeq is only set once the user has left a group and caps what they can still read.
+	if conversation.MaxSeq > 0 && conversation.MaxSeq < maxSeq {
+		maxSeq = conversation.MaxSeq
+	}
+	id := uuid.New()
+	now := time.Now().UnixMilli()
+	job := &cache.MsgExportJob{
+		JobID:          hex.EncodeToString(id[:]),
+		UserID:         req.UserID,
+		ConversationID: req.ConversationID,
+		Format:         req.Format,
+		StartTime:      req.StartTime,
+		EndTime:        req.EndTime,
+		Status:         rpcext.ExportJobRunning,
+		CreateTime:     now,
+	}
+	if job.EndTime == 0 {
+		job.EndTime = now
+	}
+	if err := m.msgExportDatabase.SetExportJob(ctx, job, exportExpire); err != nil {
+		return nil, err
+	}
+	go m.runExportJob(mcontext.NewCtx(mcontext.GetOperationID(ctx)), job, maxSeq)
+	return &rpcext.CreateExportJobResp{JobID: job.JobID}, nil
+}
+
+func (m *msgServer) GetExportJob(ctx context.Context, req *rpcext.GetExportJobReq) (*rpcext.GetExportJobResp, error) {
+	if err := req.Check(); err != nil {
+		return nil, errs.ErrArgs.Wrap(err.Error())
+	}
+	job, err := m.msgExportDatabase.TakeExportJob(ctx, req.JobID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authverify.CheckAccessV3(ctx, job.UserID); err != nil {
+		return nil, err
+	}
+	if job.Status == rpcext.ExportJobRunning && time.Since(time.UnixMilli(job.UpdateTime)) > exportStaleTime {
+		m.failExportJob(ctx, job, errors.New("export job interrupted"))
+	}
+	return &rpcext.GetExportJobResp{Job: &rpcext.ExportJob{
+		JobID:          job.JobID,
+		UserID:         job.UserID,
+		ConversationID: job.ConversationID,
+		Format:         job.Format,
+		Status:         job.Status,
+		Progress:       job.Progress,
+		MsgNum:         job.MsgNum,
+		URL:            job.URL,
+		ExpireTime:     job.ExpireTime,
+		ErrMsg:         job.ErrMsg,
+		CreateTime:     job.CreateTime,
+	}}, nil
+}
+
+func (m *msgServer) runExportJob(ctx context.Context, job *cache.MsgExportJob, maxSeq int64) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.ZError(ctx, "export job panic", nil, "jobID", job.JobID, "panic", r)
+			m.failExportJob(ctx, job, fmt.Errorf("panic: %v", r))
+		}
+	}()
+	log.ZInfo(ctx, "export job start", "jobID", job.JobID, "userID", job.UserID, "conversationID", job.ConversationID)
+	msgs, err := m.collectExportMsgs(ctx, job, maxSeq)
+	if err != nil {
+		m.failExportJob(ctx, job, err)
+		return
+	}
+	data, contentType, err := renderExport(job, msgs)
+	if err != nil {
+		m.failExportJob(ctx, job, err)
+		return
+	}
+	job.Progress = 95
+	if err := m.msgExportDatabase.SetExportJob(ctx, job, exportExpire); err != nil {
+		m.failExportJob(ctx, job, err)
+		return
+	}
+	name := path.Join(job.UserID, "export", job.JobID+exportFileExt(job.Format))
+	expireTime, url, err := m.msgExportDatabase.UploadExport(ctx, job.UserID, name, contentType, data, exportExpire)
+	if err != nil {
+		m.failExportJob(ctx, job, err)
+		return
+	}
+	job.Status = rpcext.ExportJobSucceeded
+	job.Progress = 100
+	job.MsgNum = int64(len(msgs))
+	job.Name = name
+	job.URL = url
+	job.ExpireTime = expireTime.UnixMilli()
+	if err := m.msgExportDatabase.SetExportJob(ctx, job, exportExpire); err != nil {
+		log.ZError(ctx, "set export job failed", err, "jobID", job.JobID)
+		return
+	}
+	log.ZInfo(ctx, "export job done", "jobID", job.JobID, "msgNum", job.MsgNum, "size", len(data))
+}
+
+func (m *msgServer) failExportJob(ctx context.Context, job *cache.MsgExportJob, err error) {
+	log.ZError(ctx, "export job failed", err, "jobID", job.JobID)
+	job.Status = rpcext.ExportJobFailed
+	job.ErrMsg = err.Error()
+	if err := m.msgExportDatabase.SetExportJob(ctx, job, exportExpire); err != nil {
+		log.ZError(ctx, "set export job failed", err, "jobID", job.JobID)
+	}
+}
+
+// collectExportMsgs pulls the conversation from the newest seq backwards until the messages are older than the start time.
+func (m *msgServer) collectExportMsgs(ctx context.Context, job *cache.MsgExportJob, maxSeq int64) ([]*sdkws.MsgData, error) {
+	msgMap := make(map[int64]*sdkws.MsgData)
+	end := maxSeq
+	var total int64
+	for end > 0 && len(msgMap) < exportMaxMsgNum {
+		minSeq, maxSeq, msgs, err := m.MsgDatabase.GetMsgBySeqsRange(ctx, job.UserID, job.ConversationID, 1, end, exportPullNum, maxSeq)
+		if err != nil {
+			return nil, err
+		}
+		if minSeq == 0 && maxSeq == 0 {
+			break
+		}
+		if total == 0 {
+			total = maxSeq - minSeq + 1
+		}
+		if end > maxSeq {
+			end = maxSeq
+		}
+		next := end - exportPullNum
+		var newest int64
+		for _, msg := range msgs {
+			if msg.Seq-1 < next {
+				next = msg.Seq - 1
+			}
+			if msg.SendTime > newest {
+				newest = msg.SendTime
+			}
+			if msg.SendTime == 0 || msg.SendTime < job.StartTime || msg.SendTime > job.EndTime {
+				continue
+			}
+			msgMap[msg.Seq] = msg
+		}
+		if next < minSeq || (newest != 0 && newest < job.StartTime) {
+			break
+		}
+		end = next
+		if total > 0 {
+			job.Progress = int32((maxSeq - end) * 90 / total)
+		}
+		job.MsgNum = int64(len(msgMap))
+		if err := m.msgExportDatabase.SetExportJob(ctx, job, exportExpire); err != nil {
+			return nil, err
+		}
+	}
+	msgs := make([]*sdkws.MsgData, 0, len(msgMap))
+	for _, msg := range msgMap {
+		msgs = append(msgs, msg)
+	}
+	sort.Slice(msgs, func(i, j int) bool { return msgs[i].Seq < msgs[j].Seq })
+	if len(msgs) > exportMaxMsgNum {
+		msgs = msgs[len(msgs)-exportMaxMsgNum:]
+	}
+	return msgs, nil
+}
@@ -0,0 +1,195 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msg
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"html/template"
+	"strconv"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/errs"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcext"
+)
+
+type exportMsg struct {
+	Seq            int64  `json:"seq"`
+	ServerMsgID    string `json:"serverMsgID"`
+	ClientMsgID    string `json:"clientMsgID"`
+	SendID         string `json:"sendID"`
+	SenderNickname string `json:"senderNickname"`
+	ContentType    int32  `json:"contentType"`
+	SendTime       int64  `json:"sendTime"`
+	Text           string `json:"text"`
+	Content        string `json:"content"`
+}
+
+var exportCSVHeader = []string{"seq", "serverMsgID", "clientMsgID", "sendID", "senderNickname", "contentType", "sendTime", "text", "content"}
+
+func newExportMsg(msg *sdkws.MsgData) *exportMsg {
+	return &exportMsg{
+		Seq:            msg.Seq,
+		ServerMsgID:    msg.ServerMsgID,
+		ClientMsgID:    msg.ClientMsgID,
+		SendID:         msg.SendID,
+		SenderNickname: msg.SenderNickname,
+		ContentType:    msg.ContentType,
+		SendTime:       msg.SendTime,
+		Text:           exportMsgText(msg),
+		Content:        string(msg.Content),
+	}
+}
+
+// exportMsgText the readable text of text and @ messages, other content types are kept as raw content only.
+func exportMsgText(msg *sdkws.MsgData) string {
+	switch msg.ContentType {
+	case constant.Text, constant.AtText:
+		var elem struct {
+			Content string `json:"content"`
+			Text    string `json:"text"`
+		}
+		if err := json.Unmarshal(msg.Content, &elem); err != nil {
+			return ""
+		}
+		if elem.Content != "" {
+			return elem.Content
+		}
+		return elem.Text
+	default:
+		return ""
+	}
+}
+
+func exportFileExt(format string) string {
+	switch format {
+	case rpcext.ExportFormatCSV:
+		return ".csv"
+	case rpcext.ExportFormatHTML:
+		return ".html"
+	default:
+		return ".jsonl"
+	}
+}
+
+// renderExport returns the transcript and its content type.
+func renderExport(job *cache.MsgExportJob, msgs []*sdkws.MsgData) ([]byte, string, error) {
+	switch job.Format {
+	case rpcext.ExportFormatJSONL:
+		data, err := renderExportJSONL(msgs)
+		return data, "application/x-ndjson", err
+	case rpcext.ExportFormatCSV:
+		data, err := renderExportCSV(msgs)
+		return data, "text/csv; charset=utf-8", err
+	case rpcext.ExportFormatHTML:
+		data, err := renderExportHTML(job, msgs)
+		return data, "text/html; charset=utf-8", err
+	default:
+		return nil, "", errs.ErrArgs.Wrap("unknown export format " + job.Format)
+	}
+}
+
+func renderExportJSONL(msgs []*sdkws.MsgData) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, msg := range msgs {
+		if err := enc.Encode(newExportMsg(msg)); err != nil {
+			return nil, errs.Wrap(err)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+func renderExportCSV(msgs []*sdkws.MsgData) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(exportCSVHeader); err != nil {
+		return nil, errs.Wrap(err)
+	}
+	for _, msg := range msgs {
+		m := newExportMsg(msg)
+		record := []string{
+			strconv.FormatInt(m.Seq, 10),
+			m.ServerMsgID,
+			m.ClientMsgID,
+			m.SendID,
+			m.SenderNickname,
+			strconv.Itoa(int(m.ContentType)),
+			time.UnixMilli(m.SendTime).UTC().Format(time.RFC3339),
+			m.Text,
+			m.Content,
+		}
+		if err := w.Write(record); err != nil {
+			return nil, errs.Wrap(err)
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return buf.Bytes(), nil
+}
+
+var exportHTMLTemplate = template.Must(template.New("export").Funcs(template.FuncMap{
+	"time": func(ms int64) string { return time.UnixMilli(ms).UTC().Format("2006-01-02 15:04:05") },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.ConversationID}}</title>
+<style>
+body{font-family:-apple-system,Helvetica,Arial,sans-serif;background:#f5f5f5;margin:0;padding:24px;color:#222}
+h1{font-size:18px;margin:0 0 4px}
+.meta{color:#888;font-size:12px;margin-bottom:16px}
+.msg{background:#fff;border-radius:6px;padding:8px 12px;margin:8px 0}
+.head{font-size:12px;color:#888}
+.name{color:#0064c8;font-weight:600;margin-right:8px}
+.text{white-space:pre-wrap;word-break:break-word;margin-top:4px}
+.raw{font-family:monospace;font-size:12px;color:#555;white-space:pre-wrap;word-break:break-all;margin-top:4px}
+</style>
+</head>
+<body>
+<h1>{{.ConversationID}}</h1>
+<div class="meta">{{time .StartTime}} - {{time .EndTime}} UTC, {{len .Msgs}} messages</div>
+{{range .Msgs}}<div class="msg">
+<div class="head"><span class="name">{{if .SenderNickname}}{{.SenderNickname}}{{else}}{{.SendID}}{{end}}</span>{{time .SendTime}} #{{.Seq}}</div>
+{{if .Text}}<div class="text">{{.Text}}</div>{{else}}<div class="raw">[{{.ContentType}}] {{.Content}}</div>{{end}}
+</div>
+{{end}}</body>
+</html>
+`))
+
+func renderExportHTML(job *cache.MsgExportJob, msgs []*sdkws.MsgData) ([]byte, error) {
+	exportMsgs := make([]*exportMsg, 0, len(msgs))
+	for _, msg := range msgs {
+		exportMsgs = append(exportMsgs, newExportMsg(msg))
+	}
+	var buf bytes.Buffer
+	err := exportHTMLTemplate.Execute(&buf, map[string]any{
+		"ConversationID": job.ConversationID,
+		"StartTime":      job.StartTime,
+		"EndTime":        job.EndTime,
+		"Msgs":           exportMsgs,
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return buf.Bytes(), nil
+}
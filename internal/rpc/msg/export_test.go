@@ -0,0 +1,123 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msg
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/sdkws"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+)
+
+// exportMsgDatabase serves seqs minSeq..len(sendTimes) where message seq i was sent at sendTimes[i-1].
+type exportMsgDatabase struct {
+	controller.CommonMsgDatabase
+	minSeq    int64
+	sendTimes []int64
+}
+
+func (d *exportMsgDatabase) GetMsgBySeqsRange(ctx context.Context, userID string, conversationID string, begin, end, num, userMaxSeq int64) (int64, int64, []*sdkws.MsgData, error) {
+	maxSeq := int64(len(d.sendTimes))
+	if userMaxSeq != 0 && userMaxSeq < maxSeq {
+		maxSeq = userMaxSeq
+	}
+	if begin < d.minSeq {
+		begin = d.minSeq
+	}
+	if end > maxSeq {
+		end = maxSeq
+	}
+	if end < begin {
+		return 0, 0, nil, nil
+	}
+	if end-begin+1 > num {
+		begin = end - num + 1
+	}
+	var msgs []*sdkws.MsgData
+	for seq := begin; seq <= end; seq++ {
+		msgs = append(msgs, &sdkws.MsgData{Seq: seq, SendTime: d.sendTimes[seq-1]})
+	}
+	return d.minSeq, maxSeq, msgs, nil
+}
+
+type exportJobDatabase struct {
+	controller.MsgExportDatabase
+	saves int
+}
+
+func (d *exportJobDatabase) SetExportJob(ctx context.Context, job *cache.MsgExportJob, expire time.Duration) error {
+	d.saves++
+	return nil
+}
+
+func TestCollectExportMsgs(t *testing.T) {
+	sendTimes := func(n int) []int64 {
+		times := make([]int64, n)
+		for i := range times {
+			times[i] = int64(i+1) * 10
+		}
+		return times
+	}
+	tests := []struct {
+		name      string
+		minSeq    int64
+		sendTimes []int64
+		maxSeq    int64
+		startTime int64
+		endTime   int64
+		wantFirst int64
+		wantLast  int64
+		wantNum   int
+	}{
+		{name: "empty conversation", minSeq: 1, sendTimes: nil, maxSeq: 0, endTime: 1000, wantNum: 0},
+		{name: "all messages over several pages", minSeq: 1, sendTimes: sendTimes(250), maxSeq: 250, endTime: 10000, wantFirst: 1, wantLast: 250, wantNum: 250},
+		{name: "capped by max seq", minSeq: 1, sendTimes: sendTimes(250), maxSeq: 120, endTime: 10000, wantFirst: 1, wantLast: 120, wantNum: 120},
+		{name: "cleared below min seq", minSeq: 101, sendTimes: sendTimes(250), maxSeq: 250, endTime: 10000, wantFirst: 101, wantLast: 250, wantNum: 150},
+		{name: "time window", minSeq: 1, sendTimes: sendTimes(250), maxSeq: 250, startTime: 500, endTime: 1500, wantFirst: 50, wantLast: 150, wantNum: 101},
+		{name: "window after the newest message", minSeq: 1, sendTimes: sendTimes(50), maxSeq: 50, startTime: 1000, endTime: 2000, wantNum: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobDB := &exportJobDatabase{}
+			m := &msgServer{
+				MsgDatabase:       &exportMsgDatabase{minSeq: tt.minSeq, sendTimes: tt.sendTimes},
+				msgExportDatabase: jobDB,
+			}
+			job := &cache.MsgExportJob{StartTime: tt.startTime, EndTime: tt.endTime}
+			msgs, err := m.collectExportMsgs(context.Background(), job, tt.maxSeq)
+			if err != nil {
+				t.Fatalf("collectExportMsgs() error = %v", err)
+			}
+			if len(msgs) != tt.wantNum {
+				t.Fatalf("collectExportMsgs() got %d msgs, want %d", len(msgs), tt.wantNum)
+			}
+			if tt.wantNum == 0 {
+				return
+			}
+			if msgs[0].Seq != tt.wantFirst || msgs[len(msgs)-1].Seq != tt.wantLast {
+				t.Errorf("collectExportMsgs() got seqs %d..%d, want %d..%d", msgs[0].Seq, msgs[len(msgs)-1].Seq, tt.wantFirst, tt.wantLast)
+			}
+			for i := 1; i < len(msgs); i++ {
+				if msgs[i].Seq <= msgs[i-1].Seq {
+					t.Fatalf("collectExportMsgs() not sorted at %d", i)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msg
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/OpenIMSDK/tools/utils"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcext"
+)
+
+func (m *msgServer) SetLegalHold(ctx context.Context, req *rpcext.SetLegalHoldReq) (*rpcext.SetLegalHoldResp, error) {
+	if err := req.Check(); err != nil {
+		return nil, errs.ErrArgs.Wrap(err.Error())
+	}
+	if err := authverify.CheckAdmin(ctx); err != nil {
+		return nil, err
+	}
+	hold := &relationtb.LegalHoldModel{
+		TargetType:     req.TargetType,
+		TargetID:       req.TargetID,
+		Reason:         req.Reason,
+		OperatorUserID: mcontext.GetOpUserID(ctx),
+		CreateTime:     time.Now(),
+	}
+	if err := m.legalHoldDatabase.SetLegalHold(ctx, hold); err != nil {
+		return nil, err
+	}
+	log.ZInfo(ctx, "legal hold set", "targetType", req.TargetType, "targetID", req.TargetID, "reason", req.Reason)
+	return &rpcext.SetLegalHoldResp{}, nil
+}
+
+func (m *msgServer) ReleaseLegalHold(ctx context.Context, req *rpcext.ReleaseLegalHoldReq) (*rpcext.ReleaseLegalHoldResp, error) {
+	if err := req.Check(); err != nil {
+		return nil, errs.ErrArgs.Wrap(err.Error())
+	}
+	if err := authverify.CheckAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if err := m.legalHoldDatabase.ReleaseLegalHold(ctx, req.TargetType, req.TargetID, mcontext.GetOpUserID(ctx), req.Reason); err != nil {
+		return nil, err
+	}
+	log.ZInfo(ctx, "legal hold released", "targetType", req.TargetType, "targetID", req.TargetID, "reason", req.Reason)
+	return &rpcext.ReleaseLegalHoldResp{}, nil
+}
+
+func (m *msgServer) SearchLegalHolds(ctx context.Context, req *rpcext.SearchLegalHoldsReq) (*rpcext.SearchLegalHoldsResp, error) {
+	if err := req.Check(); err != nil {
+		return nil, errs.ErrArgs.Wrap(err.Error())
+	}
+	if err := authverify.CheckAdmin(ctx); err != nil {
+		return nil, err
+	}
+	total, holds, err := m.legalHoldDatabase.PageLegalHolds(ctx, req.TargetType, req.Pagination.PageNumber, req.Pagination.ShowNumber)
+	if err != nil {
+		return nil, err
+	}
+	return &rpcext.SearchLegalHoldsResp{
+		Total: int64(total),
+		Holds: utils.Slice(holds, func(hold *relationtb.LegalHoldModel) *rpcext.LegalHold {
+			return &rpcext.LegalHold{
+				TargetType:     hold.TargetType,
+				TargetID:       hold.TargetID,
+				Reason:         hold.Reason,
+				OperatorUserID: hold.OperatorUserID,
+				CreateTime:     hold.CreateTime.UnixMilli(),
+			}
+		}),
+	}, nil
+}
+
+func (m *msgServer) SearchLegalHoldLogs(ctx context.Context, req *rpcext.SearchLegalHoldLogsReq) (*rpcext.SearchLegalHoldLogsResp, error) {
+	if err := req.Check(); err != nil {
+		return nil, errs.ErrArgs.Wrap(err.Error())
+	}
+	if err := authverify.CheckAdmin(ctx); err != nil {
+		return nil, err
+	}
+	total, logs, err := m.legalHoldDatabase.PageLegalHoldLogs(ctx, req.TargetType, req.TargetID, req.Pagination.PageNumber, req.Pagination.ShowNumber)
+	if err != nil {
+		return nil, err
+	}
+	return &rpcext.SearchLegalHoldLogsResp{
+		Total: int64(total),
+		Logs: utils.Slice(logs, func(l *relationtb.LegalHoldLogModel) *rpcext.LegalHoldLog {
+			return &rpcext.LegalHoldLog{
+				ID:             l.ID,
+				TargetType:     l.TargetType,
+				TargetID:       l.TargetID,
+				Action:         l.Action,
+				Reason:         l.Reason,
+				OperatorUserID: l.OperatorUserID,
+				CreateTime:     l.CreateTime.UnixMilli(),
+			}
+		}),
+	}, nil
+}
+
+// heldConversationIDs the conversations of conversationIDs under a legal hold, as a set.
+func (m *msgServer) heldConversationIDs(ctx context.Context, conversationIDs []string) (map[string]struct{}, error) {
+	held, err := m.legalHoldDatabase.FindHeldConversationIDs(ctx, conversationIDs)
+	if err != nil {
+		return nil, err
+	}
+	return utils.SliceSet(held), nil
+}
@@ -0,0 +1,112 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msg
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/OpenIMSDK/tools/utils"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcext"
+)
+
+func convertRetentionPolicy(policy *relationtb.RetentionPolicyModel) *rpcext.RetentionPolicy {
+	return &rpcext.RetentionPolicy{
+		ScopeType:      policy.ScopeType,
+		ScopeID:        policy.ScopeID,
+		RetainDays:     policy.RetainDays,
+		OperatorUserID: policy.OperatorUserID,
+		UpdateTime:     policy.UpdateTime.UnixMilli(),
+	}
+}
+
+func (m *msgServer) SetRetentionPolicy(ctx context.Context, req *rpcext.SetRetentionPolicyReq) (*rpcext.SetRetentionPolicyResp, error) {
+	if err := req.Check(); err != nil {
+		return nil, errs.ErrArgs.Wrap(err.Error())
+	}
+	if err := authverify.CheckAdmin(ctx); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	policy := &relationtb.RetentionPolicyModel{
+		ScopeType:      req.ScopeType,
+		ScopeID:        req.ScopeID,
+		RetainDays:     req.RetainDays,
+		OperatorUserID: mcontext.GetOpUserID(ctx),
+		CreateTime:     now,
+		UpdateTime:     now,
+	}
+	if err := m.retentionDatabase.SetRetentionPolicy(ctx, policy); err != nil {
+		return nil, err
+	}
+	log.ZInfo(ctx, "retention policy set", "scopeType", req.ScopeType, "scopeID", req.ScopeID, "retainDays", req.RetainDays)
+	return &rpcext.SetRetentionPolicyResp{}, nil
+}
+
+func (m *msgServer) DeleteRetentionPolicy(ctx context.Context, req *rpcext.DeleteRetentionPolicyReq) (*rpcext.DeleteRetentionPolicyResp, error) {
+	if err := req.Check(); err != nil {
+		return nil, errs.ErrArgs.Wrap(err.Error())
+	}
+	if err := authverify.CheckAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if err := m.retentionDatabase.DeleteRetentionPolicy(ctx, req.ScopeType, req.ScopeID); err != nil {
+		return nil, err
+	}
+	log.ZInfo(ctx, "retention policy deleted", "scopeType", req.ScopeType, "scopeID", req.ScopeID)
+	return &rpcext.DeleteRetentionPolicyResp{}, nil
+}
+
+func (m *msgServer) SearchRetentionPolicies(ctx context.Context, req *rpcext.SearchRetentionPoliciesReq) (*rpcext.SearchRetentionPoliciesResp, error) {
+	if err := req.Check(); err != nil {
+		return nil, errs.ErrArgs.Wrap(err.Error())
+	}
+	if err := authverify.CheckAdmin(ctx); err != nil {
+		return nil, err
+	}
+	total, policies, err := m.retentionDatabase.PageRetentionPolicies(ctx, req.ScopeType, req.Pagination.PageNumber, req.Pagination.ShowNumber)
+	if err != nil {
+		return nil, err
+	}
+	return &rpcext.SearchRetentionPoliciesResp{
+		Total:    int64(total),
+		Policies: utils.Slice(policies, convertRetentionPolicy),
+	}, nil
+}
+
+func (m *msgServer) GetEffectiveRetentionPolicy(ctx context.Context, req *rpcext.GetEffectiveRetentionPolicyReq) (*rpcext.GetEffectiveRetentionPolicyResp, error) {
+	if err := req.Check(); err != nil {
+		return nil, errs.ErrArgs.Wrap(err.Error())
+	}
+	if err := authverify.CheckAdmin(ctx); err != nil {
+		return nil, err
+	}
+	policies, err := m.retentionDatabase.LoadRetentionPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp := &rpcext.GetEffectiveRetentionPolicyResp{DefaultRetainDays: int32(config.Config.RetainChatRecords)}
+	if policy := policies.Effective(req.ConversationID); policy != nil {
+		resp.Policy = convertRetentionPolicy(policy)
+	}
+	return resp, nil
+}
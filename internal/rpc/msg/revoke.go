@@ -82,15 +82,13 @@ func (m *msgServer) RevokeMsg(ctx context.Context, req *msg.RevokeMsgReq) (*msg.
 				return nil, err
 			}
 			if req.UserID != msgs[0].SendID {
-				switch members[req.UserID].RoleLevel {
-				case constant.GroupOwner:
-				case constant.GroupAdmin:
-					if members[msgs[0].SendID].RoleLevel != constant.GroupOrdinaryUsers {
-						return nil, errs.ErrNoPermission.Wrap("no permission")
-					}
-				default:
+				opMember, sendMember := members[req.UserID], members[msgs[0].SendID]
+				if opMember == nil || sendMember == nil {
 					return nil, errs.ErrNoPermission.Wrap("no permission")
 				}
+				if err := m.groupPermission.CheckOn(ctx, msgs[0].GroupID, opMember.RoleLevel, sendMember.RoleLevel, authverify.GroupPermissionRevokeOther); err != nil {
+					return nil, err
+				}
 			}
 			if member := members[req.UserID]; member != nil {
 				role = member.RoleLevel
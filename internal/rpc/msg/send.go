@@ -68,6 +68,9 @@ func (m *msgServer) sendMsgSuperGroupChat(
 	if err := callbackMsgModify(ctx, req); err != nil {
 		return nil, err
 	}
+	if req.MsgData.ContentType == constant.AtText {
+		m.expandAtTags(ctx, req.MsgData)
+	}
 	err = m.MsgDatabase.MsgToMQ(ctx, utils.GenConversationUniqueKeyForGroup(req.MsgData.GroupID), req.MsgData)
 	if err != nil {
 		return nil, err
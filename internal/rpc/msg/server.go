@@ -24,11 +24,14 @@ import (
 	"github.com/OpenIMSDK/protocol/msg"
 	"github.com/OpenIMSDK/tools/discoveryregistry"
 
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/localcache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/relation"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/unrelation"
 	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcext"
 )
 
 type (
@@ -44,6 +47,11 @@ type (
 		ConversationLocalCache *localcache.ConversationLocalCache
 		Handlers               MessageInterceptorChain
 		notificationSender     *rpcclient.NotificationSender
+		msgExportDatabase      controller.MsgExportDatabase
+		legalHoldDatabase      controller.LegalHoldDatabase
+		retentionDatabase      controller.RetentionPolicyDatabase
+		muteScheduleDatabase   controller.GroupMuteScheduleDatabase
+		groupPermission        *authverify.GroupPermissionChecker
 	}
 )
 
@@ -75,12 +83,41 @@ func Start(client discoveryregistry.SvcDiscoveryRegistry, server *grpc.Server) e
 		return err
 	}
 	cacheModel := cache.NewMsgCacheModel(rdb)
-	msgDocModel := unrelation.NewMsgMongoDriver(mongo.GetDatabase())
+	msgDocModel := unrelation.NewMsgDocModel(mongo.GetDatabase())
 	conversationClient := rpcclient.NewConversationRpcClient(client)
 	userRpcClient := rpcclient.NewUserRpcClient(client)
 	groupRpcClient := rpcclient.NewGroupRpcClient(client)
 	friendRpcClient := rpcclient.NewFriendRpcClient(client)
-	msgDatabase := controller.NewCommonMsgDatabase(msgDocModel, cacheModel)
+	msgArchive, err := controller.InitMsgArchiveDatabase(rdb, mongo.GetDatabase())
+	if err != nil {
+		return err
+	}
+	msgDatabase := controller.NewCommonMsgDatabase(msgDocModel, cacheModel, msgArchive)
+	db, err := relation.NewGormDB()
+	if err != nil {
+		return err
+	}
+	o, err := controller.NewObjectStorage(rdb)
+	if err != nil {
+		return err
+	}
+	s3db := controller.NewS3Database(rdb, o, relation.NewObjectInfo(db))
+	legalHoldDatabase, err := controller.InitLegalHoldDatabase(db)
+	if err != nil {
+		return err
+	}
+	retentionDatabase, err := controller.InitRetentionPolicyDatabase(db)
+	if err != nil {
+		return err
+	}
+	muteScheduleDatabase, err := controller.InitGroupMuteScheduleDatabase(db, rdb)
+	if err != nil {
+		return err
+	}
+	roleDatabase, err := controller.InitGroupRoleDatabase(db, rdb)
+	if err != nil {
+		return err
+	}
 	s := &msgServer{
 		Conversation:           &conversationClient,
 		User:                   &userRpcClient,
@@ -90,10 +127,16 @@ func Start(client discoveryregistry.SvcDiscoveryRegistry, server *grpc.Server) e
 		GroupLocalCache:        localcache.NewGroupLocalCache(&groupRpcClient),
 		ConversationLocalCache: localcache.NewConversationLocalCache(&conversationClient),
 		friend:                 &friendRpcClient,
+		msgExportDatabase:      controller.NewMsgExportDatabase(cache.NewMsgExportCacheRedis(rdb), s3db),
+		legalHoldDatabase:      legalHoldDatabase,
+		retentionDatabase:      retentionDatabase,
+		muteScheduleDatabase:   muteScheduleDatabase,
+		groupPermission:        authverify.NewGroupPermissionChecker(roleDatabase.GetGroupRolePermissions),
 	}
 	s.notificationSender = rpcclient.NewNotificationSender(rpcclient.WithLocalSendMsg(s.SendMsg))
 	s.addInterceptorHandler(MessageHasReadEnabled)
 	msg.RegisterMsgServer(server, s)
+	rpcext.RegisterMsgExtServer(server, s)
 	return nil
 }
 
@@ -26,6 +26,7 @@ import (
 	"github.com/OpenIMSDK/tools/errs"
 	"github.com/OpenIMSDK/tools/utils"
 
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
 )
 
@@ -116,8 +117,23 @@ func (m *msgServer) messageVerification(ctx context.Context, data *msg.SendMsgRe
 			if groupMemberInfo.MuteEndTime >= time.Now().UnixMilli() {
 				return errs.ErrMutedInGroup.Wrap()
 			}
-			if groupInfo.Status == constant.GroupStatusMuted && groupMemberInfo.RoleLevel != constant.GroupAdmin {
-				return errs.ErrMutedGroup.Wrap()
+			// members who may mute others are not muted with the group
+			if m.groupPermission.Check(ctx, data.MsgData.GroupID, groupMemberInfo.RoleLevel, authverify.GroupPermissionMute) != nil {
+				if groupInfo.Status == constant.GroupStatusMuted {
+					return errs.ErrMutedGroup.Wrap()
+				}
+				muted, err := m.muteScheduleDatabase.IsGroupScheduleMuted(ctx, data.MsgData.GroupID, time.Now())
+				if err != nil {
+					return err
+				}
+				if muted {
+					return errs.ErrMutedGroup.Wrap()
+				}
+			}
+			if utils.IsContain(constant.AtAllString, data.MsgData.AtUserIDList) || hasAtTag(data.MsgData.AtUserIDList) {
+				if err := m.groupPermission.Check(ctx, data.MsgData.GroupID, groupMemberInfo.RoleLevel, authverify.GroupPermissionAtAll); err != nil {
+					return err
+				}
 			}
 		}
 		return nil
@@ -20,11 +20,6 @@ import (
 	"net/url"
 	"time"
 
-	"github.com/openimsdk/open-im-server/v3/pkg/common/db/s3"
-	"github.com/openimsdk/open-im-server/v3/pkg/common/db/s3/cos"
-	"github.com/openimsdk/open-im-server/v3/pkg/common/db/s3/minio"
-	"github.com/openimsdk/open-im-server/v3/pkg/common/db/s3/oss"
-
 	"google.golang.org/grpc"
 
 	"github.com/OpenIMSDK/protocol/third"
@@ -37,6 +32,7 @@ import (
 	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
 
 	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcext"
 )
 
 func Start(client discoveryregistry.SvcDiscoveryRegistry, server *grpc.Server) error {
@@ -63,18 +59,7 @@ func Start(client discoveryregistry.SvcDiscoveryRegistry, server *grpc.Server) e
 		return err
 	}
 	// 根据配置文件策略选择 oss 方式
-	enable := config.Config.Object.Enable
-	var o s3.Interface
-	switch config.Config.Object.Enable {
-	case "minio":
-		o, err = minio.NewMinio(cache.NewMinioCache(rdb))
-	case "cos":
-		o, err = cos.NewCos()
-	case "oss":
-		o, err = oss.NewOSS()
-	default:
-		err = fmt.Errorf("invalid object enable: %s", enable)
-	}
+	o, err := controller.NewObjectStorage(rdb)
 	if err != nil {
 		return err
 	}
@@ -84,13 +69,15 @@ func Start(client discoveryregistry.SvcDiscoveryRegistry, server *grpc.Server) e
 	//	}
 	//	return nil
 	//})
-	third.RegisterThirdServer(server, &thirdServer{
+	t := &thirdServer{
 		apiURL:        apiURL,
 		thirdDatabase: controller.NewThirdDatabase(cache.NewMsgCacheModel(rdb), db),
 		userRpcClient: rpcclient.NewUserRpcClient(client),
 		s3dataBase:    controller.NewS3Database(rdb, o, relation.NewObjectInfo(db)),
 		defaultExpire: time.Hour * 24 * 7,
-	})
+	}
+	third.RegisterThirdServer(server, t)
+	rpcext.RegisterThirdExtServer(server, t)
 	return nil
 }
 
@@ -110,6 +97,13 @@ func (t *thirdServer) FcmUpdateToken(ctx context.Context, req *third.FcmUpdateTo
 	return &third.FcmUpdateTokenResp{}, nil
 }
 
+func (t *thirdServer) VoipUpdateToken(ctx context.Context, req *rpcext.VoipUpdateTokenReq) (*rpcext.VoipUpdateTokenResp, error) {
+	if err := t.thirdDatabase.VoipUpdateToken(ctx, req.Account, int(req.PlatformID), req.VoipToken, req.ExpireTime); err != nil {
+		return nil, err
+	}
+	return &rpcext.VoipUpdateTokenResp{}, nil
+}
+
 func (t *thirdServer) SetAppBadge(ctx context.Context, req *third.SetAppBadgeReq) (resp *third.SetAppBadgeResp, err error) {
 	err = t.thirdDatabase.SetAppBadge(ctx, req.UserID, int(req.AppUnreadCount))
 	if err != nil {
@@ -0,0 +1,75 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package user
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/tools/errs"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	tablerelation "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcext"
+)
+
+func (s *userServer) SetDoNotDisturb(ctx context.Context, req *rpcext.SetDoNotDisturbReq) (*rpcext.SetDoNotDisturbResp, error) {
+	if err := authverify.CheckAccessV3(ctx, req.DoNotDisturb.UserID); err != nil {
+		return nil, err
+	}
+	if _, err := s.FindWithError(ctx, []string{req.DoNotDisturb.UserID}); err != nil {
+		return nil, err
+	}
+	dnd := &tablerelation.UserDoNotDisturbModel{
+		UserID:    req.DoNotDisturb.UserID,
+		Enable:    req.DoNotDisturb.Enable,
+		StartTime: req.DoNotDisturb.StartTime,
+		EndTime:   req.DoNotDisturb.EndTime,
+		TimeZone:  req.DoNotDisturb.TimeZone,
+	}
+	if err := s.SetUserDoNotDisturb(ctx, dnd); err != nil {
+		return nil, err
+	}
+	return &rpcext.SetDoNotDisturbResp{}, nil
+}
+
+func (s *userServer) GetDoNotDisturb(ctx context.Context, req *rpcext.GetDoNotDisturbReq) (*rpcext.GetDoNotDisturbResp, error) {
+	if err := req.Check(); err != nil {
+		return nil, errs.ErrArgs.Wrap(err.Error())
+	}
+	for _, userID := range req.UserIDs {
+		if err := authverify.CheckAccessV3(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+	return s.FindDoNotDisturb(ctx, req)
+}
+
+func (s *userServer) FindDoNotDisturb(ctx context.Context, req *rpcext.GetDoNotDisturbReq) (*rpcext.GetDoNotDisturbResp, error) {
+	dnds, err := s.FindUserDoNotDisturb(ctx, req.UserIDs)
+	if err != nil {
+		return nil, err
+	}
+	resp := &rpcext.GetDoNotDisturbResp{DoNotDisturbs: make([]*rpcext.DoNotDisturb, 0, len(dnds))}
+	for _, dnd := range dnds {
+		resp.DoNotDisturbs = append(resp.DoNotDisturbs, &rpcext.DoNotDisturb{
+			UserID:    dnd.UserID,
+			Enable:    dnd.Enable,
+			StartTime: dnd.StartTime,
+			EndTime:   dnd.EndTime,
+			TimeZone:  dnd.TimeZone,
+		})
+	}
+	return resp, nil
+}
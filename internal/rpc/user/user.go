@@ -39,6 +39,7 @@ import (
 	tablerelation "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
 	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
 	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient/notification"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcext"
 
 	pbuser "github.com/OpenIMSDK/protocol/user"
 	"github.com/OpenIMSDK/tools/utils"
@@ -67,7 +68,7 @@ func Start(client registry.SvcDiscoveryRegistry, server *grpc.Server) error {
 	if err != nil {
 		return err
 	}
-	if err := db.AutoMigrate(&tablerelation.UserModel{}); err != nil {
+	if err := db.AutoMigrate(&tablerelation.UserModel{}, &tablerelation.UserDoNotDisturbModel{}); err != nil {
 		return err
 	}
 	users := make([]*tablerelation.UserModel, 0)
@@ -80,7 +81,7 @@ func Start(client registry.SvcDiscoveryRegistry, server *grpc.Server) error {
 	userDB := relation.NewUserGorm(db)
 	cache := cache.NewUserCacheRedis(rdb, userDB, cache.GetDefaultOpt())
 	userMongoDB := unrelation.NewUserMongoDriver(mongo.GetDatabase())
-	database := controller.NewUserDatabase(userDB, cache, tx.NewGorm(db), userMongoDB)
+	database := controller.NewUserDatabase(userDB, relation.NewUserDoNotDisturbGorm(db), cache, tx.NewGorm(db), userMongoDB)
 	friendRpcClient := rpcclient.NewFriendRpcClient(client)
 	groupRpcClient := rpcclient.NewGroupRpcClient(client)
 	msgRpcClient := rpcclient.NewMessageRpcClient(client)
@@ -93,6 +94,7 @@ func Start(client registry.SvcDiscoveryRegistry, server *grpc.Server) error {
 		userNotificationSender:   notification.NewUserNotificationSender(&msgRpcClient, notification.WithUserFunc(database.FindWithError)),
 	}
 	pbuser.RegisterUserServer(server, u)
+	rpcext.RegisterUserExtServer(server, u)
 	return u.UserDatabase.InitOnce(context.Background(), users)
 }
 
@@ -0,0 +1,68 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/OpenIMSDK/tools/utils"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/relation"
+	unrelationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/unrelation"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/unrelation"
+)
+
+// BackfillChatLogs copies every msg stored in mongo into the mysql chat logs with its revoke,
+// the chat logs already mirrored are overwritten. Msgs moved to the archive are not copied.
+func BackfillChatLogs(w io.Writer) error {
+	db, err := relation.NewGormDB()
+	if err != nil {
+		return err
+	}
+	if err := relation.AutoMigrateChatLog(db); err != nil {
+		return err
+	}
+	mongo, err := unrelation.NewMongo()
+	if err != nil {
+		return err
+	}
+	msgDocModel := unrelation.NewMsgDocModel(mongo.GetDatabase())
+	chatLogDatabase := controller.NewChatLogDatabase(relation.NewChatLogGorm(db))
+	ctx := mcontext.NewCtx(utils.GetSelfFuncName())
+	var docNum, msgNum int
+	walkErr := msgDocModel.WalkMsgs(ctx, func(conversationID string, msgs []*unrelationtb.MsgInfoModel) error {
+		if err := chatLogDatabase.SaveChatLogs(ctx, conversationID, msgs); err != nil {
+			return err
+		}
+		docNum++
+		for _, msg := range msgs {
+			if msg != nil && msg.Msg != nil {
+				msgNum++
+			}
+		}
+		if docNum%1000 == 0 {
+			log.ZInfo(ctx, "backfilling chat logs", "docNum", docNum, "msgNum", msgNum)
+		}
+		return nil
+	})
+	if _, err := fmt.Fprintf(w, "backfilled %d msgs of %d docs\n", msgNum, docNum); err != nil {
+		return err
+	}
+	return walkErr
+}
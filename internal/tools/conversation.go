@@ -105,8 +105,16 @@ func (c *MsgTool) ConversationsDestructMsgs() {
 			log.ZError(ctx, "GetConversationsByConversationID failed", err, "conversationIDs", conversationIDs)
 			continue
 		}
+		held, err := c.heldConversationIDs(ctx, conversationIDs)
+		if err != nil {
+			log.ZError(ctx, "find held conversations failed", err, "conversationIDs", conversationIDs)
+			continue
+		}
 		temp := make([]*relation.ConversationModel, 0, len(conversations))
 		for i, conversation := range conversations {
+			if _, ok := held[conversation.ConversationID]; ok {
+				continue
+			}
 			if conversation.IsMsgDestruct && conversation.MsgDestructTime != 0 && (time.Now().Unix() > (conversation.MsgDestructTime+conversation.LatestMsgDestructTime.Unix()+8*60*60)) ||
 				conversation.LatestMsgDestructTime.IsZero() {
 				temp = append(temp, conversations[i])
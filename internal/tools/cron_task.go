@@ -61,6 +61,29 @@ func StartTask() error {
 		panic(err)
 	}
 
+	log.ZInfo(context.Background(), "start groupDirectoryRefresh cron task", "cron config", config.Config.GroupDirectoryRefreshTime)
+	_, err = crontab.AddFunc(config.Config.GroupDirectoryRefreshTime, cronWrapFunc(rdb, "cron_refresh_group_directory", msgTool.RefreshGroupDirectory))
+	if err != nil {
+		log.ZError(context.Background(), "start refreshGroupDirectory cron failed", err)
+		panic(err)
+	}
+
+	log.ZInfo(context.Background(), "start groupMuteSchedule cron task", "cron config", config.Config.GroupMuteScheduleTime)
+	_, err = crontab.AddFunc(config.Config.GroupMuteScheduleTime, cronWrapFunc(rdb, "cron_check_group_mute_schedules", msgTool.CheckGroupMuteSchedules))
+	if err != nil {
+		log.ZError(context.Background(), "start checkGroupMuteSchedules cron failed", err)
+		panic(err)
+	}
+
+	if config.Config.MsgArchive.Enable {
+		log.ZInfo(context.Background(), "start msgArchive cron task", "cron config", config.Config.MsgArchive.CronTime)
+		_, err = crontab.AddFunc(config.Config.MsgArchive.CronTime, cronWrapFunc(rdb, "cron_archive_msgs", msgTool.AllConversationArchiveMsgs))
+		if err != nil {
+			log.ZError(context.Background(), "start allConversationArchiveMsgs cron failed", err)
+			panic(err)
+		}
+	}
+
 	// start crontab
 	crontab.Start()
 
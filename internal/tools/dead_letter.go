@@ -0,0 +1,86 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/utils"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/kafka"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/mq"
+)
+
+// replayDeadLetterGroupID remembers how far the dead letters have been replayed.
+const replayDeadLetterGroupID = "deadLetterReplay"
+
+var errInspectLimit = errors.New("inspect limit reached")
+
+// InspectDeadLetters writes up to limit dead letters to w, oldest first, without marking them replayed.
+func InspectDeadLetters(w io.Writer, limit int) error {
+	var count int
+	err := mq.ReadDeadLetters(config.Config.Kafka.MsgDeadLetter.Topic, "", func(dl *kafka.DeadLetter) error {
+		if limit > 0 && count >= limit {
+			return errInspectLimit
+		}
+		count++
+		seqs := utils.Slice(dl.Msg.MsgData, func(msg *sdkws.MsgData) int64 { return msg.Seq })
+		position := fmt.Sprintf("partition=%d offset=%d", dl.Partition, dl.Offset)
+		if dl.ID != "" {
+			position = "id=" + dl.ID
+		}
+		_, err := fmt.Fprintf(w, "%s stage=%s failedAt=%s conversationID=%s msgs=%d seqs=%v error=%q\n",
+			position, dl.Stage, dl.FailedAt.Format(time.RFC3339), dl.Msg.ConversationID, len(dl.Msg.MsgData), seqs, dl.Error)
+		return err
+	})
+	if err == errInspectLimit {
+		return nil
+	}
+	return err
+}
+
+// ReplayDeadLetters sends every dead letter not replayed yet back to the topic of the stage it failed in
+// and returns how many were replayed.
+func ReplayDeadLetters() (int, error) {
+	toRedis := mq.NewProducer(config.Config.Kafka.LatestMsgToRedis.Topic)
+	toMongo := mq.NewProducer(config.Config.Kafka.MsgToMongo.Topic)
+	var count int
+	err := mq.ReadDeadLetters(config.Config.Kafka.MsgDeadLetter.Topic, replayDeadLetterGroupID, func(dl *kafka.DeadLetter) error {
+		switch dl.Stage {
+		case kafka.DeadLetterStageRedis:
+			// the messages were never given a seq, so they go through msgtransfer again one by one
+			for _, msg := range dl.Msg.MsgData {
+				if _, _, err := toRedis.SendMessage(dl.Ctx, dl.Key, msg); err != nil {
+					return err
+				}
+			}
+		case kafka.DeadLetterStageMongo:
+			if _, _, err := toMongo.SendMessage(dl.Ctx, dl.Key, dl.Msg); err != nil {
+				return err
+			}
+		default:
+			return errs.ErrArgs.Wrap(fmt.Sprintf("unknown dead letter stage %q at partition %d offset %d id %q", dl.Stage, dl.Partition, dl.Offset, dl.ID))
+		}
+		count++
+		return nil
+	})
+	return count, err
+}
@@ -0,0 +1,62 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"time"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/OpenIMSDK/tools/utils"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
+)
+
+// RefreshGroupDirectory sets the activity of every listed group to the send time of its newest message.
+func (c *MsgTool) RefreshGroupDirectory() {
+	ctx := mcontext.NewCtx(utils.GetSelfFuncName())
+	log.ZInfo(ctx, "============================ start refresh group directory cron task ============================")
+	const batchNum = 500
+	var num int
+	for pageNumber := int32(1); ; pageNumber++ {
+		entries, err := c.directoryDatabase.PageGroupDirectory(ctx, pageNumber, batchNum)
+		if err != nil {
+			log.ZError(ctx, "PageGroupDirectory failed", err, "pageNumber", pageNumber)
+			return
+		}
+		for _, entry := range entries {
+			conversationID := msgprocessor.GetConversationIDBySessionType(constant.SuperGroupChatType, entry.GroupID)
+			msg, err := c.msgDatabase.GetNewestMsg(ctx, conversationID)
+			if err != nil || msg.Msg == nil {
+				log.ZDebug(ctx, "GetNewestMsg failed", "err", err, "conversationID", conversationID)
+				continue
+			}
+			activeTime := time.UnixMilli(msg.Msg.SendTime)
+			if activeTime.Equal(entry.ActiveTime) {
+				continue
+			}
+			if err := c.directoryDatabase.UpdateGroupDirectoryActiveTime(ctx, entry.GroupID, activeTime); err != nil {
+				log.ZError(ctx, "UpdateGroupDirectoryActiveTime failed", err, "groupID", entry.GroupID)
+				continue
+			}
+			num++
+		}
+		if len(entries) < batchNum {
+			break
+		}
+	}
+	log.ZInfo(ctx, "============================ refresh group directory cron finished ============================", "updateNum", num)
+}
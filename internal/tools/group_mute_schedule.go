@@ -0,0 +1,112 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/OpenIMSDK/tools/mw/specialerror"
+	"github.com/OpenIMSDK/tools/utils"
+
+	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+// CheckGroupMuteSchedules announces the groups whose scheduled mute starts or ends,
+// the one-off windows that have ended and the schedules of the dismissed groups are removed.
+func (c *MsgTool) CheckGroupMuteSchedules() {
+	ctx := mcontext.NewCtx(utils.GetSelfFuncName())
+	log.ZInfo(ctx, "============================ start check group mute schedules cron task ============================")
+	const batchNum = 500
+	groupSchedules := make(map[string][]*relationtb.GroupMuteScheduleModel)
+	for pageNumber := int32(1); ; pageNumber++ {
+		_, schedules, err := c.muteScheduleDatabase.PageGroupMuteSchedules(ctx, pageNumber, batchNum)
+		if err != nil {
+			log.ZError(ctx, "PageGroupMuteSchedules failed", err, "pageNumber", pageNumber)
+			return
+		}
+		for _, schedule := range schedules {
+			groupSchedules[schedule.GroupID] = append(groupSchedules[schedule.GroupID], schedule)
+		}
+		if len(schedules) < batchNum {
+			break
+		}
+	}
+	now := time.Now()
+	var num int
+	for groupID, schedules := range groupSchedules {
+		changed, err := c.checkGroupMuteSchedules(ctx, groupID, schedules, now)
+		if err != nil {
+			log.ZError(ctx, "checkGroupMuteSchedules failed", err, "groupID", groupID)
+			continue
+		}
+		if changed {
+			num++
+		}
+	}
+	log.ZInfo(ctx, "============================ check group mute schedules cron finished ============================", "changedNum", num)
+}
+
+func (c *MsgTool) checkGroupMuteSchedules(ctx context.Context, groupID string, schedules []*relationtb.GroupMuteScheduleModel, now time.Time) (bool, error) {
+	group, err := c.groupDatabase.TakeGroup(ctx, groupID)
+	if err == nil && group.Status == constant.GroupStatusDismissed || errs.ErrRecordNotFound.Is(specialerror.ErrCode(errs.Unwrap(err))) {
+		return false, c.muteScheduleDatabase.DeleteGroupMuteSchedules(ctx, groupID, utils.Slice(schedules, func(e *relationtb.GroupMuteScheduleModel) string { return e.ID }))
+	}
+	if err != nil {
+		return false, err
+	}
+	var (
+		wasMuted, muted bool
+		opSchedule      *relationtb.GroupMuteScheduleModel
+		expiredIDs      []string
+	)
+	for _, schedule := range schedules {
+		covers := schedule.Covers(now)
+		if schedule.Active {
+			wasMuted = true
+		}
+		if covers {
+			muted = true
+		}
+		if covers != schedule.Active {
+			opSchedule = schedule
+		}
+		if schedule.Expired(now) {
+			expiredIDs = append(expiredIDs, schedule.ID)
+			continue
+		}
+		if covers != schedule.Active {
+			if err := c.muteScheduleDatabase.SetGroupMuteScheduleActive(ctx, groupID, schedule.ID, covers); err != nil {
+				return false, err
+			}
+		}
+	}
+	if err := c.muteScheduleDatabase.DeleteGroupMuteSchedules(ctx, groupID, expiredIDs); err != nil {
+		return false, err
+	}
+	// a group muted by hand stays muted whatever the schedules say
+	if muted == wasMuted || group.Status == constant.GroupStatusMuted {
+		return false, nil
+	}
+	ctx = mcontext.SetOpUserID(ctx, opSchedule.CreatorUserID)
+	if muted {
+		return true, c.groupNotification.GroupMutedNotification(ctx, groupID)
+	}
+	return true, c.groupNotification.GroupCancelMutedNotification(ctx, groupID)
+}
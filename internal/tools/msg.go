@@ -27,6 +27,7 @@ import (
 
 	"math/rand"
 
+	"github.com/OpenIMSDK/protocol/sdkws"
 	"github.com/OpenIMSDK/tools/errs"
 	"github.com/OpenIMSDK/tools/log"
 	"github.com/OpenIMSDK/tools/mcontext"
@@ -45,21 +46,35 @@ import (
 
 type MsgTool struct {
 	msgDatabase           controller.CommonMsgDatabase
+	msgArchive            controller.MsgArchiveDatabase
+	legalHoldDatabase     controller.LegalHoldDatabase
+	retentionDatabase     controller.RetentionPolicyDatabase
 	conversationDatabase  controller.ConversationDatabase
 	userDatabase          controller.UserDatabase
 	groupDatabase         controller.GroupDatabase
+	directoryDatabase     controller.GroupDirectoryDatabase
+	muteScheduleDatabase  controller.GroupMuteScheduleDatabase
 	msgNotificationSender *notification.MsgNotificationSender
+	groupNotification     *notification.GroupNotificationSender
 }
 
-func NewMsgTool(msgDatabase controller.CommonMsgDatabase, userDatabase controller.UserDatabase,
-	groupDatabase controller.GroupDatabase, conversationDatabase controller.ConversationDatabase, msgNotificationSender *notification.MsgNotificationSender,
+func NewMsgTool(msgDatabase controller.CommonMsgDatabase, msgArchive controller.MsgArchiveDatabase, legalHoldDatabase controller.LegalHoldDatabase,
+	retentionDatabase controller.RetentionPolicyDatabase, userDatabase controller.UserDatabase, groupDatabase controller.GroupDatabase, conversationDatabase controller.ConversationDatabase,
+	directoryDatabase controller.GroupDirectoryDatabase, muteScheduleDatabase controller.GroupMuteScheduleDatabase,
+	msgNotificationSender *notification.MsgNotificationSender, groupNotification *notification.GroupNotificationSender,
 ) *MsgTool {
 	return &MsgTool{
 		msgDatabase:           msgDatabase,
+		msgArchive:            msgArchive,
+		legalHoldDatabase:     legalHoldDatabase,
+		retentionDatabase:     retentionDatabase,
 		userDatabase:          userDatabase,
 		groupDatabase:         groupDatabase,
+		directoryDatabase:     directoryDatabase,
+		muteScheduleDatabase:  muteScheduleDatabase,
 		conversationDatabase:  conversationDatabase,
 		msgNotificationSender: msgNotificationSender,
+		groupNotification:     groupNotification,
 	}
 }
 
@@ -87,22 +102,57 @@ func InitMsgTool() (*MsgTool, error) {
 	discov.AddOption(mw.GrpcClient(), grpc.WithTransportCredentials(insecure.NewCredentials()))
 	userDB := relation.NewUserGorm(db)
 	msgDatabase := controller.InitCommonMsgDatabase(rdb, mongo.GetDatabase())
+	msgArchive, err := controller.InitMsgArchiveDatabase(rdb, mongo.GetDatabase())
+	if err != nil {
+		return nil, err
+	}
+	if msgArchive != nil {
+		if err := mongo.CreateMsgArchiveIndex(); err != nil {
+			return nil, err
+		}
+	}
+	legalHoldDatabase, err := controller.InitLegalHoldDatabase(db)
+	if err != nil {
+		return nil, err
+	}
+	retentionDatabase, err := controller.InitRetentionPolicyDatabase(db)
+	if err != nil {
+		return nil, err
+	}
 	userMongoDB := unrelation.NewUserMongoDriver(mongo.GetDatabase())
 	userDatabase := controller.NewUserDatabase(
 		userDB,
+		relation.NewUserDoNotDisturbGorm(db),
 		cache.NewUserCacheRedis(rdb, relation.NewUserGorm(db), cache.GetDefaultOpt()),
 		tx.NewGorm(db),
 		userMongoDB,
 	)
 	groupDatabase := controller.InitGroupDatabase(db, rdb, mongo.GetDatabase(), nil)
+	directoryDatabase, err := controller.InitGroupDirectoryDatabase(db)
+	if err != nil {
+		return nil, err
+	}
+	muteScheduleDatabase, err := controller.InitGroupMuteScheduleDatabase(db, rdb)
+	if err != nil {
+		return nil, err
+	}
 	conversationDatabase := controller.NewConversationDatabase(
 		relation.NewConversationGorm(db),
 		cache.NewConversationRedis(rdb, cache.GetDefaultOpt(), relation.NewConversationGorm(db)),
 		tx.NewGorm(db),
 	)
 	msgRpcClient := rpcclient.NewMessageRpcClient(discov)
+	userRpcClient := rpcclient.NewUserRpcClient(discov)
 	msgNotificationSender := notification.NewMsgNotificationSender(rpcclient.WithRpcClient(&msgRpcClient))
-	msgTool := NewMsgTool(msgDatabase, userDatabase, groupDatabase, conversationDatabase, msgNotificationSender)
+	groupNotification := notification.NewGroupNotificationSender(groupDatabase, &msgRpcClient, &userRpcClient, func(ctx context.Context, userIDs []string) ([]notification.CommonUser, error) {
+		users, err := userRpcClient.GetUsersInfo(ctx, userIDs)
+		if err != nil {
+			return nil, err
+		}
+		return utils.Slice(users, func(e *sdkws.UserInfo) notification.CommonUser { return e }), nil
+	})
+	msgTool := NewMsgTool(msgDatabase, msgArchive, legalHoldDatabase, retentionDatabase, userDatabase, groupDatabase, conversationDatabase,
+		directoryDatabase, muteScheduleDatabase, msgNotificationSender, groupNotification)
 	return msgTool, nil
 }
 
@@ -134,6 +184,11 @@ func (c *MsgTool) AllConversationClearMsgAndFixSeq() {
 	if num == 0 {
 		return
 	}
+	policies, err := c.retentionDatabase.LoadRetentionPolicies(ctx)
+	if err != nil {
+		log.ZError(ctx, "LoadRetentionPolicies failed", err)
+		return
+	}
 	count := int(num/batchNum + num/batchNum/2)
 	if count < 1 {
 		count = 1
@@ -153,15 +208,36 @@ func (c *MsgTool) AllConversationClearMsgAndFixSeq() {
 		if len(conversationIDs) == 0 {
 			continue
 		}
-		c.ClearConversationsMsg(ctx, conversationIDs)
+		for _, conversationID := range conversationIDs {
+			conversationIDs = append(conversationIDs, utils.GetNotificationConversationIDByConversationID(conversationID))
+		}
+		c.ClearConversationsMsg(ctx, conversationIDs, policies)
 	}
 	log.ZInfo(ctx, "============================ start del cron finished ============================")
 }
 
-func (c *MsgTool) ClearConversationsMsg(ctx context.Context, conversationIDs []string) {
+// ClearConversationsMsg deletes the messages older than the effective retention policy of each conversation,
+// the global retainChatRecords applies when no policy covers the conversation.
+func (c *MsgTool) ClearConversationsMsg(ctx context.Context, conversationIDs []string, policies *controller.RetentionPolicies) {
+	held, err := c.heldConversationIDs(ctx, conversationIDs)
+	if err != nil {
+		log.ZError(ctx, "find held conversations failed", err, "conversationIDs", conversationIDs)
+		return
+	}
 	for _, conversationID := range conversationIDs {
-		if err := c.msgDatabase.DeleteConversationMsgsAndSetMinSeq(ctx, conversationID, int64(config.Config.RetainChatRecords*24*60*60)); err != nil {
-			log.ZError(ctx, "DeleteUserSuperGroupMsgsAndSetMinSeq failed", err, "conversationID", conversationID, "DBRetainChatRecords", config.Config.RetainChatRecords)
+		if _, ok := held[conversationID]; ok {
+			log.ZInfo(ctx, "conversation is under legal hold, skip clear msg", "conversationID", conversationID)
+			continue
+		}
+		// a policy of 0 days keeps the messages forever
+		if policy := policies.Effective(conversationID); policy == nil || policy.RetainDays > 0 {
+			retainDays := int64(config.Config.RetainChatRecords)
+			if policy != nil {
+				retainDays = int64(policy.RetainDays)
+			}
+			if err := c.msgDatabase.DeleteConversationMsgsAndSetMinSeq(ctx, conversationID, retainDays*24*60*60); err != nil {
+				log.ZError(ctx, "DeleteUserSuperGroupMsgsAndSetMinSeq failed", err, "conversationID", conversationID, "retainDays", retainDays)
+			}
 		}
 		if err := c.checkMaxSeq(ctx, conversationID); err != nil {
 			log.ZError(ctx, "fixSeq failed", err, "conversationID", conversationID)
@@ -169,6 +245,15 @@ func (c *MsgTool) ClearConversationsMsg(ctx context.Context, conversationIDs []s
 	}
 }
 
+// heldConversationIDs the conversations of conversationIDs under a legal hold, as a set.
+func (c *MsgTool) heldConversationIDs(ctx context.Context, conversationIDs []string) (map[string]struct{}, error) {
+	held, err := c.legalHoldDatabase.FindHeldConversationIDs(ctx, conversationIDs)
+	if err != nil {
+		return nil, err
+	}
+	return utils.SliceSet(held), nil
+}
+
 func (c *MsgTool) checkMaxSeqWithMongo(ctx context.Context, conversationID string, maxSeqCache int64) error {
 	minSeqMongo, maxSeqMongo, err := c.msgDatabase.GetMongoMaxAndMinSeq(ctx, conversationID)
 	if err != nil {
@@ -0,0 +1,72 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/OpenIMSDK/tools/utils"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+)
+
+func (c *MsgTool) AllConversationArchiveMsgs() {
+	ctx := mcontext.NewCtx(utils.GetSelfFuncName())
+	log.ZInfo(ctx, "============================ start archive cron task ============================")
+	before := time.Now().AddDate(0, 0, -config.Config.MsgArchive.ArchiveDays)
+	const batchNum = 500
+	var docNum int
+	for pageNumber := int32(1); ; pageNumber++ {
+		conversationIDs, err := c.conversationDatabase.PageConversationIDs(ctx, pageNumber, batchNum)
+		if err != nil {
+			log.ZError(ctx, "PageConversationIDs failed", err, "pageNumber", pageNumber)
+			return
+		}
+		for _, conversationID := range conversationIDs {
+			for _, id := range []string{conversationID, utils.GetNotificationConversationIDByConversationID(conversationID)} {
+				num, err := c.archiveConversationMsgs(ctx, id, before)
+				if err != nil {
+					log.ZError(ctx, "archiveConversationMsgs failed", err, "conversationID", id)
+				}
+				docNum += num
+			}
+		}
+		if len(conversationIDs) < batchNum {
+			break
+		}
+	}
+	log.ZInfo(ctx, "============================ archive cron finished ============================", "docNum", docNum)
+}
+
+func (c *MsgTool) archiveConversationMsgs(ctx context.Context, conversationID string, before time.Time) (int, error) {
+	maxSeq, err := c.msgDatabase.GetMaxSeq(ctx, conversationID)
+	if err != nil {
+		if errs.Unwrap(err) == redis.Nil {
+			return 0, nil
+		}
+		return 0, err
+	}
+	minSeq, err := c.msgDatabase.GetMinSeq(ctx, conversationID)
+	if err != nil && errs.Unwrap(err) != redis.Nil {
+		return 0, err
+	}
+	return c.msgArchive.ArchiveConversationMsgs(ctx, conversationID, minSeq, maxSeq, before)
+}
@@ -0,0 +1,43 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/OpenIMSDK/tools/utils"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/unrelation"
+)
+
+// MigrateMsgLayout copies the msgs of the array layout into the single layout, the msg docs are left as they are.
+// Set mongo.msgLayout to single once it is done, msgs stored in between are copied by running it again.
+func MigrateMsgLayout(w io.Writer) error {
+	mongo, err := unrelation.NewMongo()
+	if err != nil {
+		return err
+	}
+	if err := mongo.CreateMsgSingleIndex(); err != nil {
+		return err
+	}
+	ctx := mcontext.NewCtx(utils.GetSelfFuncName())
+	docNum, msgNum, copyErr := unrelation.CopyMsgDocsToSingle(ctx, mongo.GetDatabase())
+	if _, err := fmt.Fprintf(w, "copied %d msgs of %d docs\n", msgNum, docNum); err != nil {
+		return err
+	}
+	return copyErr
+}
@@ -0,0 +1,105 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authverify
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/tools/errs"
+)
+
+// Group permissions, a custom group role holds a bitmap of them.
+const (
+	GroupPermissionKick        int64 = 1 << 0
+	GroupPermissionMute        int64 = 1 << 1
+	GroupPermissionEditInfo    int64 = 1 << 2
+	GroupPermissionPin         int64 = 1 << 3
+	GroupPermissionRevokeOther int64 = 1 << 4
+	GroupPermissionApproveJoin int64 = 1 << 5
+	GroupPermissionAtAll       int64 = 1 << 6
+	// GroupPermissionViewAuditLog reads the group audit log.
+	GroupPermissionViewAuditLog int64 = 1 << 7
+
+	GroupPermissionAll = GroupPermissionKick | GroupPermissionMute | GroupPermissionEditInfo | GroupPermissionPin |
+		GroupPermissionRevokeOther | GroupPermissionApproveJoin | GroupPermissionAtAll | GroupPermissionViewAuditLog
+)
+
+// Custom role levels rank between ordinary members and admins.
+const (
+	GroupCustomRoleMinLevel = constant.GroupOrdinaryUsers + 1
+	GroupCustomRoleMaxLevel = constant.GroupAdmin - 1
+)
+
+func IsGroupCustomRole(roleLevel int32) bool {
+	return roleLevel >= GroupCustomRoleMinLevel && roleLevel <= GroupCustomRoleMaxLevel
+}
+
+// GroupRoleFinder returns the permissions of a custom role of the group.
+type GroupRoleFinder func(ctx context.Context, groupID string, roleLevel int32) (permissions int64, err error)
+
+// GroupPermissionChecker is the one place deciding what a group member may do.
+// Owners and admins hold every permission, ordinary members may only @all.
+// A custom role ranks above ordinary members, so it holds @all on top of its own permissions.
+type GroupPermissionChecker struct {
+	find GroupRoleFinder
+}
+
+func NewGroupPermissionChecker(find GroupRoleFinder) *GroupPermissionChecker {
+	return &GroupPermissionChecker{find: find}
+}
+
+func (c *GroupPermissionChecker) Permissions(ctx context.Context, groupID string, roleLevel int32) (int64, error) {
+	switch {
+	case roleLevel == constant.GroupOwner, roleLevel == constant.GroupAdmin:
+		return GroupPermissionAll, nil
+	case roleLevel == constant.GroupOrdinaryUsers:
+		return GroupPermissionAtAll, nil
+	case IsGroupCustomRole(roleLevel):
+		permissions, err := c.find(ctx, groupID, roleLevel)
+		if err != nil {
+			return 0, err
+		}
+		return permissions | GroupPermissionAtAll, nil
+	default:
+		return 0, nil
+	}
+}
+
+// Check the role level holds all bits of permission.
+func (c *GroupPermissionChecker) Check(ctx context.Context, groupID string, roleLevel int32, permission int64) error {
+	permissions, err := c.Permissions(ctx, groupID, roleLevel)
+	if err != nil {
+		return err
+	}
+	if permissions&permission != permission {
+		return errs.ErrNoPermission.Wrap(fmt.Sprintf("role level %d lacks group permission %d", roleLevel, permission))
+	}
+	return nil
+}
+
+// GroupRoleOutranks a member only acts on the members ranking below it.
+func GroupRoleOutranks(opRoleLevel int32, targetRoleLevel int32) bool {
+	return opRoleLevel > targetRoleLevel
+}
+
+// CheckOn is Check for an action on another member, who must rank below the operator.
+func (c *GroupPermissionChecker) CheckOn(ctx context.Context, groupID string, opRoleLevel int32, targetRoleLevel int32, permission int64) error {
+	if !GroupRoleOutranks(opRoleLevel, targetRoleLevel) {
+		return errs.ErrNoPermission.Wrap(fmt.Sprintf("role level %d can not manage role level %d", opRoleLevel, targetRoleLevel))
+	}
+	return c.Check(ctx, groupID, opRoleLevel, permission)
+}
@@ -0,0 +1,113 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authverify
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/OpenIMSDK/protocol/constant"
+)
+
+func TestGroupPermissionCheckerCheckOn(t *testing.T) {
+	const (
+		moderator = GroupCustomRoleMinLevel + 10
+		helper    = GroupCustomRoleMinLevel
+	)
+	errFind := errors.New("find role failed")
+	checker := NewGroupPermissionChecker(func(ctx context.Context, groupID string, roleLevel int32) (int64, error) {
+		switch roleLevel {
+		case moderator:
+			return GroupPermissionKick | GroupPermissionMute, nil
+		case helper:
+			return GroupPermissionMute, nil
+		default:
+			return 0, errFind
+		}
+	})
+	tests := []struct {
+		name       string
+		op         int32
+		target     int32
+		permission int64
+		wantErr    bool
+	}{
+		{name: "owner on admin", op: constant.GroupOwner, target: constant.GroupAdmin, permission: GroupPermissionKick},
+		{name: "admin on member", op: constant.GroupAdmin, target: constant.GroupOrdinaryUsers, permission: GroupPermissionRevokeOther},
+		{name: "admin on admin", op: constant.GroupAdmin, target: constant.GroupAdmin, permission: GroupPermissionKick, wantErr: true},
+		{name: "admin on owner", op: constant.GroupAdmin, target: constant.GroupOwner, permission: GroupPermissionKick, wantErr: true},
+		{name: "custom role holding the bit", op: moderator, target: constant.GroupOrdinaryUsers, permission: GroupPermissionKick},
+		{name: "custom role holding all bits", op: moderator, target: helper, permission: GroupPermissionKick | GroupPermissionMute},
+		{name: "custom role lacking the bit", op: helper, target: constant.GroupOrdinaryUsers, permission: GroupPermissionKick, wantErr: true},
+		{name: "custom role lacking one of the bits", op: helper, target: constant.GroupOrdinaryUsers, permission: GroupPermissionKick | GroupPermissionMute, wantErr: true},
+		{name: "custom role on a higher custom role", op: helper, target: moderator, permission: GroupPermissionMute, wantErr: true},
+		{name: "custom role on admin", op: moderator, target: constant.GroupAdmin, permission: GroupPermissionKick, wantErr: true},
+		{name: "member on member", op: constant.GroupOrdinaryUsers, target: constant.GroupOrdinaryUsers, permission: GroupPermissionAtAll, wantErr: true},
+		{name: "member on nobody", op: constant.GroupOrdinaryUsers, target: 0, permission: GroupPermissionKick, wantErr: true},
+		{name: "unknown custom role", op: moderator + 1, target: constant.GroupOrdinaryUsers, permission: GroupPermissionKick, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checker.CheckOn(context.Background(), "g1", tt.op, tt.target, tt.permission)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckOn(%d, %d, %d) error = %v, wantErr %v", tt.op, tt.target, tt.permission, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGroupPermissionCheckerPermissions(t *testing.T) {
+	const (
+		moderator = GroupCustomRoleMinLevel + 10
+		removed   = GroupCustomRoleMinLevel
+	)
+	errFind := errors.New("find role failed")
+	checker := NewGroupPermissionChecker(func(ctx context.Context, groupID string, roleLevel int32) (int64, error) {
+		switch roleLevel {
+		case moderator:
+			return GroupPermissionKick, nil
+		case removed:
+			return 0, nil
+		default:
+			return 0, errFind
+		}
+	})
+	tests := []struct {
+		name      string
+		roleLevel int32
+		want      int64
+		wantErr   bool
+	}{
+		{name: "owner", roleLevel: constant.GroupOwner, want: GroupPermissionAll},
+		{name: "admin", roleLevel: constant.GroupAdmin, want: GroupPermissionAll},
+		{name: "member", roleLevel: constant.GroupOrdinaryUsers, want: GroupPermissionAtAll},
+		{name: "custom role keeps @all of members", roleLevel: moderator, want: GroupPermissionKick | GroupPermissionAtAll},
+		{name: "removed custom role keeps @all of members", roleLevel: removed, want: GroupPermissionAtAll},
+		{name: "custom role lookup failed", roleLevel: moderator + 1, wantErr: true},
+		{name: "not a member", roleLevel: 0, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := checker.Permissions(context.Background(), "g1", tt.roleLevel)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Permissions(%d) error = %v, wantErr %v", tt.roleLevel, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Permissions(%d) = %d, want %d", tt.roleLevel, got, tt.want)
+			}
+		})
+	}
+}
@@ -15,9 +15,15 @@
 package cmd
 
 import (
+	"fmt"
+	"os"
+
 	"github.com/spf13/cobra"
 
+	"github.com/OpenIMSDK/protocol/constant"
+
 	"github.com/openimsdk/open-im-server/v3/internal/tools"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
 )
 
 type MsgUtilsCmd struct {
@@ -79,6 +85,15 @@ func (m *MsgUtilsCmd) getLimitFlag(cmdLines *cobra.Command) int64 {
 	return limit
 }
 
+func (m *MsgUtilsCmd) AddConfigFlag() {
+	m.Command.PersistentFlags().StringP(constant.FlagConf, "c", "", "path to config file folder")
+}
+
+func (m *MsgUtilsCmd) initConfig(cmdLines *cobra.Command) error {
+	configFolderPath, _ := cmdLines.Flags().GetString(constant.FlagConf)
+	return config.InitConfig(configFolderPath)
+}
+
 func (m *MsgUtilsCmd) Execute() error {
 	return m.Command.Execute()
 }
@@ -123,6 +138,46 @@ func NewClearCmd() *ClearCmd {
 	}
 }
 
+type InspectCmd struct {
+	*MsgUtilsCmd
+}
+
+func NewInspectCmd() *InspectCmd {
+	return &InspectCmd{
+		NewMsgUtilsCmd("inspect [resource]", "inspect action", cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs)),
+	}
+}
+
+type ReplayCmd struct {
+	*MsgUtilsCmd
+}
+
+func NewReplayCmd() *ReplayCmd {
+	return &ReplayCmd{
+		NewMsgUtilsCmd("replay [resource]", "replay action", cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs)),
+	}
+}
+
+type MigrateCmd struct {
+	*MsgUtilsCmd
+}
+
+func NewMigrateCmd() *MigrateCmd {
+	return &MigrateCmd{
+		NewMsgUtilsCmd("migrate [resource]", "migrate action", cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs)),
+	}
+}
+
+type BackfillCmd struct {
+	*MsgUtilsCmd
+}
+
+func NewBackfillCmd() *BackfillCmd {
+	return &BackfillCmd{
+		NewMsgUtilsCmd("backfill [resource]", "backfill action", cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs)),
+	}
+}
+
 type SeqCmd struct {
 	*MsgUtilsCmd
 }
@@ -183,3 +238,83 @@ func (m *MsgCmd) GetMsgCmd() *cobra.Command {
 func (m *MsgCmd) ClearMsgCmd() *cobra.Command {
 	return &m.Command
 }
+
+type DeadLetterCmd struct {
+	*MsgUtilsCmd
+}
+
+func NewDeadLetterCmd() *DeadLetterCmd {
+	return &DeadLetterCmd{
+		NewMsgUtilsCmd("deadLetter", "message batches that failed to be stored", nil),
+	}
+}
+
+func (d *DeadLetterCmd) InspectDeadLetterCmd() *cobra.Command {
+	d.Command.Run = func(cmdLines *cobra.Command, args []string) {
+		if err := d.initConfig(cmdLines); err != nil {
+			panic(err)
+		}
+		if err := tools.InspectDeadLetters(os.Stdout, int(d.getLimitFlag(cmdLines))); err != nil {
+			panic(err)
+		}
+	}
+	return &d.Command
+}
+
+func (d *DeadLetterCmd) ReplayDeadLetterCmd() *cobra.Command {
+	d.Command.Run = func(cmdLines *cobra.Command, args []string) {
+		if err := d.initConfig(cmdLines); err != nil {
+			panic(err)
+		}
+		count, err := tools.ReplayDeadLetters()
+		if err != nil {
+			panic(err)
+		}
+		fmt.Printf("replayed %d dead letters\n", count)
+	}
+	return &d.Command
+}
+
+type MsgLayoutCmd struct {
+	*MsgUtilsCmd
+}
+
+func NewMsgLayoutCmd() *MsgLayoutCmd {
+	return &MsgLayoutCmd{
+		NewMsgUtilsCmd("msgLayout", "copy the msgs of the array layout into the single layout", nil),
+	}
+}
+
+func (l *MsgLayoutCmd) MigrateMsgLayoutCmd() *cobra.Command {
+	l.Command.Run = func(cmdLines *cobra.Command, args []string) {
+		if err := l.initConfig(cmdLines); err != nil {
+			panic(err)
+		}
+		if err := tools.MigrateMsgLayout(os.Stdout); err != nil {
+			panic(err)
+		}
+	}
+	return &l.Command
+}
+
+type ChatLogCmd struct {
+	*MsgUtilsCmd
+}
+
+func NewChatLogCmd() *ChatLogCmd {
+	return &ChatLogCmd{
+		NewMsgUtilsCmd("chatLog", "copy the msgs stored in mongo into the mysql chat logs", nil),
+	}
+}
+
+func (c *ChatLogCmd) BackfillChatLogCmd() *cobra.Command {
+	c.Command.Run = func(cmdLines *cobra.Command, args []string) {
+		if err := c.initConfig(cmdLines); err != nil {
+			panic(err)
+		}
+		if err := tools.BackfillChatLogs(os.Stdout); err != nil {
+			panic(err)
+		}
+	}
+	return &c.Command
+}
@@ -75,6 +75,7 @@ type configStruct struct {
 		Username    string   `yaml:"username"`
 		Password    string   `yaml:"password"`
 		MaxPoolSize int      `yaml:"maxPoolSize"`
+		MsgLayout   string   `yaml:"msgLayout"`
 	} `yaml:"mongo"`
 
 	Redis struct {
@@ -107,6 +108,12 @@ type configStruct struct {
 		MsgToPush struct {
 			Topic string `yaml:"topic"`
 		} `yaml:"msgToPush"`
+		MsgDeadLetter struct {
+			Topic string `yaml:"topic"`
+		} `yaml:"msgDeadLetter"`
+		MsgToModify struct {
+			Topic string `yaml:"topic"`
+		} `yaml:"msgToModify"`
 		ConsumerGroupID struct {
 			MsgToRedis string `yaml:"msgToRedis"`
 			MsgToMongo string `yaml:"msgToMongo"`
@@ -115,6 +122,13 @@ type configStruct struct {
 		} `yaml:"consumerGroupID"`
 	} `yaml:"kafka"`
 
+	MQ struct {
+		Type  string `yaml:"type"`
+		Redis struct {
+			MaxLen int64 `yaml:"maxLen"`
+		} `yaml:"redis"`
+	} `yaml:"mq"`
+
 	Rpc struct {
 		RegisterIP string `yaml:"registerIP"`
 		ListenIP   string `yaml:"listenIP"`
@@ -219,6 +233,30 @@ type configStruct struct {
 			PushUrl      string `yaml:"pushUrl"`
 			PushIntent   string `yaml:"pushIntent"`
 		} `yaml:"jpns"`
+		DoNotDisturb struct {
+			Enable       bool   `yaml:"enable"`
+			Mode         string `yaml:"mode"`
+			AtBypass     bool   `yaml:"atBypass"`
+			SignalBypass bool   `yaml:"signalBypass"`
+		} `yaml:"doNotDisturb"`
+		Coalesce struct {
+			Enable bool `yaml:"enable"`
+			Window int  `yaml:"window"`
+		} `yaml:"coalesce"`
+		Audit struct {
+			Enable    bool `yaml:"enable"`
+			Retention int  `yaml:"retention"`
+		} `yaml:"audit"`
+		Voip struct {
+			Enable bool `yaml:"enable"`
+			TTL    int  `yaml:"ttl"`
+			Apns   struct {
+				KeyFile  string `yaml:"keyFile"`
+				KeyID    string `yaml:"keyID"`
+				TeamID   string `yaml:"teamID"`
+				BundleID string `yaml:"bundleID"`
+			} `yaml:"apns"`
+		} `yaml:"voip"`
 	}
 	Manager struct {
 		UserID   []string `yaml:"userID"`
@@ -233,6 +271,9 @@ type configStruct struct {
 	RetainChatRecords                 int    `yaml:"retainChatRecords"`
 	ChatRecordsClearTime              string `yaml:"chatRecordsClearTime"`
 	MsgDestructTime                   string `yaml:"msgDestructTime"`
+	GroupDirectoryRefreshTime         string `yaml:"groupDirectoryRefreshTime"`
+	GroupMuteScheduleTime             string `yaml:"groupMuteScheduleTime"`
+	GroupOwnerSuccession              string `yaml:"groupOwnerSuccession"`
 	Secret                            string `yaml:"secret"`
 	EnableCronLocker                  bool   `yaml:"enableCronLocker"`
 	TokenPolicy                       struct {
@@ -241,6 +282,18 @@ type configStruct struct {
 	MessageVerify struct {
 		FriendVerify *bool `yaml:"friendVerify"`
 	} `yaml:"messageVerify"`
+	MsgTransfer struct {
+		WorkerNum        int `yaml:"workerNum"`
+		MaxBatchSize     int `yaml:"maxBatchSize"`
+		MaxPendingMsgs   int `yaml:"maxPendingMsgs"`
+		SeqDedupeTimeout int `yaml:"seqDedupeTimeout"`
+	} `yaml:"msgTransfer"`
+	MsgArchive struct {
+		Enable      bool   `yaml:"enable"`
+		CronTime    string `yaml:"cronTime"`
+		ArchiveDays int    `yaml:"archiveDays"`
+		CacheExpire int    `yaml:"cacheExpire"`
+	} `yaml:"msgArchive"`
 
 	IOSPush struct {
 		PushSound  string `yaml:"pushSound"`
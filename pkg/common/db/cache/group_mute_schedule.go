@@ -0,0 +1,81 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/dtm-labs/rockscache"
+	"github.com/redis/go-redis/v9"
+
+	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+const groupMuteSchedulesKey = "GROUP_MUTE_SCHEDULES:"
+
+// GroupMuteScheduleCache the mute schedules of a group, read on every group msg sent.
+type GroupMuteScheduleCache interface {
+	metaCache
+	NewCache() GroupMuteScheduleCache
+	GetGroupMuteSchedules(ctx context.Context, groupID string) ([]*relationtb.GroupMuteScheduleModel, error)
+	DelGroupMuteSchedules(groupIDs ...string) GroupMuteScheduleCache
+}
+
+func NewGroupMuteScheduleCacheRedis(rdb redis.UniversalClient, scheduleDB relationtb.GroupMuteScheduleModelInterface) GroupMuteScheduleCache {
+	rcClient := rockscache.NewClient(rdb, rockscache.NewDefaultOptions())
+	return &groupMuteScheduleCacheRedis{
+		rcClient:   rcClient,
+		expireTime: time.Hour * 12,
+		scheduleDB: scheduleDB,
+		metaCache:  NewMetaCacheRedis(rcClient),
+	}
+}
+
+type groupMuteScheduleCacheRedis struct {
+	metaCache
+	scheduleDB relationtb.GroupMuteScheduleModelInterface
+	rcClient   *rockscache.Client
+	expireTime time.Duration
+}
+
+func (g *groupMuteScheduleCacheRedis) NewCache() GroupMuteScheduleCache {
+	return &groupMuteScheduleCacheRedis{
+		rcClient:   g.rcClient,
+		expireTime: g.expireTime,
+		scheduleDB: g.scheduleDB,
+		metaCache:  NewMetaCacheRedis(g.rcClient, g.metaCache.GetPreDelKeys()...),
+	}
+}
+
+func (g *groupMuteScheduleCacheRedis) getGroupMuteSchedulesKey(groupID string) string {
+	return groupMuteSchedulesKey + groupID
+}
+
+func (g *groupMuteScheduleCacheRedis) GetGroupMuteSchedules(ctx context.Context, groupID string) ([]*relationtb.GroupMuteScheduleModel, error) {
+	return getCache(ctx, g.rcClient, g.getGroupMuteSchedulesKey(groupID), g.expireTime, func(ctx context.Context) ([]*relationtb.GroupMuteScheduleModel, error) {
+		return g.scheduleDB.FindByGroupID(ctx, groupID)
+	})
+}
+
+func (g *groupMuteScheduleCacheRedis) DelGroupMuteSchedules(groupIDs ...string) GroupMuteScheduleCache {
+	cache := g.NewCache()
+	keys := make([]string, 0, len(groupIDs))
+	for _, groupID := range groupIDs {
+		keys = append(keys, g.getGroupMuteSchedulesKey(groupID))
+	}
+	cache.AddKeys(keys...)
+	return cache
+}
@@ -0,0 +1,81 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/dtm-labs/rockscache"
+	"github.com/redis/go-redis/v9"
+
+	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+const groupRolesKey = "GROUP_ROLES:"
+
+// GroupRoleCache the custom roles of a group, read when a member of a custom role sends @all.
+type GroupRoleCache interface {
+	metaCache
+	NewCache() GroupRoleCache
+	GetGroupRoles(ctx context.Context, groupID string) ([]*relationtb.GroupRoleModel, error)
+	DelGroupRoles(groupIDs ...string) GroupRoleCache
+}
+
+func NewGroupRoleCacheRedis(rdb redis.UniversalClient, roleDB relationtb.GroupRoleModelInterface) GroupRoleCache {
+	rcClient := rockscache.NewClient(rdb, rockscache.NewDefaultOptions())
+	return &groupRoleCacheRedis{
+		rcClient:   rcClient,
+		expireTime: time.Hour * 12,
+		roleDB:     roleDB,
+		metaCache:  NewMetaCacheRedis(rcClient),
+	}
+}
+
+type groupRoleCacheRedis struct {
+	metaCache
+	roleDB     relationtb.GroupRoleModelInterface
+	rcClient   *rockscache.Client
+	expireTime time.Duration
+}
+
+func (g *groupRoleCacheRedis) NewCache() GroupRoleCache {
+	return &groupRoleCacheRedis{
+		rcClient:   g.rcClient,
+		expireTime: g.expireTime,
+		roleDB:     g.roleDB,
+		metaCache:  NewMetaCacheRedis(g.rcClient, g.metaCache.GetPreDelKeys()...),
+	}
+}
+
+func (g *groupRoleCacheRedis) getGroupRolesKey(groupID string) string {
+	return groupRolesKey + groupID
+}
+
+func (g *groupRoleCacheRedis) GetGroupRoles(ctx context.Context, groupID string) ([]*relationtb.GroupRoleModel, error) {
+	return getCache(ctx, g.rcClient, g.getGroupRolesKey(groupID), g.expireTime, func(ctx context.Context) ([]*relationtb.GroupRoleModel, error) {
+		return g.roleDB.FindByGroupID(ctx, groupID)
+	})
+}
+
+func (g *groupRoleCacheRedis) DelGroupRoles(groupIDs ...string) GroupRoleCache {
+	cache := g.NewCache()
+	keys := make([]string, 0, len(groupIDs))
+	for _, groupID := range groupIDs {
+		keys = append(keys, g.getGroupRolesKey(groupID))
+	}
+	cache.AddKeys(keys...)
+	return cache
+}
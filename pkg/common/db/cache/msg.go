@@ -16,6 +16,7 @@ package cache
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"strconv"
 	"time"
@@ -43,6 +44,7 @@ const (
 	minSeq                 = "MIN_SEQ:"
 	conversationUserMinSeq = "CON_USER_MIN_SEQ:"
 	hasReadSeq             = "HAS_READ_SEQ:"
+	clientMsgSeq           = "CLIENT_MSG_SEQ:"
 
 	appleDeviceToken = "DEVICE_TOKEN"
 	getuiToken       = "GETUI_TOKEN"
@@ -50,6 +52,7 @@ const (
 	signalCache      = "SIGNAL_CACHE:"
 	signalListCache  = "SIGNAL_LIST_CACHE:"
 	FCM_TOKEN        = "FCM_TOKEN:"
+	voipToken        = "VOIP_TOKEN:"
 
 	messageCache            = "MESSAGE_CACHE:"
 	messageDelUserList      = "MESSAGE_DEL_USER_LIST:"
@@ -58,12 +61,20 @@ const (
 	userBadgeUnreadCountSum = "USER_BADGE_UNREAD_COUNT_SUM:"
 	exTypeKeyLocker         = "EX_LOCK:"
 	uidPidToken             = "UID_PID_TOKEN_STATUS:"
+	pushCoalesceCount       = "PUSH_COALESCE_COUNT:"
+	pushCoalesceDue         = "PUSH_COALESCE_DUE"
 )
 
 var concurrentLimit = 3
 
+// userBadgeUnreadCountSumExpire bounds how long a badge adjusted only incrementally is trusted before it is recounted.
+const userBadgeUnreadCountSumExpire = time.Hour * 24
+
 type SeqCache interface {
 	SetMaxSeq(ctx context.Context, conversationID string, maxSeq int64) error
+	// AllocateSeqs atomically raises the max seq to at least floor and then adds size to it,
+	// the seqs maxSeq-size+1 to maxSeq belong to the caller. isNew is true when the conversation had no max seq.
+	AllocateSeqs(ctx context.Context, conversationID string, floor int64, size int64) (maxSeq int64, isNew bool, err error)
 	GetMaxSeqs(ctx context.Context, conversationIDs []string) (map[string]int64, error)
 	GetMaxSeq(ctx context.Context, conversationID string) (int64, error)
 	SetMinSeq(ctx context.Context, conversationID string, minSeq int64) error
@@ -85,19 +96,46 @@ type SeqCache interface {
 	UserSetHasReadSeqs(ctx context.Context, userID string, hasReadSeqs map[string]int64) error
 	GetHasReadSeqs(ctx context.Context, userID string, conversationIDs []string) (map[string]int64, error)
 	GetHasReadSeq(ctx context.Context, userID string, conversationID string) (int64, error)
+	// k: clientMsgID, v: the seq the message was given, messages not given a seq within the expire time are absent
+	GetClientMsgSeqs(ctx context.Context, conversationID string, clientMsgIDs []string) (map[string]int64, error)
+	SetClientMsgSeqs(ctx context.Context, conversationID string, seqs map[string]int64, expireTime time.Duration) error
 }
 
 type thirdCache interface {
 	SetFcmToken(ctx context.Context, account string, platformID int, fcmToken string, expireTime int64) (err error)
 	GetFcmToken(ctx context.Context, account string, platformID int) (string, error)
 	DelFcmToken(ctx context.Context, account string, platformID int) error
-	IncrUserBadgeUnreadCountSum(ctx context.Context, userID string) (int, error)
+	// SetVoipToken the call push token, a PushKit token on iOS and a FCM token on Android
+	SetVoipToken(ctx context.Context, account string, platformID int, token string, expireTime int64) error
+	GetVoipToken(ctx context.Context, account string, platformID int) (string, error)
+	DelVoipToken(ctx context.Context, account string, platformID int) error
 	SetUserBadgeUnreadCountSum(ctx context.Context, userID string, value int) error
 	GetUserBadgeUnreadCountSum(ctx context.Context, userID string) (int, error)
+	// GetUserBadgeUnreadCountSums k: userID, v: cached badge, users without a cached badge are absent
+	GetUserBadgeUnreadCountSums(ctx context.Context, userIDs []string) (map[string]int, error)
+	// IncrUserBadgeUnreadCountSums add delta to the cached badges that exist, never below 0
+	IncrUserBadgeUnreadCountSums(ctx context.Context, userIDs []string, delta int) error
+	DelUserBadgeUnreadCountSum(ctx context.Context, userIDs ...string) error
 	SetGetuiToken(ctx context.Context, token string, expireTime int64) error
 	GetGetuiToken(ctx context.Context) (string, error)
 	SetGetuiTaskID(ctx context.Context, taskID string, expireTime int64) error
 	GetGetuiTaskID(ctx context.Context) (string, error)
+	// IncrPushCoalesceCounts k: userID, v: offline pushes of the conversation counted in the user's current window.
+	// A count of 1 means this push opened a new window, its key expires after expireTime.
+	IncrPushCoalesceCounts(ctx context.Context, conversationID string, userIDs []string, expireTime time.Duration) (map[string]int64, error)
+	// GetDelPushCoalesceCounts k: userID, v: count of the closed window, users without a window are absent.
+	GetDelPushCoalesceCounts(ctx context.Context, conversationID string, userIDs []string) (map[string]int64, error)
+	// AddPushCoalesceWindows schedules the windows to be closed at the due time.
+	AddPushCoalesceWindows(ctx context.Context, windows []*PushCoalesceWindow, due time.Time) error
+	// PopDuePushCoalesceWindows takes up to count windows due by now, every window is taken by one caller only.
+	PopDuePushCoalesceWindows(ctx context.Context, now time.Time, count int64) ([]*PushCoalesceWindow, error)
+}
+
+// PushCoalesceWindow a user's open coalescing window of a group conversation.
+type PushCoalesceWindow struct {
+	ConversationID string `json:"conversationID"`
+	GroupID        string `json:"groupID"`
+	UserID         string `json:"userID"`
 }
 
 type MsgModel interface {
@@ -176,6 +214,30 @@ func (c *msgCache) SetMaxSeq(ctx context.Context, conversationID string, maxSeq
 	return c.setSeq(ctx, conversationID, maxSeq, c.getMaxSeqKey)
 }
 
+// allocateSeqsScript returns {existed, maxSeq}.
+var allocateSeqsScript = redis.NewScript(`
+local existed = redis.call("EXISTS", KEYS[1])
+local seq = tonumber(redis.call("GET", KEYS[1]) or "0")
+local floor = tonumber(ARGV[1])
+if seq < floor then
+	seq = floor
+end
+seq = seq + tonumber(ARGV[2])
+redis.call("SET", KEYS[1], seq)
+return {existed, seq}
+`)
+
+func (c *msgCache) AllocateSeqs(ctx context.Context, conversationID string, floor int64, size int64) (int64, bool, error) {
+	res, err := allocateSeqsScript.Run(ctx, c.rdb, []string{c.getMaxSeqKey(conversationID)}, floor, size).Int64Slice()
+	if err != nil {
+		return 0, false, errs.Wrap(err)
+	}
+	if len(res) != 2 {
+		return 0, false, errs.ErrInternalServer.Wrap("unexpected allocate seqs result")
+	}
+	return res[1], res[0] == 0, nil
+}
+
 func (c *msgCache) GetMaxSeqs(ctx context.Context, conversationIDs []string) (m map[string]int64, err error) {
 	return c.getSeqs(ctx, conversationIDs, c.getMaxSeqKey)
 }
@@ -265,6 +327,45 @@ func (c *msgCache) GetHasReadSeq(ctx context.Context, userID string, conversatio
 	return utils.Wrap2(c.rdb.Get(ctx, c.getHasReadSeqKey(conversationID, userID)).Int64())
 }
 
+func (c *msgCache) getClientMsgSeqKey(conversationID string, clientMsgID string) string {
+	return clientMsgSeq + conversationID + ":" + clientMsgID
+}
+
+func (c *msgCache) GetClientMsgSeqs(ctx context.Context, conversationID string, clientMsgIDs []string) (map[string]int64, error) {
+	pipe := c.rdb.Pipeline()
+	results := make(map[string]*redis.StringCmd, len(clientMsgIDs))
+	for _, clientMsgID := range clientMsgIDs {
+		results[clientMsgID] = pipe.Get(ctx, c.getClientMsgSeqKey(conversationID, clientMsgID))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, errs.Wrap(err)
+	}
+	seqs := make(map[string]int64, len(results))
+	for clientMsgID, result := range results {
+		seq, err := result.Int64()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			return nil, errs.Wrap(err)
+		}
+		seqs[clientMsgID] = seq
+	}
+	return seqs, nil
+}
+
+func (c *msgCache) SetClientMsgSeqs(ctx context.Context, conversationID string, seqs map[string]int64, expireTime time.Duration) error {
+	if len(seqs) == 0 {
+		return nil
+	}
+	pipe := c.rdb.Pipeline()
+	for clientMsgID, seq := range seqs {
+		pipe.Set(ctx, c.getClientMsgSeqKey(conversationID, clientMsgID), seq, expireTime)
+	}
+	_, err := pipe.Exec(ctx)
+	return errs.Wrap(err)
+}
+
 func (c *msgCache) AddTokenFlag(ctx context.Context, userID string, platformID int, token string, flag int) error {
 	key := uidPidToken + userID + ":" + constant.PlatformIDToName(platformID)
 
@@ -727,20 +828,179 @@ func (c *msgCache) DelFcmToken(ctx context.Context, account string, platformID i
 	return errs.Wrap(c.rdb.Del(ctx, FCM_TOKEN+account+":"+strconv.Itoa(platformID)).Err())
 }
 
-func (c *msgCache) IncrUserBadgeUnreadCountSum(ctx context.Context, userID string) (int, error) {
-	seq, err := c.rdb.Incr(ctx, userBadgeUnreadCountSum+userID).Result()
+func (c *msgCache) SetVoipToken(ctx context.Context, account string, platformID int, token string, expireTime int64) error {
+	return errs.Wrap(c.rdb.Set(ctx, voipToken+account+":"+strconv.Itoa(platformID), token, time.Duration(expireTime)*time.Second).Err())
+}
+
+func (c *msgCache) GetVoipToken(ctx context.Context, account string, platformID int) (string, error) {
+	return utils.Wrap2(c.rdb.Get(ctx, voipToken+account+":"+strconv.Itoa(platformID)).Result())
+}
 
-	return int(seq), errs.Wrap(err)
+func (c *msgCache) DelVoipToken(ctx context.Context, account string, platformID int) error {
+	return errs.Wrap(c.rdb.Del(ctx, voipToken+account+":"+strconv.Itoa(platformID)).Err())
 }
 
 func (c *msgCache) SetUserBadgeUnreadCountSum(ctx context.Context, userID string, value int) error {
-	return errs.Wrap(c.rdb.Set(ctx, userBadgeUnreadCountSum+userID, value, 0).Err())
+	return errs.Wrap(c.rdb.Set(ctx, userBadgeUnreadCountSum+userID, value, userBadgeUnreadCountSumExpire).Err())
 }
 
 func (c *msgCache) GetUserBadgeUnreadCountSum(ctx context.Context, userID string) (int, error) {
 	return utils.Wrap2(c.rdb.Get(ctx, userBadgeUnreadCountSum+userID).Int())
 }
 
+func (c *msgCache) GetUserBadgeUnreadCountSums(ctx context.Context, userIDs []string) (map[string]int, error) {
+	pipe := c.rdb.Pipeline()
+	results := make(map[string]*redis.StringCmd, len(userIDs))
+	for _, userID := range userIDs {
+		results[userID] = pipe.Get(ctx, userBadgeUnreadCountSum+userID)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, errs.Wrap(err)
+	}
+	badges := make(map[string]int, len(results))
+	for userID, result := range results {
+		badge, err := result.Int()
+		if err == redis.Nil {
+			continue
+		} else if err != nil {
+			return nil, errs.Wrap(err)
+		}
+		badges[userID] = badge
+	}
+	return badges, nil
+}
+
+// incrIfExistsScript only adjusts a badge that was already counted, a missing one is recounted on the next read.
+var incrIfExistsScript = `
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return 0
+end
+if redis.call("INCRBY", KEYS[1], ARGV[1]) < 0 then
+	redis.call("SET", KEYS[1], 0, "KEEPTTL")
+end
+return 1
+`
+
+func (c *msgCache) IncrUserBadgeUnreadCountSums(ctx context.Context, userIDs []string, delta int) error {
+	if len(userIDs) == 0 || delta == 0 {
+		return nil
+	}
+	pipe := c.rdb.Pipeline()
+	for _, userID := range userIDs {
+		pipe.Eval(ctx, incrIfExistsScript, []string{userBadgeUnreadCountSum + userID}, delta)
+	}
+	_, err := pipe.Exec(ctx)
+	return errs.Wrap(err)
+}
+
+func (c *msgCache) DelUserBadgeUnreadCountSum(ctx context.Context, userIDs ...string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	pipe := c.rdb.Pipeline()
+	for _, userID := range userIDs {
+		pipe.Del(ctx, userBadgeUnreadCountSum+userID)
+	}
+	_, err := pipe.Exec(ctx)
+	return errs.Wrap(err)
+}
+
+func (c *msgCache) getPushCoalesceCountKey(conversationID, userID string) string {
+	return pushCoalesceCount + conversationID + ":" + userID
+}
+
+// incrPushCoalesceCountScript opens the window with its expiry in the same step, so a counter never lives forever.
+var incrPushCoalesceCountScript = `
+local count = redis.call("INCR", KEYS[1])
+if count == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return count
+`
+
+func (c *msgCache) IncrPushCoalesceCounts(ctx context.Context, conversationID string, userIDs []string, expireTime time.Duration) (map[string]int64, error) {
+	pipe := c.rdb.Pipeline()
+	results := make(map[string]*redis.Cmd, len(userIDs))
+	for _, userID := range userIDs {
+		results[userID] = pipe.Eval(ctx, incrPushCoalesceCountScript, []string{c.getPushCoalesceCountKey(conversationID, userID)}, expireTime.Milliseconds())
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, errs.Wrap(err)
+	}
+	counts := make(map[string]int64, len(results))
+	for userID, result := range results {
+		count, err := result.Int64()
+		if err != nil {
+			return nil, errs.Wrap(err)
+		}
+		counts[userID] = count
+	}
+	return counts, nil
+}
+
+func (c *msgCache) GetDelPushCoalesceCounts(ctx context.Context, conversationID string, userIDs []string) (map[string]int64, error) {
+	pipe := c.rdb.Pipeline()
+	results := make(map[string]*redis.StringCmd, len(userIDs))
+	for _, userID := range userIDs {
+		results[userID] = pipe.GetDel(ctx, c.getPushCoalesceCountKey(conversationID, userID))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, errs.Wrap(err)
+	}
+	counts := make(map[string]int64, len(results))
+	for userID, result := range results {
+		count, err := result.Int64()
+		if err == redis.Nil {
+			continue
+		} else if err != nil {
+			return nil, errs.Wrap(err)
+		}
+		counts[userID] = count
+	}
+	return counts, nil
+}
+
+// popDuePushCoalesceWindowsScript removes the windows it returns, so concurrent push instances never close one twice.
+var popDuePushCoalesceWindowsScript = `
+local windows = redis.call("ZRANGEBYSCORE", KEYS[1], "-inf", ARGV[1], "LIMIT", 0, ARGV[2])
+if #windows > 0 then
+	redis.call("ZREM", KEYS[1], unpack(windows))
+end
+return windows
+`
+
+func (c *msgCache) AddPushCoalesceWindows(ctx context.Context, windows []*PushCoalesceWindow, due time.Time) error {
+	if len(windows) == 0 {
+		return nil
+	}
+	members := make([]redis.Z, 0, len(windows))
+	for _, window := range windows {
+		data, err := json.Marshal(window)
+		if err != nil {
+			return errs.Wrap(err)
+		}
+		members = append(members, redis.Z{Score: float64(due.UnixMilli()), Member: string(data)})
+	}
+	return errs.Wrap(c.rdb.ZAddNX(ctx, pushCoalesceDue, members...).Err())
+}
+
+func (c *msgCache) PopDuePushCoalesceWindows(ctx context.Context, now time.Time, count int64) ([]*PushCoalesceWindow, error) {
+	members, err := c.rdb.Eval(ctx, popDuePushCoalesceWindowsScript, []string{pushCoalesceDue}, now.UnixMilli(), count).StringSlice()
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	windows := make([]*PushCoalesceWindow, 0, len(members))
+	for _, member := range members {
+		var window PushCoalesceWindow
+		if err := json.Unmarshal([]byte(member), &window); err != nil {
+			log.ZWarn(ctx, "invalid push coalesce window", err, "window", member)
+			continue
+		}
+		windows = append(windows, &window)
+	}
+	return windows, nil
+}
+
 func (c *msgCache) LockMessageTypeKey(ctx context.Context, clientMsgID string, TypeKey string) error {
 	key := exTypeKeyLocker + clientMsgID + "_" + TypeKey
 
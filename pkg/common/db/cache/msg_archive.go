@@ -0,0 +1,104 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/dtm-labs/rockscache"
+	"github.com/redis/go-redis/v9"
+
+	unrelationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/unrelation"
+)
+
+const (
+	msgArchiveMaxSeqKey = "MSG_ARCHIVE_MAX_SEQ:"
+	msgArchiveDocKey    = "MSG_ARCHIVE_DOC:"
+)
+
+type MsgArchiveCache interface {
+	metaCache
+	NewCache() MsgArchiveCache
+	GetArchivedMaxSeq(ctx context.Context, conversationID string) (int64, error)
+	// GetArchivedDoc fn loads the doc from the object storage on a cache miss
+	GetArchivedDoc(ctx context.Context, docID string, fn func(ctx context.Context) (*unrelationtb.MsgDocModel, error)) (*unrelationtb.MsgDocModel, error)
+	DelArchivedMaxSeq(conversationIDs ...string) MsgArchiveCache
+	DelArchivedDoc(docIDs ...string) MsgArchiveCache
+}
+
+func NewMsgArchiveCacheRedis(rdb redis.UniversalClient, archiveDB unrelationtb.MsgArchiveModelInterface, expireTime time.Duration) MsgArchiveCache {
+	rcClient := rockscache.NewClient(rdb, rockscache.NewDefaultOptions())
+	return &msgArchiveCacheRedis{
+		rcClient:   rcClient,
+		expireTime: expireTime,
+		archiveDB:  archiveDB,
+		metaCache:  NewMetaCacheRedis(rcClient),
+	}
+}
+
+type msgArchiveCacheRedis struct {
+	metaCache
+	archiveDB  unrelationtb.MsgArchiveModelInterface
+	rcClient   *rockscache.Client
+	expireTime time.Duration
+}
+
+func (m *msgArchiveCacheRedis) NewCache() MsgArchiveCache {
+	return &msgArchiveCacheRedis{
+		rcClient:   m.rcClient,
+		expireTime: m.expireTime,
+		archiveDB:  m.archiveDB,
+		metaCache:  NewMetaCacheRedis(m.rcClient, m.metaCache.GetPreDelKeys()...),
+	}
+}
+
+func (m *msgArchiveCacheRedis) getArchivedMaxSeqKey(conversationID string) string {
+	return msgArchiveMaxSeqKey + conversationID
+}
+
+func (m *msgArchiveCacheRedis) getArchivedDocKey(docID string) string {
+	return msgArchiveDocKey + docID
+}
+
+func (m *msgArchiveCacheRedis) GetArchivedMaxSeq(ctx context.Context, conversationID string) (int64, error) {
+	return getCache(ctx, m.rcClient, m.getArchivedMaxSeqKey(conversationID), m.expireTime, func(ctx context.Context) (int64, error) {
+		return m.archiveDB.GetMaxSeq(ctx, conversationID)
+	})
+}
+
+func (m *msgArchiveCacheRedis) GetArchivedDoc(ctx context.Context, docID string, fn func(ctx context.Context) (*unrelationtb.MsgDocModel, error)) (*unrelationtb.MsgDocModel, error) {
+	return getCache(ctx, m.rcClient, m.getArchivedDocKey(docID), m.expireTime, fn)
+}
+
+func (m *msgArchiveCacheRedis) DelArchivedMaxSeq(conversationIDs ...string) MsgArchiveCache {
+	cache := m.NewCache()
+	keys := make([]string, 0, len(conversationIDs))
+	for _, conversationID := range conversationIDs {
+		keys = append(keys, m.getArchivedMaxSeqKey(conversationID))
+	}
+	cache.AddKeys(keys...)
+	return cache
+}
+
+func (m *msgArchiveCacheRedis) DelArchivedDoc(docIDs ...string) MsgArchiveCache {
+	cache := m.NewCache()
+	keys := make([]string, 0, len(docIDs))
+	for _, docID := range docIDs {
+		keys = append(keys, m.getArchivedDocKey(docID))
+	}
+	cache.AddKeys(keys...)
+	return cache
+}
@@ -0,0 +1,85 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/redis/go-redis/v9"
+)
+
+const msgExportJobKey = "MSG_EXPORT_JOB:"
+
+// MsgExportJob the state of a conversation history export, kept in redis until it expires.
+type MsgExportJob struct {
+	JobID          string `json:"jobID"`
+	UserID         string `json:"userID"`
+	ConversationID string `json:"conversationID"`
+	Format         string `json:"format"`
+	StartTime      int64  `json:"startTime"`
+	EndTime        int64  `json:"endTime"`
+	Status         string `json:"status"`
+	Progress       int32  `json:"progress"`
+	MsgNum         int64  `json:"msgNum"`
+	Name           string `json:"name"`
+	URL            string `json:"url"`
+	ExpireTime     int64  `json:"expireTime"`
+	ErrMsg         string `json:"errMsg"`
+	CreateTime     int64  `json:"createTime"`
+	UpdateTime     int64  `json:"updateTime"`
+}
+
+type MsgExportCache interface {
+	SetExportJob(ctx context.Context, job *MsgExportJob, expireTime time.Duration) error
+	GetExportJob(ctx context.Context, jobID string) (*MsgExportJob, error)
+}
+
+func NewMsgExportCacheRedis(rdb redis.UniversalClient) MsgExportCache {
+	return &msgExportCacheRedis{rdb: rdb}
+}
+
+type msgExportCacheRedis struct {
+	rdb redis.UniversalClient
+}
+
+func (m *msgExportCacheRedis) getExportJobKey(jobID string) string {
+	return msgExportJobKey + jobID
+}
+
+func (m *msgExportCacheRedis) SetExportJob(ctx context.Context, job *MsgExportJob, expireTime time.Duration) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	return errs.Wrap(m.rdb.Set(ctx, m.getExportJobKey(job.JobID), data, expireTime).Err())
+}
+
+func (m *msgExportCacheRedis) GetExportJob(ctx context.Context, jobID string) (*MsgExportJob, error) {
+	data, err := m.rdb.Get(ctx, m.getExportJobKey(jobID)).Bytes()
+	if err == redis.Nil {
+		return nil, errs.ErrRecordNotFound.Wrap("export job not found")
+	}
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	var job MsgExportJob
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &job, nil
+}
@@ -0,0 +1,145 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+// newPushTestCache a msgCache on the local redis, the test is skipped when there is none.
+func newPushTestCache(t *testing.T) (*msgCache, redis.UniversalClient) {
+	rdb := redis.NewClient(&redis.Options{})
+	if err := rdb.Ping(context.Background()).Err(); err != nil {
+		rdb.Close()
+		t.Skipf("redis not available: %v", err)
+	}
+	t.Cleanup(func() { rdb.Close() })
+	return &msgCache{rdb: rdb}, rdb
+}
+
+func TestIncrPushCoalesceCounts(t *testing.T) {
+	cacher, rdb := newPushTestCache(t)
+	ctx := context.Background()
+	conversationID := fmt.Sprintf("sg_%d", rand.Int63())
+	tests := []struct {
+		name    string
+		userIDs []string
+		want    map[string]int64
+	}{
+		{name: "open windows", userIDs: []string{"u1", "u2"}, want: map[string]int64{"u1": 1, "u2": 1}},
+		{name: "count in open windows", userIDs: []string{"u1"}, want: map[string]int64{"u1": 2}},
+		{name: "mixed", userIDs: []string{"u1", "u2", "u3"}, want: map[string]int64{"u1": 3, "u2": 2, "u3": 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counts, err := cacher.IncrPushCoalesceCounts(ctx, conversationID, tt.userIDs, time.Minute)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, counts)
+			// every counter carries the expiry set when its window was opened
+			for _, userID := range tt.userIDs {
+				ttl := rdb.PTTL(ctx, cacher.getPushCoalesceCountKey(conversationID, userID)).Val()
+				assert.True(t, ttl > 0 && ttl <= time.Minute, "ttl of %s is %s", userID, ttl)
+			}
+		})
+	}
+	counts, err := cacher.GetDelPushCoalesceCounts(ctx, conversationID, []string{"u1", "u2", "u3", "u4"})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]int64{"u1": 3, "u2": 2, "u3": 1}, counts)
+	counts, err = cacher.IncrPushCoalesceCounts(ctx, conversationID, []string{"u1"}, time.Minute)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]int64{"u1": 1}, counts)
+	_, _ = cacher.GetDelPushCoalesceCounts(ctx, conversationID, []string{"u1"})
+}
+
+func TestPopDuePushCoalesceWindows(t *testing.T) {
+	cacher, rdb := newPushTestCache(t)
+	ctx := context.Background()
+	rdb.Del(ctx, pushCoalesceDue)
+	defer rdb.Del(ctx, pushCoalesceDue)
+	now := time.Now()
+	due := []*PushCoalesceWindow{
+		{ConversationID: "sg_g1", GroupID: "g1", UserID: "u1"},
+		{ConversationID: "sg_g1", GroupID: "g1", UserID: "u2"},
+	}
+	later := []*PushCoalesceWindow{{ConversationID: "sg_g2", GroupID: "g2", UserID: "u1"}}
+	assert.Nil(t, cacher.AddPushCoalesceWindows(ctx, due, now.Add(-time.Second)))
+	assert.Nil(t, cacher.AddPushCoalesceWindows(ctx, later, now.Add(time.Minute)))
+	tests := []struct {
+		name  string
+		now   time.Time
+		count int64
+		want  int
+	}{
+		{name: "limited by count", now: now, count: 1, want: 1},
+		{name: "rest of the due windows", now: now, count: 10, want: 1},
+		{name: "taken windows are gone", now: now, count: 10, want: 0},
+		{name: "later window", now: now.Add(time.Minute), count: 10, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			windows, err := cacher.PopDuePushCoalesceWindows(ctx, tt.now, tt.count)
+			assert.Nil(t, err)
+			assert.Len(t, windows, tt.want)
+		})
+	}
+}
+
+func TestIncrUserBadgeUnreadCountSums(t *testing.T) {
+	cacher, rdb := newPushTestCache(t)
+	ctx := context.Background()
+	userID := fmt.Sprintf("badge_%d", rand.Int63())
+	defer rdb.Del(ctx, userBadgeUnreadCountSum+userID)
+	tests := []struct {
+		name   string
+		set    *int
+		delta  int
+		want   int
+		exists bool
+	}{
+		{name: "missing badge is left to be recounted", delta: 3, exists: false},
+		{name: "increment", set: intPtr(2), delta: 3, want: 5, exists: true},
+		{name: "decrement", set: intPtr(5), delta: -2, want: 3, exists: true},
+		{name: "never below zero", set: intPtr(1), delta: -4, want: 0, exists: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rdb.Del(ctx, userBadgeUnreadCountSum+userID)
+			if tt.set != nil {
+				assert.Nil(t, cacher.SetUserBadgeUnreadCountSum(ctx, userID, *tt.set))
+			}
+			assert.Nil(t, cacher.IncrUserBadgeUnreadCountSums(ctx, []string{userID}, tt.delta))
+			badges, err := cacher.GetUserBadgeUnreadCountSums(ctx, []string{userID})
+			assert.Nil(t, err)
+			badge, ok := badges[userID]
+			assert.Equal(t, tt.exists, ok)
+			assert.Equal(t, tt.want, badge)
+			if tt.exists {
+				// the expiry of the counted badge is kept
+				assert.True(t, rdb.TTL(ctx, userBadgeUnreadCountSum+userID).Val() > 0)
+			}
+		})
+	}
+}
+
+func intPtr(i int) *int {
+	return &i
+}
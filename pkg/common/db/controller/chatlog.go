@@ -15,13 +15,30 @@
 package controller
 
 import (
-	pbmsg "github.com/OpenIMSDK/protocol/msg"
+	"context"
+	"time"
 
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/jinzhu/copier"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/utils"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/convert"
 	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	unrelationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/unrelation"
 )
 
 type ChatLogDatabase interface {
-	CreateChatLog(msg *pbmsg.MsgDataToMQ) error
+	// CreateChatLogs mirrors the msgs stored in mongo.
+	CreateChatLogs(ctx context.Context, conversationID string, msgs []*sdkws.MsgData) error
+	// SaveChatLogs overwrites the chat logs with the msgs read back from mongo, including their revoke.
+	SaveChatLogs(ctx context.Context, conversationID string, msgs []*unrelationtb.MsgInfoModel) error
+	RevokeChatLog(ctx context.Context, conversationID string, seq int64, revoke *unrelationtb.RevokeModel) error
+	DeleteChatLogs(ctx context.Context, conversationID string, seqs []int64) error
+	DeleteChatLogsBefore(ctx context.Context, conversationID string, seq int64) error
 }
 
 func NewChatLogDatabase(chatLogModelInterface relationtb.ChatLogModelInterface) ChatLogDatabase {
@@ -32,6 +49,65 @@ type chatLogDatabase struct {
 	chatLogModel relationtb.ChatLogModelInterface
 }
 
-func (c *chatLogDatabase) CreateChatLog(msg *pbmsg.MsgDataToMQ) error {
-	return c.chatLogModel.Create(msg)
+func (c *chatLogDatabase) toChatLog(conversationID string, msg *sdkws.MsgData) *relationtb.ChatLogModel {
+	chatLog := new(relationtb.ChatLogModel)
+	copier.Copy(chatLog, msg)
+	chatLog.ConversationID = conversationID
+	switch msg.SessionType {
+	case constant.GroupChatType, constant.SuperGroupChatType:
+		chatLog.RecvID = msg.GroupID
+	case constant.SingleChatType:
+		chatLog.RecvID = msg.RecvID
+	}
+	if msg.ContentType >= constant.NotificationBegin && msg.ContentType <= constant.NotificationEnd {
+		var tips sdkws.TipsComm
+		_ = proto.Unmarshal(msg.Content, &tips)
+		marshaler := jsonpb.Marshaler{
+			OrigName:     true,
+			EnumsAsInts:  false,
+			EmitDefaults: false,
+		}
+		chatLog.Content, _ = marshaler.MarshalToString(&tips)
+	} else {
+		chatLog.Content = string(msg.Content)
+	}
+	chatLog.CreateTime = utils.UnixMillSecondToTime(msg.CreateTime)
+	chatLog.SendTime = utils.UnixMillSecondToTime(msg.SendTime)
+	return chatLog
+}
+
+func (c *chatLogDatabase) CreateChatLogs(ctx context.Context, conversationID string, msgs []*sdkws.MsgData) error {
+	chatLogs := utils.Slice(msgs, func(msg *sdkws.MsgData) *relationtb.ChatLogModel {
+		return c.toChatLog(conversationID, msg)
+	})
+	return c.chatLogModel.Create(ctx, chatLogs)
+}
+
+func (c *chatLogDatabase) SaveChatLogs(ctx context.Context, conversationID string, msgs []*unrelationtb.MsgInfoModel) error {
+	chatLogs := make([]*relationtb.ChatLogModel, 0, len(msgs))
+	for _, msg := range msgs {
+		if msg == nil || msg.Msg == nil {
+			continue
+		}
+		chatLog := c.toChatLog(conversationID, convert.MsgDB2Pb(msg.Msg))
+		if msg.Revoke != nil {
+			revokeTime := utils.UnixMillSecondToTime(msg.Revoke.Time)
+			chatLog.RevokeUserID = msg.Revoke.UserID
+			chatLog.RevokeTime = &revokeTime
+		}
+		chatLogs = append(chatLogs, chatLog)
+	}
+	return c.chatLogModel.Save(ctx, chatLogs)
+}
+
+func (c *chatLogDatabase) RevokeChatLog(ctx context.Context, conversationID string, seq int64, revoke *unrelationtb.RevokeModel) error {
+	return c.chatLogModel.Revoke(ctx, conversationID, seq, revoke.UserID, utils.UnixMillSecondToTime(revoke.Time))
+}
+
+func (c *chatLogDatabase) DeleteChatLogs(ctx context.Context, conversationID string, seqs []int64) error {
+	return c.chatLogModel.Delete(ctx, conversationID, seqs, time.Now())
+}
+
+func (c *chatLogDatabase) DeleteChatLogsBefore(ctx context.Context, conversationID string, seq int64) error {
+	return c.chatLogModel.DeleteBefore(ctx, conversationID, seq, time.Now())
 }
@@ -56,6 +56,8 @@ type ConversationDatabase interface {
 	GetConversationsByConversationID(ctx context.Context, conversationIDs []string) ([]*relationtb.ConversationModel, error)
 	GetConversationIDsNeedDestruct(ctx context.Context) ([]*relationtb.ConversationModel, error)
 	GetConversationNotReceiveMessageUserIDs(ctx context.Context, conversationID string) ([]string, error)
+	// FindSnoozedUserIDs returns the users of userIDs whose snooze of the conversation hasn't ended at now.
+	FindSnoozedUserIDs(ctx context.Context, conversationID string, userIDs []string, now time.Time) ([]string, error)
 }
 
 func NewConversationDatabase(conversation relationtb.ConversationModelInterface, cache cache.ConversationCache, tx tx.Tx) ConversationDatabase {
@@ -330,3 +332,10 @@ func (c *conversationDatabase) GetConversationIDsNeedDestruct(ctx context.Contex
 func (c *conversationDatabase) GetConversationNotReceiveMessageUserIDs(ctx context.Context, conversationID string) ([]string, error) {
 	return c.cache.GetConversationNotReceiveMessageUserIDs(ctx, conversationID)
 }
+
+func (c *conversationDatabase) FindSnoozedUserIDs(ctx context.Context, conversationID string, userIDs []string, now time.Time) ([]string, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+	return c.conversationDB.FindSnoozedUserIDs(ctx, conversationID, userIDs, now.UnixMilli())
+}
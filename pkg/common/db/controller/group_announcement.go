@@ -0,0 +1,102 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/tools/tx"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/relation"
+	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+type GroupAnnouncementDatabase interface {
+	// CreateGroupAnnouncement fills announcement.AnnouncementID with a new id
+	CreateGroupAnnouncement(ctx context.Context, announcement *relationtb.GroupAnnouncementModel) error
+	// UpdateGroupAnnouncement resetAcks drops the acks, the members have to read the changed content again
+	UpdateGroupAnnouncement(ctx context.Context, announcementID string, data map[string]any, resetAcks bool) error
+	DeleteGroupAnnouncement(ctx context.Context, announcementID string) error
+	TakeGroupAnnouncement(ctx context.Context, announcementID string) (*relationtb.GroupAnnouncementModel, error)
+	PageGroupAnnouncements(ctx context.Context, groupID string, pageNumber, showNumber int32) (uint32, []*relationtb.GroupAnnouncementModel, error)
+	AckGroupAnnouncement(ctx context.Context, ack *relationtb.GroupAnnouncementAckModel) error
+	FindGroupAnnouncementAckUserIDs(ctx context.Context, announcementID string) ([]string, error)
+	FindAckedGroupAnnouncementIDs(ctx context.Context, userID string, announcementIDs []string) ([]string, error)
+}
+
+func NewGroupAnnouncementDatabase(announcement relationtb.GroupAnnouncementModelInterface, ack relationtb.GroupAnnouncementAckModelInterface, tx tx.Tx) GroupAnnouncementDatabase {
+	return &groupAnnouncementDatabase{announcement: announcement, ack: ack, tx: tx}
+}
+
+func InitGroupAnnouncementDatabase(db *gorm.DB) (GroupAnnouncementDatabase, error) {
+	if err := db.AutoMigrate(&relationtb.GroupAnnouncementModel{}, &relationtb.GroupAnnouncementAckModel{}); err != nil {
+		return nil, err
+	}
+	return NewGroupAnnouncementDatabase(relation.NewGroupAnnouncementGorm(db), relation.NewGroupAnnouncementAckGorm(db), tx.NewGorm(db)), nil
+}
+
+type groupAnnouncementDatabase struct {
+	announcement relationtb.GroupAnnouncementModelInterface
+	ack          relationtb.GroupAnnouncementAckModelInterface
+	tx           tx.Tx
+}
+
+func (g *groupAnnouncementDatabase) CreateGroupAnnouncement(ctx context.Context, announcement *relationtb.GroupAnnouncementModel) error {
+	announcement.AnnouncementID = uuid.New().String()
+	return g.announcement.Create(ctx, announcement)
+}
+
+func (g *groupAnnouncementDatabase) UpdateGroupAnnouncement(ctx context.Context, announcementID string, data map[string]any, resetAcks bool) error {
+	return g.tx.Transaction(func(tx any) error {
+		if err := g.announcement.NewTx(tx).Update(ctx, announcementID, data); err != nil {
+			return err
+		}
+		if resetAcks {
+			return g.ack.NewTx(tx).Delete(ctx, announcementID)
+		}
+		return nil
+	})
+}
+
+func (g *groupAnnouncementDatabase) DeleteGroupAnnouncement(ctx context.Context, announcementID string) error {
+	return g.tx.Transaction(func(tx any) error {
+		if err := g.announcement.NewTx(tx).Delete(ctx, announcementID); err != nil {
+			return err
+		}
+		return g.ack.NewTx(tx).Delete(ctx, announcementID)
+	})
+}
+
+func (g *groupAnnouncementDatabase) TakeGroupAnnouncement(ctx context.Context, announcementID string) (*relationtb.GroupAnnouncementModel, error) {
+	return g.announcement.Take(ctx, announcementID)
+}
+
+func (g *groupAnnouncementDatabase) PageGroupAnnouncements(ctx context.Context, groupID string, pageNumber, showNumber int32) (uint32, []*relationtb.GroupAnnouncementModel, error) {
+	return g.announcement.Page(ctx, groupID, pageNumber, showNumber)
+}
+
+func (g *groupAnnouncementDatabase) AckGroupAnnouncement(ctx context.Context, ack *relationtb.GroupAnnouncementAckModel) error {
+	return g.ack.Create(ctx, ack)
+}
+
+func (g *groupAnnouncementDatabase) FindGroupAnnouncementAckUserIDs(ctx context.Context, announcementID string) ([]string, error) {
+	return g.ack.FindUserID(ctx, announcementID)
+}
+
+func (g *groupAnnouncementDatabase) FindAckedGroupAnnouncementIDs(ctx context.Context, userID string, announcementIDs []string) ([]string, error) {
+	return g.ack.FindAckedAnnouncementID(ctx, userID, announcementIDs)
+}
@@ -0,0 +1,55 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/relation"
+	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+type GroupAuditLogDatabase interface {
+	CreateGroupAuditLogs(ctx context.Context, logs []*relationtb.GroupAuditLogModel) error
+	PageGroupAuditLogs(ctx context.Context, groupID string, action string, targetUserID string, pageNumber, showNumber int32) (uint32, []*relationtb.GroupAuditLogModel, error)
+}
+
+func NewGroupAuditLogDatabase(auditLog relationtb.GroupAuditLogModelInterface) GroupAuditLogDatabase {
+	return &groupAuditLogDatabase{auditLog: auditLog}
+}
+
+func InitGroupAuditLogDatabase(db *gorm.DB) (GroupAuditLogDatabase, error) {
+	if err := db.AutoMigrate(&relationtb.GroupAuditLogModel{}); err != nil {
+		return nil, err
+	}
+	return NewGroupAuditLogDatabase(relation.NewGroupAuditLogGorm(db)), nil
+}
+
+type groupAuditLogDatabase struct {
+	auditLog relationtb.GroupAuditLogModelInterface
+}
+
+func (g *groupAuditLogDatabase) CreateGroupAuditLogs(ctx context.Context, logs []*relationtb.GroupAuditLogModel) error {
+	if len(logs) == 0 {
+		return nil
+	}
+	return g.auditLog.Create(ctx, logs)
+}
+
+func (g *groupAuditLogDatabase) PageGroupAuditLogs(ctx context.Context, groupID string, action string, targetUserID string, pageNumber, showNumber int32) (uint32, []*relationtb.GroupAuditLogModel, error) {
+	return g.auditLog.Page(ctx, groupID, action, targetUserID, pageNumber, showNumber)
+}
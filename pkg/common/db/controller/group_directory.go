@@ -0,0 +1,73 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/relation"
+	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+type GroupDirectoryDatabase interface {
+	SetGroupDirectory(ctx context.Context, entry *relationtb.GroupDirectoryModel) error
+	DeleteGroupDirectory(ctx context.Context, groupID string) error
+	TakeGroupDirectory(ctx context.Context, groupID string) (*relationtb.GroupDirectoryModel, error)
+	SearchGroupDirectory(ctx context.Context, keyword string, category string, tag string, orderBy string, pageNumber, showNumber int32) (uint32, []*relationtb.GroupDirectoryModel, error)
+	PageGroupDirectory(ctx context.Context, pageNumber, showNumber int32) ([]*relationtb.GroupDirectoryModel, error)
+	UpdateGroupDirectoryActiveTime(ctx context.Context, groupID string, activeTime time.Time) error
+}
+
+func NewGroupDirectoryDatabase(directory relationtb.GroupDirectoryModelInterface) GroupDirectoryDatabase {
+	return &groupDirectoryDatabase{directory: directory}
+}
+
+func InitGroupDirectoryDatabase(db *gorm.DB) (GroupDirectoryDatabase, error) {
+	if err := db.AutoMigrate(&relationtb.GroupDirectoryModel{}); err != nil {
+		return nil, err
+	}
+	return NewGroupDirectoryDatabase(relation.NewGroupDirectoryGorm(db)), nil
+}
+
+type groupDirectoryDatabase struct {
+	directory relationtb.GroupDirectoryModelInterface
+}
+
+func (g *groupDirectoryDatabase) SetGroupDirectory(ctx context.Context, entry *relationtb.GroupDirectoryModel) error {
+	return g.directory.Save(ctx, entry)
+}
+
+func (g *groupDirectoryDatabase) DeleteGroupDirectory(ctx context.Context, groupID string) error {
+	return g.directory.Delete(ctx, groupID)
+}
+
+func (g *groupDirectoryDatabase) TakeGroupDirectory(ctx context.Context, groupID string) (*relationtb.GroupDirectoryModel, error) {
+	return g.directory.Take(ctx, groupID)
+}
+
+func (g *groupDirectoryDatabase) SearchGroupDirectory(ctx context.Context, keyword string, category string, tag string, orderBy string, pageNumber, showNumber int32) (uint32, []*relationtb.GroupDirectoryModel, error) {
+	return g.directory.Search(ctx, keyword, category, tag, orderBy, pageNumber, showNumber)
+}
+
+func (g *groupDirectoryDatabase) PageGroupDirectory(ctx context.Context, pageNumber, showNumber int32) ([]*relationtb.GroupDirectoryModel, error) {
+	return g.directory.Page(ctx, pageNumber, showNumber)
+}
+
+func (g *groupDirectoryDatabase) UpdateGroupDirectoryActiveTime(ctx context.Context, groupID string, activeTime time.Time) error {
+	return g.directory.UpdateActiveTime(ctx, groupID, activeTime)
+}
@@ -0,0 +1,112 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"gorm.io/gorm"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/relation"
+	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+type GroupInviteLinkDatabase interface {
+	// CreateGroupInviteLink fills link.Code with a new random code
+	CreateGroupInviteLink(ctx context.Context, link *relationtb.GroupInviteLinkModel) error
+	TakeGroupInviteLink(ctx context.Context, code string) (*relationtb.GroupInviteLinkModel, error)
+	PageGroupInviteLinks(ctx context.Context, groupID string, valid bool, pageNumber, showNumber int32) (uint32, []*relationtb.GroupInviteLinkModel, error)
+	RevokeGroupInviteLink(ctx context.Context, code string) error
+	// UseGroupInviteLink takes one use of the link, it fails if the link is revoked, expired or used up
+	UseGroupInviteLink(ctx context.Context, code string) (*relationtb.GroupInviteLinkModel, error)
+	// UnuseGroupInviteLink gives the use back when the join failed
+	UnuseGroupInviteLink(ctx context.Context, code string) error
+}
+
+func NewGroupInviteLinkDatabase(link relationtb.GroupInviteLinkModelInterface) GroupInviteLinkDatabase {
+	return &groupInviteLinkDatabase{link: link}
+}
+
+func InitGroupInviteLinkDatabase(db *gorm.DB) (GroupInviteLinkDatabase, error) {
+	if err := db.AutoMigrate(&relationtb.GroupInviteLinkModel{}); err != nil {
+		return nil, err
+	}
+	return NewGroupInviteLinkDatabase(relation.NewGroupInviteLinkGorm(db)), nil
+}
+
+type groupInviteLinkDatabase struct {
+	link relationtb.GroupInviteLinkModelInterface
+}
+
+func (g *groupInviteLinkDatabase) genCode() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", errs.Wrap(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func (g *groupInviteLinkDatabase) CreateGroupInviteLink(ctx context.Context, link *relationtb.GroupInviteLinkModel) error {
+	code, err := g.genCode()
+	if err != nil {
+		return err
+	}
+	link.Code = code
+	return g.link.Create(ctx, link)
+}
+
+func (g *groupInviteLinkDatabase) TakeGroupInviteLink(ctx context.Context, code string) (*relationtb.GroupInviteLinkModel, error) {
+	return g.link.Take(ctx, code)
+}
+
+func (g *groupInviteLinkDatabase) PageGroupInviteLinks(ctx context.Context, groupID string, valid bool, pageNumber, showNumber int32) (uint32, []*relationtb.GroupInviteLinkModel, error) {
+	return g.link.Page(ctx, groupID, valid, pageNumber, showNumber)
+}
+
+func (g *groupInviteLinkDatabase) RevokeGroupInviteLink(ctx context.Context, code string) error {
+	_, err := g.link.Revoke(ctx, code)
+	return err
+}
+
+func (g *groupInviteLinkDatabase) UseGroupInviteLink(ctx context.Context, code string) (*relationtb.GroupInviteLinkModel, error) {
+	now := time.Now()
+	rows, err := g.link.Use(ctx, code, now)
+	if err != nil {
+		return nil, err
+	}
+	link, err := g.link.Take(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if rows > 0 {
+		return link, nil
+	}
+	switch {
+	case link.Revoked:
+		return nil, errs.ErrArgs.Wrap("invite link is revoked")
+	case !link.ExpireTime.After(now):
+		return nil, errs.ErrArgs.Wrap("invite link is expired")
+	default:
+		return nil, errs.ErrArgs.Wrap("invite link is used up")
+	}
+}
+
+func (g *groupInviteLinkDatabase) UnuseGroupInviteLink(ctx context.Context, code string) error {
+	return g.link.Unuse(ctx, code)
+}
@@ -0,0 +1,57 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/relation"
+	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+type GroupJoinRuleDatabase interface {
+	SetGroupJoinRule(ctx context.Context, rule *relationtb.GroupJoinRuleModel) error
+	DeleteGroupJoinRule(ctx context.Context, groupID string) error
+	TakeGroupJoinRule(ctx context.Context, groupID string) (*relationtb.GroupJoinRuleModel, error)
+}
+
+func NewGroupJoinRuleDatabase(rule relationtb.GroupJoinRuleModelInterface) GroupJoinRuleDatabase {
+	return &groupJoinRuleDatabase{rule: rule}
+}
+
+func InitGroupJoinRuleDatabase(db *gorm.DB) (GroupJoinRuleDatabase, error) {
+	if err := db.AutoMigrate(&relationtb.GroupJoinRuleModel{}); err != nil {
+		return nil, err
+	}
+	return NewGroupJoinRuleDatabase(relation.NewGroupJoinRuleGorm(db)), nil
+}
+
+type groupJoinRuleDatabase struct {
+	rule relationtb.GroupJoinRuleModelInterface
+}
+
+func (g *groupJoinRuleDatabase) SetGroupJoinRule(ctx context.Context, rule *relationtb.GroupJoinRuleModel) error {
+	return g.rule.Save(ctx, rule)
+}
+
+func (g *groupJoinRuleDatabase) DeleteGroupJoinRule(ctx context.Context, groupID string) error {
+	return g.rule.Delete(ctx, groupID)
+}
+
+func (g *groupJoinRuleDatabase) TakeGroupJoinRule(ctx context.Context, groupID string) (*relationtb.GroupJoinRuleModel, error) {
+	return g.rule.Take(ctx, groupID)
+}
@@ -0,0 +1,66 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/tools/tx"
+	"gorm.io/gorm"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/relation"
+	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+type GroupMemberTagDatabase interface {
+	// SetGroupMemberTag replaces the members carrying the tag
+	SetGroupMemberTag(ctx context.Context, groupID string, tag string, memberTags []*relationtb.GroupMemberTagModel) error
+	DeleteGroupMemberTag(ctx context.Context, groupID string, tag string) error
+	FindGroupMemberTags(ctx context.Context, groupID string, tags []string) ([]*relationtb.GroupMemberTagModel, error)
+}
+
+func NewGroupMemberTagDatabase(tag relationtb.GroupMemberTagModelInterface, tx tx.Tx) GroupMemberTagDatabase {
+	return &groupMemberTagDatabase{tag: tag, tx: tx}
+}
+
+func InitGroupMemberTagDatabase(db *gorm.DB) (GroupMemberTagDatabase, error) {
+	if err := db.AutoMigrate(&relationtb.GroupMemberTagModel{}); err != nil {
+		return nil, err
+	}
+	return NewGroupMemberTagDatabase(relation.NewGroupMemberTagGorm(db), tx.NewGorm(db)), nil
+}
+
+type groupMemberTagDatabase struct {
+	tag relationtb.GroupMemberTagModelInterface
+	tx  tx.Tx
+}
+
+func (g *groupMemberTagDatabase) SetGroupMemberTag(ctx context.Context, groupID string, tag string, memberTags []*relationtb.GroupMemberTagModel) error {
+	return g.tx.Transaction(func(tx any) error {
+		db := g.tag.NewTx(tx)
+		if err := db.Delete(ctx, groupID, tag); err != nil {
+			return err
+		}
+		return db.Create(ctx, memberTags)
+	})
+}
+
+func (g *groupMemberTagDatabase) DeleteGroupMemberTag(ctx context.Context, groupID string, tag string) error {
+	return g.tag.Delete(ctx, groupID, tag)
+}
+
+func (g *groupMemberTagDatabase) FindGroupMemberTags(ctx context.Context, groupID string, tags []string) ([]*relationtb.GroupMemberTagModel, error) {
+	return g.tag.Find(ctx, groupID, tags)
+}
@@ -0,0 +1,108 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/relation"
+	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+type GroupMuteScheduleDatabase interface {
+	// CreateGroupMuteSchedule fills schedule.ID with a new id
+	CreateGroupMuteSchedule(ctx context.Context, schedule *relationtb.GroupMuteScheduleModel) error
+	TakeGroupMuteSchedule(ctx context.Context, id string) (*relationtb.GroupMuteScheduleModel, error)
+	DeleteGroupMuteSchedules(ctx context.Context, groupID string, ids []string) error
+	SetGroupMuteScheduleActive(ctx context.Context, groupID string, id string, active bool) error
+	// FindGroupMuteSchedules reads the cached schedules of the group
+	FindGroupMuteSchedules(ctx context.Context, groupID string) ([]*relationtb.GroupMuteScheduleModel, error)
+	// IsGroupScheduleMuted whether a schedule of the group covers now
+	IsGroupScheduleMuted(ctx context.Context, groupID string, now time.Time) (bool, error)
+	PageGroupMuteSchedules(ctx context.Context, pageNumber, showNumber int32) (uint32, []*relationtb.GroupMuteScheduleModel, error)
+}
+
+func NewGroupMuteScheduleDatabase(schedule relationtb.GroupMuteScheduleModelInterface, cache cache.GroupMuteScheduleCache) GroupMuteScheduleDatabase {
+	return &groupMuteScheduleDatabase{schedule: schedule, cache: cache}
+}
+
+func InitGroupMuteScheduleDatabase(db *gorm.DB, rdb redis.UniversalClient) (GroupMuteScheduleDatabase, error) {
+	if err := db.AutoMigrate(&relationtb.GroupMuteScheduleModel{}); err != nil {
+		return nil, err
+	}
+	scheduleDB := relation.NewGroupMuteScheduleGorm(db)
+	return NewGroupMuteScheduleDatabase(scheduleDB, cache.NewGroupMuteScheduleCacheRedis(rdb, scheduleDB)), nil
+}
+
+type groupMuteScheduleDatabase struct {
+	schedule relationtb.GroupMuteScheduleModelInterface
+	cache    cache.GroupMuteScheduleCache
+}
+
+func (g *groupMuteScheduleDatabase) CreateGroupMuteSchedule(ctx context.Context, schedule *relationtb.GroupMuteScheduleModel) error {
+	schedule.ID = uuid.New().String()
+	if err := g.schedule.Create(ctx, []*relationtb.GroupMuteScheduleModel{schedule}); err != nil {
+		return err
+	}
+	return g.cache.DelGroupMuteSchedules(schedule.GroupID).ExecDel(ctx)
+}
+
+func (g *groupMuteScheduleDatabase) TakeGroupMuteSchedule(ctx context.Context, id string) (*relationtb.GroupMuteScheduleModel, error) {
+	return g.schedule.Take(ctx, id)
+}
+
+func (g *groupMuteScheduleDatabase) DeleteGroupMuteSchedules(ctx context.Context, groupID string, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := g.schedule.Delete(ctx, ids); err != nil {
+		return err
+	}
+	return g.cache.DelGroupMuteSchedules(groupID).ExecDel(ctx)
+}
+
+func (g *groupMuteScheduleDatabase) SetGroupMuteScheduleActive(ctx context.Context, groupID string, id string, active bool) error {
+	if err := g.schedule.UpdateActive(ctx, id, active); err != nil {
+		return err
+	}
+	return g.cache.DelGroupMuteSchedules(groupID).ExecDel(ctx)
+}
+
+func (g *groupMuteScheduleDatabase) FindGroupMuteSchedules(ctx context.Context, groupID string) ([]*relationtb.GroupMuteScheduleModel, error) {
+	return g.cache.GetGroupMuteSchedules(ctx, groupID)
+}
+
+func (g *groupMuteScheduleDatabase) IsGroupScheduleMuted(ctx context.Context, groupID string, now time.Time) (bool, error) {
+	schedules, err := g.cache.GetGroupMuteSchedules(ctx, groupID)
+	if err != nil {
+		return false, err
+	}
+	for _, schedule := range schedules {
+		if schedule.Covers(now) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (g *groupMuteScheduleDatabase) PageGroupMuteSchedules(ctx context.Context, pageNumber, showNumber int32) (uint32, []*relationtb.GroupMuteScheduleModel, error) {
+	return g.schedule.Page(ctx, pageNumber, showNumber)
+}
@@ -0,0 +1,93 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/relation"
+	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+type GroupRoleDatabase interface {
+	SetGroupRole(ctx context.Context, role *relationtb.GroupRoleModel) error
+	DeleteGroupRole(ctx context.Context, groupID string, roleLevel int32) error
+	TakeGroupRole(ctx context.Context, groupID string, roleLevel int32) (*relationtb.GroupRoleModel, error)
+	// FindGroupRoles reads the cached roles of the group
+	FindGroupRoles(ctx context.Context, groupID string) ([]*relationtb.GroupRoleModel, error)
+	// GetGroupRolePermissions the permissions of a custom role, 0 if the group has no such role
+	GetGroupRolePermissions(ctx context.Context, groupID string, roleLevel int32) (int64, error)
+}
+
+func NewGroupRoleDatabase(role relationtb.GroupRoleModelInterface, cache cache.GroupRoleCache) GroupRoleDatabase {
+	return &groupRoleDatabase{role: role, cache: cache}
+}
+
+func InitGroupRoleDatabase(db *gorm.DB, rdb redis.UniversalClient) (GroupRoleDatabase, error) {
+	if err := db.AutoMigrate(&relationtb.GroupRoleModel{}); err != nil {
+		return nil, err
+	}
+	roleDB := relation.NewGroupRoleGorm(db)
+	return NewGroupRoleDatabase(roleDB, cache.NewGroupRoleCacheRedis(rdb, roleDB)), nil
+}
+
+type groupRoleDatabase struct {
+	role  relationtb.GroupRoleModelInterface
+	cache cache.GroupRoleCache
+}
+
+func (g *groupRoleDatabase) SetGroupRole(ctx context.Context, role *relationtb.GroupRoleModel) error {
+	if err := g.role.Save(ctx, role); err != nil {
+		return err
+	}
+	return g.cache.DelGroupRoles(role.GroupID).ExecDel(ctx)
+}
+
+func (g *groupRoleDatabase) DeleteGroupRole(ctx context.Context, groupID string, roleLevel int32) error {
+	rows, err := g.role.Delete(ctx, groupID, roleLevel)
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errs.ErrRecordNotFound.Wrap("group role not found")
+	}
+	return g.cache.DelGroupRoles(groupID).ExecDel(ctx)
+}
+
+func (g *groupRoleDatabase) TakeGroupRole(ctx context.Context, groupID string, roleLevel int32) (*relationtb.GroupRoleModel, error) {
+	return g.role.Take(ctx, groupID, roleLevel)
+}
+
+func (g *groupRoleDatabase) FindGroupRoles(ctx context.Context, groupID string) ([]*relationtb.GroupRoleModel, error) {
+	return g.cache.GetGroupRoles(ctx, groupID)
+}
+
+func (g *groupRoleDatabase) GetGroupRolePermissions(ctx context.Context, groupID string, roleLevel int32) (int64, error) {
+	roles, err := g.FindGroupRoles(ctx, groupID)
+	if err != nil {
+		return 0, err
+	}
+	for _, role := range roles {
+		if role.RoleLevel == roleLevel {
+			return role.Permissions, nil
+		}
+	}
+	return 0, nil
+}
@@ -0,0 +1,129 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/tx"
+	"github.com/OpenIMSDK/tools/utils"
+	"gorm.io/gorm"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/relation"
+	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+// LegalHoldDatabase holds keep messages from being deleted, every change is written to the audit log in the same transaction.
+type LegalHoldDatabase interface {
+	SetLegalHold(ctx context.Context, hold *relationtb.LegalHoldModel) error
+	ReleaseLegalHold(ctx context.Context, targetType int32, targetID string, operatorUserID string, reason string) error
+	// FindHeldConversationIDs the conversations which must not be deleted
+	FindHeldConversationIDs(ctx context.Context, conversationIDs []string) ([]string, error)
+	PageLegalHolds(ctx context.Context, targetType int32, pageNumber, showNumber int32) (uint32, []*relationtb.LegalHoldModel, error)
+	PageLegalHoldLogs(ctx context.Context, targetType int32, targetID string, pageNumber, showNumber int32) (uint32, []*relationtb.LegalHoldLogModel, error)
+}
+
+func NewLegalHoldDatabase(hold relationtb.LegalHoldModelInterface, holdLog relationtb.LegalHoldLogModelInterface, conversation relationtb.ConversationModelInterface, tx tx.Tx) LegalHoldDatabase {
+	return &legalHoldDatabase{hold: hold, holdLog: holdLog, conversation: conversation, tx: tx}
+}
+
+func InitLegalHoldDatabase(db *gorm.DB) (LegalHoldDatabase, error) {
+	if err := db.AutoMigrate(&relationtb.LegalHoldModel{}, &relationtb.LegalHoldLogModel{}); err != nil {
+		return nil, err
+	}
+	return NewLegalHoldDatabase(relation.NewLegalHoldGorm(db), relation.NewLegalHoldLogGorm(db), relation.NewConversationGorm(db), tx.NewGorm(db)), nil
+}
+
+type legalHoldDatabase struct {
+	hold         relationtb.LegalHoldModelInterface
+	holdLog      relationtb.LegalHoldLogModelInterface
+	conversation relationtb.ConversationModelInterface
+	tx           tx.Tx
+}
+
+func (l *legalHoldDatabase) SetLegalHold(ctx context.Context, hold *relationtb.LegalHoldModel) error {
+	return l.tx.Transaction(func(tx any) error {
+		holdTx := l.hold.NewTx(tx)
+		if _, err := holdTx.Take(ctx, hold.TargetType, hold.TargetID); err == nil {
+			return errs.ErrArgs.Wrap("legal hold already exists")
+		} else if !relationtb.IsNotFound(err) {
+			return err
+		}
+		if err := holdTx.Create(ctx, hold); err != nil {
+			return err
+		}
+		return l.holdLog.NewTx(tx).Create(ctx, []*relationtb.LegalHoldLogModel{{
+			TargetType:     hold.TargetType,
+			TargetID:       hold.TargetID,
+			Action:         relationtb.LegalHoldActionSet,
+			Reason:         hold.Reason,
+			OperatorUserID: hold.OperatorUserID,
+			CreateTime:     hold.CreateTime,
+		}})
+	})
+}
+
+func (l *legalHoldDatabase) ReleaseLegalHold(ctx context.Context, targetType int32, targetID string, operatorUserID string, reason string) error {
+	return l.tx.Transaction(func(tx any) error {
+		rows, err := l.hold.NewTx(tx).Delete(ctx, targetType, targetID)
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return errs.ErrRecordNotFound.Wrap("legal hold not found")
+		}
+		return l.holdLog.NewTx(tx).Create(ctx, []*relationtb.LegalHoldLogModel{{
+			TargetType:     targetType,
+			TargetID:       targetID,
+			Action:         relationtb.LegalHoldActionRelease,
+			Reason:         reason,
+			OperatorUserID: operatorUserID,
+			CreateTime:     time.Now(),
+		}})
+	})
+}
+
+// FindHeldConversationIDs a conversation is held directly, or through a hold on its owner.
+func (l *legalHoldDatabase) FindHeldConversationIDs(ctx context.Context, conversationIDs []string) ([]string, error) {
+	if len(conversationIDs) == 0 {
+		return nil, nil
+	}
+	held, err := l.hold.FindTargetIDs(ctx, relationtb.LegalHoldTargetConversation, conversationIDs)
+	if err != nil {
+		return nil, err
+	}
+	heldUserIDs, err := l.hold.FindTargetIDs(ctx, relationtb.LegalHoldTargetUser, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(heldUserIDs) > 0 {
+		ownerHeld, err := l.conversation.FindOwnerConversationIDs(ctx, heldUserIDs, conversationIDs)
+		if err != nil {
+			return nil, err
+		}
+		held = append(held, ownerHeld...)
+	}
+	return utils.Distinct(held), nil
+}
+
+func (l *legalHoldDatabase) PageLegalHolds(ctx context.Context, targetType int32, pageNumber, showNumber int32) (uint32, []*relationtb.LegalHoldModel, error) {
+	return l.hold.Page(ctx, targetType, pageNumber, showNumber)
+}
+
+func (l *legalHoldDatabase) PageLegalHoldLogs(ctx context.Context, targetType int32, targetID string, pageNumber, showNumber int32) (uint32, []*relationtb.LegalHoldLogModel, error) {
+	return l.holdLog.Page(ctx, targetType, targetID, pageNumber, showNumber)
+}
@@ -0,0 +1,138 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/OpenIMSDK/tools/utils"
+
+	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+type legalHoldModel struct {
+	relationtb.LegalHoldModelInterface
+	holds []*relationtb.LegalHoldModel
+}
+
+func (m *legalHoldModel) FindTargetIDs(ctx context.Context, targetType int32, targetIDs []string) ([]string, error) {
+	var held []string
+	for _, hold := range m.holds {
+		if hold.TargetType == targetType && (targetIDs == nil || utils.IsContain(hold.TargetID, targetIDs)) {
+			held = append(held, hold.TargetID)
+		}
+	}
+	return held, nil
+}
+
+type ownerConversationModel struct {
+	relationtb.ConversationModelInterface
+	conversations []*relationtb.ConversationModel
+	calls         int
+}
+
+func (m *ownerConversationModel) FindOwnerConversationIDs(ctx context.Context, ownerUserIDs []string, conversationIDs []string) ([]string, error) {
+	m.calls++
+	var owned []string
+	for _, conversation := range m.conversations {
+		if utils.IsContain(conversation.OwnerUserID, ownerUserIDs) && utils.IsContain(conversation.ConversationID, conversationIDs) {
+			owned = append(owned, conversation.ConversationID)
+		}
+	}
+	return utils.Distinct(owned), nil
+}
+
+func TestFindHeldConversationIDs(t *testing.T) {
+	conversations := []*relationtb.ConversationModel{
+		{OwnerUserID: "u1", ConversationID: "si_u1_u2"},
+		{OwnerUserID: "u2", ConversationID: "si_u1_u2"},
+		{OwnerUserID: "u1", ConversationID: "sg_g1"},
+		{OwnerUserID: "u2", ConversationID: "sg_g1"},
+		{OwnerUserID: "u3", ConversationID: "sg_g2"},
+		{OwnerUserID: "u3", ConversationID: "si_u3_u4"},
+	}
+	tests := []struct {
+		name            string
+		holds           []*relationtb.LegalHoldModel
+		conversationIDs []string
+		want            []string
+		wantOwnerLookup bool
+	}{
+		{
+			name:            "no holds",
+			conversationIDs: []string{"si_u1_u2", "sg_g1"},
+			want:            []string{},
+		},
+		{
+			name:            "conversation hold",
+			holds:           []*relationtb.LegalHoldModel{{TargetType: relationtb.LegalHoldTargetConversation, TargetID: "sg_g1"}},
+			conversationIDs: []string{"si_u1_u2", "sg_g1"},
+			want:            []string{"sg_g1"},
+		},
+		{
+			name:            "user hold expands to the conversations of the user",
+			holds:           []*relationtb.LegalHoldModel{{TargetType: relationtb.LegalHoldTargetUser, TargetID: "u1"}},
+			conversationIDs: []string{"si_u1_u2", "sg_g1", "sg_g2"},
+			want:            []string{"sg_g1", "si_u1_u2"},
+			wantOwnerLookup: true,
+		},
+		{
+			name: "user hold only covers the asked conversations",
+			holds: []*relationtb.LegalHoldModel{
+				{TargetType: relationtb.LegalHoldTargetUser, TargetID: "u3"},
+				{TargetType: relationtb.LegalHoldTargetConversation, TargetID: "sg_g1"},
+			},
+			conversationIDs: []string{"sg_g1", "sg_g2"},
+			want:            []string{"sg_g1", "sg_g2"},
+			wantOwnerLookup: true,
+		},
+		{
+			name: "held twice is returned once",
+			holds: []*relationtb.LegalHoldModel{
+				{TargetType: relationtb.LegalHoldTargetUser, TargetID: "u2"},
+				{TargetType: relationtb.LegalHoldTargetConversation, TargetID: "sg_g1"},
+			},
+			conversationIDs: []string{"sg_g1"},
+			want:            []string{"sg_g1"},
+			wantOwnerLookup: true,
+		},
+		{
+			name:  "no conversations",
+			holds: []*relationtb.LegalHoldModel{{TargetType: relationtb.LegalHoldTargetUser, TargetID: "u1"}},
+			want:  []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conversation := &ownerConversationModel{conversations: conversations}
+			db := &legalHoldDatabase{hold: &legalHoldModel{holds: tt.holds}, conversation: conversation}
+			got, err := db.FindHeldConversationIDs(context.Background(), tt.conversationIDs)
+			if err != nil {
+				t.Fatalf("FindHeldConversationIDs() error = %v", err)
+			}
+			got = append([]string{}, got...)
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindHeldConversationIDs() = %v, want %v", got, tt.want)
+			}
+			if (conversation.calls > 0) != tt.wantOwnerLookup {
+				t.Errorf("owner lookups = %d, want lookup %v", conversation.calls, tt.wantOwnerLookup)
+			}
+		})
+	}
+}
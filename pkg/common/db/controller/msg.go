@@ -18,6 +18,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/OpenIMSDK/protocol/constant"
@@ -37,6 +38,7 @@ import (
 	unrelationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/unrelation"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/unrelation"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/kafka"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/mq"
 
 	pbmsg "github.com/OpenIMSDK/protocol/msg"
 	"github.com/OpenIMSDK/protocol/sdkws"
@@ -72,12 +74,16 @@ type CommonMsgDatabase interface {
 
 	// 用户根据seq删除消息
 	DeleteUserMsgsBySeqs(ctx context.Context, userID string, conversationID string, seqs []int64) error
+	// 用户删除会话中maxSeq及之前所有可见的消息, 只写入del_list不改变minSeq, 已归档的消息通过提高用户的minSeq隐藏
+	DeleteUserConversationMsgs(ctx context.Context, userID string, conversationID string, maxSeq int64) error
 	// 物理删除消息置空
 	DeleteMsgsPhysicalBySeqs(ctx context.Context, conversationID string, seqs []int64) error
 
 	SetMaxSeq(ctx context.Context, conversationID string, maxSeq int64) error
 	GetMaxSeqs(ctx context.Context, conversationIDs []string) (map[string]int64, error)
 	GetMaxSeq(ctx context.Context, conversationID string) (int64, error)
+	// GetArchivedMaxSeq the messages up to the seq are archived and read only, 0 if the archive is not enabled
+	GetArchivedMaxSeq(ctx context.Context, conversationID string) (int64, error)
 	SetMinSeq(ctx context.Context, conversationID string, minSeq int64) error
 	SetMinSeqs(ctx context.Context, seqs map[string]int64) error
 
@@ -92,8 +98,13 @@ type CommonMsgDatabase interface {
 	GetHasReadSeqs(ctx context.Context, userID string, conversationIDs []string) (map[string]int64, error)
 	GetHasReadSeq(ctx context.Context, userID string, conversationID string) (int64, error)
 	UserSetHasReadSeqs(ctx context.Context, userID string, hasReadSeqs map[string]int64) error
+	// IncrUserBadgeUnreadCountSums adjust the cached badges of the users, a missing badge is recounted on the next push
+	IncrUserBadgeUnreadCountSums(ctx context.Context, userIDs []string, delta int) error
+	DelUserBadgeUnreadCountSum(ctx context.Context, userIDs ...string) error
 
 	GetMongoMaxAndMinSeq(ctx context.Context, conversationID string) (minSeqMongo, maxSeqMongo int64, err error)
+	// GetNewestMsg the newest msg stored in mongo
+	GetNewestMsg(ctx context.Context, conversationID string) (*unrelationtb.MsgInfoModel, error)
 	GetConversationMinMaxSeqInMongoAndCache(ctx context.Context, conversationID string) (minSeqMongo, maxSeqMongo, minSeqCache, maxSeqCache int64, err error)
 	SetSendMsgStatus(ctx context.Context, id string, status int32) error
 	GetSendMsgStatus(ctx context.Context, id string) (int32, error)
@@ -104,6 +115,8 @@ type CommonMsgDatabase interface {
 	MsgToModifyMQ(ctx context.Context, key, conversarionID string, msgs []*sdkws.MsgData) error
 	MsgToPushMQ(ctx context.Context, key, conversarionID string, msg2mq *sdkws.MsgData) (int32, int64, error)
 	MsgToMongoMQ(ctx context.Context, key, conversarionID string, msgs []*sdkws.MsgData, lastSeq int64) error
+	// MsgToDeadLetterMQ keeps a batch that failed at stage, see kafka.DeadLetterStageRedis and kafka.DeadLetterStageMongo
+	MsgToDeadLetterMQ(ctx context.Context, key, stage, conversationID string, msgs []*sdkws.MsgData, lastSeq int64, cause error) error
 
 	RangeUserSendCount(
 		ctx context.Context,
@@ -125,31 +138,37 @@ type CommonMsgDatabase interface {
 	ConvertMsgsDocLen(ctx context.Context, conversationIDs []string)
 }
 
-func NewCommonMsgDatabase(msgDocModel unrelationtb.MsgDocModelInterface, cacheModel cache.MsgModel) CommonMsgDatabase {
+// NewCommonMsgDatabase archive is nil if the msg docs are not archived.
+func NewCommonMsgDatabase(msgDocModel unrelationtb.MsgDocModelInterface, cacheModel cache.MsgModel, archive MsgArchiveDatabase) CommonMsgDatabase {
 	return &commonMsgDatabase{
-		msgDocDatabase:  msgDocModel,
-		cache:           cacheModel,
-		producer:        kafka.NewKafkaProducer(config.Config.Kafka.Addr, config.Config.Kafka.LatestMsgToRedis.Topic),
-		producerToMongo: kafka.NewKafkaProducer(config.Config.Kafka.Addr, config.Config.Kafka.MsgToMongo.Topic),
-		producerToPush:  kafka.NewKafkaProducer(config.Config.Kafka.Addr, config.Config.Kafka.MsgToPush.Topic),
+		msgDocDatabase:       msgDocModel,
+		cache:                cacheModel,
+		archive:              archive,
+		producer:             mq.NewProducer(config.Config.Kafka.LatestMsgToRedis.Topic),
+		producerToMongo:      mq.NewProducer(config.Config.Kafka.MsgToMongo.Topic),
+		producerToPush:       mq.NewProducer(config.Config.Kafka.MsgToPush.Topic),
+		producerToDeadLetter: mq.NewProducer(config.Config.Kafka.MsgDeadLetter.Topic),
+		producerToModify:     mq.NewProducer(config.Config.Kafka.MsgToModify.Topic),
 	}
 }
 
 func InitCommonMsgDatabase(rdb redis.UniversalClient, database *mongo.Database) CommonMsgDatabase {
 	cacheModel := cache.NewMsgCacheModel(rdb)
-	msgDocModel := unrelation.NewMsgMongoDriver(database)
-	CommonMsgDatabase := NewCommonMsgDatabase(msgDocModel, cacheModel)
+	msgDocModel := unrelation.NewMsgDocModel(database)
+	CommonMsgDatabase := NewCommonMsgDatabase(msgDocModel, cacheModel, nil)
 	return CommonMsgDatabase
 }
 
 type commonMsgDatabase struct {
-	msgDocDatabase   unrelationtb.MsgDocModelInterface
-	msg              unrelationtb.MsgDocModel
-	cache            cache.MsgModel
-	producer         *kafka.Producer
-	producerToMongo  *kafka.Producer
-	producerToModify *kafka.Producer
-	producerToPush   *kafka.Producer
+	msgDocDatabase       unrelationtb.MsgDocModelInterface
+	msg                  unrelationtb.MsgDocModel
+	cache                cache.MsgModel
+	archive              MsgArchiveDatabase
+	producer             mq.Producer
+	producerToMongo      mq.Producer
+	producerToModify     mq.Producer
+	producerToPush       mq.Producer
+	producerToDeadLetter mq.Producer
 }
 
 func (db *commonMsgDatabase) MsgToMQ(ctx context.Context, key string, msg2mq *sdkws.MsgData) error {
@@ -165,6 +184,19 @@ func (db *commonMsgDatabase) MsgToModifyMQ(ctx context.Context, key, conversatio
 	return nil
 }
 
+// msgStateToModifyMQ sends a revoke or delete of stored msgs to the chat log mirror, see kafka.ModifyTypeRevoke.
+// The msgs are already changed, a failure is only logged.
+func (db *commonMsgDatabase) msgStateToModifyMQ(ctx context.Context, modifyType, conversationID string, messages []*sdkws.MsgData) {
+	if !config.Config.ChatPersistenceMysql || len(messages) == 0 {
+		return
+	}
+	_, _, err := db.producerToModify.SendMessage(ctx, conversationID, &pbmsg.MsgDataToModifyByMQ{ConversationID: conversationID, Messages: messages},
+		kafka.ModifyHeaders(modifyType)...)
+	if err != nil {
+		log.ZError(ctx, "msg state to modify mq failed", err, "modifyType", modifyType, "conversationID", conversationID)
+	}
+}
+
 func (db *commonMsgDatabase) MsgToPushMQ(ctx context.Context, key, conversationID string, msg2mq *sdkws.MsgData) (int32, int64, error) {
 	partition, offset, err := db.producerToPush.SendMessage(ctx, key, &pbmsg.PushMsgDataToMQ{MsgData: msg2mq, ConversationID: conversationID})
 	if err != nil {
@@ -182,6 +214,15 @@ func (db *commonMsgDatabase) MsgToMongoMQ(ctx context.Context, key, conversation
 	return nil
 }
 
+func (db *commonMsgDatabase) MsgToDeadLetterMQ(ctx context.Context, key, stage, conversationID string, messages []*sdkws.MsgData, lastSeq int64, cause error) error {
+	if len(messages) > 0 {
+		_, _, err := db.producerToDeadLetter.SendMessage(ctx, key, &pbmsg.MsgDataToMongoByMQ{LastSeq: lastSeq, ConversationID: conversationID, MsgData: messages},
+			kafka.DeadLetterHeaders(stage, cause, time.Now())...)
+		return err
+	}
+	return nil
+}
+
 func (db *commonMsgDatabase) BatchInsertBlock(ctx context.Context, conversationID string, fields []any, key int8, firstSeq int64) error {
 	if len(fields) == 0 {
 		return nil
@@ -330,10 +371,24 @@ func (db *commonMsgDatabase) BatchInsertChat2DB(ctx context.Context, conversatio
 }
 
 func (db *commonMsgDatabase) RevokeMsg(ctx context.Context, conversationID string, seq int64, revoke *unrelationtb.RevokeModel) error {
-	return db.BatchInsertBlock(ctx, conversationID, []any{revoke}, updateKeyRevoke, seq)
+	if err := db.checkNotArchived(ctx, conversationID, []int64{seq}); err != nil {
+		return err
+	}
+	if err := db.BatchInsertBlock(ctx, conversationID, []any{revoke}, updateKeyRevoke, seq); err != nil {
+		return err
+	}
+	content, err := json.Marshal(revoke)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	db.msgStateToModifyMQ(ctx, kafka.ModifyTypeRevoke, conversationID, []*sdkws.MsgData{{Seq: seq, Content: content}})
+	return nil
 }
 
 func (db *commonMsgDatabase) MarkSingleChatMsgsAsRead(ctx context.Context, userID string, conversationID string, totalSeqs []int64) error {
+	if err := db.checkNotArchived(ctx, conversationID, totalSeqs); err != nil {
+		return err
+	}
 	for docID, seqs := range db.msg.GetDocIDSeqsMap(conversationID, totalSeqs) {
 		var indexes []int64
 		for _, seq := range seqs {
@@ -356,12 +411,9 @@ func (db *commonMsgDatabase) DelUserDeleteMsgsList(ctx context.Context, conversa
 	db.cache.DelUserDeleteMsgsList(ctx, conversationID, seqs)
 }
 
+// BatchInsertChat2Cache gives the messages their seqs and caches them. The seqs are allocated atomically,
+// so several msgtransfer processes consuming the same conversation never hand out a seq twice.
 func (db *commonMsgDatabase) BatchInsertChat2Cache(ctx context.Context, conversationID string, msgs []*sdkws.MsgData) (seq int64, isNew bool, err error) {
-	currentMaxSeq, err := db.cache.GetMaxSeq(ctx, conversationID)
-	if err != nil && errs.Unwrap(err) != redis.Nil {
-		log.ZError(ctx, "db.cache.GetMaxSeq", err)
-		return 0, false, err
-	}
 	lenList := len(msgs)
 	if int64(lenList) > db.msg.GetSingleGocMsgNum() {
 		return 0, false, errors.New("too large")
@@ -369,15 +421,60 @@ func (db *commonMsgDatabase) BatchInsertChat2Cache(ctx context.Context, conversa
 	if lenList < 1 {
 		return 0, false, errors.New("too short as 0")
 	}
-	if errs.Unwrap(err) == redis.Nil {
-		isNew = true
+	clientMsgSeqs, err := db.getClientMsgSeqs(ctx, conversationID, msgs)
+	if err != nil {
+		return 0, false, err
+	}
+	// seqs recorded for the client msg ids are never handed out again, even if the max seq lost them
+	var floor int64
+	for _, seq := range clientMsgSeqs {
+		if seq > floor {
+			floor = seq
+		}
+	}
+	var size int64
+	newClientMsgIDs := make(map[string]struct{})
+	for _, m := range msgs {
+		if _, ok := clientMsgSeqs[m.ClientMsgID]; ok {
+			continue
+		}
+		if m.ClientMsgID != "" {
+			if _, ok := newClientMsgIDs[m.ClientMsgID]; ok {
+				continue
+			}
+			newClientMsgIDs[m.ClientMsgID] = struct{}{}
+		}
+		size++
+	}
+	currentMaxSeq, isNew, err := db.cache.AllocateSeqs(ctx, conversationID, floor, size)
+	if err != nil {
+		log.ZError(ctx, "db.cache.AllocateSeqs", err, "conversationID", conversationID)
+		prommetrics.SeqSetFailedCounter.Inc()
+		return 0, false, err
 	}
-	lastMaxSeq := currentMaxSeq
+	lastMaxSeq := currentMaxSeq - size
+	nextSeq := lastMaxSeq
+	newClientMsgSeqs := make(map[string]int64)
 	userSeqMap := make(map[string]int64)
 	for _, m := range msgs {
-		currentMaxSeq++
-		m.Seq = currentMaxSeq
-		userSeqMap[m.SendID] = m.Seq
+		if seq, ok := clientMsgSeqs[m.ClientMsgID]; ok {
+			// re-delivered, or sent again by the client, the message keeps the seq it was given
+			m.Seq = seq
+		} else {
+			nextSeq++
+			m.Seq = nextSeq
+			if m.ClientMsgID != "" {
+				clientMsgSeqs[m.ClientMsgID] = m.Seq
+				newClientMsgSeqs[m.ClientMsgID] = m.Seq
+			}
+		}
+		if m.Seq > userSeqMap[m.SendID] {
+			userSeqMap[m.SendID] = m.Seq
+		}
+	}
+	// the seqs are recorded right after they are allocated, a batch re-delivered after a crash at any later point reuses them
+	if err := db.setClientMsgSeqs(ctx, conversationID, newClientMsgSeqs); err != nil {
+		return 0, false, err
 	}
 	failedNum, err := db.cache.SetMessageToCache(ctx, conversationID, msgs)
 	if err != nil {
@@ -386,17 +483,44 @@ func (db *commonMsgDatabase) BatchInsertChat2Cache(ctx context.Context, conversa
 	} else {
 		prommetrics.MsgInsertRedisSuccessCounter.Inc()
 	}
-	err = db.cache.SetMaxSeq(ctx, conversationID, currentMaxSeq)
-	if err != nil {
-		log.ZError(ctx, "db.cache.SetMaxSeq error", err, "conversationID", conversationID)
+	if err := db.cache.SetHasReadSeqs(ctx, conversationID, userSeqMap); err != nil {
+		log.ZError(ctx, "SetHasReadSeqs error", err, "userSeqMap", userSeqMap, "conversationID", conversationID)
 		prommetrics.SeqSetFailedCounter.Inc()
 	}
-	err2 := db.cache.SetHasReadSeqs(ctx, conversationID, userSeqMap)
+	return lastMaxSeq, isNew, nil
+}
+
+// getClientMsgSeqs k: clientMsgID, v: the seq a message of msgs was already given in the conversation.
+func (db *commonMsgDatabase) getClientMsgSeqs(ctx context.Context, conversationID string, msgs []*sdkws.MsgData) (map[string]int64, error) {
+	if config.Config.MsgTransfer.SeqDedupeTimeout <= 0 {
+		return make(map[string]int64), nil
+	}
+	clientMsgIDs := make([]string, 0, len(msgs))
+	for _, m := range msgs {
+		if m.ClientMsgID != "" {
+			clientMsgIDs = append(clientMsgIDs, m.ClientMsgID)
+		}
+	}
+	if len(clientMsgIDs) == 0 {
+		return make(map[string]int64), nil
+	}
+	seqs, err := db.cache.GetClientMsgSeqs(ctx, conversationID, utils.Distinct(clientMsgIDs))
 	if err != nil {
-		log.ZError(ctx, "SetHasReadSeqs error", err2, "userSeqMap", userSeqMap, "conversationID", conversationID)
-		prommetrics.SeqSetFailedCounter.Inc()
+		log.ZError(ctx, "db.cache.GetClientMsgSeqs error", err, "conversationID", conversationID)
+		return nil, err
+	}
+	return seqs, nil
+}
+
+func (db *commonMsgDatabase) setClientMsgSeqs(ctx context.Context, conversationID string, seqs map[string]int64) error {
+	if config.Config.MsgTransfer.SeqDedupeTimeout <= 0 {
+		return nil
+	}
+	err := db.cache.SetClientMsgSeqs(ctx, conversationID, seqs, time.Duration(config.Config.MsgTransfer.SeqDedupeTimeout)*time.Second)
+	if err != nil {
+		log.ZError(ctx, "db.cache.SetClientMsgSeqs error", err, "conversationID", conversationID)
 	}
-	return lastMaxSeq, isNew, utils.Wrap(err, "")
+	return err
 }
 
 func (db *commonMsgDatabase) getMsgBySeqs(ctx context.Context, userID, conversationID string, seqs []int64) (totalMsgs []*sdkws.MsgData, err error) {
@@ -440,7 +564,7 @@ func (db *commonMsgDatabase) handlerDBMsg(ctx context.Context, cache map[int64][
 		msgs = v
 	} else {
 		if quoteMsg.QuoteMessage.Seq > 0 {
-			ms, err := db.msgDocDatabase.GetMsgBySeqIndexIn1Doc(ctx, userID, db.msg.GetDocID(conversationID, quoteMsg.QuoteMessage.Seq), []int64{quoteMsg.QuoteMessage.Seq})
+			ms, err := db.getMsgBySeqIndexIn1Doc(ctx, userID, db.msg.GetDocID(conversationID, quoteMsg.QuoteMessage.Seq), conversationID, []int64{quoteMsg.QuoteMessage.Seq})
 			if err != nil {
 				log.ZError(ctx, "GetMsgBySeqIndexIn1Doc", err, "conversationID", conversationID, "seq", quoteMsg.QuoteMessage.Seq)
 				return
@@ -469,8 +593,22 @@ func (db *commonMsgDatabase) handlerDBMsg(ctx context.Context, cache map[int64][
 	}
 }
 
+// getMsgBySeqIndexIn1Doc reads the doc from the archive once its seqs are below the mongo range.
+func (db *commonMsgDatabase) getMsgBySeqIndexIn1Doc(ctx context.Context, userID, docID string, conversationID string, seqs []int64) ([]*unrelationtb.MsgInfoModel, error) {
+	if db.archive != nil && len(seqs) > 0 {
+		archivedMaxSeq, err := db.archive.GetArchivedMaxSeq(ctx, conversationID)
+		if err != nil {
+			return nil, err
+		}
+		if seqs[0] <= archivedMaxSeq {
+			return db.archive.GetMsgBySeqIndexIn1Doc(ctx, userID, docID, seqs)
+		}
+	}
+	return db.msgDocDatabase.GetMsgBySeqIndexIn1Doc(ctx, userID, docID, seqs)
+}
+
 func (db *commonMsgDatabase) findMsgInfoBySeq(ctx context.Context, userID, docID string, conversationID string, seqs []int64) (totalMsgs []*unrelationtb.MsgInfoModel, err error) {
-	msgs, err := db.msgDocDatabase.GetMsgBySeqIndexIn1Doc(ctx, userID, docID, seqs)
+	msgs, err := db.getMsgBySeqIndexIn1Doc(ctx, userID, docID, conversationID, seqs)
 	if err != nil {
 		return nil, err
 	}
@@ -716,6 +854,16 @@ func (db *commonMsgDatabase) DeleteConversationMsgsAndSetMinSeq(ctx context.Cont
 	if err != nil {
 		return err
 	}
+	if db.archive != nil {
+		before := time.UnixMilli(utils.GetCurrentTimestampByMill() - remainTime*1000)
+		archivedMinSeq, err := db.archive.PurgeConversationMsgs(ctx, conversationID, minSeq, before)
+		if err != nil {
+			return err
+		}
+		if archivedMinSeq > minSeq {
+			minSeq = archivedMinSeq
+		}
+	}
 	log.ZInfo(ctx, "DeleteConversationMsgsAndSetMinSeq", "conversationID", conversationID, "minSeq", minSeq)
 	if minSeq == 0 {
 		return nil
@@ -726,7 +874,11 @@ func (db *commonMsgDatabase) DeleteConversationMsgsAndSetMinSeq(ctx context.Cont
 			log.ZWarn(ctx, "CleanUpOneUserAllMsg", err, "conversationID", conversationID)
 		}
 	}
-	return db.cache.SetMinSeq(ctx, conversationID, minSeq)
+	if err := db.cache.SetMinSeq(ctx, conversationID, minSeq); err != nil {
+		return err
+	}
+	db.msgStateToModifyMQ(ctx, kafka.ModifyTypeDeleteBefore, conversationID, []*sdkws.MsgData{{Seq: minSeq}})
+	return nil
 }
 
 func (db *commonMsgDatabase) UserMsgsDestruct(ctx context.Context, userID string, conversationID string, destructTime int64, lastMsgDestructTime time.Time) (seqs []int64, err error) {
@@ -845,6 +997,9 @@ func (db *commonMsgDatabase) deleteMsgRecursion(ctx context.Context, conversatio
 }
 
 func (db *commonMsgDatabase) DeleteMsgsPhysicalBySeqs(ctx context.Context, conversationID string, allSeqs []int64) error {
+	if err := db.checkNotArchived(ctx, conversationID, allSeqs); err != nil {
+		return err
+	}
 	if err := db.cache.DeleteMessages(ctx, conversationID, allSeqs); err != nil {
 		return err
 	}
@@ -857,10 +1012,16 @@ func (db *commonMsgDatabase) DeleteMsgsPhysicalBySeqs(ctx context.Context, conve
 			return err
 		}
 	}
+	db.msgStateToModifyMQ(ctx, kafka.ModifyTypeDelete, conversationID, utils.Slice(allSeqs, func(seq int64) *sdkws.MsgData {
+		return &sdkws.MsgData{Seq: seq}
+	}))
 	return nil
 }
 
 func (db *commonMsgDatabase) DeleteUserMsgsBySeqs(ctx context.Context, userID string, conversationID string, seqs []int64) error {
+	if err := db.checkNotArchived(ctx, conversationID, seqs); err != nil {
+		return err
+	}
 	cachedMsgs, _, err := db.cache.GetMessagesBySeq(ctx, conversationID, seqs)
 	if err != nil && errs.Unwrap(err) != redis.Nil {
 		log.ZWarn(ctx, "DeleteUserMsgsBySeqs", err, "conversationID", conversationID, "seqs", seqs)
@@ -877,10 +1038,58 @@ func (db *commonMsgDatabase) DeleteUserMsgsBySeqs(ctx context.Context, userID st
 	}
 
 	for docID, seqs := range db.msg.GetDocIDSeqsMap(conversationID, seqs) {
+		indexes := make([]int64, 0, len(seqs))
 		for _, seq := range seqs {
-			if _, err := db.msgDocDatabase.PushUnique(ctx, docID, db.msg.GetMsgIndex(seq), "del_list", []string{userID}); err != nil {
-				return err
-			}
+			indexes = append(indexes, db.msg.GetMsgIndex(seq))
+		}
+		if _, err := db.msgDocDatabase.PushUniqueByIndexes(ctx, docID, indexes, "del_list", []string{userID}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (db *commonMsgDatabase) DeleteUserConversationMsgs(ctx context.Context, userID string, conversationID string, maxSeq int64) error {
+	minSeq, err := db.cache.GetMinSeq(ctx, conversationID)
+	if err != nil && errs.Unwrap(err) != redis.Nil {
+		return err
+	}
+	userMinSeq, err := db.cache.GetConversationUserMinSeq(ctx, conversationID, userID)
+	if err != nil && errs.Unwrap(err) != redis.Nil {
+		return err
+	}
+	if userMinSeq > minSeq {
+		minSeq = userMinSeq
+	}
+	if minSeq < 1 {
+		minSeq = 1
+	}
+	archivedMaxSeq, err := db.GetArchivedMaxSeq(ctx, conversationID)
+	if err != nil {
+		return err
+	}
+	// the archived docs can not take the del_list, the messages are hidden from the user by the user minSeq instead
+	if archivedMaxSeq >= minSeq {
+		if archivedMaxSeq > maxSeq {
+			archivedMaxSeq = maxSeq
+		}
+		if err := db.cache.SetConversationUserMinSeq(ctx, conversationID, userID, archivedMaxSeq+1); err != nil {
+			return err
+		}
+		minSeq = archivedMaxSeq + 1
+	}
+	const batchNum = 1000
+	for begin := minSeq; begin <= maxSeq; begin += batchNum {
+		end := begin + batchNum - 1
+		if end > maxSeq {
+			end = maxSeq
+		}
+		seqs := make([]int64, 0, end-begin+1)
+		for seq := begin; seq <= end; seq++ {
+			seqs = append(seqs, seq)
+		}
+		if err := db.DeleteUserMsgsBySeqs(ctx, userID, conversationID, seqs); err != nil {
+			return err
 		}
 	}
 	return nil
@@ -919,6 +1128,28 @@ func (db *commonMsgDatabase) GetMaxSeq(ctx context.Context, conversationID strin
 	return db.cache.GetMaxSeq(ctx, conversationID)
 }
 
+func (db *commonMsgDatabase) GetArchivedMaxSeq(ctx context.Context, conversationID string) (int64, error) {
+	if db.archive == nil {
+		return 0, nil
+	}
+	return db.archive.GetArchivedMaxSeq(ctx, conversationID)
+}
+
+// checkNotArchived the archived docs are immutable objects, a change to one of their messages is rejected
+// instead of being written to a msg doc that is never read again.
+func (db *commonMsgDatabase) checkNotArchived(ctx context.Context, conversationID string, seqs []int64) error {
+	archivedMaxSeq, err := db.GetArchivedMaxSeq(ctx, conversationID)
+	if err != nil {
+		return err
+	}
+	for _, seq := range seqs {
+		if seq <= archivedMaxSeq {
+			return errs.ErrArgs.Wrap(fmt.Sprintf("msg seq %d of %s is archived and read only", seq, conversationID))
+		}
+	}
+	return nil
+}
+
 func (db *commonMsgDatabase) SetMinSeq(ctx context.Context, conversationID string, minSeq int64) error {
 	return db.cache.SetMinSeq(ctx, conversationID, minSeq)
 }
@@ -971,6 +1202,14 @@ func (db *commonMsgDatabase) GetHasReadSeq(ctx context.Context, userID string, c
 	return db.cache.GetHasReadSeq(ctx, userID, conversationID)
 }
 
+func (db *commonMsgDatabase) IncrUserBadgeUnreadCountSums(ctx context.Context, userIDs []string, delta int) error {
+	return db.cache.IncrUserBadgeUnreadCountSums(ctx, userIDs, delta)
+}
+
+func (db *commonMsgDatabase) DelUserBadgeUnreadCountSum(ctx context.Context, userIDs ...string) error {
+	return db.cache.DelUserBadgeUnreadCountSum(ctx, userIDs...)
+}
+
 func (db *commonMsgDatabase) SetSendMsgStatus(ctx context.Context, id string, status int32) error {
 	return db.cache.SetSendMsgStatus(ctx, id, status)
 }
@@ -999,6 +1238,10 @@ func (db *commonMsgDatabase) GetMongoMaxAndMinSeq(ctx context.Context, conversat
 	return db.GetMinMaxSeqMongo(ctx, conversationID)
 }
 
+func (db *commonMsgDatabase) GetNewestMsg(ctx context.Context, conversationID string) (*unrelationtb.MsgInfoModel, error) {
+	return db.msgDocDatabase.GetNewestMsg(ctx, conversationID)
+}
+
 func (db *commonMsgDatabase) GetMinMaxSeqMongo(ctx context.Context, conversationID string) (minSeqMongo, maxSeqMongo int64, err error) {
 	oldestMsgMongo, err := db.msgDocDatabase.GetOldestMsg(ctx, conversationID)
 	if err != nil {
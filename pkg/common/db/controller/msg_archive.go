@@ -0,0 +1,247 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/s3"
+	unrelationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/unrelation"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/unrelation"
+)
+
+const msgArchivePath = "openim/msg_archive"
+
+// MsgArchiveDatabase moves old msg docs from mongo to the object storage and reads them back.
+// Archived docs are read only, the msg database rejects revoking, deleting or marking an archived message as read.
+type MsgArchiveDatabase interface {
+	// ArchiveConversationMsgs archives the docs of the conversation from minSeq on, oldest first, while the doc
+	// is complete below maxSeq and its newest message was sent before the time. It returns the number of archived docs.
+	ArchiveConversationMsgs(ctx context.Context, conversationID string, minSeq, maxSeq int64, before time.Time) (int, error)
+	// GetArchivedMaxSeq the messages of the conversation up to the seq are only in the archive
+	GetArchivedMaxSeq(ctx context.Context, conversationID string) (int64, error)
+	// GetMsgBySeqIndexIn1Doc same as MsgDocModelInterface.GetMsgBySeqIndexIn1Doc on an archived doc
+	GetMsgBySeqIndexIn1Doc(ctx context.Context, userID, docID string, seqs []int64) ([]*unrelationtb.MsgInfoModel, error)
+	// PurgeConversationMsgs deletes the archived docs of the conversation, oldest first, while the doc is entirely
+	// below minSeq or its newest message was sent before the time. It returns the seq the remaining archive starts
+	// from, 0 if nothing of the conversation is archived.
+	PurgeConversationMsgs(ctx context.Context, conversationID string, minSeq int64, before time.Time) (int64, error)
+}
+
+func NewMsgArchiveDatabase(msgDocModel unrelationtb.MsgDocModelInterface, archiveModel unrelationtb.MsgArchiveModelInterface,
+	cache cache.MsgArchiveCache, s3 s3.Interface,
+) MsgArchiveDatabase {
+	return &msgArchiveDatabase{
+		msgDocDatabase: msgDocModel,
+		archiveDB:      archiveModel,
+		cache:          cache,
+		s3:             s3,
+	}
+}
+
+// InitMsgArchiveDatabase nil if the archive is not enabled.
+func InitMsgArchiveDatabase(rdb redis.UniversalClient, database *mongo.Database) (MsgArchiveDatabase, error) {
+	if !config.Config.MsgArchive.Enable {
+		return nil, nil
+	}
+	o, err := NewObjectStorage(rdb)
+	if err != nil {
+		return nil, err
+	}
+	archiveModel := unrelation.NewMsgArchiveMongoDriver(database)
+	archiveCache := cache.NewMsgArchiveCacheRedis(rdb, archiveModel, time.Duration(config.Config.MsgArchive.CacheExpire)*time.Second)
+	return NewMsgArchiveDatabase(unrelation.NewMsgDocModel(database), archiveModel, archiveCache, o), nil
+}
+
+type msgArchiveDatabase struct {
+	msgDocDatabase unrelationtb.MsgDocModelInterface
+	msg            unrelationtb.MsgDocModel
+	archiveDB      unrelationtb.MsgArchiveModelInterface
+	cache          cache.MsgArchiveCache
+	s3             s3.Interface
+}
+
+func (a *msgArchiveDatabase) ArchiveConversationMsgs(ctx context.Context, conversationID string, minSeq, maxSeq int64, before time.Time) (int, error) {
+	archivedMaxSeq, err := a.archiveDB.GetMaxSeq(ctx, conversationID)
+	if err != nil {
+		return 0, err
+	}
+	seq := archivedMaxSeq + 1
+	if minSeq > seq {
+		seq = minSeq
+	}
+	num := a.msg.GetSingleGocMsgNum()
+	// the first seq of the doc of seq
+	seq = (seq-1)/num*num + 1
+	var docNum int
+	for ; seq+num-1 <= maxSeq; seq += num {
+		docID := a.msg.GetDocID(conversationID, seq)
+		doc, err := a.msgDocDatabase.FindOneByDocID(ctx, docID)
+		if err == mongo.ErrNoDocuments {
+			break
+		}
+		if err != nil {
+			return docNum, errs.Wrap(err)
+		}
+		if newestMsgSendTime(doc) >= before.UnixMilli() {
+			break
+		}
+		if err := a.putArchivedDoc(ctx, doc); err != nil {
+			return docNum, err
+		}
+		if err := a.archiveDB.SetMaxSeq(ctx, conversationID, seq+num-1); err != nil {
+			return docNum, err
+		}
+		if err := a.cache.DelArchivedMaxSeq(conversationID).ExecDel(ctx); err != nil {
+			return docNum, err
+		}
+		if err := a.msgDocDatabase.DeleteDocs(ctx, []string{docID}); err != nil {
+			return docNum, errs.Wrap(err)
+		}
+		log.ZDebug(ctx, "msg doc archived", "docID", docID)
+		docNum++
+	}
+	return docNum, nil
+}
+
+func (a *msgArchiveDatabase) GetArchivedMaxSeq(ctx context.Context, conversationID string) (int64, error) {
+	return a.cache.GetArchivedMaxSeq(ctx, conversationID)
+}
+
+func (a *msgArchiveDatabase) GetMsgBySeqIndexIn1Doc(ctx context.Context, userID, docID string, seqs []int64) ([]*unrelationtb.MsgInfoModel, error) {
+	doc, err := a.cache.GetArchivedDoc(ctx, docID, func(ctx context.Context) (*unrelationtb.MsgDocModel, error) {
+		return a.getArchivedDoc(ctx, docID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		return nil, nil
+	}
+	return unrelation.GetMsgBySeqIndexInDoc(doc, userID, seqs)
+}
+
+func (a *msgArchiveDatabase) PurgeConversationMsgs(ctx context.Context, conversationID string, minSeq int64, before time.Time) (int64, error) {
+	archivedMaxSeq, err := a.archiveDB.GetMaxSeq(ctx, conversationID)
+	if err != nil {
+		return 0, err
+	}
+	if archivedMaxSeq == 0 {
+		return 0, nil
+	}
+	seq, err := a.archiveDB.GetMinSeq(ctx, conversationID)
+	if err != nil {
+		return 0, err
+	}
+	if seq < 1 {
+		seq = 1
+	}
+	num := a.msg.GetSingleGocMsgNum()
+	seq = (seq-1)/num*num + 1
+	for ; seq+num-1 <= archivedMaxSeq; seq += num {
+		docID := a.msg.GetDocID(conversationID, seq)
+		if seq+num-1 >= minSeq {
+			doc, err := a.getArchivedDoc(ctx, docID)
+			if err != nil {
+				return 0, err
+			}
+			if doc != nil && newestMsgSendTime(doc) >= before.UnixMilli() {
+				break
+			}
+		}
+		if err := a.s3.DeleteObject(ctx, archivedDocName(docID)); err != nil && !a.s3.IsNotFound(err) {
+			return 0, err
+		}
+		if err := a.archiveDB.SetMinSeq(ctx, conversationID, seq+num); err != nil {
+			return 0, err
+		}
+		if err := a.cache.DelArchivedDoc(docID).ExecDel(ctx); err != nil {
+			return 0, err
+		}
+		log.ZDebug(ctx, "archived msg doc purged", "docID", docID)
+	}
+	return seq, nil
+}
+
+func (a *msgArchiveDatabase) putArchivedDoc(ctx context.Context, doc *unrelationtb.MsgDocModel) error {
+	data, err := bson.Marshal(doc)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	if _, err := gw.Write(data); err != nil {
+		return errs.Wrap(err)
+	}
+	if err := gw.Close(); err != nil {
+		return errs.Wrap(err)
+	}
+	return a.s3.PutObject(ctx, archivedDocName(doc.DocID), &buf, int64(buf.Len()))
+}
+
+// getArchivedDoc nil if the doc was never archived, e.g. it was deleted by the retention before.
+func (a *msgArchiveDatabase) getArchivedDoc(ctx context.Context, docID string) (*unrelationtb.MsgDocModel, error) {
+	reader, err := a.s3.GetObject(ctx, archivedDocName(docID))
+	if err != nil {
+		if a.s3.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer reader.Close()
+	gr, err := gzip.NewReader(reader)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	data, err := io.ReadAll(gr)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	var doc unrelationtb.MsgDocModel
+	if err := bson.Unmarshal(data, &doc); err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &doc, nil
+}
+
+// archivedDocName e.g. openim/msg_archive/si_u1_u2/3.bson.gz for the doc si_u1_u2:3.
+func archivedDocName(docID string) string {
+	return path.Join(msgArchivePath, strings.ReplaceAll(docID, ":", "/")+".bson.gz")
+}
+
+func newestMsgSendTime(doc *unrelationtb.MsgDocModel) int64 {
+	var sendTime int64
+	for _, msg := range doc.Msg {
+		if msg != nil && msg.Msg != nil && msg.Msg.SendTime > sendTime {
+			sendTime = msg.Msg.SendTime
+		}
+	}
+	return sendTime
+}
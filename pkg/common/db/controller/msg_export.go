@@ -0,0 +1,63 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+// MsgExportDatabase keeps the export jobs and uploads the rendered transcripts.
+type MsgExportDatabase interface {
+	SetExportJob(ctx context.Context, job *cache.MsgExportJob, expire time.Duration) error
+	TakeExportJob(ctx context.Context, jobID string) (*cache.MsgExportJob, error)
+	// UploadExport stores the data under the object name and returns a download url valid for expire.
+	UploadExport(ctx context.Context, userID, name, contentType string, data []byte, expire time.Duration) (time.Time, string, error)
+}
+
+func NewMsgExportDatabase(cache cache.MsgExportCache, s3db S3Database) MsgExportDatabase {
+	return &msgExportDatabase{cache: cache, s3db: s3db}
+}
+
+type msgExportDatabase struct {
+	cache cache.MsgExportCache
+	s3db  S3Database
+}
+
+func (m *msgExportDatabase) SetExportJob(ctx context.Context, job *cache.MsgExportJob, expire time.Duration) error {
+	job.UpdateTime = time.Now().UnixMilli()
+	return m.cache.SetExportJob(ctx, job, expire)
+}
+
+func (m *msgExportDatabase) TakeExportJob(ctx context.Context, jobID string) (*cache.MsgExportJob, error) {
+	return m.cache.GetExportJob(ctx, jobID)
+}
+
+func (m *msgExportDatabase) UploadExport(ctx context.Context, userID, name, contentType string, data []byte, expire time.Duration) (time.Time, string, error) {
+	obj := &relation.ObjectModel{
+		Name:        name,
+		UserID:      userID,
+		ContentType: contentType,
+		Cause:       "msg_export",
+		CreateTime:  time.Now(),
+	}
+	if err := m.s3db.UploadObject(ctx, obj, data); err != nil {
+		return time.Time{}, "", err
+	}
+	return m.s3db.AccessURL(ctx, name, expire, nil)
+}
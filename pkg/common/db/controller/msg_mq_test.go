@@ -0,0 +1,135 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	pbmsg "github.com/OpenIMSDK/protocol/msg"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/kafka"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/mq"
+)
+
+const memoryMQConfig = `
+kafka:
+  latestMsgToRedis:
+    topic: testLatestMsgToRedis
+  offlineMsgToMongo:
+    topic: testOfflineMsgToMongo
+  msgToPush:
+    topic: testMsgToPush
+  msgDeadLetter:
+    topic: testMsgDeadLetter
+`
+
+type topicHandler chan *mq.Message
+
+func (h topicHandler) ConsumeClaim(sess mq.ConsumerGroupSession, claim mq.ConsumerGroupClaim) error {
+	for msg := range claim.Messages() {
+		h <- msg
+		sess.MarkMessage(msg)
+	}
+	return nil
+}
+
+func consumeTopic(t *testing.T, topic string) topicHandler {
+	group := mq.NewConsumerGroup([]string{topic}, "test")
+	handler := make(topicHandler, 10)
+	go group.RegisterHandleAndConsumer(handler)
+	t.Cleanup(func() { _ = group.Close() })
+	return handler
+}
+
+func (h topicHandler) receive(t *testing.T, msg proto.Message) *mq.Message {
+	t.Helper()
+	select {
+	case m := <-h:
+		if err := proto.Unmarshal(m.Value, msg); err != nil {
+			t.Fatal(err)
+		}
+		return m
+	case <-time.After(time.Second * 3):
+		t.Fatal("no message received")
+		return nil
+	}
+}
+
+func TestMsgToMQOnMemoryBackend(t *testing.T) {
+	if err := yaml.Unmarshal([]byte(memoryMQConfig), &config.Config); err != nil {
+		t.Fatal(err)
+	}
+	mq.UseMemoryBackend()
+	toRedis := consumeTopic(t, config.Config.Kafka.LatestMsgToRedis.Topic)
+	toPush := consumeTopic(t, config.Config.Kafka.MsgToPush.Topic)
+	toMongo := consumeTopic(t, config.Config.Kafka.MsgToMongo.Topic)
+	toDeadLetter := consumeTopic(t, config.Config.Kafka.MsgDeadLetter.Topic)
+	db := NewCommonMsgDatabase(nil, nil, nil)
+	ctx := mcontext.WithMustInfoCtx([]string{"operationID", "opUserID", "1", "connID"})
+	msg := &sdkws.MsgData{SendID: "u1", RecvID: "u2", ClientMsgID: "c1", Seq: 7}
+
+	if err := db.MsgToMQ(ctx, "u1_u2", msg); err != nil {
+		t.Fatal(err)
+	}
+	var msgData sdkws.MsgData
+	m := toRedis.receive(t, &msgData)
+	if string(m.Key) != "u1_u2" || msgData.ClientMsgID != "c1" {
+		t.Fatalf("latestMsgToRedis got %v with key %s", &msgData, m.Key)
+	}
+	if operationID := mcontext.GetOperationID(mq.GetContextFromMsg(m)); operationID != "operationID" {
+		t.Fatalf("operationID %q is not carried", operationID)
+	}
+
+	if _, _, err := db.MsgToPushMQ(ctx, "u1_u2", "si_u1_u2", msg); err != nil {
+		t.Fatal(err)
+	}
+	var push pbmsg.PushMsgDataToMQ
+	toPush.receive(t, &push)
+	if push.ConversationID != "si_u1_u2" || push.MsgData.Seq != 7 {
+		t.Fatalf("msgToPush got %v", &push)
+	}
+
+	if err := db.MsgToMongoMQ(ctx, "u1_u2", "si_u1_u2", []*sdkws.MsgData{msg}, 7); err != nil {
+		t.Fatal(err)
+	}
+	var toMongoMsg pbmsg.MsgDataToMongoByMQ
+	toMongo.receive(t, &toMongoMsg)
+	if toMongoMsg.LastSeq != 7 || len(toMongoMsg.MsgData) != 1 {
+		t.Fatalf("offlineMsgToMongo got %v", &toMongoMsg)
+	}
+
+	cause := errors.New("mongo is down")
+	if err := db.MsgToDeadLetterMQ(ctx, "u1_u2", kafka.DeadLetterStageMongo, "si_u1_u2", []*sdkws.MsgData{msg}, 7, cause); err != nil {
+		t.Fatal(err)
+	}
+	m = toDeadLetter.receive(t, &toMongoMsg)
+	dl, err := kafka.ParseDeadLetterRecord(m.Partition, m.Offset, m.Key, m.Value, m.Headers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dl.Stage != kafka.DeadLetterStageMongo || dl.Error != cause.Error() || dl.Msg.ConversationID != "si_u1_u2" {
+		t.Fatalf("dead letter %+v", dl)
+	}
+	if operationID := mcontext.GetOperationID(dl.Ctx); operationID != "operationID" {
+		t.Fatalf("dead letter operationID %q is not carried", operationID)
+	}
+}
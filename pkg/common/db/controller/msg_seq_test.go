@@ -0,0 +1,318 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/mq"
+)
+
+const seqDedupeConfig = memoryMQConfig + `
+msgTransfer:
+  seqDedupeTimeout: 86400
+`
+
+// seqCache keeps the seqs of BatchInsertChat2Cache in memory, setMsgErr makes SetMessageToCache fail as a crash would.
+type seqCache struct {
+	cache.MsgModel
+	lock          sync.Mutex
+	maxSeqs       map[string]int64
+	clientMsgSeqs map[string]int64
+	msgs          map[string]map[int64]string
+	setMsgErr     error
+}
+
+func newSeqCache() *seqCache {
+	return &seqCache{
+		maxSeqs:       make(map[string]int64),
+		clientMsgSeqs: make(map[string]int64),
+		msgs:          make(map[string]map[int64]string),
+	}
+}
+
+func (c *seqCache) GetMaxSeq(ctx context.Context, conversationID string) (int64, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	seq, ok := c.maxSeqs[conversationID]
+	if !ok {
+		return 0, redis.Nil
+	}
+	return seq, nil
+}
+
+func (c *seqCache) SetMaxSeq(ctx context.Context, conversationID string, maxSeq int64) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.maxSeqs[conversationID] = maxSeq
+	return nil
+}
+
+func (c *seqCache) AllocateSeqs(ctx context.Context, conversationID string, floor int64, size int64) (int64, bool, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	seq, ok := c.maxSeqs[conversationID]
+	if seq < floor {
+		seq = floor
+	}
+	seq += size
+	c.maxSeqs[conversationID] = seq
+	return seq, !ok, nil
+}
+
+func (c *seqCache) SetMessageToCache(ctx context.Context, conversationID string, msgs []*sdkws.MsgData) (int, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.setMsgErr != nil {
+		return len(msgs), c.setMsgErr
+	}
+	if c.msgs[conversationID] == nil {
+		c.msgs[conversationID] = make(map[int64]string)
+	}
+	for _, msg := range msgs {
+		if clientMsgID, ok := c.msgs[conversationID][msg.Seq]; ok && clientMsgID != msg.ClientMsgID {
+			return 0, fmt.Errorf("seq %d of %s is given to %s", msg.Seq, clientMsgID, msg.ClientMsgID)
+		}
+		c.msgs[conversationID][msg.Seq] = msg.ClientMsgID
+	}
+	return 0, nil
+}
+
+func (c *seqCache) SetHasReadSeqs(ctx context.Context, conversationID string, hasReadSeqs map[string]int64) error {
+	return nil
+}
+
+func (c *seqCache) GetClientMsgSeqs(ctx context.Context, conversationID string, clientMsgIDs []string) (map[string]int64, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	seqs := make(map[string]int64)
+	for _, clientMsgID := range clientMsgIDs {
+		if seq, ok := c.clientMsgSeqs[conversationID+":"+clientMsgID]; ok {
+			seqs[clientMsgID] = seq
+		}
+	}
+	return seqs, nil
+}
+
+func (c *seqCache) SetClientMsgSeqs(ctx context.Context, conversationID string, seqs map[string]int64, expireTime time.Duration) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for clientMsgID, seq := range seqs {
+		c.clientMsgSeqs[conversationID+":"+clientMsgID] = seq
+	}
+	return nil
+}
+
+// transferHandler inserts every message it gets to the cache, it marks them only if mark is set,
+// so that closing the group without marking is a msgtransfer crashing before it commits.
+type transferHandler struct {
+	db   CommonMsgDatabase
+	mark bool
+	msgs chan *sdkws.MsgData
+}
+
+func (h *transferHandler) ConsumeClaim(sess mq.ConsumerGroupSession, claim mq.ConsumerGroupClaim) error {
+	for m := range claim.Messages() {
+		msg := &sdkws.MsgData{}
+		if err := proto.Unmarshal(m.Value, msg); err != nil {
+			return err
+		}
+		_, _, err := h.db.BatchInsertChat2Cache(mq.GetContextFromMsg(m), "si_u1_u2", []*sdkws.MsgData{msg})
+		h.msgs <- msg
+		if err == nil && h.mark {
+			sess.MarkMessage(m)
+		}
+	}
+	return nil
+}
+
+func startTransfer(db CommonMsgDatabase, mark bool) (*transferHandler, func()) {
+	group := mq.NewConsumerGroup([]string{config.Config.Kafka.LatestMsgToRedis.Topic}, "transfer")
+	handler := &transferHandler{db: db, mark: mark, msgs: make(chan *sdkws.MsgData, 10)}
+	done := make(chan struct{})
+	go func() {
+		group.RegisterHandleAndConsumer(handler)
+		close(done)
+	}()
+	return handler, func() {
+		_ = group.Close()
+		<-done
+	}
+}
+
+func (h *transferHandler) receive(t *testing.T, n int) map[string]int64 {
+	t.Helper()
+	seqs := make(map[string]int64)
+	for i := 0; i < n; i++ {
+		select {
+		case msg := <-h.msgs:
+			seqs[msg.ClientMsgID] = msg.Seq
+		case <-time.After(time.Second * 3):
+			t.Fatalf("received %d messages, want %d", i, n)
+		}
+	}
+	return seqs
+}
+
+func newSeqTestDatabase(t *testing.T) (*commonMsgDatabase, *seqCache) {
+	if err := yaml.Unmarshal([]byte(seqDedupeConfig), &config.Config); err != nil {
+		t.Fatal(err)
+	}
+	mq.UseMemoryBackend()
+	c := newSeqCache()
+	return NewCommonMsgDatabase(nil, c, nil).(*commonMsgDatabase), c
+}
+
+func sendChats(t *testing.T, db CommonMsgDatabase, clientMsgIDs ...string) {
+	ctx := mcontext.WithMustInfoCtx([]string{"operationID", "u1", "1", "connID"})
+	for _, clientMsgID := range clientMsgIDs {
+		if err := db.MsgToMQ(ctx, "u1_u2", &sdkws.MsgData{SendID: "u1", RecvID: "u2", ClientMsgID: clientMsgID}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestSeqReusedAfterConsumerRestart(t *testing.T) {
+	db, _ := newSeqTestDatabase(t)
+
+	handler, stop := startTransfer(db, false)
+	sendChats(t, db, "c1", "c2", "c3")
+	first := handler.receive(t, 3)
+	stop()
+
+	// the messages were never marked, the restarted consumer gets them again
+	handler, stop = startTransfer(db, true)
+	defer stop()
+	again := handler.receive(t, 3)
+	for clientMsgID, seq := range first {
+		if again[clientMsgID] != seq {
+			t.Fatalf("%s got seq %d after restart, it had %d", clientMsgID, again[clientMsgID], seq)
+		}
+	}
+	sendChats(t, db, "c4")
+	if seq := handler.receive(t, 1)["c4"]; seq != 4 {
+		t.Fatalf("c4 got seq %d, want 4", seq)
+	}
+}
+
+func TestSeqReusedAfterCrashBeforeCache(t *testing.T) {
+	db, c := newSeqTestDatabase(t)
+
+	handler, stop := startTransfer(db, false)
+	sendChats(t, db, "c1")
+	first := handler.receive(t, 1)
+	// the seq of c2 is allocated and recorded but the message is not cached, as if msgtransfer died in between
+	c.setMsgErr = errors.New("crash")
+	sendChats(t, db, "c2")
+	first["c2"] = handler.receive(t, 1)["c2"]
+	stop()
+	c.setMsgErr = nil
+
+	handler, stop = startTransfer(db, true)
+	defer stop()
+	sendChats(t, db, "c3")
+	seqs := handler.receive(t, 3)
+	for clientMsgID, seq := range first {
+		if seqs[clientMsgID] != seq {
+			t.Fatalf("%s got seq %d after restart, it had %d", clientMsgID, seqs[clientMsgID], seq)
+		}
+	}
+	// SetMessageToCache fails the handler if c3 collides with a seq given before the crash
+	if seqs["c3"] != 3 {
+		t.Fatalf("c3 got seq %d, want 3", seqs["c3"])
+	}
+}
+
+func TestSeqUniqueAcrossConcurrentTransfers(t *testing.T) {
+	db, c := newSeqTestDatabase(t)
+	// a second msgtransfer process sharing the redis of the first
+	other := NewCommonMsgDatabase(nil, c, nil)
+	ctx := mcontext.WithMustInfoCtx([]string{"operationID", "u1", "1", "connID"})
+	const batches = 50
+	var wg sync.WaitGroup
+	errCh := make(chan error, batches*2)
+	for i := 0; i < batches; i++ {
+		for j, d := range []CommonMsgDatabase{db, other} {
+			wg.Add(1)
+			go func(d CommonMsgDatabase, clientMsgID string) {
+				defer wg.Done()
+				msgs := []*sdkws.MsgData{{SendID: "u1", ClientMsgID: clientMsgID + "a"}, {SendID: "u1", ClientMsgID: clientMsgID + "b"}}
+				if _, _, err := d.BatchInsertChat2Cache(ctx, "si_u1_u4", msgs); err != nil {
+					errCh <- err
+				}
+			}(d, fmt.Sprintf("c%d-%d", i, j))
+		}
+	}
+	wg.Wait()
+	close(errCh)
+	// SetMessageToCache fails when a seq is given to two messages
+	for err := range errCh {
+		t.Fatal(err)
+	}
+	if seq := c.maxSeqs["si_u1_u4"]; seq != batches*2*2 {
+		t.Fatalf("max seq %d, want %d", seq, batches*2*2)
+	}
+}
+
+func TestSeqOfRepeatedClientMsgIDInBatch(t *testing.T) {
+	db, _ := newSeqTestDatabase(t)
+	ctx := mcontext.WithMustInfoCtx([]string{"operationID", "u1", "1", "connID"})
+	msgs := []*sdkws.MsgData{
+		{SendID: "u1", ClientMsgID: "c1"},
+		{SendID: "u1", ClientMsgID: "c2"},
+		{SendID: "u1", ClientMsgID: "c1"},
+		{SendID: "u1"},
+	}
+	lastSeq, isNew, err := db.BatchInsertChat2Cache(ctx, "si_u1_u2", msgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lastSeq != 0 || !isNew {
+		t.Fatalf("lastSeq %d isNew %v, want 0 and true", lastSeq, isNew)
+	}
+	for i, seq := range []int64{1, 2, 1, 3} {
+		if msgs[i].Seq != seq {
+			t.Fatalf("message %d got seq %d, want %d", i, msgs[i].Seq, seq)
+		}
+	}
+}
+
+func TestSeqDedupeDisabled(t *testing.T) {
+	db, _ := newSeqTestDatabase(t)
+	config.Config.MsgTransfer.SeqDedupeTimeout = 0
+	ctx := mcontext.WithMustInfoCtx([]string{"operationID", "u1", "1", "connID"})
+	for _, want := range []int64{1, 2} {
+		msgs := []*sdkws.MsgData{{SendID: "u1", ClientMsgID: "c1"}}
+		if _, _, err := db.BatchInsertChat2Cache(ctx, "si_u1_u3", msgs); err != nil {
+			t.Fatal(err)
+		}
+		if msgs[0].Seq != want {
+			t.Fatalf("got seq %d, want %d", msgs[0].Seq, want)
+		}
+	}
+}
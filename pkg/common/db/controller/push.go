@@ -16,22 +16,91 @@ package controller
 
 import (
 	"context"
+	"time"
 
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	unrelationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/unrelation"
 )
 
 type PushDatabase interface {
 	DelFcmToken(ctx context.Context, userID string, platformID int) error
+	DelVoipToken(ctx context.Context, userID string, platformID int) error
+	// IncrPushCoalesceCounts count an offline push of the conversation for each user, 1 means a new window was opened
+	IncrPushCoalesceCounts(ctx context.Context, conversationID string, userIDs []string, expireTime time.Duration) (map[string]int64, error)
+	// GetDelPushCoalesceCounts close the windows of the users and return how many pushes each of them counted
+	GetDelPushCoalesceCounts(ctx context.Context, conversationID string, userIDs []string) (map[string]int64, error)
+	// AddPushCoalesceWindows schedule the opened windows to be flushed at the due time
+	AddPushCoalesceWindows(ctx context.Context, windows []*cache.PushCoalesceWindow, due time.Time) error
+	// PopDuePushCoalesceWindows take up to count windows that are due, each window is taken once
+	PopDuePushCoalesceWindows(ctx context.Context, now time.Time, count int64) ([]*cache.PushCoalesceWindow, error)
+	// GetUserBadgeUnreadCountSums get the cached badges, users without one are absent
+	GetUserBadgeUnreadCountSums(ctx context.Context, userIDs []string) (map[string]int, error)
+	SetUserBadgeUnreadCountSum(ctx context.Context, userID string, value int) error
+	// IncrUserBadgeUnreadCountSums adjust the cached badges of the users, a missing badge is left to be recounted
+	IncrUserBadgeUnreadCountSums(ctx context.Context, userIDs []string, delta int) error
+	GetMaxSeqs(ctx context.Context, conversationIDs []string) (map[string]int64, error)
+	GetHasReadSeqs(ctx context.Context, userID string, conversationIDs []string) (map[string]int64, error)
+	CreatePushRecords(ctx context.Context, records []*unrelationtb.PushRecordModel) error
+	SearchPushRecords(ctx context.Context, userID, clientMsgID string, start, end time.Time, pageNumber, showNumber int32) (int64, []*unrelationtb.PushRecordModel, error)
 }
 
 type pushDataBase struct {
-	cache cache.MsgModel
+	cache        cache.MsgModel
+	pushRecordDB unrelationtb.PushRecordModelInterface
 }
 
-func NewPushDatabase(cache cache.MsgModel) PushDatabase {
-	return &pushDataBase{cache: cache}
+func NewPushDatabase(cache cache.MsgModel, pushRecordDB unrelationtb.PushRecordModelInterface) PushDatabase {
+	return &pushDataBase{cache: cache, pushRecordDB: pushRecordDB}
 }
 
 func (p *pushDataBase) DelFcmToken(ctx context.Context, userID string, platformID int) error {
 	return p.cache.DelFcmToken(ctx, userID, platformID)
 }
+
+func (p *pushDataBase) DelVoipToken(ctx context.Context, userID string, platformID int) error {
+	return p.cache.DelVoipToken(ctx, userID, platformID)
+}
+
+func (p *pushDataBase) IncrPushCoalesceCounts(ctx context.Context, conversationID string, userIDs []string, expireTime time.Duration) (map[string]int64, error) {
+	return p.cache.IncrPushCoalesceCounts(ctx, conversationID, userIDs, expireTime)
+}
+
+func (p *pushDataBase) GetDelPushCoalesceCounts(ctx context.Context, conversationID string, userIDs []string) (map[string]int64, error) {
+	return p.cache.GetDelPushCoalesceCounts(ctx, conversationID, userIDs)
+}
+
+func (p *pushDataBase) AddPushCoalesceWindows(ctx context.Context, windows []*cache.PushCoalesceWindow, due time.Time) error {
+	return p.cache.AddPushCoalesceWindows(ctx, windows, due)
+}
+
+func (p *pushDataBase) PopDuePushCoalesceWindows(ctx context.Context, now time.Time, count int64) ([]*cache.PushCoalesceWindow, error) {
+	return p.cache.PopDuePushCoalesceWindows(ctx, now, count)
+}
+
+func (p *pushDataBase) GetUserBadgeUnreadCountSums(ctx context.Context, userIDs []string) (map[string]int, error) {
+	return p.cache.GetUserBadgeUnreadCountSums(ctx, userIDs)
+}
+
+func (p *pushDataBase) SetUserBadgeUnreadCountSum(ctx context.Context, userID string, value int) error {
+	return p.cache.SetUserBadgeUnreadCountSum(ctx, userID, value)
+}
+
+func (p *pushDataBase) IncrUserBadgeUnreadCountSums(ctx context.Context, userIDs []string, delta int) error {
+	return p.cache.IncrUserBadgeUnreadCountSums(ctx, userIDs, delta)
+}
+
+func (p *pushDataBase) GetMaxSeqs(ctx context.Context, conversationIDs []string) (map[string]int64, error) {
+	return p.cache.GetMaxSeqs(ctx, conversationIDs)
+}
+
+func (p *pushDataBase) GetHasReadSeqs(ctx context.Context, userID string, conversationIDs []string) (map[string]int64, error) {
+	return p.cache.GetHasReadSeqs(ctx, userID, conversationIDs)
+}
+
+func (p *pushDataBase) CreatePushRecords(ctx context.Context, records []*unrelationtb.PushRecordModel) error {
+	return p.pushRecordDB.Create(ctx, records)
+}
+
+func (p *pushDataBase) SearchPushRecords(ctx context.Context, userID, clientMsgID string, start, end time.Time, pageNumber, showNumber int32) (int64, []*unrelationtb.PushRecordModel, error) {
+	return p.pushRecordDB.Search(ctx, userID, clientMsgID, start, end, pageNumber, showNumber)
+}
@@ -0,0 +1,132 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/tools/errs"
+	"gorm.io/gorm"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/relation"
+	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+type RetentionPolicyDatabase interface {
+	SetRetentionPolicy(ctx context.Context, policy *relationtb.RetentionPolicyModel) error
+	DeleteRetentionPolicy(ctx context.Context, scopeType int32, scopeID string) error
+	PageRetentionPolicies(ctx context.Context, scopeType int32, pageNumber, showNumber int32) (uint32, []*relationtb.RetentionPolicyModel, error)
+	// LoadRetentionPolicies all policies at once, to resolve the policy of many conversations
+	LoadRetentionPolicies(ctx context.Context) (*RetentionPolicies, error)
+}
+
+func NewRetentionPolicyDatabase(policy relationtb.RetentionPolicyModelInterface) RetentionPolicyDatabase {
+	return &retentionPolicyDatabase{policy: policy}
+}
+
+func InitRetentionPolicyDatabase(db *gorm.DB) (RetentionPolicyDatabase, error) {
+	if err := db.AutoMigrate(&relationtb.RetentionPolicyModel{}); err != nil {
+		return nil, err
+	}
+	return NewRetentionPolicyDatabase(relation.NewRetentionPolicyGorm(db)), nil
+}
+
+type retentionPolicyDatabase struct {
+	policy relationtb.RetentionPolicyModelInterface
+}
+
+func (r *retentionPolicyDatabase) SetRetentionPolicy(ctx context.Context, policy *relationtb.RetentionPolicyModel) error {
+	return r.policy.Save(ctx, policy)
+}
+
+func (r *retentionPolicyDatabase) DeleteRetentionPolicy(ctx context.Context, scopeType int32, scopeID string) error {
+	rows, err := r.policy.Delete(ctx, scopeType, scopeID)
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errs.ErrRecordNotFound.Wrap("retention policy not found")
+	}
+	return nil
+}
+
+func (r *retentionPolicyDatabase) PageRetentionPolicies(ctx context.Context, scopeType int32, pageNumber, showNumber int32) (uint32, []*relationtb.RetentionPolicyModel, error) {
+	return r.policy.Page(ctx, scopeType, pageNumber, showNumber)
+}
+
+func (r *retentionPolicyDatabase) LoadRetentionPolicies(ctx context.Context) (*RetentionPolicies, error) {
+	policies, err := r.policy.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return NewRetentionPolicies(policies), nil
+}
+
+// RetentionPolicies resolves the effective policy of a conversation, the most specific scope wins:
+// conversation, then group, then conversation type.
+type RetentionPolicies struct {
+	scopes map[int32]map[string]*relationtb.RetentionPolicyModel
+}
+
+func NewRetentionPolicies(policies []*relationtb.RetentionPolicyModel) *RetentionPolicies {
+	r := &RetentionPolicies{scopes: make(map[int32]map[string]*relationtb.RetentionPolicyModel)}
+	for _, policy := range policies {
+		if r.scopes[policy.ScopeType] == nil {
+			r.scopes[policy.ScopeType] = make(map[string]*relationtb.RetentionPolicyModel)
+		}
+		r.scopes[policy.ScopeType][policy.ScopeID] = policy
+	}
+	return r
+}
+
+// Effective nil if no policy covers the conversation.
+func (r *RetentionPolicies) Effective(conversationID string) *relationtb.RetentionPolicyModel {
+	if policy := r.scopes[relationtb.RetentionScopeConversation][conversationID]; policy != nil {
+		return policy
+	}
+	sessionType, groupID := parseRetentionConversationID(conversationID)
+	if groupID != "" {
+		if policy := r.scopes[relationtb.RetentionScopeGroup][groupID]; policy != nil {
+			return policy
+		}
+	}
+	if sessionType != 0 {
+		return r.scopes[relationtb.RetentionScopeConversationType][strconv.Itoa(int(sessionType))]
+	}
+	return nil
+}
+
+// parseRetentionConversationID the session type of the conversation, and the group id for group conversations.
+func parseRetentionConversationID(conversationID string) (sessionType int32, groupID string) {
+	prefix, rest, ok := strings.Cut(conversationID, "_")
+	if !ok {
+		return 0, ""
+	}
+	switch prefix {
+	case "si":
+		return constant.SingleChatType, ""
+	case "g":
+		return constant.GroupChatType, rest
+	case "sg":
+		return constant.SuperGroupChatType, rest
+	case "n":
+		return constant.NotificationChatType, ""
+	default:
+		return 0, ""
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"testing"
+
+	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+func TestRetentionPoliciesEffective(t *testing.T) {
+	conversation := &relationtb.RetentionPolicyModel{ScopeType: relationtb.RetentionScopeConversation, ScopeID: "sg_g1"}
+	group := &relationtb.RetentionPolicyModel{ScopeType: relationtb.RetentionScopeGroup, ScopeID: "g2"}
+	superGroupType := &relationtb.RetentionPolicyModel{ScopeType: relationtb.RetentionScopeConversationType, ScopeID: "3"}
+	singleType := &relationtb.RetentionPolicyModel{ScopeType: relationtb.RetentionScopeConversationType, ScopeID: "1"}
+	policies := NewRetentionPolicies([]*relationtb.RetentionPolicyModel{conversation, group, superGroupType, singleType})
+	tests := []struct {
+		name           string
+		conversationID string
+		want           *relationtb.RetentionPolicyModel
+	}{
+		{name: "conversation wins over group and type", conversationID: "sg_g1", want: conversation},
+		{name: "group wins over type", conversationID: "sg_g2", want: group},
+		{name: "type of super group", conversationID: "sg_g3", want: superGroupType},
+		{name: "type of single chat", conversationID: "si_u1_u2", want: singleType},
+		{name: "group id in a single chat is not a group", conversationID: "si_g2_u1", want: singleType},
+		{name: "no policy for the type", conversationID: "n_u1_u2", want: nil},
+		{name: "unknown prefix", conversationID: "x_g2", want: nil},
+		{name: "no prefix", conversationID: "g2", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policies.Effective(tt.conversationID); got != tt.want {
+				t.Errorf("Effective(%q) = %+v, want %+v", tt.conversationID, got, tt.want)
+			}
+		})
+	}
+	if got := NewRetentionPolicies(nil).Effective("sg_g1"); got != nil {
+		t.Errorf("Effective() without policies = %+v, want nil", got)
+	}
+}
@@ -16,14 +16,20 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"path/filepath"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/s3"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/s3/cont"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/s3/cos"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/s3/minio"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/s3/oss"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
 )
 
@@ -35,6 +41,24 @@ type S3Database interface {
 	CompleteMultipartUpload(ctx context.Context, uploadID string, parts []string) (*cont.UploadResult, error)
 	AccessURL(ctx context.Context, name string, expire time.Duration, opt *s3.AccessURLOption) (time.Time, string, error)
 	SetObject(ctx context.Context, info *relation.ObjectModel) error
+	// UploadObject stores data and records it under info.Name, Key, Size and Hash of info are filled in.
+	UploadObject(ctx context.Context, info *relation.ObjectModel, data []byte) error
+	// GetObject the record and the content of the object name, the caller closes the returned reader.
+	GetObject(ctx context.Context, name string) (*relation.ObjectModel, io.ReadCloser, error)
+}
+
+// NewObjectStorage the object storage chosen by config.Config.Object.Enable.
+func NewObjectStorage(rdb redis.UniversalClient) (s3.Interface, error) {
+	switch enable := config.Config.Object.Enable; enable {
+	case "minio":
+		return minio.NewMinio(cache.NewMinioCache(rdb))
+	case "cos":
+		return cos.NewCos()
+	case "oss":
+		return oss.NewOSS()
+	default:
+		return nil, fmt.Errorf("invalid object enable: %s", enable)
+	}
 }
 
 func NewS3Database(rdb redis.UniversalClient, s3 s3.Interface, obj relation.ObjectInfoModelInterface) S3Database {
@@ -78,6 +102,29 @@ func (s *s3Database) SetObject(ctx context.Context, info *relation.ObjectModel)
 	return s.cache.DelObjectName(info.Name).ExecDel(ctx)
 }
 
+func (s *s3Database) UploadObject(ctx context.Context, info *relation.ObjectModel, data []byte) error {
+	result, err := s.s3.UploadData(ctx, data)
+	if err != nil {
+		return err
+	}
+	info.Key = result.Key
+	info.Size = result.Size
+	info.Hash = result.Hash
+	return s.SetObject(ctx, info)
+}
+
+func (s *s3Database) GetObject(ctx context.Context, name string) (*relation.ObjectModel, io.ReadCloser, error) {
+	obj, err := s.cache.GetName(ctx, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	reader, err := s.s3.GetObject(ctx, obj.Key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return obj, reader, nil
+}
+
 func (s *s3Database) AccessURL(ctx context.Context, name string, expire time.Duration, opt *s3.AccessURLOption) (time.Time, string, error) {
 	obj, err := s.cache.GetName(ctx, name)
 	if err != nil {
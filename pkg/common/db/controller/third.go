@@ -27,6 +27,8 @@ import (
 
 type ThirdDatabase interface {
 	FcmUpdateToken(ctx context.Context, account string, platformID int, fcmToken string, expireTime int64) error
+	VoipUpdateToken(ctx context.Context, account string, platformID int, voipToken string, expireTime int64) error
+	// SetAppBadge the badge is counted on the server, a client report only makes the next push recount it
 	SetAppBadge(ctx context.Context, userID string, value int) error
 	// about log for debug
 	UploadLogs(ctx context.Context, logs []*relation.Log) error
@@ -81,6 +83,16 @@ func (t *thirdDatabase) FcmUpdateToken(
 	return t.cache.SetFcmToken(ctx, account, platformID, fcmToken, expireTime)
 }
 
+func (t *thirdDatabase) VoipUpdateToken(
+	ctx context.Context,
+	account string,
+	platformID int,
+	voipToken string,
+	expireTime int64,
+) error {
+	return t.cache.SetVoipToken(ctx, account, platformID, voipToken, expireTime)
+}
+
 func (t *thirdDatabase) SetAppBadge(ctx context.Context, userID string, value int) error {
-	return t.cache.SetUserBadgeUnreadCountSum(ctx, userID, value)
+	return t.cache.DelUserBadgeUnreadCountSum(ctx, userID)
 }
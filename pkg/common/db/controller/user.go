@@ -65,17 +65,22 @@ type UserDatabase interface {
 	GetUserStatus(ctx context.Context, userIDs []string) ([]*user.OnlineStatus, error)
 	// SetUserStatus Set the user status and store the user status in redis
 	SetUserStatus(ctx context.Context, userID string, status, platformID int32) error
+	// SetUserDoNotDisturb Save the quiet hours of the user
+	SetUserDoNotDisturb(ctx context.Context, dnd *relation.UserDoNotDisturbModel) error
+	// FindUserDoNotDisturb Get the quiet hours of the users, users who never set them are ignored
+	FindUserDoNotDisturb(ctx context.Context, userIDs []string) ([]*relation.UserDoNotDisturbModel, error)
 }
 
 type userDatabase struct {
-	userDB  relation.UserModelInterface
-	cache   cache.UserCache
-	tx      tx.Tx
-	mongoDB unrelationtb.UserModelInterface
+	userDB         relation.UserModelInterface
+	doNotDisturbDB relation.UserDoNotDisturbModelInterface
+	cache          cache.UserCache
+	tx             tx.Tx
+	mongoDB        unrelationtb.UserModelInterface
 }
 
-func NewUserDatabase(userDB relation.UserModelInterface, cache cache.UserCache, tx tx.Tx, mongoDB unrelationtb.UserModelInterface) UserDatabase {
-	return &userDatabase{userDB: userDB, cache: cache, tx: tx, mongoDB: mongoDB}
+func NewUserDatabase(userDB relation.UserModelInterface, doNotDisturbDB relation.UserDoNotDisturbModelInterface, cache cache.UserCache, tx tx.Tx, mongoDB unrelationtb.UserModelInterface) UserDatabase {
+	return &userDatabase{userDB: userDB, doNotDisturbDB: doNotDisturbDB, cache: cache, tx: tx, mongoDB: mongoDB}
 }
 
 func (u *userDatabase) InitOnce(ctx context.Context, users []*relation.UserModel) (err error) {
@@ -220,3 +225,13 @@ func (u *userDatabase) GetUserStatus(ctx context.Context, userIDs []string) ([]*
 func (u *userDatabase) SetUserStatus(ctx context.Context, userID string, status, platformID int32) error {
 	return u.cache.SetUserStatus(ctx, userID, status, platformID)
 }
+
+// SetUserDoNotDisturb Save the quiet hours of the user.
+func (u *userDatabase) SetUserDoNotDisturb(ctx context.Context, dnd *relation.UserDoNotDisturbModel) error {
+	return u.doNotDisturbDB.Save(ctx, dnd)
+}
+
+// FindUserDoNotDisturb Get the quiet hours of the users.
+func (u *userDatabase) FindUserDoNotDisturb(ctx context.Context, userIDs []string) ([]*relation.UserDoNotDisturbModel, error) {
+	return u.doNotDisturbDB.Find(ctx, userIDs)
+}
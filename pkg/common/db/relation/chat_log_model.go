@@ -15,14 +15,13 @@
 package relation
 
 import (
-	"github.com/golang/protobuf/jsonpb"
-	"github.com/jinzhu/copier"
-	"google.golang.org/protobuf/proto"
+	"context"
+	"strconv"
+	"time"
+
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
-	"github.com/OpenIMSDK/protocol/constant"
-	pbmsg "github.com/OpenIMSDK/protocol/msg"
-	sdkws "github.com/OpenIMSDK/protocol/sdkws"
 	"github.com/OpenIMSDK/tools/utils"
 
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
@@ -36,28 +35,62 @@ func NewChatLogGorm(db *gorm.DB) relation.ChatLogModelInterface {
 	return &ChatLogGorm{NewMetaDB(db, &relation.ChatLogModel{})}
 }
 
-func (c *ChatLogGorm) Create(msg *pbmsg.MsgDataToMQ) error {
-	chatLog := new(relation.ChatLogModel)
-	copier.Copy(chatLog, msg.MsgData)
-	switch msg.MsgData.SessionType {
-	case constant.GroupChatType, constant.SuperGroupChatType:
-		chatLog.RecvID = msg.MsgData.GroupID
-	case constant.SingleChatType:
-		chatLog.RecvID = msg.MsgData.RecvID
+// AutoMigrateChatLog migrates the chat log table, the non unique conversation_seq index of older versions
+// is replaced by conversation_seq_unique.
+func AutoMigrateChatLog(db *gorm.DB) error {
+	if err := db.AutoMigrate(&relation.ChatLogModel{}); err != nil {
+		return err
+	}
+	if db.Migrator().HasIndex(&relation.ChatLogModel{}, "conversation_seq") {
+		return db.Migrator().DropIndex(&relation.ChatLogModel{}, "conversation_seq")
 	}
-	if msg.MsgData.ContentType >= constant.NotificationBegin && msg.MsgData.ContentType <= constant.NotificationEnd {
-		var tips sdkws.TipsComm
-		_ = proto.Unmarshal(msg.MsgData.Content, &tips)
-		marshaler := jsonpb.Marshaler{
-			OrigName:     true,
-			EnumsAsInts:  false,
-			EmitDefaults: false,
-		}
-		chatLog.Content, _ = marshaler.MarshalToString(&tips)
-	} else {
-		chatLog.Content = string(msg.MsgData.Content)
+	return nil
+}
+
+// chatLogMsgColumns the columns of the msg itself, without the revoke and delete state.
+var chatLogMsgColumns = []string{
+	"server_msg_id", "client_msg_id", "send_id", "recv_id", "sender_platform_id", "sender_nick_name", "sender_face_url",
+	"session_type", "msg_from", "content_type", "content", "status", "send_time", "create_time", "ex",
+}
+
+func (c *ChatLogGorm) Create(ctx context.Context, chatLogs []*relation.ChatLogModel) error {
+	if len(chatLogs) == 0 {
+		return nil
+	}
+	return utils.Wrap(c.db(ctx).Clauses(clause.OnConflict{DoUpdates: clause.AssignmentColumns(chatLogMsgColumns)}).Create(chatLogs).Error, "")
+}
+
+func (c *ChatLogGorm) Save(ctx context.Context, chatLogs []*relation.ChatLogModel) error {
+	if len(chatLogs) == 0 {
+		return nil
 	}
-	chatLog.CreateTime = utils.UnixMillSecondToTime(msg.MsgData.CreateTime)
-	chatLog.SendTime = utils.UnixMillSecondToTime(msg.MsgData.SendTime)
-	return c.DB.Create(chatLog).Error
+	return utils.Wrap(c.db(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(chatLogs).Error, "")
+}
+
+func (c *ChatLogGorm) Revoke(ctx context.Context, conversationID string, seq int64, revokeUserID string, revokeTime time.Time) error {
+	// the placeholder gets the real server msg id once Create stores the msg
+	placeholder := &relation.ChatLogModel{
+		ServerMsgID:    utils.Md5(conversationID + ":" + strconv.FormatInt(seq, 10)),
+		ConversationID: conversationID,
+		Seq:            seq,
+		SendTime:       revokeTime,
+		CreateTime:     revokeTime,
+		RevokeUserID:   revokeUserID,
+		RevokeTime:     &revokeTime,
+	}
+	return utils.Wrap(c.db(ctx).Clauses(clause.OnConflict{DoUpdates: clause.AssignmentColumns([]string{"revoke_user_id", "revoke_time"})}).
+		Create(placeholder).Error, "")
+}
+
+func (c *ChatLogGorm) Delete(ctx context.Context, conversationID string, seqs []int64, deleteTime time.Time) error {
+	if len(seqs) == 0 {
+		return nil
+	}
+	return utils.Wrap(c.db(ctx).Where("conversation_id = ? and seq in (?) and is_deleted = ?", conversationID, seqs, false).
+		Updates(map[string]any{"is_deleted": true, "delete_time": deleteTime}).Error, "")
+}
+
+func (c *ChatLogGorm) DeleteBefore(ctx context.Context, conversationID string, seq int64, deleteTime time.Time) error {
+	return utils.Wrap(c.db(ctx).Where("conversation_id = ? and seq < ? and is_deleted = ?", conversationID, seq, false).
+		Updates(map[string]any{"is_deleted": true, "delete_time": deleteTime}).Error, "")
 }
@@ -248,3 +248,23 @@ func (c *ConversationGorm) GetConversationNotReceiveMessageUserIDs(ctx context.C
 			Pluck("owner_user_id", &userIDs).Error,
 	)
 }
+
+func (c *ConversationGorm) FindSnoozedUserIDs(ctx context.Context, conversationID string, userIDs []string, now int64) ([]string, error) {
+	var snoozedUserIDs []string
+	return snoozedUserIDs, errs.Wrap(
+		c.db(ctx).
+			Model(&relation.ConversationModel{}).
+			Where("conversation_id = ? and owner_user_id in ? and snooze_until > ?", conversationID, userIDs, now).
+			Pluck("owner_user_id", &snoozedUserIDs).Error,
+	)
+}
+
+func (c *ConversationGorm) FindOwnerConversationIDs(ctx context.Context, ownerUserIDs []string, conversationIDs []string) ([]string, error) {
+	var ownerConversationIDs []string
+	return ownerConversationIDs, errs.Wrap(
+		c.db(ctx).
+			Model(&relation.ConversationModel{}).
+			Where("owner_user_id in ? and conversation_id in ?", ownerUserIDs, conversationIDs).
+			Distinct().Pluck("conversation_id", &ownerConversationIDs).Error,
+	)
+}
@@ -0,0 +1,91 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/tools/ormutil"
+	"github.com/OpenIMSDK/tools/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+type GroupAnnouncementGorm struct {
+	*MetaDB
+}
+
+func NewGroupAnnouncementGorm(db *gorm.DB) relation.GroupAnnouncementModelInterface {
+	return &GroupAnnouncementGorm{NewMetaDB(db, &relation.GroupAnnouncementModel{})}
+}
+
+func (g *GroupAnnouncementGorm) NewTx(tx any) relation.GroupAnnouncementModelInterface {
+	return &GroupAnnouncementGorm{NewMetaDB(tx.(*gorm.DB), &relation.GroupAnnouncementModel{})}
+}
+
+func (g *GroupAnnouncementGorm) Create(ctx context.Context, announcement *relation.GroupAnnouncementModel) (err error) {
+	return utils.Wrap(g.db(ctx).Create(announcement).Error, "")
+}
+
+func (g *GroupAnnouncementGorm) Update(ctx context.Context, announcementID string, data map[string]any) (err error) {
+	return utils.Wrap(g.db(ctx).Where("announcement_id = ?", announcementID).Updates(data).Error, "")
+}
+
+func (g *GroupAnnouncementGorm) Delete(ctx context.Context, announcementID string) (err error) {
+	return utils.Wrap(g.db(ctx).Where("announcement_id = ?", announcementID).Delete(&relation.GroupAnnouncementModel{}).Error, "")
+}
+
+func (g *GroupAnnouncementGorm) Take(ctx context.Context, announcementID string) (announcement *relation.GroupAnnouncementModel, err error) {
+	announcement = &relation.GroupAnnouncementModel{}
+	return announcement, utils.Wrap(g.db(ctx).Where("announcement_id = ?", announcementID).Take(announcement).Error, "")
+}
+
+func (g *GroupAnnouncementGorm) Page(ctx context.Context, groupID string, pageNumber, showNumber int32) (total uint32, announcements []*relation.GroupAnnouncementModel, err error) {
+	db := g.db(ctx).Where("group_id = ?", groupID).Order("pinned desc").Order("create_time desc")
+	return ormutil.GormPage[relation.GroupAnnouncementModel](db, pageNumber, showNumber)
+}
+
+type GroupAnnouncementAckGorm struct {
+	*MetaDB
+}
+
+func NewGroupAnnouncementAckGorm(db *gorm.DB) relation.GroupAnnouncementAckModelInterface {
+	return &GroupAnnouncementAckGorm{NewMetaDB(db, &relation.GroupAnnouncementAckModel{})}
+}
+
+func (g *GroupAnnouncementAckGorm) NewTx(tx any) relation.GroupAnnouncementAckModelInterface {
+	return &GroupAnnouncementAckGorm{NewMetaDB(tx.(*gorm.DB), &relation.GroupAnnouncementAckModel{})}
+}
+
+func (g *GroupAnnouncementAckGorm) Create(ctx context.Context, ack *relation.GroupAnnouncementAckModel) (err error) {
+	return utils.Wrap(g.db(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(ack).Error, "")
+}
+
+func (g *GroupAnnouncementAckGorm) Delete(ctx context.Context, announcementID string) (err error) {
+	return utils.Wrap(g.db(ctx).Where("announcement_id = ?", announcementID).Delete(&relation.GroupAnnouncementAckModel{}).Error, "")
+}
+
+func (g *GroupAnnouncementAckGorm) FindUserID(ctx context.Context, announcementID string) (userIDs []string, err error) {
+	return userIDs, utils.Wrap(g.db(ctx).Where("announcement_id = ?", announcementID).Pluck("user_id", &userIDs).Error, "")
+}
+
+func (g *GroupAnnouncementAckGorm) FindAckedAnnouncementID(ctx context.Context, userID string, announcementIDs []string) (ackedIDs []string, err error) {
+	if len(announcementIDs) == 0 {
+		return nil, nil
+	}
+	return ackedIDs, utils.Wrap(g.db(ctx).Where("user_id = ? and announcement_id in (?)", userID, announcementIDs).Pluck("announcement_id", &ackedIDs).Error, "")
+}
@@ -0,0 +1,48 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/tools/ormutil"
+	"github.com/OpenIMSDK/tools/utils"
+	"gorm.io/gorm"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+type GroupAuditLogGorm struct {
+	*MetaDB
+}
+
+func NewGroupAuditLogGorm(db *gorm.DB) relation.GroupAuditLogModelInterface {
+	return &GroupAuditLogGorm{NewMetaDB(db, &relation.GroupAuditLogModel{})}
+}
+
+func (g *GroupAuditLogGorm) Create(ctx context.Context, logs []*relation.GroupAuditLogModel) (err error) {
+	return utils.Wrap(g.db(ctx).Create(&logs).Error, "")
+}
+
+func (g *GroupAuditLogGorm) Page(ctx context.Context, groupID string, action string, targetUserID string, pageNumber, showNumber int32) (total uint32, logs []*relation.GroupAuditLogModel, err error) {
+	db := g.db(ctx).Where("group_id = ?", groupID)
+	if action != "" {
+		db = db.Where("action = ?", action)
+	}
+	if targetUserID != "" {
+		db = db.Where("target_user_id = ?", targetUserID)
+	}
+	return ormutil.GormPage[relation.GroupAuditLogModel](db.Order("create_time desc, id desc"), pageNumber, showNumber)
+}
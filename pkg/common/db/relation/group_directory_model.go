@@ -0,0 +1,81 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/tools/ormutil"
+	"github.com/OpenIMSDK/tools/utils"
+	"gorm.io/gorm"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+type GroupDirectoryGorm struct {
+	*MetaDB
+}
+
+func NewGroupDirectoryGorm(db *gorm.DB) relation.GroupDirectoryModelInterface {
+	return &GroupDirectoryGorm{NewMetaDB(db, &relation.GroupDirectoryModel{})}
+}
+
+func (g *GroupDirectoryGorm) Save(ctx context.Context, entry *relation.GroupDirectoryModel) (err error) {
+	return utils.Wrap(g.DB.WithContext(ctx).Save(entry).Error, "")
+}
+
+func (g *GroupDirectoryGorm) Delete(ctx context.Context, groupID string) (err error) {
+	return utils.Wrap(g.db(ctx).Where("group_id = ?", groupID).Delete(&relation.GroupDirectoryModel{}).Error, "")
+}
+
+func (g *GroupDirectoryGorm) Take(ctx context.Context, groupID string) (entry *relation.GroupDirectoryModel, err error) {
+	entry = &relation.GroupDirectoryModel{}
+	return entry, utils.Wrap(g.db(ctx).Where("group_id = ?", groupID).Take(entry).Error, "")
+}
+
+func (g *GroupDirectoryGorm) UpdateActiveTime(ctx context.Context, groupID string, activeTime time.Time) (err error) {
+	return utils.Wrap(g.db(ctx).Where("group_id = ?", groupID).UpdateColumn("active_time", activeTime).Error, "")
+}
+
+func (g *GroupDirectoryGorm) Search(ctx context.Context, keyword string, category string, tag string, orderBy string, pageNumber, showNumber int32) (total uint32, entries []*relation.GroupDirectoryModel, err error) {
+	groups := g.DB.WithContext(ctx).Model(&relation.GroupModel{})
+	db := g.db(ctx).Where("group_id in (?)", groups.Where("status <> ?", constant.GroupStatusDismissed).Select("group_id"))
+	if keyword != "" {
+		like := "%" + keyword + "%"
+		named := g.DB.WithContext(ctx).Model(&relation.GroupModel{}).Where("name like ?", like).Select("group_id")
+		db = db.Where("description like ? or tags like ? or group_id in (?)", like, like, named)
+	}
+	if category != "" {
+		db = db.Where("category = ?", category)
+	}
+	if tag != "" {
+		db = db.Where("tags like ?", "%,"+tag+",%")
+	}
+	switch orderBy {
+	case relation.GroupDirectoryOrderActivity:
+		db = db.Order("active_time desc")
+	default:
+		db = db.Order("(select count(*) from " + relation.GroupMemberModelTableName + " m where m.group_id = " +
+			relation.GroupDirectoryModelTableName + ".group_id) desc")
+	}
+	return ormutil.GormPage[relation.GroupDirectoryModel](db, pageNumber, showNumber)
+}
+
+func (g *GroupDirectoryGorm) Page(ctx context.Context, pageNumber, showNumber int32) (entries []*relation.GroupDirectoryModel, err error) {
+	err = g.db(ctx).Order("group_id").Limit(int(showNumber)).Offset(int((pageNumber - 1) * showNumber)).Find(&entries).Error
+	return entries, utils.Wrap(err, "")
+}
@@ -0,0 +1,70 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/ormutil"
+	"github.com/OpenIMSDK/tools/utils"
+	"gorm.io/gorm"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+type GroupInviteLinkGorm struct {
+	*MetaDB
+}
+
+func NewGroupInviteLinkGorm(db *gorm.DB) relation.GroupInviteLinkModelInterface {
+	return &GroupInviteLinkGorm{NewMetaDB(db, &relation.GroupInviteLinkModel{})}
+}
+
+func (g *GroupInviteLinkGorm) NewTx(tx any) relation.GroupInviteLinkModelInterface {
+	return &GroupInviteLinkGorm{NewMetaDB(tx.(*gorm.DB), &relation.GroupInviteLinkModel{})}
+}
+
+func (g *GroupInviteLinkGorm) Create(ctx context.Context, link *relation.GroupInviteLinkModel) (err error) {
+	return utils.Wrap(g.db(ctx).Create(link).Error, "")
+}
+
+func (g *GroupInviteLinkGorm) Take(ctx context.Context, code string) (link *relation.GroupInviteLinkModel, err error) {
+	link = &relation.GroupInviteLinkModel{}
+	return link, utils.Wrap(g.db(ctx).Where("code = ?", code).Take(link).Error, "")
+}
+
+func (g *GroupInviteLinkGorm) Revoke(ctx context.Context, code string) (rows int64, err error) {
+	res := g.db(ctx).Where("code = ? and revoked = ?", code, false).Update("revoked", true)
+	return res.RowsAffected, utils.Wrap(res.Error, "")
+}
+
+func (g *GroupInviteLinkGorm) Use(ctx context.Context, code string, now time.Time) (rows int64, err error) {
+	res := g.db(ctx).Where("code = ? and revoked = ? and expire_time > ? and (max_uses = 0 or used_count < max_uses)", code, false, now).
+		Update("used_count", gorm.Expr("used_count + 1"))
+	return res.RowsAffected, utils.Wrap(res.Error, "")
+}
+
+func (g *GroupInviteLinkGorm) Unuse(ctx context.Context, code string) (err error) {
+	return utils.Wrap(g.db(ctx).Where("code = ? and used_count > 0", code).Update("used_count", gorm.Expr("used_count - 1")).Error, "")
+}
+
+func (g *GroupInviteLinkGorm) Page(ctx context.Context, groupID string, valid bool, pageNumber, showNumber int32) (total uint32, links []*relation.GroupInviteLinkModel, err error) {
+	db := g.db(ctx).Where("group_id = ?", groupID)
+	if valid {
+		db = db.Where("revoked = ? and expire_time > ? and (max_uses = 0 or used_count < max_uses)", false, time.Now())
+	}
+	return ormutil.GormPage[relation.GroupInviteLinkModel](db.Order("create_time desc"), pageNumber, showNumber)
+}
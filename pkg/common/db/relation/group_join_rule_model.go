@@ -0,0 +1,45 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/tools/utils"
+	"gorm.io/gorm"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+type GroupJoinRuleGorm struct {
+	*MetaDB
+}
+
+func NewGroupJoinRuleGorm(db *gorm.DB) relation.GroupJoinRuleModelInterface {
+	return &GroupJoinRuleGorm{NewMetaDB(db, &relation.GroupJoinRuleModel{})}
+}
+
+func (g *GroupJoinRuleGorm) Save(ctx context.Context, rule *relation.GroupJoinRuleModel) (err error) {
+	return utils.Wrap(g.DB.WithContext(ctx).Save(rule).Error, "")
+}
+
+func (g *GroupJoinRuleGorm) Delete(ctx context.Context, groupID string) (err error) {
+	return utils.Wrap(g.db(ctx).Where("group_id = ?", groupID).Delete(&relation.GroupJoinRuleModel{}).Error, "")
+}
+
+func (g *GroupJoinRuleGorm) Take(ctx context.Context, groupID string) (rule *relation.GroupJoinRuleModel, err error) {
+	rule = &relation.GroupJoinRuleModel{}
+	return rule, utils.Wrap(g.db(ctx).Where("group_id = ?", groupID).Take(rule).Error, "")
+}
@@ -0,0 +1,55 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/tools/utils"
+	"gorm.io/gorm"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+type GroupMemberTagGorm struct {
+	*MetaDB
+}
+
+func NewGroupMemberTagGorm(db *gorm.DB) relation.GroupMemberTagModelInterface {
+	return &GroupMemberTagGorm{NewMetaDB(db, &relation.GroupMemberTagModel{})}
+}
+
+func (g *GroupMemberTagGorm) NewTx(tx any) relation.GroupMemberTagModelInterface {
+	return &GroupMemberTagGorm{NewMetaDB(tx.(*gorm.DB), &relation.GroupMemberTagModel{})}
+}
+
+func (g *GroupMemberTagGorm) Create(ctx context.Context, tags []*relation.GroupMemberTagModel) (err error) {
+	if len(tags) == 0 {
+		return nil
+	}
+	return utils.Wrap(g.db(ctx).Create(tags).Error, "")
+}
+
+func (g *GroupMemberTagGorm) Delete(ctx context.Context, groupID string, tag string) (err error) {
+	return utils.Wrap(g.db(ctx).Where("group_id = ? and tag = ?", groupID, tag).Delete(&relation.GroupMemberTagModel{}).Error, "")
+}
+
+func (g *GroupMemberTagGorm) Find(ctx context.Context, groupID string, tags []string) (memberTags []*relation.GroupMemberTagModel, err error) {
+	db := g.db(ctx).Where("group_id = ?", groupID)
+	if len(tags) > 0 {
+		db = db.Where("tag in (?)", tags)
+	}
+	return memberTags, utils.Wrap(db.Order("tag").Order("create_time").Find(&memberTags).Error, "")
+}
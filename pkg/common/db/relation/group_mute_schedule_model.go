@@ -0,0 +1,61 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/tools/ormutil"
+	"github.com/OpenIMSDK/tools/utils"
+	"gorm.io/gorm"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+type GroupMuteScheduleGorm struct {
+	*MetaDB
+}
+
+func NewGroupMuteScheduleGorm(db *gorm.DB) relation.GroupMuteScheduleModelInterface {
+	return &GroupMuteScheduleGorm{NewMetaDB(db, &relation.GroupMuteScheduleModel{})}
+}
+
+func (g *GroupMuteScheduleGorm) Create(ctx context.Context, schedules []*relation.GroupMuteScheduleModel) (err error) {
+	return utils.Wrap(g.db(ctx).Create(schedules).Error, "")
+}
+
+func (g *GroupMuteScheduleGorm) Take(ctx context.Context, id string) (schedule *relation.GroupMuteScheduleModel, err error) {
+	schedule = &relation.GroupMuteScheduleModel{}
+	return schedule, utils.Wrap(g.db(ctx).Where("id = ?", id).Take(schedule).Error, "")
+}
+
+func (g *GroupMuteScheduleGorm) Delete(ctx context.Context, ids []string) (err error) {
+	if len(ids) == 0 {
+		return nil
+	}
+	return utils.Wrap(g.db(ctx).Where("id in (?)", ids).Delete(&relation.GroupMuteScheduleModel{}).Error, "")
+}
+
+func (g *GroupMuteScheduleGorm) UpdateActive(ctx context.Context, id string, active bool) (err error) {
+	return utils.Wrap(g.db(ctx).Where("id = ?", id).Update("active", active).Error, "")
+}
+
+func (g *GroupMuteScheduleGorm) FindByGroupID(ctx context.Context, groupID string) (schedules []*relation.GroupMuteScheduleModel, err error) {
+	return schedules, utils.Wrap(g.db(ctx).Where("group_id = ?", groupID).Order("create_time").Find(&schedules).Error, "")
+}
+
+func (g *GroupMuteScheduleGorm) Page(ctx context.Context, pageNumber int32, showNumber int32) (total uint32, schedules []*relation.GroupMuteScheduleModel, err error) {
+	return ormutil.GormPage[relation.GroupMuteScheduleModel](g.db(ctx).Order("id"), pageNumber, showNumber)
+}
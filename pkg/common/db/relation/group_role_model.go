@@ -0,0 +1,50 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/tools/utils"
+	"gorm.io/gorm"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+type GroupRoleGorm struct {
+	*MetaDB
+}
+
+func NewGroupRoleGorm(db *gorm.DB) relation.GroupRoleModelInterface {
+	return &GroupRoleGorm{NewMetaDB(db, &relation.GroupRoleModel{})}
+}
+
+func (g *GroupRoleGorm) Save(ctx context.Context, role *relation.GroupRoleModel) (err error) {
+	return utils.Wrap(g.DB.WithContext(ctx).Save(role).Error, "")
+}
+
+func (g *GroupRoleGorm) Delete(ctx context.Context, groupID string, roleLevel int32) (rows int64, err error) {
+	res := g.db(ctx).Where("group_id = ? and role_level = ?", groupID, roleLevel).Delete(&relation.GroupRoleModel{})
+	return res.RowsAffected, utils.Wrap(res.Error, "")
+}
+
+func (g *GroupRoleGorm) Take(ctx context.Context, groupID string, roleLevel int32) (role *relation.GroupRoleModel, err error) {
+	role = &relation.GroupRoleModel{}
+	return role, utils.Wrap(g.db(ctx).Where("group_id = ? and role_level = ?", groupID, roleLevel).Take(role).Error, "")
+}
+
+func (g *GroupRoleGorm) FindByGroupID(ctx context.Context, groupID string) (roles []*relation.GroupRoleModel, err error) {
+	return roles, utils.Wrap(g.db(ctx).Where("group_id = ?", groupID).Order("role_level desc").Find(&roles).Error, "")
+}
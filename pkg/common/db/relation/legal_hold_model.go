@@ -0,0 +1,94 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/tools/ormutil"
+	"github.com/OpenIMSDK/tools/utils"
+	"gorm.io/gorm"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+type LegalHoldGorm struct {
+	*MetaDB
+}
+
+func NewLegalHoldGorm(db *gorm.DB) relation.LegalHoldModelInterface {
+	return &LegalHoldGorm{NewMetaDB(db, &relation.LegalHoldModel{})}
+}
+
+func (l *LegalHoldGorm) NewTx(tx any) relation.LegalHoldModelInterface {
+	return &LegalHoldGorm{NewMetaDB(tx.(*gorm.DB), &relation.LegalHoldModel{})}
+}
+
+func (l *LegalHoldGorm) Create(ctx context.Context, hold *relation.LegalHoldModel) (err error) {
+	return utils.Wrap(l.db(ctx).Create(hold).Error, "")
+}
+
+func (l *LegalHoldGorm) Delete(ctx context.Context, targetType int32, targetID string) (rows int64, err error) {
+	res := l.db(ctx).Where("target_type = ? and target_id = ?", targetType, targetID).Delete(&relation.LegalHoldModel{})
+	return res.RowsAffected, utils.Wrap(res.Error, "")
+}
+
+func (l *LegalHoldGorm) Take(ctx context.Context, targetType int32, targetID string) (hold *relation.LegalHoldModel, err error) {
+	hold = &relation.LegalHoldModel{}
+	return hold, utils.Wrap(l.db(ctx).Where("target_type = ? and target_id = ?", targetType, targetID).Take(hold).Error, "")
+}
+
+func (l *LegalHoldGorm) FindTargetIDs(ctx context.Context, targetType int32, targetIDs []string) (heldTargetIDs []string, err error) {
+	db := l.db(ctx).Where("target_type = ?", targetType)
+	if targetIDs != nil {
+		db = db.Where("target_id in (?)", targetIDs)
+	}
+	return heldTargetIDs, utils.Wrap(db.Pluck("target_id", &heldTargetIDs).Error, "")
+}
+
+func (l *LegalHoldGorm) Page(ctx context.Context, targetType int32, pageNumber, showNumber int32) (total uint32, holds []*relation.LegalHoldModel, err error) {
+	db := l.db(ctx)
+	if targetType != 0 {
+		db = db.Where("target_type = ?", targetType)
+	}
+	return ormutil.GormPage[relation.LegalHoldModel](db.Order("create_time desc"), pageNumber, showNumber)
+}
+
+type LegalHoldLogGorm struct {
+	*MetaDB
+}
+
+func NewLegalHoldLogGorm(db *gorm.DB) relation.LegalHoldLogModelInterface {
+	return &LegalHoldLogGorm{NewMetaDB(db, &relation.LegalHoldLogModel{})}
+}
+
+func (l *LegalHoldLogGorm) NewTx(tx any) relation.LegalHoldLogModelInterface {
+	return &LegalHoldLogGorm{NewMetaDB(tx.(*gorm.DB), &relation.LegalHoldLogModel{})}
+}
+
+func (l *LegalHoldLogGorm) Create(ctx context.Context, logs []*relation.LegalHoldLogModel) (err error) {
+	return utils.Wrap(l.db(ctx).Create(&logs).Error, "")
+}
+
+func (l *LegalHoldLogGorm) Page(ctx context.Context, targetType int32, targetID string, pageNumber, showNumber int32) (total uint32, logs []*relation.LegalHoldLogModel, err error) {
+	db := l.db(ctx)
+	if targetType != 0 {
+		db = db.Where("target_type = ?", targetType)
+	}
+	if targetID != "" {
+		db = db.Where("target_id = ?", targetID)
+	}
+	return ormutil.GormPage[relation.LegalHoldLogModel](db.Order("id desc"), pageNumber, showNumber)
+}
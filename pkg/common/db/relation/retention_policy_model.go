@@ -0,0 +1,54 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/tools/ormutil"
+	"github.com/OpenIMSDK/tools/utils"
+	"gorm.io/gorm"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+type RetentionPolicyGorm struct {
+	*MetaDB
+}
+
+func NewRetentionPolicyGorm(db *gorm.DB) relation.RetentionPolicyModelInterface {
+	return &RetentionPolicyGorm{NewMetaDB(db, &relation.RetentionPolicyModel{})}
+}
+
+func (r *RetentionPolicyGorm) Save(ctx context.Context, policy *relation.RetentionPolicyModel) (err error) {
+	return utils.Wrap(r.DB.WithContext(ctx).Save(policy).Error, "")
+}
+
+func (r *RetentionPolicyGorm) Delete(ctx context.Context, scopeType int32, scopeID string) (rows int64, err error) {
+	res := r.db(ctx).Where("scope_type = ? and scope_id = ?", scopeType, scopeID).Delete(&relation.RetentionPolicyModel{})
+	return res.RowsAffected, utils.Wrap(res.Error, "")
+}
+
+func (r *RetentionPolicyGorm) FindAll(ctx context.Context) (policies []*relation.RetentionPolicyModel, err error) {
+	return policies, utils.Wrap(r.db(ctx).Find(&policies).Error, "")
+}
+
+func (r *RetentionPolicyGorm) Page(ctx context.Context, scopeType int32, pageNumber, showNumber int32) (total uint32, policies []*relation.RetentionPolicyModel, err error) {
+	db := r.db(ctx)
+	if scopeType != 0 {
+		db = db.Where("scope_type = ?", scopeType)
+	}
+	return ormutil.GormPage[relation.RetentionPolicyModel](db.Order("update_time desc"), pageNumber, showNumber)
+}
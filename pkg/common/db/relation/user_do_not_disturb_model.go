@@ -0,0 +1,40 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/tools/utils"
+	"gorm.io/gorm"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+type UserDoNotDisturbGorm struct {
+	*MetaDB
+}
+
+func NewUserDoNotDisturbGorm(db *gorm.DB) relation.UserDoNotDisturbModelInterface {
+	return &UserDoNotDisturbGorm{NewMetaDB(db, &relation.UserDoNotDisturbModel{})}
+}
+
+func (u *UserDoNotDisturbGorm) Save(ctx context.Context, dnd *relation.UserDoNotDisturbModel) (err error) {
+	return utils.Wrap(u.DB.WithContext(ctx).Save(dnd).Error, "")
+}
+
+func (u *UserDoNotDisturbGorm) Find(ctx context.Context, userIDs []string) (dnds []*relation.UserDoNotDisturbModel, err error) {
+	return dnds, utils.Wrap(u.db(ctx).Where("user_id in (?)", userIDs).Find(&dnds).Error, "")
+}
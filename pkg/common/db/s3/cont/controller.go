@@ -15,11 +15,13 @@
 package cont
 
 import (
+	"bytes"
 	"context"
 	"crypto/md5"
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
 	"path"
 	"strings"
 	"time"
@@ -79,6 +81,11 @@ func (c *Controller) StatObject(ctx context.Context, name string) (*s3.ObjectInf
 	return c.cache.GetKey(ctx, c.impl.Engine(), name)
 }
 
+// GetObject the caller closes the returned reader.
+func (c *Controller) GetObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	return c.impl.GetObject(ctx, key)
+}
+
 func (c *Controller) GetHashObject(ctx context.Context, hash string) (*s3.ObjectInfo, error) {
 	return c.StatObject(ctx, c.HashPath(hash))
 }
@@ -249,6 +256,34 @@ func (c *Controller) CompleteUpload(ctx context.Context, uploadID string, partHa
 	}, nil
 }
 
+// UploadData puts a small in memory object, hashed the same way as a single part upload so equal data is stored once.
+func (c *Controller) UploadData(ctx context.Context, data []byte) (*UploadResult, error) {
+	partSum := md5.Sum(data)
+	md5Sum := md5.Sum([]byte(hex.EncodeToString(partSum[:])))
+	hash := hex.EncodeToString(md5Sum[:])
+	key := c.HashPath(hash)
+	if info, err := c.StatObject(ctx, key); err == nil {
+		return &UploadResult{
+			Key:  info.Key,
+			Size: info.Size,
+			Hash: hash,
+		}, nil
+	} else if !c.IsNotFound(err) {
+		return nil, err
+	}
+	if err := c.impl.PutObject(ctx, key, bytes.NewReader(data), int64(len(data))); err != nil {
+		return nil, err
+	}
+	if err := c.cache.DelS3Key(c.impl.Engine(), key).ExecDel(ctx); err != nil {
+		return nil, err
+	}
+	return &UploadResult{
+		Key:  key,
+		Size: int64(len(data)),
+		Hash: hash,
+	}, nil
+}
+
 func (c *Controller) AuthSign(ctx context.Context, uploadID string, partNumbers []int) (*s3.AuthSignResult, error) {
 	upload, err := parseMultipartUploadID(uploadID)
 	if err != nil {
@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -173,6 +174,21 @@ func (c *Cos) DeleteObject(ctx context.Context, name string) error {
 	return err
 }
 
+func (c *Cos) PutObject(ctx context.Context, name string, reader io.Reader, size int64) error {
+	_, err := c.client.Object.Put(ctx, name, reader, &cos.ObjectPutOptions{
+		ObjectPutHeaderOptions: &cos.ObjectPutHeaderOptions{ContentLength: size},
+	})
+	return err
+}
+
+func (c *Cos) GetObject(ctx context.Context, name string) (io.ReadCloser, error) {
+	resp, err := c.client.Object.Get(ctx, name, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
 func (c *Cos) StatObject(ctx context.Context, name string) (*s3.ObjectInfo, error) {
 	if name != "" && name[0] == '/' {
 		name = name[1:]
@@ -304,6 +304,30 @@ func (m *Minio) DeleteObject(ctx context.Context, name string) error {
 	return m.core.Client.RemoveObject(ctx, m.bucket, name, minio.RemoveObjectOptions{})
 }
 
+func (m *Minio) PutObject(ctx context.Context, name string, reader io.Reader, size int64) error {
+	if err := m.initMinio(ctx); err != nil {
+		return err
+	}
+	_, err := m.core.Client.PutObject(ctx, m.bucket, name, reader, size, minio.PutObjectOptions{})
+	return err
+}
+
+func (m *Minio) GetObject(ctx context.Context, name string) (io.ReadCloser, error) {
+	if err := m.initMinio(ctx); err != nil {
+		return nil, err
+	}
+	object, err := m.core.Client.GetObject(ctx, m.bucket, name, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	// minio reports a missing object on the first read, stat surfaces it here
+	if _, err := object.Stat(); err != nil {
+		_ = object.Close()
+		return nil, err
+	}
+	return object, nil
+}
+
 func (m *Minio) StatObject(ctx context.Context, name string) (*s3.ObjectInfo, error) {
 	if err := m.initMinio(ctx); err != nil {
 		return nil, err
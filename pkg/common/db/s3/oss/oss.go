@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"reflect"
@@ -213,6 +214,14 @@ func (o *OSS) StatObject(ctx context.Context, name string) (*s3.ObjectInfo, erro
 	return res, nil
 }
 
+func (o *OSS) PutObject(ctx context.Context, name string, reader io.Reader, size int64) error {
+	return o.bucket.PutObject(name, reader, oss.ContentLength(size))
+}
+
+func (o *OSS) GetObject(ctx context.Context, name string) (io.ReadCloser, error) {
+	return o.bucket.GetObject(name)
+}
+
 func (o *OSS) DeleteObject(ctx context.Context, name string) error {
 	return o.bucket.DeleteObject(name)
 }
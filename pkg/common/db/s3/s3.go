@@ -16,6 +16,7 @@ package s3
 
 import (
 	"context"
+	"io"
 	"net/http"
 	"net/url"
 	"time"
@@ -142,6 +143,11 @@ type Interface interface {
 
 	DeleteObject(ctx context.Context, name string) error
 
+	// PutObject uploads the size bytes of reader as name, for objects the server writes itself
+	PutObject(ctx context.Context, name string, reader io.Reader, size int64) error
+	// GetObject the caller closes the returned reader
+	GetObject(ctx context.Context, name string) (io.ReadCloser, error)
+
 	CopyObject(ctx context.Context, src string, dst string) (*CopyObjectInfo, error)
 
 	StatObject(ctx context.Context, name string) (*ObjectInfo, error)
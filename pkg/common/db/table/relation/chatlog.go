@@ -15,9 +15,8 @@
 package relation
 
 import (
+	"context"
 	"time"
-
-	pbmsg "github.com/OpenIMSDK/protocol/msg"
 )
 
 const (
@@ -25,21 +24,27 @@ const (
 )
 
 type ChatLogModel struct {
-	ServerMsgID      string    `gorm:"column:server_msg_id;primary_key;type:char(64)"                                                                                                json:"serverMsgID"`
-	ClientMsgID      string    `gorm:"column:client_msg_id;type:char(64)"                                                                                                            json:"clientMsgID"`
-	SendID           string    `gorm:"column:send_id;type:char(64);index:send_id,priority:2"                                                                                         json:"sendID"`
-	RecvID           string    `gorm:"column:recv_id;type:char(64);index:recv_id,priority:2"                                                                                         json:"recvID"`
-	SenderPlatformID int32     `gorm:"column:sender_platform_id"                                                                                                                     json:"senderPlatformID"`
-	SenderNickname   string    `gorm:"column:sender_nick_name;type:varchar(255)"                                                                                                     json:"senderNickname"`
-	SenderFaceURL    string    `gorm:"column:sender_face_url;type:varchar(255);"                                                                                                     json:"senderFaceURL"`
-	SessionType      int32     `gorm:"column:session_type;index:session_type,priority:2;index:session_type_alone"                                                                    json:"sessionType"`
-	MsgFrom          int32     `gorm:"column:msg_from"                                                                                                                               json:"msgFrom"`
-	ContentType      int32     `gorm:"column:content_type;index:content_type,priority:2;index:content_type_alone"                                                                    json:"contentType"`
-	Content          string    `gorm:"column:content;type:varchar(3000)"                                                                                                             json:"content"`
-	Status           int32     `gorm:"column:status"                                                                                                                                 json:"status"`
-	SendTime         time.Time `gorm:"column:send_time;index:sendTime;index:content_type,priority:1;index:session_type,priority:1;index:recv_id,priority:1;index:send_id,priority:1" json:"sendTime"`
-	CreateTime       time.Time `gorm:"column:create_time"                                                                                                                            json:"createTime"`
-	Ex               string    `gorm:"column:ex;type:varchar(1024)"                                                                                                                  json:"ex"`
+	ServerMsgID      string     `gorm:"column:server_msg_id;primary_key;type:char(64)"                                                                                                json:"serverMsgID"`
+	ConversationID   string     `gorm:"column:conversation_id;type:char(128);uniqueIndex:conversation_seq_unique,priority:1"                                                         json:"conversationID"`
+	Seq              int64      `gorm:"column:seq;uniqueIndex:conversation_seq_unique,priority:2"                                                                                    json:"seq"`
+	ClientMsgID      string     `gorm:"column:client_msg_id;type:char(64)"                                                                                                            json:"clientMsgID"`
+	SendID           string     `gorm:"column:send_id;type:char(64);index:send_id,priority:2"                                                                                         json:"sendID"`
+	RecvID           string     `gorm:"column:recv_id;type:char(64);index:recv_id,priority:2"                                                                                         json:"recvID"`
+	SenderPlatformID int32      `gorm:"column:sender_platform_id"                                                                                                                     json:"senderPlatformID"`
+	SenderNickname   string     `gorm:"column:sender_nick_name;type:varchar(255)"                                                                                                     json:"senderNickname"`
+	SenderFaceURL    string     `gorm:"column:sender_face_url;type:varchar(255);"                                                                                                     json:"senderFaceURL"`
+	SessionType      int32      `gorm:"column:session_type;index:session_type,priority:2;index:session_type_alone"                                                                    json:"sessionType"`
+	MsgFrom          int32      `gorm:"column:msg_from"                                                                                                                               json:"msgFrom"`
+	ContentType      int32      `gorm:"column:content_type;index:content_type,priority:2;index:content_type_alone"                                                                    json:"contentType"`
+	Content          string     `gorm:"column:content;type:longtext"                                                                                                                  json:"content"`
+	Status           int32      `gorm:"column:status"                                                                                                                                 json:"status"`
+	SendTime         time.Time  `gorm:"column:send_time;index:sendTime;index:content_type,priority:1;index:session_type,priority:1;index:recv_id,priority:1;index:send_id,priority:1" json:"sendTime"`
+	CreateTime       time.Time  `gorm:"column:create_time"                                                                                                                            json:"createTime"`
+	Ex               string     `gorm:"column:ex;type:varchar(1024)"                                                                                                                  json:"ex"`
+	RevokeUserID     string     `gorm:"column:revoke_user_id;type:char(64)"                                                                                                           json:"revokeUserID"`
+	RevokeTime       *time.Time `gorm:"column:revoke_time"                                                                                                                            json:"revokeTime"`
+	IsDeleted        bool       `gorm:"column:is_deleted"                                                                                                                             json:"isDeleted"`
+	DeleteTime       *time.Time `gorm:"column:delete_time"                                                                                                                            json:"deleteTime"`
 }
 
 func (ChatLogModel) TableName() string {
@@ -47,5 +52,14 @@ func (ChatLogModel) TableName() string {
 }
 
 type ChatLogModelInterface interface {
-	Create(msg *pbmsg.MsgDataToMQ) error
+	// Create fills in the msgs of the chat logs already stored, their revoke and delete state is kept,
+	// so neither a redelivered msg nor a revoke that arrived first is lost.
+	Create(ctx context.Context, chatLogs []*ChatLogModel) error
+	// Save overwrites the chat logs already stored.
+	Save(ctx context.Context, chatLogs []*ChatLogModel) error
+	// Revoke stores a placeholder carrying the revoke if the msg of conversationID and seq is not stored yet.
+	Revoke(ctx context.Context, conversationID string, seq int64, revokeUserID string, revokeTime time.Time) error
+	Delete(ctx context.Context, conversationID string, seqs []int64, deleteTime time.Time) error
+	// DeleteBefore marks the chat logs of the conversation below seq deleted.
+	DeleteBefore(ctx context.Context, conversationID string, seq int64, deleteTime time.Time) error
 }
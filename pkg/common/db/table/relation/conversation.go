@@ -42,6 +42,7 @@ type ConversationModel struct {
 	IsMsgDestruct         bool      `gorm:"column:is_msg_destruct;default:false"`
 	MsgDestructTime       int64     `gorm:"column:msg_destruct_time;default:604800"`
 	LatestMsgDestructTime time.Time `gorm:"column:latest_msg_destruct_time;autoCreateTime"`
+	SnoozeUntil           int64     `gorm:"column:snooze_until;default:0"                       json:"snoozeUntil"`
 }
 
 func (ConversationModel) TableName() string {
@@ -69,5 +70,9 @@ type ConversationModelInterface interface {
 	GetConversationsByConversationID(ctx context.Context, conversationIDs []string) ([]*ConversationModel, error)
 	GetConversationIDsNeedDestruct(ctx context.Context) ([]*ConversationModel, error)
 	GetConversationNotReceiveMessageUserIDs(ctx context.Context, conversationID string) ([]string, error)
+	// FindSnoozedUserIDs returns the owners of conversationID among userIDs whose snooze_until (ms) is after now
+	FindSnoozedUserIDs(ctx context.Context, conversationID string, userIDs []string, now int64) ([]string, error)
+	// FindOwnerConversationIDs the conversations of conversationIDs owned by any of ownerUserIDs
+	FindOwnerConversationIDs(ctx context.Context, ownerUserIDs []string, conversationIDs []string) ([]string, error)
 	NewTx(tx any) ConversationModelInterface
 }
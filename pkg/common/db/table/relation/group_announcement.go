@@ -0,0 +1,73 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	GroupAnnouncementModelTableName    = "group_announcements"
+	GroupAnnouncementAckModelTableName = "group_announcement_acks"
+)
+
+// GroupAnnouncementModel the newest published announcement is also kept in GroupModel.Notification for the old clients.
+type GroupAnnouncementModel struct {
+	AnnouncementID string    `gorm:"column:announcement_id;primary_key;size:64"`
+	GroupID        string    `gorm:"column:group_id;size:64;index:idx_group_pinned_time,priority:1"`
+	Content        string    `gorm:"column:content;type:text"`
+	CreatorUserID  string    `gorm:"column:creator_user_id;size:64"`
+	UpdaterUserID  string    `gorm:"column:updater_user_id;size:64"`
+	Pinned         bool      `gorm:"column:pinned;index:idx_group_pinned_time,priority:2"`
+	CreateTime     time.Time `gorm:"column:create_time;index:idx_group_pinned_time,priority:3"`
+	UpdateTime     time.Time `gorm:"column:update_time"`
+}
+
+func (GroupAnnouncementModel) TableName() string {
+	return GroupAnnouncementModelTableName
+}
+
+// GroupAnnouncementAckModel a member acknowledged reading the announcement.
+type GroupAnnouncementAckModel struct {
+	AnnouncementID string    `gorm:"column:announcement_id;primary_key;size:64"`
+	UserID         string    `gorm:"column:user_id;primary_key;size:64"`
+	GroupID        string    `gorm:"column:group_id;size:64"`
+	AckTime        time.Time `gorm:"column:ack_time"`
+}
+
+func (GroupAnnouncementAckModel) TableName() string {
+	return GroupAnnouncementAckModelTableName
+}
+
+type GroupAnnouncementModelInterface interface {
+	NewTx(tx any) GroupAnnouncementModelInterface
+	Create(ctx context.Context, announcement *GroupAnnouncementModel) (err error)
+	Update(ctx context.Context, announcementID string, data map[string]any) (err error)
+	Delete(ctx context.Context, announcementID string) (err error)
+	Take(ctx context.Context, announcementID string) (announcement *GroupAnnouncementModel, err error)
+	// Page the pinned announcements first, then the newest first
+	Page(ctx context.Context, groupID string, pageNumber, showNumber int32) (total uint32, announcements []*GroupAnnouncementModel, err error)
+}
+
+type GroupAnnouncementAckModelInterface interface {
+	NewTx(tx any) GroupAnnouncementAckModelInterface
+	// Create an ack which exists already is kept
+	Create(ctx context.Context, ack *GroupAnnouncementAckModel) (err error)
+	Delete(ctx context.Context, announcementID string) (err error)
+	FindUserID(ctx context.Context, announcementID string) (userIDs []string, err error)
+	// FindAckedAnnouncementID the announcements of announcementIDs acknowledged by the user
+	FindAckedAnnouncementID(ctx context.Context, userID string, announcementIDs []string) (ackedIDs []string, err error)
+}
@@ -0,0 +1,65 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	GroupAuditLogModelTableName = "group_audit_log"
+)
+
+const (
+	GroupAuditActionKickMember          = "kick_member"
+	GroupAuditActionMuteMember          = "mute_member"
+	GroupAuditActionCancelMuteMember    = "cancel_mute_member"
+	GroupAuditActionMuteGroup           = "mute_group"
+	GroupAuditActionCancelMuteGroup     = "cancel_mute_group"
+	GroupAuditActionSetGroupInfo        = "set_group_info"
+	GroupAuditActionTransferOwner       = "transfer_owner"
+	GroupAuditActionApplicationResponse = "application_response"
+	GroupAuditActionSetMemberInfo       = "set_member_info"
+	GroupAuditActionDismissGroup        = "dismiss_group"
+	GroupAuditActionSetDirectory        = "set_directory"
+	GroupAuditActionImportMembers       = "import_members"
+	GroupAuditActionSetAnnouncement     = "set_announcement"
+	GroupAuditActionSetMemberTag        = "set_member_tag"
+	GroupAuditActionSetJoinRule         = "set_join_rule"
+	GroupAuditActionSetMuteSchedule     = "set_mute_schedule"
+)
+
+// GroupAuditLogModel a mutation made by a group operator, Before and After are json objects of the changed values.
+type GroupAuditLogModel struct {
+	ID             int64     `gorm:"column:id;primary_key;autoIncrement"`
+	GroupID        string    `gorm:"column:group_id;size:64;index:idx_group_time"`
+	Action         string    `gorm:"column:action;size:32"`
+	OperatorUserID string    `gorm:"column:operator_user_id;size:64"`
+	TargetUserID   string    `gorm:"column:target_user_id;size:64"`
+	Before         string    `gorm:"column:before_value;type:text"`
+	After          string    `gorm:"column:after_value;type:text"`
+	CreateTime     time.Time `gorm:"column:create_time;index:idx_group_time"`
+}
+
+func (GroupAuditLogModel) TableName() string {
+	return GroupAuditLogModelTableName
+}
+
+type GroupAuditLogModelInterface interface {
+	Create(ctx context.Context, logs []*GroupAuditLogModel) (err error)
+	// Page an empty action or targetUserID matches all
+	Page(ctx context.Context, groupID string, action string, targetUserID string, pageNumber, showNumber int32) (total uint32, logs []*GroupAuditLogModel, err error)
+}
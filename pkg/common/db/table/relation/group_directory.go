@@ -0,0 +1,74 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+const (
+	GroupDirectoryModelTableName = "group_directory"
+)
+
+const (
+	GroupDirectoryOrderMemberCount = "memberCount"
+	GroupDirectoryOrderActivity    = "activity"
+)
+
+// GroupDirectoryModel a group listed in the public directory, only discoverable groups have a row.
+// Tags are stored as ",tag1,tag2," so a tag can be matched with like.
+type GroupDirectoryModel struct {
+	GroupID     string `gorm:"column:group_id;primary_key;size:64"`
+	Category    string `gorm:"column:category;size:64;index"`
+	Tags        string `gorm:"column:tags;size:512"`
+	Description string `gorm:"column:description;size:1024"`
+	// ActiveTime the send time of the newest group message, refreshed by the cron task
+	ActiveTime time.Time `gorm:"column:active_time;index"`
+	CreateTime time.Time `gorm:"column:create_time"`
+	UpdateTime time.Time `gorm:"column:update_time;autoUpdateTime"`
+}
+
+func (GroupDirectoryModel) TableName() string {
+	return GroupDirectoryModelTableName
+}
+
+func (g *GroupDirectoryModel) SetTags(tags []string) {
+	if len(tags) == 0 {
+		g.Tags = ""
+		return
+	}
+	g.Tags = "," + strings.Join(tags, ",") + ","
+}
+
+func (g *GroupDirectoryModel) GetTags() []string {
+	tags := strings.Trim(g.Tags, ",")
+	if tags == "" {
+		return nil
+	}
+	return strings.Split(tags, ",")
+}
+
+type GroupDirectoryModelInterface interface {
+	// Save insert or overwrite the entry
+	Save(ctx context.Context, entry *GroupDirectoryModel) (err error)
+	Delete(ctx context.Context, groupID string) (err error)
+	Take(ctx context.Context, groupID string) (entry *GroupDirectoryModel, err error)
+	UpdateActiveTime(ctx context.Context, groupID string, activeTime time.Time) (err error)
+	// Search the listed groups which are not dismissed, empty conditions match all, orderBy is a GroupDirectoryOrder value
+	Search(ctx context.Context, keyword string, category string, tag string, orderBy string, pageNumber, showNumber int32) (total uint32, entries []*GroupDirectoryModel, err error)
+	Page(ctx context.Context, pageNumber, showNumber int32) (entries []*GroupDirectoryModel, err error)
+}
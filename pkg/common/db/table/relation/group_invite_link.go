@@ -0,0 +1,59 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	GroupInviteLinkModelTableName = "group_invite_links"
+)
+
+// GroupJoinByInviteLink JoinSource of the members and requests coming from an invite link,
+// it follows constant.JoinByQRCode.
+const GroupJoinByInviteLink = 5
+
+// GroupInviteLinkModel a link is usable until it expires, is revoked or UsedCount reaches MaxUses.
+type GroupInviteLinkModel struct {
+	Code          string `gorm:"column:code;primary_key;size:64"`
+	GroupID       string `gorm:"column:group_id;size:64;index"`
+	CreatorUserID string `gorm:"column:creator_user_id;size:64"`
+	// MaxUses 0 means unlimited
+	MaxUses         int32     `gorm:"column:max_uses"`
+	UsedCount       int32     `gorm:"column:used_count"`
+	RequireApproval bool      `gorm:"column:require_approval"`
+	Revoked         bool      `gorm:"column:revoked"`
+	ExpireTime      time.Time `gorm:"column:expire_time"`
+	CreateTime      time.Time `gorm:"column:create_time"`
+}
+
+func (GroupInviteLinkModel) TableName() string {
+	return GroupInviteLinkModelTableName
+}
+
+type GroupInviteLinkModelInterface interface {
+	NewTx(tx any) GroupInviteLinkModelInterface
+	Create(ctx context.Context, link *GroupInviteLinkModel) (err error)
+	Take(ctx context.Context, code string) (link *GroupInviteLinkModel, err error)
+	Revoke(ctx context.Context, code string) (rows int64, err error)
+	// Use increase UsedCount if the link is still usable at now, rows is 0 otherwise
+	Use(ctx context.Context, code string, now time.Time) (rows int64, err error)
+	// Unuse give back a use taken by Use
+	Unuse(ctx context.Context, code string) (err error)
+	// Page valid only returns the links which can still be used
+	Page(ctx context.Context, groupID string, valid bool, pageNumber, showNumber int32) (total uint32, links []*GroupInviteLinkModel, err error)
+}
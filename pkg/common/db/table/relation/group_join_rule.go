@@ -0,0 +1,76 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+const (
+	GroupJoinRuleModelTableName = "group_join_rules"
+)
+
+// GroupJoinQuestion an applicant answers every question, AnswerPattern is a regular expression, empty accepts any answer.
+type GroupJoinQuestion struct {
+	Question      string `json:"question"`
+	AnswerPattern string `json:"answerPattern"`
+}
+
+// GroupJoinRuleModel the join questions and auto-approval rules of a group, a request matching any rule joins directly.
+// Questions are stored as json.
+type GroupJoinRuleModel struct {
+	GroupID   string `gorm:"column:group_id;primary_key;size:64"`
+	Questions string `gorm:"column:questions;type:text"`
+	// ApproveByAnswers approve when every answer matches its pattern
+	ApproveByAnswers bool `gorm:"column:approve_by_answers"`
+	// ExPattern approve when the Ex of the applicant matches, empty disables the rule
+	ExPattern string `gorm:"column:ex_pattern;size:512"`
+	// ApproveAdminInvited approve the applicants coming from an invite link created by a member allowed to approve joins
+	ApproveAdminInvited bool `gorm:"column:approve_admin_invited"`
+	// MinFriendMembers approve when the applicant is friends with this many members, 0 disables the rule
+	MinFriendMembers int32     `gorm:"column:min_friend_members"`
+	UpdateUserID     string    `gorm:"column:update_user_id;size:64"`
+	UpdateTime       time.Time `gorm:"column:update_time"`
+}
+
+func (GroupJoinRuleModel) TableName() string {
+	return GroupJoinRuleModelTableName
+}
+
+func (g *GroupJoinRuleModel) SetQuestions(questions []*GroupJoinQuestion) {
+	if len(questions) == 0 {
+		g.Questions = ""
+		return
+	}
+	data, _ := json.Marshal(questions)
+	g.Questions = string(data)
+}
+
+func (g *GroupJoinRuleModel) GetQuestions() []*GroupJoinQuestion {
+	var questions []*GroupJoinQuestion
+	if g.Questions != "" {
+		_ = json.Unmarshal([]byte(g.Questions), &questions)
+	}
+	return questions
+}
+
+type GroupJoinRuleModelInterface interface {
+	// Save insert or overwrite the rules
+	Save(ctx context.Context, rule *GroupJoinRuleModel) (err error)
+	Delete(ctx context.Context, groupID string) (err error)
+	Take(ctx context.Context, groupID string) (rule *GroupJoinRuleModel, err error)
+}
@@ -0,0 +1,45 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	GroupMemberTagModelTableName = "group_member_tags"
+)
+
+// GroupMemberTagModel a member carries the tag in the group, a message mentioning the tag mentions all its members.
+type GroupMemberTagModel struct {
+	GroupID    string    `gorm:"column:group_id;primary_key;size:64"`
+	Tag        string    `gorm:"column:tag;primary_key;size:64"`
+	UserID     string    `gorm:"column:user_id;primary_key;size:64"`
+	CreateTime time.Time `gorm:"column:create_time"`
+}
+
+func (GroupMemberTagModel) TableName() string {
+	return GroupMemberTagModelTableName
+}
+
+type GroupMemberTagModelInterface interface {
+	NewTx(tx any) GroupMemberTagModelInterface
+	Create(ctx context.Context, tags []*GroupMemberTagModel) (err error)
+	// Delete removes the tag from all members
+	Delete(ctx context.Context, groupID string, tag string) (err error)
+	// Find all tags of the group if tags is empty
+	Find(ctx context.Context, groupID string, tags []string) (memberTags []*GroupMemberTagModel, err error)
+}
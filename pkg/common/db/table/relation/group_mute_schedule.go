@@ -0,0 +1,88 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	GroupMuteScheduleModelTableName = "group_mute_schedules"
+)
+
+const (
+	// GroupMuteScheduleDaily mutes the group every day from StartMinute to EndMinute.
+	GroupMuteScheduleDaily = "daily"
+	// GroupMuteScheduleOnce mutes the group once from StartTime to EndTime.
+	GroupMuteScheduleOnce = "once"
+)
+
+// GroupMuteScheduleModel a window in which the group is muted, Active is the state last announced to the group.
+type GroupMuteScheduleModel struct {
+	ID            string    `gorm:"column:id;primary_key;size:64"`
+	GroupID       string    `gorm:"column:group_id;index:group_id;size:64"`
+	Type          string    `gorm:"column:type;size:16"`
+	StartMinute   int32     `gorm:"column:start_minute"`
+	EndMinute     int32     `gorm:"column:end_minute"`
+	TimeZone      string    `gorm:"column:time_zone;size:64"`
+	StartTime     time.Time `gorm:"column:start_time"`
+	EndTime       time.Time `gorm:"column:end_time"`
+	Active        bool      `gorm:"column:active"`
+	CreatorUserID string    `gorm:"column:creator_user_id;size:64"`
+	CreateTime    time.Time `gorm:"column:create_time"`
+}
+
+func (GroupMuteScheduleModel) TableName() string {
+	return GroupMuteScheduleModelTableName
+}
+
+// Covers whether now is inside the window, a daily window ending before it starts spans midnight.
+func (g *GroupMuteScheduleModel) Covers(now time.Time) bool {
+	switch g.Type {
+	case GroupMuteScheduleDaily:
+		loc := time.Local
+		if g.TimeZone != "" {
+			if l, err := time.LoadLocation(g.TimeZone); err == nil {
+				loc = l
+			}
+		}
+		t := now.In(loc)
+		minute := int32(t.Hour()*60 + t.Minute())
+		if g.StartMinute <= g.EndMinute {
+			return minute >= g.StartMinute && minute < g.EndMinute
+		}
+		return minute >= g.StartMinute || minute < g.EndMinute
+	case GroupMuteScheduleOnce:
+		return !now.Before(g.StartTime) && now.Before(g.EndTime)
+	default:
+		return false
+	}
+}
+
+// Expired a one-off window that has ended never applies again.
+func (g *GroupMuteScheduleModel) Expired(now time.Time) bool {
+	return g.Type == GroupMuteScheduleOnce && !now.Before(g.EndTime)
+}
+
+type GroupMuteScheduleModelInterface interface {
+	Create(ctx context.Context, schedules []*GroupMuteScheduleModel) (err error)
+	Take(ctx context.Context, id string) (schedule *GroupMuteScheduleModel, err error)
+	Delete(ctx context.Context, ids []string) (err error)
+	UpdateActive(ctx context.Context, id string, active bool) (err error)
+	FindByGroupID(ctx context.Context, groupID string) (schedules []*GroupMuteScheduleModel, err error)
+	// Page all schedules of all groups
+	Page(ctx context.Context, pageNumber int32, showNumber int32) (total uint32, schedules []*GroupMuteScheduleModel, err error)
+}
@@ -0,0 +1,58 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGroupMuteScheduleCovers(t *testing.T) {
+	// 22:00-07:00 in Shanghai (UTC+8) is 14:00-23:00 UTC
+	night := &GroupMuteScheduleModel{Type: GroupMuteScheduleDaily, StartMinute: 22 * 60, EndMinute: 7 * 60, TimeZone: "Asia/Shanghai"}
+	lunch := &GroupMuteScheduleModel{Type: GroupMuteScheduleDaily, StartMinute: 12 * 60, EndMinute: 13 * 60, TimeZone: "UTC"}
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	once := &GroupMuteScheduleModel{Type: GroupMuteScheduleOnce, StartTime: start, EndTime: start.Add(time.Hour)}
+	utc := func(hour, minute int) time.Time {
+		return time.Date(2024, 5, 1, hour, minute, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name     string
+		schedule *GroupMuteScheduleModel
+		now      time.Time
+		want     bool
+	}{
+		{name: "across midnight before start", schedule: night, now: utc(13, 59), want: false},
+		{name: "across midnight at start", schedule: night, now: utc(14, 0), want: true},
+		{name: "across midnight after local midnight", schedule: night, now: utc(18, 30), want: true},
+		{name: "across midnight before end", schedule: night, now: utc(22, 59), want: true},
+		{name: "across midnight at end", schedule: night, now: utc(23, 0), want: false},
+		{name: "across midnight daytime", schedule: night, now: utc(4, 0), want: false},
+		{name: "same day inside", schedule: lunch, now: utc(12, 30), want: true},
+		{name: "same day at end", schedule: lunch, now: utc(13, 0), want: false},
+		{name: "same day other timezone", schedule: lunch, now: time.Date(2024, 5, 1, 20, 30, 0, 0, time.FixedZone("UTC+8", 8*3600)), want: true},
+		{name: "once at start", schedule: once, now: start, want: true},
+		{name: "once at end", schedule: once, now: start.Add(time.Hour), want: false},
+		{name: "once before", schedule: once, now: start.Add(-time.Minute), want: false},
+		{name: "unknown type", schedule: &GroupMuteScheduleModel{Type: "weekly"}, now: start, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.schedule.Covers(tt.now); got != tt.want {
+				t.Errorf("Covers(%s) = %v, want %v", tt.now, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	GroupRoleModelTableName = "group_roles"
+)
+
+// GroupRoleModel a custom role of a group, members get it by setting their role level to RoleLevel.
+// Permissions is a bitmap of the authverify.GroupPermission values.
+type GroupRoleModel struct {
+	GroupID     string    `gorm:"column:group_id;primary_key;size:64"`
+	RoleLevel   int32     `gorm:"column:role_level;primary_key"`
+	Name        string    `gorm:"column:name;size:64"`
+	Permissions int64     `gorm:"column:permissions"`
+	CreateTime  time.Time `gorm:"column:create_time"`
+	UpdateTime  time.Time `gorm:"column:update_time;autoUpdateTime"`
+}
+
+func (GroupRoleModel) TableName() string {
+	return GroupRoleModelTableName
+}
+
+type GroupRoleModelInterface interface {
+	// Save insert or overwrite the role
+	Save(ctx context.Context, role *GroupRoleModel) (err error)
+	Delete(ctx context.Context, groupID string, roleLevel int32) (rows int64, err error)
+	Take(ctx context.Context, groupID string, roleLevel int32) (role *GroupRoleModel, err error)
+	FindByGroupID(ctx context.Context, groupID string) (roles []*GroupRoleModel, err error)
+}
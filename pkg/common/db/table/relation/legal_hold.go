@@ -0,0 +1,81 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	LegalHoldModelTableName    = "legal_holds"
+	LegalHoldLogModelTableName = "legal_hold_logs"
+)
+
+const (
+	LegalHoldTargetUser         = 1
+	LegalHoldTargetConversation = 2
+)
+
+const (
+	LegalHoldActionSet     = "set"
+	LegalHoldActionRelease = "release"
+)
+
+// LegalHoldModel an active hold, a user hold covers every conversation the user has.
+type LegalHoldModel struct {
+	TargetType     int32     `gorm:"column:target_type;primary_key"`
+	TargetID       string    `gorm:"column:target_id;primary_key;size:128"`
+	Reason         string    `gorm:"column:reason;size:1024"`
+	OperatorUserID string    `gorm:"column:operator_user_id;size:64"`
+	CreateTime     time.Time `gorm:"column:create_time"`
+}
+
+func (LegalHoldModel) TableName() string {
+	return LegalHoldModelTableName
+}
+
+// LegalHoldLogModel audit record of a hold change.
+type LegalHoldLogModel struct {
+	ID             int64     `gorm:"column:id;primary_key;autoIncrement"`
+	TargetType     int32     `gorm:"column:target_type;index:idx_target"`
+	TargetID       string    `gorm:"column:target_id;size:128;index:idx_target"`
+	Action         string    `gorm:"column:action;size:16"`
+	Reason         string    `gorm:"column:reason;size:1024"`
+	OperatorUserID string    `gorm:"column:operator_user_id;size:64"`
+	CreateTime     time.Time `gorm:"column:create_time;index"`
+}
+
+func (LegalHoldLogModel) TableName() string {
+	return LegalHoldLogModelTableName
+}
+
+type LegalHoldModelInterface interface {
+	NewTx(tx any) LegalHoldModelInterface
+	Create(ctx context.Context, hold *LegalHoldModel) (err error)
+	Delete(ctx context.Context, targetType int32, targetID string) (rows int64, err error)
+	Take(ctx context.Context, targetType int32, targetID string) (hold *LegalHoldModel, err error)
+	// FindTargetIDs the held targets of targetIDs, nil targetIDs finds all the held targets of the type
+	FindTargetIDs(ctx context.Context, targetType int32, targetIDs []string) (heldTargetIDs []string, err error)
+	// Page targetType 0 means all types
+	Page(ctx context.Context, targetType int32, pageNumber, showNumber int32) (total uint32, holds []*LegalHoldModel, err error)
+}
+
+type LegalHoldLogModelInterface interface {
+	NewTx(tx any) LegalHoldLogModelInterface
+	Create(ctx context.Context, logs []*LegalHoldLogModel) (err error)
+	// Page the newest first, an empty targetID means all targets
+	Page(ctx context.Context, targetType int32, targetID string, pageNumber, showNumber int32) (total uint32, logs []*LegalHoldLogModel, err error)
+}
@@ -0,0 +1,56 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	RetentionPolicyModelTableName = "retention_policies"
+)
+
+const (
+	// RetentionScopeConversation ScopeID is a conversation id
+	RetentionScopeConversation = 1
+	// RetentionScopeGroup ScopeID is a group id, covers the group conversation
+	RetentionScopeGroup = 2
+	// RetentionScopeConversationType ScopeID is a session type, e.g. "4" for notification conversations
+	RetentionScopeConversationType = 3
+)
+
+// RetentionPolicyModel messages older than RetainDays are deleted by the cron, 0 keeps them forever.
+type RetentionPolicyModel struct {
+	ScopeType      int32     `gorm:"column:scope_type;primary_key"`
+	ScopeID        string    `gorm:"column:scope_id;primary_key;size:128"`
+	RetainDays     int32     `gorm:"column:retain_days"`
+	OperatorUserID string    `gorm:"column:operator_user_id;size:64"`
+	CreateTime     time.Time `gorm:"column:create_time"`
+	UpdateTime     time.Time `gorm:"column:update_time;autoUpdateTime"`
+}
+
+func (RetentionPolicyModel) TableName() string {
+	return RetentionPolicyModelTableName
+}
+
+type RetentionPolicyModelInterface interface {
+	// Save insert or overwrite the policy of the scope
+	Save(ctx context.Context, policy *RetentionPolicyModel) (err error)
+	Delete(ctx context.Context, scopeType int32, scopeID string) (rows int64, err error)
+	FindAll(ctx context.Context) (policies []*RetentionPolicyModel, err error)
+	// Page scopeType 0 means all types
+	Page(ctx context.Context, scopeType int32, pageNumber, showNumber int32) (total uint32, policies []*RetentionPolicyModel, err error)
+}
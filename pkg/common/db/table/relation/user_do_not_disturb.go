@@ -0,0 +1,45 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	UserDoNotDisturbModelTableName = "user_do_not_disturb"
+)
+
+// UserDoNotDisturbModel daily quiet hours of a user, StartTime and EndTime are "HH:MM" in TimeZone.
+type UserDoNotDisturbModel struct {
+	UserID     string    `gorm:"column:user_id;primary_key;size:64"`
+	Enable     bool      `gorm:"column:enable"`
+	StartTime  string    `gorm:"column:start_time;size:5"`
+	EndTime    string    `gorm:"column:end_time;size:5"`
+	TimeZone   string    `gorm:"column:time_zone;size:64"`
+	UpdateTime time.Time `gorm:"column:update_time;autoUpdateTime"`
+}
+
+func (UserDoNotDisturbModel) TableName() string {
+	return UserDoNotDisturbModelTableName
+}
+
+type UserDoNotDisturbModelInterface interface {
+	// Save insert or overwrite the settings of the user
+	Save(ctx context.Context, dnd *UserDoNotDisturbModel) (err error)
+	// Find not found users are ignored
+	Find(ctx context.Context, userIDs []string) (dnds []*UserDoNotDisturbModel, err error)
+}
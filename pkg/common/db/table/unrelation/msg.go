@@ -101,6 +101,8 @@ type MsgDocModelInterface interface {
 	Create(ctx context.Context, model *MsgDocModel) error
 	UpdateMsg(ctx context.Context, docID string, index int64, key string, value any) (*mongo.UpdateResult, error)
 	PushUnique(ctx context.Context, docID string, index int64, key string, value any) (*mongo.UpdateResult, error)
+	// PushUniqueByIndexes same as PushUnique on several msgs of the doc in one update
+	PushUniqueByIndexes(ctx context.Context, docID string, indexes []int64, key string, value any) (*mongo.UpdateResult, error)
 	UpdateMsgContent(ctx context.Context, docID string, index int64, msg []byte) error
 	IsExistDocID(ctx context.Context, docID string) (bool, error)
 	FindOneByDocID(ctx context.Context, docID string) (*MsgDocModel, error)
@@ -130,6 +132,8 @@ type MsgDocModelInterface interface {
 		showNumber int32,
 	) (msgCount int64, userCount int64, groups []*GroupCount, dateCount map[string]int64, err error)
 	ConvertMsgsDocLen(ctx context.Context, conversationIDs []string)
+	// WalkMsgs calls fn with the stored msgs of every conversation, at most one doc at a time, until fn returns an error.
+	WalkMsgs(ctx context.Context, fn func(conversationID string, msgs []*MsgInfoModel) error) error
 }
 
 func (MsgDocModel) TableName() string {
@@ -0,0 +1,48 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unrelation
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	MsgArchive = "msg_archive"
+)
+
+// MsgArchiveModel the messages of the conversation up to MaxSeq have been moved from the msg docs to the object storage,
+// the archived docs below MinSeq have been purged again.
+type MsgArchiveModel struct {
+	ConversationID string    `bson:"conversation_id"`
+	MinSeq         int64     `bson:"min_seq"`
+	MaxSeq         int64     `bson:"max_seq"`
+	UpdateTime     time.Time `bson:"update_time"`
+}
+
+func (MsgArchiveModel) TableName() string {
+	return MsgArchive
+}
+
+type MsgArchiveModelInterface interface {
+	// SetMaxSeq raises the archived max seq of the conversation, a lower maxSeq is ignored
+	SetMaxSeq(ctx context.Context, conversationID string, maxSeq int64) error
+	// GetMaxSeq 0 if nothing of the conversation is archived
+	GetMaxSeq(ctx context.Context, conversationID string) (int64, error)
+	// SetMinSeq raises the purged min seq of the conversation, a lower minSeq is ignored
+	SetMinSeq(ctx context.Context, conversationID string, minSeq int64) error
+	// GetMinSeq 0 if nothing of the conversation was purged
+	GetMinSeq(ctx context.Context, conversationID string) (int64, error)
+}
@@ -0,0 +1,34 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unrelation
+
+const (
+	MsgSingle = "msg_single"
+
+	// MsgLayoutArray keeps singleGocMsgNum msgs in the msgs array of one MsgDocModel.
+	MsgLayoutArray = "array"
+	// MsgLayoutSingle keeps one MsgSingleModel per msg, the doc id and index are mapped to the seq.
+	MsgLayoutSingle = "single"
+)
+
+type MsgSingleModel struct {
+	ConversationID string `bson:"conversation_id"`
+	Seq            int64  `bson:"seq"`
+	MsgInfoModel   `bson:",inline"`
+}
+
+func (MsgSingleModel) TableName() string {
+	return MsgSingle
+}
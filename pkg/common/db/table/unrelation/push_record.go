@@ -0,0 +1,45 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unrelation
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	PushRecord = "push_record"
+)
+
+// PushRecordModel one offline push attempt of a message to a user.
+type PushRecordModel struct {
+	Vendor      string    `bson:"vendor"        json:"vendor"`
+	UserID      string    `bson:"user_id"       json:"userID"`
+	ClientMsgID string    `bson:"client_msg_id" json:"clientMsgID"`
+	Code        int       `bson:"code"          json:"code"`
+	ErrMsg      string    `bson:"err_msg"       json:"errMsg"`
+	Latency     int64     `bson:"latency"       json:"latency"`
+	CreateTime  time.Time `bson:"create_time"   json:"createTime"`
+}
+
+func (PushRecordModel) TableName() string {
+	return PushRecord
+}
+
+type PushRecordModelInterface interface {
+	Create(ctx context.Context, records []*PushRecordModel) error
+	// Search filters by the non-empty userID and clientMsgID, newest first
+	Search(ctx context.Context, userID, clientMsgID string, start, end time.Time, pageNumber, showNumber int32) (int64, []*PushRecordModel, error)
+}
@@ -96,7 +96,24 @@ func (m *Mongo) GetDatabase() *mongo.Database {
 }
 
 func (m *Mongo) CreateMsgIndex() error {
-	return m.createMongoIndex(unrelation.Msg, true, "doc_id")
+	if err := m.createMongoIndex(unrelation.Msg, true, "doc_id"); err != nil {
+		return err
+	}
+	if config.Config.Mongo.MsgLayout != unrelation.MsgLayoutSingle {
+		return nil
+	}
+	return m.CreateMsgSingleIndex()
+}
+
+func (m *Mongo) CreateMsgSingleIndex() error {
+	if err := m.createMongoIndex(unrelation.MsgSingle, true, "conversation_id", "seq"); err != nil {
+		return err
+	}
+	return m.createMongoIndex(unrelation.MsgSingle, false, "-msg.send_time")
+}
+
+func (m *Mongo) CreateMsgArchiveIndex() error {
+	return m.createMongoIndex(unrelation.MsgArchive, true, "conversation_id")
 }
 
 func (m *Mongo) CreateSuperGroupIndex() error {
@@ -109,6 +126,40 @@ func (m *Mongo) CreateSuperGroupIndex() error {
 	return nil
 }
 
+// pushRecordTTLIndex the name of the TTL index expiring the push records.
+const pushRecordTTLIndex = "create_time_ttl"
+
+// CreatePushRecordIndexes creates the indexes of the push records, the TTL index removes the records
+// older than retention days, 0 keeps them. A changed retention is applied to the existing TTL index.
+func (m *Mongo) CreatePushRecordIndexes(retention int) error {
+	if err := m.createMongoIndex(unrelation.PushRecord, false, "user_id", "-create_time"); err != nil {
+		return err
+	}
+	if err := m.createMongoIndex(unrelation.PushRecord, false, "client_msg_id"); err != nil {
+		return err
+	}
+	if retention <= 0 {
+		return nil
+	}
+	expire := int32(retention * 24 * 3600)
+	index := mongo.IndexModel{
+		Keys:    bson.D{{Key: "create_time", Value: 1}},
+		Options: options.Index().SetName(pushRecordTTLIndex).SetExpireAfterSeconds(expire),
+	}
+	ctx := context.Background()
+	_, err := m.GetDatabase().Collection(unrelation.PushRecord).Indexes().CreateOne(ctx, index, options.CreateIndexes().SetMaxTime(10*time.Second))
+	if cmdErr, ok := err.(mongo.CommandError); ok && cmdErr.Name == "IndexOptionsConflict" {
+		err = m.GetDatabase().RunCommand(ctx, bson.D{
+			{Key: "collMod", Value: unrelation.PushRecord},
+			{Key: "index", Value: bson.D{{Key: "name", Value: pushRecordTTLIndex}, {Key: "expireAfterSeconds", Value: expire}}},
+		}).Err()
+	}
+	if err != nil {
+		return utils.Wrap(err, "")
+	}
+	return nil
+}
+
 func (m *Mongo) createMongoIndex(collection string, isUnique bool, keys ...string) error {
 	db := m.db.Database(config.Config.Mongo.Database).Collection(collection)
 	opts := options.CreateIndexes().SetMaxTime(10 * time.Second)
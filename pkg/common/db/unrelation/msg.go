@@ -19,6 +19,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/OpenIMSDK/tools/log"
@@ -37,6 +38,7 @@ import (
 	"github.com/OpenIMSDK/tools/errs"
 	"github.com/OpenIMSDK/tools/utils"
 
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
 	table "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/unrelation"
 )
 
@@ -52,6 +54,14 @@ func NewMsgMongoDriver(database *mongo.Database) table.MsgDocModelInterface {
 	return &MsgMongoDriver{MsgCollection: collection}
 }
 
+// NewMsgDocModel returns the driver of the msg layout set in the mongo config.
+func NewMsgDocModel(database *mongo.Database) table.MsgDocModelInterface {
+	if config.Config.Mongo.MsgLayout == table.MsgLayoutSingle {
+		return NewMsgSingleMongoDriver(database)
+	}
+	return NewMsgMongoDriver(database)
+}
+
 func (m *MsgMongoDriver) PushMsgsToDoc(ctx context.Context, docID string, msgsToMongo []table.MsgInfoModel) error {
 	return m.MsgCollection.FindOneAndUpdate(ctx, bson.M{"doc_id": docID}, bson.M{"$push": bson.M{"msgs": bson.M{"$each": msgsToMongo}}}).
 		Err()
@@ -111,6 +121,26 @@ func (m *MsgMongoDriver) PushUnique(
 	return res, nil
 }
 
+func (m *MsgMongoDriver) PushUniqueByIndexes(
+	ctx context.Context,
+	docID string,
+	indexes []int64,
+	key string,
+	value any,
+) (*mongo.UpdateResult, error) {
+	set := make(bson.M, len(indexes))
+	for _, index := range indexes {
+		set[fmt.Sprintf("msgs.%d.%s", index, key)] = bson.M{"$each": value}
+	}
+	filter := bson.M{"doc_id": docID}
+	update := bson.M{"$addToSet": set}
+	res, err := m.MsgCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return nil, utils.Wrap(err, "")
+	}
+	return res, nil
+}
+
 func (m *MsgMongoDriver) UpdateMsgContent(ctx context.Context, docID string, index int64, msg []byte) error {
 	_, err := m.MsgCollection.UpdateOne(
 		ctx,
@@ -306,39 +336,69 @@ func (m *MsgMongoDriver) GetMsgBySeqIndexIn1Doc(
 		if msg == nil || msg.Msg == nil {
 			continue
 		}
-		if msg.Revoke != nil {
-			revokeContent := sdkws.MessageRevokedContent{
-				RevokerID:                   msg.Revoke.UserID,
-				RevokerRole:                 msg.Revoke.Role,
-				ClientMsgID:                 msg.Msg.ClientMsgID,
-				RevokerNickname:             msg.Revoke.Nickname,
-				RevokeTime:                  msg.Revoke.Time,
-				SourceMessageSendTime:       msg.Msg.SendTime,
-				SourceMessageSendID:         msg.Msg.SendID,
-				SourceMessageSenderNickname: msg.Msg.SenderNickname,
-				SessionType:                 msg.Msg.SessionType,
-				Seq:                         msg.Msg.Seq,
-				Ex:                          msg.Msg.Ex,
-			}
-			data, err := json.Marshal(&revokeContent)
-			if err != nil {
-				return nil, err
-			}
-			elem := sdkws.NotificationElem{
-				Detail: string(data),
-			}
-			content, err := json.Marshal(&elem)
-			if err != nil {
-				return nil, err
-			}
-			msg.Msg.ContentType = constant.MsgRevokeNotification
-			msg.Msg.Content = string(content)
+		if err := convertRevokedMsg(msg); err != nil {
+			return nil, err
 		}
 		msgs = append(msgs, msg)
 	}
 	return msgs, nil
 }
 
+// GetMsgBySeqIndexInDoc does what MsgMongoDriver.GetMsgBySeqIndexIn1Doc does on a doc that is not in mongo.
+func GetMsgBySeqIndexInDoc(doc *table.MsgDocModel, userID string, seqs []int64) ([]*table.MsgInfoModel, error) {
+	msgs := make([]*table.MsgInfoModel, 0, len(seqs))
+	for _, seq := range seqs {
+		index := doc.GetMsgIndex(seq)
+		if index < 0 || index >= int64(len(doc.Msg)) {
+			continue
+		}
+		msg := doc.Msg[index]
+		if msg == nil || msg.Msg == nil || utils.Contain(userID, msg.DelList...) {
+			continue
+		}
+		msg.DelList = nil
+		if err := convertRevokedMsg(msg); err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
+
+// convertRevokedMsg replaces the content of a revoked message with the revoke notification.
+func convertRevokedMsg(msg *table.MsgInfoModel) error {
+	if msg.Revoke == nil {
+		return nil
+	}
+	revokeContent := sdkws.MessageRevokedContent{
+		RevokerID:                   msg.Revoke.UserID,
+		RevokerRole:                 msg.Revoke.Role,
+		ClientMsgID:                 msg.Msg.ClientMsgID,
+		RevokerNickname:             msg.Revoke.Nickname,
+		RevokeTime:                  msg.Revoke.Time,
+		SourceMessageSendTime:       msg.Msg.SendTime,
+		SourceMessageSendID:         msg.Msg.SendID,
+		SourceMessageSenderNickname: msg.Msg.SenderNickname,
+		SessionType:                 msg.Msg.SessionType,
+		Seq:                         msg.Msg.Seq,
+		Ex:                          msg.Msg.Ex,
+	}
+	data, err := json.Marshal(&revokeContent)
+	if err != nil {
+		return err
+	}
+	elem := sdkws.NotificationElem{
+		Detail: string(data),
+	}
+	content, err := json.Marshal(&elem)
+	if err != nil {
+		return err
+	}
+	msg.Msg.ContentType = constant.MsgRevokeNotification
+	msg.Msg.Content = string(content)
+	return nil
+}
+
 func (m *MsgMongoDriver) IsExistDocID(ctx context.Context, docID string) (bool, error) {
 	count, err := m.MsgCollection.CountDocuments(ctx, bson.M{"doc_id": docID})
 	if err != nil {
@@ -1216,3 +1276,26 @@ func (m *MsgMongoDriver) searchMessage(ctx context.Context, req *msg.SearchMessa
 	}
 	return n, msgs, nil
 }
+
+func (m *MsgMongoDriver) WalkMsgs(ctx context.Context, fn func(conversationID string, msgs []*table.MsgInfoModel) error) error {
+	cursor, err := m.MsgCollection.Find(ctx, bson.M{}, options.Find().SetBatchSize(100))
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	defer cursor.Close(ctx)
+	for cursor.Next(ctx) {
+		var doc table.MsgDocModel
+		if err := cursor.Decode(&doc); err != nil {
+			return errs.Wrap(err)
+		}
+		i := strings.LastIndex(doc.DocID, ":")
+		if i < 0 {
+			log.ZWarn(ctx, "skip msg doc", nil, "docID", doc.DocID)
+			continue
+		}
+		if err := fn(doc.DocID[:i], doc.Msg); err != nil {
+			return err
+		}
+	}
+	return errs.Wrap(cursor.Err())
+}
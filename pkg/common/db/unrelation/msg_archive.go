@@ -0,0 +1,85 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unrelation
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/OpenIMSDK/tools/errs"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/unrelation"
+)
+
+func NewMsgArchiveMongoDriver(database *mongo.Database) unrelation.MsgArchiveModelInterface {
+	return &MsgArchiveMongoDriver{
+		msgArchiveCollection: database.Collection(unrelation.MsgArchive),
+	}
+}
+
+type MsgArchiveMongoDriver struct {
+	msgArchiveCollection *mongo.Collection
+}
+
+func (m *MsgArchiveMongoDriver) SetMaxSeq(ctx context.Context, conversationID string, maxSeq int64) error {
+	_, err := m.msgArchiveCollection.UpdateOne(ctx,
+		bson.M{"conversation_id": conversationID},
+		bson.M{"$max": bson.M{"max_seq": maxSeq}, "$set": bson.M{"update_time": time.Now()}},
+		options.Update().SetUpsert(true),
+	)
+	return errs.Wrap(err)
+}
+
+func (m *MsgArchiveMongoDriver) GetMaxSeq(ctx context.Context, conversationID string) (int64, error) {
+	archive, err := m.take(ctx, conversationID)
+	if err != nil {
+		return 0, err
+	}
+	return archive.MaxSeq, nil
+}
+
+func (m *MsgArchiveMongoDriver) SetMinSeq(ctx context.Context, conversationID string, minSeq int64) error {
+	_, err := m.msgArchiveCollection.UpdateOne(ctx,
+		bson.M{"conversation_id": conversationID},
+		bson.M{"$max": bson.M{"min_seq": minSeq}, "$set": bson.M{"update_time": time.Now()}},
+		options.Update().SetUpsert(true),
+	)
+	return errs.Wrap(err)
+}
+
+func (m *MsgArchiveMongoDriver) GetMinSeq(ctx context.Context, conversationID string) (int64, error) {
+	archive, err := m.take(ctx, conversationID)
+	if err != nil {
+		return 0, err
+	}
+	return archive.MinSeq, nil
+}
+
+// take an empty model if nothing of the conversation is archived.
+func (m *MsgArchiveMongoDriver) take(ctx context.Context, conversationID string) (*unrelation.MsgArchiveModel, error) {
+	var archive unrelation.MsgArchiveModel
+	err := m.msgArchiveCollection.FindOne(ctx, bson.M{"conversation_id": conversationID}).Decode(&archive)
+	if err == mongo.ErrNoDocuments {
+		return &archive, nil
+	}
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &archive, nil
+}
@@ -0,0 +1,684 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unrelation
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/msg"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	table "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/unrelation"
+)
+
+// MsgSingleMongoDriver stores one doc per msg in the msg_single collection.
+// The doc id and index used by MsgDocModelInterface are mapped to the (conversation_id, seq) of the msg,
+// so a doc of the array layout is the seq range of its singleGocMsgNum msgs.
+type MsgSingleMongoDriver struct {
+	MsgCollection *mongo.Collection
+	model         table.MsgDocModel
+}
+
+func NewMsgSingleMongoDriver(database *mongo.Database) table.MsgDocModelInterface {
+	collection := database.Collection(table.MsgSingleModel{}.TableName())
+	return &MsgSingleMongoDriver{MsgCollection: collection}
+}
+
+// parseDocID returns the conversationID and the first seq of the doc.
+func (m *MsgSingleMongoDriver) parseDocID(docID string) (string, int64, error) {
+	i := strings.LastIndex(docID, ":")
+	if i < 0 {
+		return "", 0, errs.ErrArgs.Wrap("invalid doc id " + docID)
+	}
+	docIndex, err := strconv.ParseInt(docID[i+1:], 10, 64)
+	if err != nil {
+		return "", 0, errs.ErrArgs.Wrap("invalid doc id " + docID)
+	}
+	return docID[:i], docIndex*m.model.GetSingleGocMsgNum() + 1, nil
+}
+
+func (m *MsgSingleMongoDriver) docFilter(docID string) (bson.M, error) {
+	conversationID, begin, err := m.parseDocID(docID)
+	if err != nil {
+		return nil, err
+	}
+	return bson.M{
+		"conversation_id": conversationID,
+		"seq":             bson.M{"$gte": begin, "$lt": begin + m.model.GetSingleGocMsgNum()},
+	}, nil
+}
+
+func (m *MsgSingleMongoDriver) indexesFilter(docID string, indexes []int64) (bson.M, error) {
+	conversationID, begin, err := m.parseDocID(docID)
+	if err != nil {
+		return nil, err
+	}
+	seqs := make([]int64, 0, len(indexes))
+	for _, index := range indexes {
+		seqs = append(seqs, begin+index)
+	}
+	return bson.M{"conversation_id": conversationID, "seq": bson.M{"$in": seqs}}, nil
+}
+
+// toDoc puts the msgs of docID back into the singleGocMsgNum slots of a MsgDocModel.
+func (m *MsgSingleMongoDriver) toDoc(docID string, begin int64, msgs []*table.MsgSingleModel) *table.MsgDocModel {
+	doc := &table.MsgDocModel{
+		DocID: docID,
+		Msg:   make([]*table.MsgInfoModel, m.model.GetSingleGocMsgNum()),
+	}
+	for _, msg := range msgs {
+		index := msg.Seq - begin
+		if index < 0 || index >= int64(len(doc.Msg)) {
+			continue
+		}
+		doc.Msg[index] = &msg.MsgInfoModel
+	}
+	for i, info := range doc.Msg {
+		if info == nil {
+			doc.Msg[i] = &table.MsgInfoModel{DelList: []string{}}
+		}
+	}
+	return doc
+}
+
+func (m *MsgSingleMongoDriver) PushMsgsToDoc(ctx context.Context, docID string, msgsToMongo []table.MsgInfoModel) error {
+	filter, err := m.docFilter(docID)
+	if err != nil {
+		return err
+	}
+	var last table.MsgSingleModel
+	opts := options.FindOne().SetSort(bson.M{"seq": -1}).SetProjection(bson.M{"conversation_id": 1, "seq": 1})
+	if err := m.MsgCollection.FindOne(ctx, filter, opts).Decode(&last); err != nil {
+		return err
+	}
+	msgs := make([]any, 0, len(msgsToMongo))
+	for i := range msgsToMongo {
+		msgs = append(msgs, &table.MsgSingleModel{
+			ConversationID: last.ConversationID,
+			Seq:            last.Seq + int64(i) + 1,
+			MsgInfoModel:   msgsToMongo[i],
+		})
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	_, err = m.MsgCollection.InsertMany(ctx, msgs)
+	return err
+}
+
+// Create inserts the used slots of the doc, the empty ones are not stored.
+// The insert is ordered, so a duplicate key error leaves the msgs before it inserted and the caller retries them as updates.
+func (m *MsgSingleMongoDriver) Create(ctx context.Context, model *table.MsgDocModel) error {
+	conversationID, begin, err := m.parseDocID(model.DocID)
+	if err != nil {
+		return err
+	}
+	msgs := make([]any, 0, len(model.Msg))
+	for i, info := range model.Msg {
+		if info == nil || (info.Msg == nil && info.Revoke == nil) {
+			continue
+		}
+		single := &table.MsgSingleModel{
+			ConversationID: conversationID,
+			Seq:            begin + int64(i),
+			MsgInfoModel:   *info,
+		}
+		if single.DelList == nil {
+			single.DelList = []string{}
+		}
+		msgs = append(msgs, single)
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	_, err = m.MsgCollection.InsertMany(ctx, msgs)
+	return err
+}
+
+func (m *MsgSingleMongoDriver) UpdateMsg(
+	ctx context.Context,
+	docID string,
+	index int64,
+	key string,
+	value any,
+) (*mongo.UpdateResult, error) {
+	filter, err := m.indexesFilter(docID, []int64{index})
+	if err != nil {
+		return nil, err
+	}
+	update := bson.M{"$set": value}
+	if key != "" {
+		update = bson.M{"$set": bson.M{key: value}}
+	}
+	res, err := m.MsgCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return nil, utils.Wrap(err, "")
+	}
+	return res, nil
+}
+
+// PushUnique value must slice.
+func (m *MsgSingleMongoDriver) PushUnique(
+	ctx context.Context,
+	docID string,
+	index int64,
+	key string,
+	value any,
+) (*mongo.UpdateResult, error) {
+	return m.PushUniqueByIndexes(ctx, docID, []int64{index}, key, value)
+}
+
+func (m *MsgSingleMongoDriver) PushUniqueByIndexes(
+	ctx context.Context,
+	docID string,
+	indexes []int64,
+	key string,
+	value any,
+) (*mongo.UpdateResult, error) {
+	if key == "" {
+		return nil, errs.ErrArgs.Wrap("key is empty")
+	}
+	filter, err := m.indexesFilter(docID, indexes)
+	if err != nil {
+		return nil, err
+	}
+	update := bson.M{"$addToSet": bson.M{key: bson.M{"$each": value}}}
+	res, err := m.MsgCollection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return nil, utils.Wrap(err, "")
+	}
+	return res, nil
+}
+
+func (m *MsgSingleMongoDriver) UpdateMsgContent(ctx context.Context, docID string, index int64, msg []byte) error {
+	filter, err := m.indexesFilter(docID, []int64{index})
+	if err != nil {
+		return err
+	}
+	if _, err := m.MsgCollection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"msg": msg}}); err != nil {
+		return utils.Wrap(err, "")
+	}
+	return nil
+}
+
+func (m *MsgSingleMongoDriver) IsExistDocID(ctx context.Context, docID string) (bool, error) {
+	filter, err := m.docFilter(docID)
+	if err != nil {
+		return false, err
+	}
+	count, err := m.MsgCollection.CountDocuments(ctx, filter, options.Count().SetLimit(1))
+	if err != nil {
+		return false, errs.Wrap(err)
+	}
+	return count > 0, nil
+}
+
+func (m *MsgSingleMongoDriver) FindOneByDocID(ctx context.Context, docID string) (*table.MsgDocModel, error) {
+	conversationID, begin, err := m.parseDocID(docID)
+	if err != nil {
+		return nil, err
+	}
+	filter := bson.M{
+		"conversation_id": conversationID,
+		"seq":             bson.M{"$gte": begin, "$lt": begin + m.model.GetSingleGocMsgNum()},
+	}
+	cursor, err := m.MsgCollection.Find(ctx, filter, options.Find().SetSort(bson.M{"seq": 1}))
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	var msgs []*table.MsgSingleModel
+	if err := cursor.All(ctx, &msgs); err != nil {
+		return nil, errs.Wrap(err)
+	}
+	if len(msgs) == 0 {
+		return &table.MsgDocModel{}, mongo.ErrNoDocuments
+	}
+	return m.toDoc(docID, begin, msgs), nil
+}
+
+func (m *MsgSingleMongoDriver) GetMsgBySeqIndexIn1Doc(
+	ctx context.Context,
+	userID string,
+	docID string,
+	seqs []int64,
+) ([]*table.MsgInfoModel, error) {
+	conversationID, _, err := m.parseDocID(docID)
+	if err != nil {
+		return nil, err
+	}
+	filter := bson.M{
+		"conversation_id": conversationID,
+		"seq":             bson.M{"$in": seqs},
+		"msg":             bson.M{"$ne": nil},
+		"del_list":        bson.M{"$ne": userID},
+	}
+	opts := options.Find().SetSort(bson.M{"seq": 1}).SetProjection(bson.M{"del_list": 0})
+	cursor, err := m.MsgCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	var singles []*table.MsgSingleModel
+	if err := cursor.All(ctx, &singles); err != nil {
+		return nil, errs.Wrap(err)
+	}
+	msgs := make([]*table.MsgInfoModel, 0, len(singles))
+	for _, single := range singles {
+		if err := convertRevokedMsg(&single.MsgInfoModel); err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, &single.MsgInfoModel)
+	}
+	return msgs, nil
+}
+
+func (m *MsgSingleMongoDriver) getEndMsg(ctx context.Context, conversationID string, sort int) (*table.MsgInfoModel, error) {
+	var single table.MsgSingleModel
+	filter := bson.M{"conversation_id": conversationID, "msg": bson.M{"$ne": nil}}
+	err := m.MsgCollection.FindOne(ctx, filter, options.FindOne().SetSort(bson.M{"seq": sort})).Decode(&single)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrMsgListNotExist
+	} else if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &single.MsgInfoModel, nil
+}
+
+func (m *MsgSingleMongoDriver) GetNewestMsg(ctx context.Context, conversationID string) (*table.MsgInfoModel, error) {
+	return m.getEndMsg(ctx, conversationID, -1)
+}
+
+func (m *MsgSingleMongoDriver) GetOldestMsg(ctx context.Context, conversationID string) (*table.MsgInfoModel, error) {
+	return m.getEndMsg(ctx, conversationID, 1)
+}
+
+func (m *MsgSingleMongoDriver) DeleteDocs(ctx context.Context, docIDs []string) error {
+	if len(docIDs) == 0 {
+		return nil
+	}
+	or := make(bson.A, 0, len(docIDs))
+	for _, docID := range docIDs {
+		filter, err := m.docFilter(docID)
+		if err != nil {
+			return err
+		}
+		or = append(or, filter)
+	}
+	_, err := m.MsgCollection.DeleteMany(ctx, bson.M{"$or": or})
+	return err
+}
+
+// GetMsgDocModelByIndex walks the docs of the conversation one seq range at a time,
+// a range with no msg left is skipped like a deleted doc of the array layout.
+func (m *MsgSingleMongoDriver) GetMsgDocModelByIndex(
+	ctx context.Context,
+	conversationID string,
+	index, sort int64,
+) (*table.MsgDocModel, error) {
+	if sort != 1 && sort != -1 {
+		return nil, errs.ErrArgs.Wrap("mongo sort must be 1 or -1")
+	}
+	num := m.model.GetSingleGocMsgNum()
+	opts := options.FindOne().SetSort(bson.M{"seq": sort}).SetProjection(bson.M{"_id": 0, "seq": 1})
+	var (
+		docID string
+		bound int64
+	)
+	for i := int64(0); i <= index; i++ {
+		filter := bson.M{"conversation_id": conversationID}
+		if i > 0 {
+			if sort == 1 {
+				filter["seq"] = bson.M{"$gt": bound}
+			} else {
+				filter["seq"] = bson.M{"$lt": bound}
+			}
+		}
+		var single table.MsgSingleModel
+		err := m.MsgCollection.FindOne(ctx, filter, opts).Decode(&single)
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrMsgListNotExist
+		} else if err != nil {
+			return nil, errs.Wrap(err)
+		}
+		docID = m.model.GetDocID(conversationID, single.Seq)
+		if sort == 1 {
+			bound = (single.Seq-1)/num*num + num
+		} else {
+			bound = (single.Seq-1)/num*num + 1
+		}
+	}
+	return m.FindOneByDocID(ctx, docID)
+}
+
+// DeleteMsgsInOneDocByIndex keeps the deleted msgs, so the docs found by GetMsgDocModelByIndex do not shift
+// while the caller is walking them.
+func (m *MsgSingleMongoDriver) DeleteMsgsInOneDocByIndex(ctx context.Context, docID string, indexes []int) error {
+	filter, err := m.indexesFilter(docID, utils.Slice(indexes, func(index int) int64 { return int64(index) }))
+	if err != nil {
+		return err
+	}
+	update := bson.M{"$set": bson.M{"msg": nil, "revoke": nil, "del_list": []string{}, "is_read": false}}
+	if _, err := m.MsgCollection.UpdateMany(ctx, filter, update); err != nil {
+		return utils.Wrap(err, "")
+	}
+	return nil
+}
+
+func (m *MsgSingleMongoDriver) MarkSingleChatMsgsAsRead(
+	ctx context.Context,
+	userID string,
+	docID string,
+	indexes []int64,
+) error {
+	filter, err := m.indexesFilter(docID, indexes)
+	if err != nil {
+		return err
+	}
+	filter["msg.send_id"] = bson.M{"$ne": userID}
+	_, err = m.MsgCollection.UpdateMany(ctx, filter, bson.M{"$set": bson.M{"is_read": true}})
+	return err
+}
+
+func (m *MsgSingleMongoDriver) SearchMessage(ctx context.Context, req *msg.SearchMessageReq) (int32, []*table.MsgInfoModel, error) {
+	filter := bson.M{
+		"conversation_id": primitive.Regex{Pattern: "^(si|g|sg)_"},
+		"msg":             bson.M{"$ne": nil},
+	}
+	if req.SendTime != "" {
+		day, err := time.Parse("2006-01-02", req.SendTime)
+		if err != nil {
+			return 0, nil, errs.ErrArgs.Wrap("invalid send time " + req.SendTime)
+		}
+		filter["msg.send_time"] = bson.M{"$gte": day.UnixMilli(), "$lt": day.AddDate(0, 0, 1).UnixMilli()}
+	}
+	if req.MsgType != 0 {
+		filter["msg.content_type"] = req.MsgType
+	}
+	if req.SessionType != 0 {
+		filter["msg.session_type"] = req.SessionType
+	}
+	if req.RecvID != "" {
+		filter["msg.recv_id"] = primitive.Regex{Pattern: req.RecvID}
+	}
+	if req.SendID != "" {
+		filter["msg.send_id"] = primitive.Regex{Pattern: req.SendID}
+	}
+	total, err := m.MsgCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return 0, nil, errs.Wrap(err)
+	}
+	skip := int64(req.Pagination.PageNumber-1) * int64(req.Pagination.ShowNumber)
+	if skip < 0 {
+		skip = 0
+	}
+	if skip >= total {
+		return int32(total), []*table.MsgInfoModel{}, nil
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "msg.send_time", Value: -1}}).
+		SetSkip(skip).
+		SetLimit(int64(req.Pagination.ShowNumber))
+	cursor, err := m.MsgCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return 0, nil, errs.Wrap(err)
+	}
+	var singles []*table.MsgSingleModel
+	if err := cursor.All(ctx, &singles); err != nil {
+		return 0, nil, errs.Wrap(err)
+	}
+	msgs := make([]*table.MsgInfoModel, 0, len(singles))
+	for _, single := range singles {
+		if err := convertRevokedMsg(&single.MsgInfoModel); err != nil {
+			return 0, nil, err
+		}
+		msgs = append(msgs, &single.MsgInfoModel)
+	}
+	return int32(total), msgs, nil
+}
+
+type sendCount struct {
+	ID    string `bson:"_id"`
+	Count int64  `bson:"count"`
+}
+
+// rangeSendCount counts the msgs sent in [start, end) in the conversations matching pattern,
+// grouped by field and by send date. The page is taken the same way as the array layout does.
+func (m *MsgSingleMongoDriver) rangeSendCount(
+	ctx context.Context,
+	start time.Time,
+	end time.Time,
+	pattern string,
+	field string,
+	ase bool,
+	pageNumber int32,
+	showNumber int32,
+) (msgCount int64, idCount int64, counts []sendCount, dateCount map[string]int64, err error) {
+	sort := -1
+	if ase {
+		sort = 1
+	}
+	skip := pageNumber - 1
+	if skip < 0 {
+		skip = 0
+	}
+	group := bson.M{"$group": bson.M{"_id": "$" + field, "count": bson.M{"$sum": 1}}}
+	pipeline := bson.A{
+		bson.M{
+			"$match": bson.M{
+				"conversation_id": primitive.Regex{Pattern: pattern},
+				"msg.send_time":   bson.M{"$gte": start.UnixMilli(), "$lt": end.UnixMilli()},
+			},
+		},
+		bson.M{
+			"$facet": bson.M{
+				"msg_count": bson.A{bson.M{"$count": "count"}},
+				"id_count":  bson.A{group, bson.M{"$count": "count"}},
+				"ids": bson.A{
+					group,
+					bson.M{"$sort": bson.D{{Key: "count", Value: sort}, {Key: "_id", Value: 1}}},
+					bson.M{"$skip": skip},
+					bson.M{"$limit": showNumber},
+				},
+				"dates": bson.A{
+					bson.M{
+						"$group": bson.M{
+							"_id": bson.M{
+								"$dateToString": bson.M{
+									"format": "%Y-%m-%d",
+									"date":   bson.M{"$toDate": "$msg.send_time"},
+								},
+							},
+							"count": bson.M{"$sum": 1},
+						},
+					},
+				},
+			},
+		},
+	}
+	cur, err := m.MsgCollection.Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return 0, 0, nil, nil, errs.Wrap(err)
+	}
+	defer cur.Close(ctx)
+	var result []struct {
+		MsgCount []sendCount `bson:"msg_count"`
+		IDCount  []sendCount `bson:"id_count"`
+		IDs      []sendCount `bson:"ids"`
+		Dates    []sendCount `bson:"dates"`
+	}
+	if err := cur.All(ctx, &result); err != nil {
+		return 0, 0, nil, nil, errs.Wrap(err)
+	}
+	if len(result) == 0 || len(result[0].MsgCount) == 0 {
+		return 0, 0, nil, nil, nil
+	}
+	dateCount = make(map[string]int64)
+	for _, r := range result[0].Dates {
+		dateCount[r.ID] = r.Count
+	}
+	if len(result[0].IDCount) > 0 {
+		idCount = result[0].IDCount[0].Count
+	}
+	return result[0].MsgCount[0].Count, idCount, result[0].IDs, dateCount, nil
+}
+
+func (m *MsgSingleMongoDriver) RangeUserSendCount(
+	ctx context.Context,
+	start time.Time,
+	end time.Time,
+	group bool,
+	ase bool,
+	pageNumber int32,
+	showNumber int32,
+) (msgCount int64, userCount int64, users []*table.UserCount, dateCount map[string]int64, err error) {
+	pattern := "^si_"
+	if group {
+		pattern = "^(si|g|sg)_"
+	}
+	msgCount, userCount, counts, dateCount, err := m.rangeSendCount(ctx, start, end, pattern, "msg.send_id", ase, pageNumber, showNumber)
+	if err != nil {
+		return 0, 0, nil, nil, err
+	}
+	users = utils.Slice(counts, func(c sendCount) *table.UserCount {
+		return &table.UserCount{UserID: c.ID, Count: c.Count}
+	})
+	return msgCount, userCount, users, dateCount, nil
+}
+
+func (m *MsgSingleMongoDriver) RangeGroupSendCount(
+	ctx context.Context,
+	start time.Time,
+	end time.Time,
+	ase bool,
+	pageNumber int32,
+	showNumber int32,
+) (msgCount int64, userCount int64, groups []*table.GroupCount, dateCount map[string]int64, err error) {
+	msgCount, userCount, counts, dateCount, err := m.rangeSendCount(ctx, start, end, "^(g|sg)_", "msg.group_id", ase, pageNumber, showNumber)
+	if err != nil {
+		return 0, 0, nil, nil, err
+	}
+	groups = utils.Slice(counts, func(c sendCount) *table.GroupCount {
+		return &table.GroupCount{GroupID: c.ID, Count: c.Count}
+	})
+	return msgCount, userCount, groups, dateCount, nil
+}
+
+func (m *MsgSingleMongoDriver) WalkMsgs(ctx context.Context, fn func(conversationID string, msgs []*table.MsgInfoModel) error) error {
+	opts := options.Find().SetSort(bson.D{{Key: "conversation_id", Value: 1}, {Key: "seq", Value: 1}}).SetBatchSize(1000)
+	cursor, err := m.MsgCollection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	defer cursor.Close(ctx)
+	var (
+		conversationID string
+		docID          string
+		msgs           []*table.MsgInfoModel
+	)
+	for cursor.Next(ctx) {
+		var single table.MsgSingleModel
+		if err := cursor.Decode(&single); err != nil {
+			return errs.Wrap(err)
+		}
+		if id := m.model.GetDocID(single.ConversationID, single.Seq); id != docID {
+			if len(msgs) > 0 {
+				if err := fn(conversationID, msgs); err != nil {
+					return err
+				}
+			}
+			conversationID, docID, msgs = single.ConversationID, id, nil
+		}
+		msgs = append(msgs, &single.MsgInfoModel)
+	}
+	if err := cursor.Err(); err != nil {
+		return errs.Wrap(err)
+	}
+	if len(msgs) > 0 {
+		return fn(conversationID, msgs)
+	}
+	return nil
+}
+
+// ConvertMsgsDocLen nothing to convert, there are no docs of singleGocMsgNum5000 msgs in this layout.
+func (m *MsgSingleMongoDriver) ConvertMsgsDocLen(ctx context.Context, conversationIDs []string) {}
+
+// CopyMsgDocsToSingle copies every msg of the array layout into the msg_single collection.
+// A msg already copied is replaced, so the copy can be run again after it was interrupted.
+func CopyMsgDocsToSingle(ctx context.Context, database *mongo.Database) (docNum int64, msgNum int64, err error) {
+	var model table.MsgDocModel
+	single := database.Collection(table.MsgSingleModel{}.TableName())
+	cursor, err := database.Collection(model.TableName()).Find(ctx, bson.M{}, options.Find().SetBatchSize(100))
+	if err != nil {
+		return 0, 0, errs.Wrap(err)
+	}
+	defer cursor.Close(ctx)
+	driver := &MsgSingleMongoDriver{}
+	for cursor.Next(ctx) {
+		var doc table.MsgDocModel
+		if err := cursor.Decode(&doc); err != nil {
+			return docNum, msgNum, errs.Wrap(err)
+		}
+		conversationID, begin, err := driver.parseDocID(doc.DocID)
+		if err != nil {
+			log.ZWarn(ctx, "skip msg doc", err, "docID", doc.DocID)
+			continue
+		}
+		var writes []mongo.WriteModel
+		for i, info := range doc.Msg {
+			if info == nil || (info.Msg == nil && info.Revoke == nil) {
+				continue
+			}
+			var seq int64
+			if info.Msg != nil {
+				seq = info.Msg.Seq
+			} else if int64(len(doc.Msg)) == model.GetSingleGocMsgNum() {
+				seq = begin + int64(i)
+			} else {
+				// the seq of a revoke without msg is only known from its slot in a doc of singleGocMsgNum msgs
+				continue
+			}
+			msg := &table.MsgSingleModel{ConversationID: conversationID, Seq: seq, MsgInfoModel: *info}
+			if msg.DelList == nil {
+				msg.DelList = []string{}
+			}
+			writes = append(writes, mongo.NewReplaceOneModel().
+				SetFilter(bson.M{"conversation_id": conversationID, "seq": seq}).
+				SetReplacement(msg).
+				SetUpsert(true))
+		}
+		if len(writes) > 0 {
+			if _, err := single.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+				return docNum, msgNum, errs.Wrap(err)
+			}
+		}
+		docNum++
+		msgNum += int64(len(writes))
+		if docNum%1000 == 0 {
+			log.ZInfo(ctx, "copying msg docs", "docNum", docNum, "msgNum", msgNum)
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return docNum, msgNum, errs.Wrap(err)
+	}
+	return docNum, msgNum, nil
+}
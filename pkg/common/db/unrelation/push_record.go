@@ -0,0 +1,74 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unrelation
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/utils"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/unrelation"
+)
+
+func NewPushRecordMongoDriver(database *mongo.Database) unrelation.PushRecordModelInterface {
+	return &PushRecordMongoDriver{
+		pushRecordCollection: database.Collection(unrelation.PushRecord),
+	}
+}
+
+type PushRecordMongoDriver struct {
+	pushRecordCollection *mongo.Collection
+}
+
+func (p *PushRecordMongoDriver) Create(ctx context.Context, records []*unrelation.PushRecordModel) error {
+	if len(records) == 0 {
+		return nil
+	}
+	_, err := p.pushRecordCollection.InsertMany(ctx, utils.Slice(records, func(r *unrelation.PushRecordModel) any { return r }))
+	return errs.Wrap(err)
+}
+
+func (p *PushRecordMongoDriver) Search(ctx context.Context, userID, clientMsgID string, start, end time.Time, pageNumber, showNumber int32) (int64, []*unrelation.PushRecordModel, error) {
+	filter := bson.M{"create_time": bson.M{"$gte": start, "$lte": end}}
+	if userID != "" {
+		filter["user_id"] = userID
+	}
+	if clientMsgID != "" {
+		filter["client_msg_id"] = clientMsgID
+	}
+	total, err := p.pushRecordCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return 0, nil, errs.Wrap(err)
+	}
+	opts := options.Find().SetSort(bson.M{"create_time": -1})
+	if pageNumber > 0 && showNumber > 0 {
+		opts.SetSkip(int64(pageNumber-1) * int64(showNumber)).SetLimit(int64(showNumber))
+	}
+	cursor, err := p.pushRecordCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return 0, nil, errs.Wrap(err)
+	}
+	var records []*unrelation.PushRecordModel
+	if err := cursor.All(ctx, &records); err != nil {
+		return 0, nil, errs.Wrap(err)
+	}
+	return total, records, nil
+}
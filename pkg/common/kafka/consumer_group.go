@@ -16,6 +16,7 @@ package kafka
 
 import (
 	"context"
+	"errors"
 
 	"github.com/OpenIMSDK/tools/log"
 
@@ -67,6 +68,9 @@ func (mc *MConsumerGroup) RegisterHandleAndConsumer(handler sarama.ConsumerGroup
 	ctx := context.Background()
 	for {
 		err := mc.ConsumerGroup.Consume(ctx, mc.topics, handler)
+		if errors.Is(err, sarama.ErrClosedConsumerGroup) {
+			return
+		}
 		if err != nil {
 			panic(err.Error())
 		}
@@ -0,0 +1,191 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/IBM/sarama"
+	"google.golang.org/protobuf/proto"
+
+	pbmsg "github.com/OpenIMSDK/protocol/msg"
+	"github.com/OpenIMSDK/tools/utils"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+)
+
+// Dead letter stages, the stage a batch failed in decides the topic it is replayed to.
+const (
+	// DeadLetterStageRedis BatchInsertChat2Cache failed, the messages have no seq yet and go back to latestMsgToRedis.
+	DeadLetterStageRedis = "redis"
+	// DeadLetterStageMongo BatchInsertChat2DB failed, the messages are still cached and go back to offlineMsgToMongo.
+	DeadLetterStageMongo = "mongo"
+)
+
+const (
+	deadLetterHeaderPrefix   = "deadLetter"
+	deadLetterHeaderStage    = deadLetterHeaderPrefix + "Stage"
+	deadLetterHeaderError    = deadLetterHeaderPrefix + "Error"
+	deadLetterHeaderFailedAt = deadLetterHeaderPrefix + "FailedAt"
+)
+
+// DeadLetter a failed batch read back from the dead letter topic.
+type DeadLetter struct {
+	Partition int32
+	Offset    int64
+	// ID the entry id when the dead letter was read from a redis stream
+	ID       string
+	Key      string
+	Stage    string
+	Error    string
+	FailedAt time.Time
+	// Ctx the context of the original batch, carrying its operationID
+	Ctx context.Context
+	Msg *pbmsg.MsgDataToMongoByMQ
+}
+
+// DeadLetterHeaders the headers recording why and when a batch was dead-lettered.
+func DeadLetterHeaders(stage string, cause error, failedAt time.Time) []sarama.RecordHeader {
+	var errMsg string
+	if cause != nil {
+		errMsg = cause.Error()
+	}
+	return []sarama.RecordHeader{
+		{Key: []byte(deadLetterHeaderStage), Value: []byte(stage)},
+		{Key: []byte(deadLetterHeaderError), Value: []byte(errMsg)},
+		{Key: []byte(deadLetterHeaderFailedAt), Value: []byte(failedAt.UTC().Format(time.RFC3339Nano))},
+	}
+}
+
+// ParseDeadLetter splits a dead letter record into the original batch and its dead letter metadata.
+func ParseDeadLetter(cMsg *sarama.ConsumerMessage) (*DeadLetter, error) {
+	return ParseDeadLetterRecord(cMsg.Partition, cMsg.Offset, cMsg.Key, cMsg.Value, cMsg.Headers)
+}
+
+// ParseDeadLetterRecord is ParseDeadLetter for a record read from any message queue backend.
+func ParseDeadLetterRecord(partition int32, offset int64, key, value []byte, headers []*sarama.RecordHeader) (*DeadLetter, error) {
+	dl := &DeadLetter{Partition: partition, Offset: offset, Key: string(key), Msg: &pbmsg.MsgDataToMongoByMQ{}}
+	var ctxHeaders []*sarama.RecordHeader
+	for _, header := range headers {
+		switch string(header.Key) {
+		case deadLetterHeaderStage:
+			dl.Stage = string(header.Value)
+		case deadLetterHeaderError:
+			dl.Error = string(header.Value)
+		case deadLetterHeaderFailedAt:
+			dl.FailedAt, _ = time.Parse(time.RFC3339Nano, string(header.Value))
+		default:
+			if !strings.HasPrefix(string(header.Key), deadLetterHeaderPrefix) {
+				ctxHeaders = append(ctxHeaders, header)
+			}
+		}
+	}
+	dl.Ctx = GetContextWithMQHeader(ctxHeaders)
+	if err := proto.Unmarshal(value, dl.Msg); err != nil {
+		return nil, utils.Wrap(err, "")
+	}
+	return dl, nil
+}
+
+// ReadDeadLetters calls fn for the dead letters of topic up to its end at the time of the call, partition by partition.
+// With a groupID, reading resumes after the last dead letter the group marked and every dead letter fn accepts is marked,
+// so that running it again does not hand out the same dead letter twice. Reading stops at the first error of fn.
+func ReadDeadLetters(addr []string, topic, groupID string, fn func(dl *DeadLetter) error) error {
+	cfg := sarama.NewConfig()
+	cfg.Consumer.Offsets.Initial = sarama.OffsetOldest
+	if config.Config.Kafka.Username != "" && config.Config.Kafka.Password != "" {
+		cfg.Net.SASL.Enable = true
+		cfg.Net.SASL.User = config.Config.Kafka.Username
+		cfg.Net.SASL.Password = config.Config.Kafka.Password
+	}
+	SetupTLSConfig(cfg)
+	client, err := sarama.NewClient(addr, cfg)
+	if err != nil {
+		return utils.Wrap(err, "")
+	}
+	defer client.Close()
+	consumer, err := sarama.NewConsumerFromClient(client)
+	if err != nil {
+		return utils.Wrap(err, "")
+	}
+	defer consumer.Close()
+	var offsetManager sarama.OffsetManager
+	if groupID != "" {
+		if offsetManager, err = sarama.NewOffsetManagerFromClient(groupID, client); err != nil {
+			return utils.Wrap(err, "")
+		}
+		// closing commits the marked offsets
+		defer offsetManager.Close()
+	}
+	partitions, err := client.Partitions(topic)
+	if err != nil {
+		return utils.Wrap(err, "")
+	}
+	for _, partition := range partitions {
+		if err := readDeadLetterPartition(client, consumer, offsetManager, topic, partition, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readDeadLetterPartition(client sarama.Client, consumer sarama.Consumer, offsetManager sarama.OffsetManager,
+	topic string, partition int32, fn func(dl *DeadLetter) error,
+) error {
+	end, err := client.GetOffset(topic, partition, sarama.OffsetNewest)
+	if err != nil {
+		return utils.Wrap(err, "")
+	}
+	start, err := client.GetOffset(topic, partition, sarama.OffsetOldest)
+	if err != nil {
+		return utils.Wrap(err, "")
+	}
+	var partitionOffsetManager sarama.PartitionOffsetManager
+	if offsetManager != nil {
+		if partitionOffsetManager, err = offsetManager.ManagePartition(topic, partition); err != nil {
+			return utils.Wrap(err, "")
+		}
+		defer partitionOffsetManager.Close()
+		if next, _ := partitionOffsetManager.NextOffset(); next > start {
+			start = next
+		}
+	}
+	if start >= end {
+		return nil
+	}
+	partitionConsumer, err := consumer.ConsumePartition(topic, partition, start)
+	if err != nil {
+		return utils.Wrap(err, "")
+	}
+	defer partitionConsumer.Close()
+	for cMsg := range partitionConsumer.Messages() {
+		dl, err := ParseDeadLetter(cMsg)
+		if err != nil {
+			return err
+		}
+		if err := fn(dl); err != nil {
+			return err
+		}
+		if partitionOffsetManager != nil {
+			partitionOffsetManager.MarkOffset(cMsg.Offset+1, "")
+		}
+		if cMsg.Offset+1 >= end {
+			break
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"github.com/IBM/sarama"
+)
+
+// Modify types, the messages of a modify record only carry the seqs they apply to.
+const (
+	// ModifyTypeRevoke the message of the seq was revoked, its content is the json of the revoke.
+	ModifyTypeRevoke = "revoke"
+	// ModifyTypeDelete the messages of the seqs were deleted.
+	ModifyTypeDelete = "delete"
+	// ModifyTypeDeleteBefore the messages before the seq were deleted.
+	ModifyTypeDeleteBefore = "deleteBefore"
+)
+
+const modifyHeaderType = "modifyType"
+
+// ModifyHeaders the headers marking a record of the modify topic as a state update of stored messages.
+func ModifyHeaders(modifyType string) []sarama.RecordHeader {
+	return []sarama.RecordHeader{{Key: []byte(modifyHeaderType), Value: []byte(modifyType)}}
+}
+
+// GetModifyType empty for the records that are not state updates, such as reactions.
+func GetModifyType(headers []*sarama.RecordHeader) string {
+	for _, header := range headers {
+		if string(header.Key) == modifyHeaderType {
+			return string(header.Value)
+		}
+	}
+	return ""
+}
@@ -122,7 +122,8 @@ func GetContextWithMQHeader(header []*sarama.RecordHeader) context.Context {
 	return mcontext.WithMustInfoCtx(values) // TODO
 }
 
-func (p *Producer) SendMessage(ctx context.Context, key string, msg proto.Message) (int32, int64, error) {
+// SendMessage sends msg with the context carried in the headers, extraHeaders are appended after them.
+func (p *Producer) SendMessage(ctx context.Context, key string, msg proto.Message, extraHeaders ...sarama.RecordHeader) (int32, int64, error) {
 	log.ZDebug(ctx, "SendMessage", "msg", msg, "topic", p.topic, "key", key)
 	kMsg := &sarama.ProducerMessage{}
 	kMsg.Topic = p.topic
@@ -143,7 +144,7 @@ func (p *Producer) SendMessage(ctx context.Context, key string, msg proto.Messag
 	if err != nil {
 		return 0, 0, utils.Wrap(err, "")
 	}
-	kMsg.Headers = header
+	kMsg.Headers = append(header, extraHeaders...)
 	partition, offset, err := p.producer.SendMessage(kMsg)
 	log.ZDebug(ctx, "ByteEncoder SendMessage end", "key ", kMsg.Key, "key length", kMsg.Value.Length())
 	if err != nil {
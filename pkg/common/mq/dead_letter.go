@@ -0,0 +1,117 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mq
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/utils"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/kafka"
+)
+
+// ReadDeadLetters is kafka.ReadDeadLetters on the backend of the config.
+func ReadDeadLetters(topic, groupID string, fn func(dl *kafka.DeadLetter) error) error {
+	if memoryBackend.Load() {
+		return errs.ErrArgs.Wrap("dead letters of the memory mq only live in the process that wrote them")
+	}
+	switch strings.ToLower(config.Config.MQ.Type) {
+	case TypeRedis:
+		return readRedisDeadLetters(context.Background(), mustRedis(), topic, groupID, fn)
+	default:
+		return kafka.ReadDeadLetters(config.Config.Kafka.Addr, topic, groupID, fn)
+	}
+}
+
+// readRedisDeadLetters reads the stream of topic from its start, with a groupID the stream is read as that group
+// by a consumer of the same name and every dead letter fn accepts is acknowledged. Entries fn failed on stay pending
+// and are read first on the next call.
+func readRedisDeadLetters(ctx context.Context, rdb redis.UniversalClient, topic, groupID string, fn func(dl *kafka.DeadLetter) error) error {
+	if groupID == "" {
+		start := "-"
+		for {
+			entries, err := rdb.XRangeN(ctx, topic, start, "+", redisReadCount).Result()
+			if err != nil {
+				return utils.Wrap(err, "")
+			}
+			for _, entry := range entries {
+				if err := handleRedisDeadLetter(topic, entry, fn); err != nil {
+					return err
+				}
+			}
+			if len(entries) < redisReadCount {
+				return nil
+			}
+			start = "(" + entries[len(entries)-1].ID
+		}
+	}
+	err := rdb.XGroupCreateMkStream(ctx, topic, groupID, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return utils.Wrap(err, "")
+	}
+	// "0" reads the pending entries of the consumer, ">" the ones never delivered to the group
+	for _, position := range []string{"0", ">"} {
+		for {
+			streams, err := rdb.XReadGroup(ctx, &redis.XReadGroupArgs{
+				Group:    groupID,
+				Consumer: groupID,
+				Streams:  []string{topic, position},
+				Count:    redisReadCount,
+				Block:    -1,
+			}).Result()
+			if errors.Is(err, redis.Nil) {
+				break
+			}
+			if err != nil {
+				return utils.Wrap(err, "")
+			}
+			var n int
+			for _, stream := range streams {
+				for _, entry := range stream.Messages {
+					if err := handleRedisDeadLetter(topic, entry, fn); err != nil {
+						return err
+					}
+					if err := rdb.XAck(ctx, topic, groupID, entry.ID).Err(); err != nil {
+						return utils.Wrap(err, "")
+					}
+					n++
+				}
+			}
+			if n == 0 {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func handleRedisDeadLetter(topic string, entry redis.XMessage, fn func(dl *kafka.DeadLetter) error) error {
+	msg, err := redisEntryToMessage(topic, entry)
+	if err != nil {
+		return err
+	}
+	dl, err := kafka.ParseDeadLetterRecord(msg.Partition, msg.Offset, msg.Key, msg.Value, msg.Headers)
+	if err != nil {
+		return err
+	}
+	dl.ID = entry.ID
+	return fn(dl)
+}
@@ -0,0 +1,100 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mq
+
+import (
+	"context"
+
+	"github.com/IBM/sarama"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/kafka"
+)
+
+type kafkaConsumerGroup struct {
+	*kafka.MConsumerGroup
+}
+
+func newKafkaConsumerGroup(topics []string, groupID string) *kafkaConsumerGroup {
+	return &kafkaConsumerGroup{kafka.NewMConsumerGroup(&kafka.MConsumerGroupConfig{
+		KafkaVersion:   sarama.V2_0_0_0,
+		OffsetsInitial: sarama.OffsetNewest, IsReturnErr: false,
+	}, topics, config.Config.Kafka.Addr, groupID)}
+}
+
+func (g *kafkaConsumerGroup) RegisterHandleAndConsumer(handler ConsumerGroupHandler) {
+	g.MConsumerGroup.RegisterHandleAndConsumer(&kafkaHandler{handler: handler})
+}
+
+func (g *kafkaConsumerGroup) Pause() {
+	g.PauseAll()
+}
+
+func (g *kafkaConsumerGroup) Resume() {
+	g.ResumeAll()
+}
+
+// kafkaHandler adapts a ConsumerGroupHandler to sarama, one claim per partition.
+type kafkaHandler struct {
+	handler ConsumerGroupHandler
+}
+
+func (kafkaHandler) Setup(_ sarama.ConsumerGroupSession) error   { return nil }
+func (kafkaHandler) Cleanup(_ sarama.ConsumerGroupSession) error { return nil }
+
+func (h *kafkaHandler) ConsumeClaim(sess sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	messages := make(chan *Message)
+	go func() {
+		defer close(messages)
+		for cMsg := range claim.Messages() {
+			msg := &Message{
+				Topic:     cMsg.Topic,
+				Partition: cMsg.Partition,
+				Offset:    cMsg.Offset,
+				Key:       cMsg.Key,
+				Value:     cMsg.Value,
+				Headers:   cMsg.Headers,
+			}
+			select {
+			case messages <- msg:
+			case <-sess.Context().Done():
+				return
+			}
+		}
+	}()
+	return h.handler.ConsumeClaim(&kafkaSession{sess: sess}, &kafkaClaim{claim: claim, messages: messages})
+}
+
+type kafkaSession struct {
+	sess sarama.ConsumerGroupSession
+}
+
+func (s *kafkaSession) Context() context.Context {
+	return s.sess.Context()
+}
+
+func (s *kafkaSession) MarkMessage(msg *Message) {
+	s.sess.MarkOffset(msg.Topic, msg.Partition, msg.Offset+1, "")
+}
+
+type kafkaClaim struct {
+	claim    sarama.ConsumerGroupClaim
+	messages chan *Message
+}
+
+func (c *kafkaClaim) Topic() string              { return c.claim.Topic() }
+func (c *kafkaClaim) Partition() int32           { return c.claim.Partition() }
+func (c *kafkaClaim) HighWaterMarkOffset() int64 { return c.claim.HighWaterMarkOffset() }
+func (c *kafkaClaim) Messages() <-chan *Message  { return c.messages }
@@ -0,0 +1,215 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mq
+
+import (
+	"context"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+)
+
+const memoryFetchCount = 100
+
+var defaultMemoryBroker = newMemoryBroker()
+
+// memoryBroker keeps every topic as a single partition log, a group remembers the offset of the next message to deliver.
+type memoryBroker struct {
+	lock    sync.Mutex
+	cond    *sync.Cond
+	logs    map[string][]*Message
+	offsets map[string]map[string]int64 // topic -> groupID -> next offset
+}
+
+func newMemoryBroker() *memoryBroker {
+	b := &memoryBroker{logs: make(map[string][]*Message), offsets: make(map[string]map[string]int64)}
+	b.cond = sync.NewCond(&b.lock)
+	return b
+}
+
+func (b *memoryBroker) append(msg *Message) int64 {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	msg.Offset = int64(len(b.logs[msg.Topic]))
+	b.logs[msg.Topic] = append(b.logs[msg.Topic], msg)
+	b.cond.Broadcast()
+	return msg.Offset
+}
+
+// join returns the offset the group resumes from, a new group starts after the last message.
+func (b *memoryBroker) join(topic, groupID string) int64 {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	groups, ok := b.offsets[topic]
+	if !ok {
+		groups = make(map[string]int64)
+		b.offsets[topic] = groups
+	}
+	offset, ok := groups[groupID]
+	if !ok {
+		offset = int64(len(b.logs[topic]))
+		groups[groupID] = offset
+	}
+	return offset
+}
+
+func (b *memoryBroker) mark(topic, groupID string, offset int64) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.offsets[topic][groupID] < offset+1 {
+		b.offsets[topic][groupID] = offset + 1
+	}
+}
+
+// fetch waits for up to memoryFetchCount messages from offset on while the group is not paused,
+// nil is returned once the group is closed.
+func (b *memoryBroker) fetch(topic string, offset int64, g *memoryConsumerGroup) []*Message {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	for int64(len(b.logs[topic])) <= offset || g.paused {
+		if g.closed {
+			return nil
+		}
+		b.cond.Wait()
+	}
+	if g.closed {
+		return nil
+	}
+	msgs := b.logs[topic][offset:]
+	if len(msgs) > memoryFetchCount {
+		msgs = msgs[:memoryFetchCount]
+	}
+	return msgs
+}
+
+func (b *memoryBroker) highWaterMark(topic string) int64 {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return int64(len(b.logs[topic]))
+}
+
+type memoryProducer struct {
+	broker *memoryBroker
+	topic  string
+}
+
+func newMemoryProducer(broker *memoryBroker, topic string) *memoryProducer {
+	return &memoryProducer{broker: broker, topic: topic}
+}
+
+func (p *memoryProducer) SendMessage(ctx context.Context, key string, msg proto.Message, extraHeaders ...Header) (int32, int64, error) {
+	bMsg, headers, err := encodeMessage(ctx, key, msg, extraHeaders)
+	if err != nil {
+		return 0, 0, err
+	}
+	mMsg := &Message{Topic: p.topic, Key: []byte(key), Value: bMsg}
+	for i := range headers {
+		mMsg.Headers = append(mMsg.Headers, &headers[i])
+	}
+	return 0, p.broker.append(mMsg), nil
+}
+
+type memoryConsumerGroup struct {
+	broker  *memoryBroker
+	topics  []string
+	groupID string
+	ctx     context.Context
+	cancel  context.CancelFunc
+	closed  bool // guarded by broker.lock
+	paused  bool // guarded by broker.lock
+}
+
+func newMemoryConsumerGroup(broker *memoryBroker, topics []string, groupID string) *memoryConsumerGroup {
+	g := &memoryConsumerGroup{broker: broker, topics: topics, groupID: groupID}
+	g.ctx, g.cancel = context.WithCancel(context.Background())
+	for _, topic := range topics {
+		broker.join(topic, groupID)
+	}
+	return g
+}
+
+func (g *memoryConsumerGroup) RegisterHandleAndConsumer(handler ConsumerGroupHandler) {
+	var wg sync.WaitGroup
+	for _, topic := range g.topics {
+		wg.Add(1)
+		go func(topic string) {
+			defer wg.Done()
+			claim := &memoryClaim{topic: topic, broker: g.broker, messages: make(chan *Message)}
+			go g.deliver(topic, claim.messages)
+			_ = handler.ConsumeClaim(g, claim)
+		}(topic)
+	}
+	wg.Wait()
+}
+
+func (g *memoryConsumerGroup) deliver(topic string, messages chan<- *Message) {
+	defer close(messages)
+	offset := g.broker.join(topic, g.groupID)
+	for {
+		msgs := g.broker.fetch(topic, offset, g)
+		if msgs == nil {
+			return
+		}
+		for _, msg := range msgs {
+			select {
+			case messages <- msg:
+			case <-g.ctx.Done():
+				return
+			}
+		}
+		offset += int64(len(msgs))
+	}
+}
+
+func (g *memoryConsumerGroup) Context() context.Context {
+	return g.ctx
+}
+
+func (g *memoryConsumerGroup) MarkMessage(msg *Message) {
+	g.broker.mark(msg.Topic, g.groupID, msg.Offset)
+}
+
+func (g *memoryConsumerGroup) Pause() {
+	g.broker.lock.Lock()
+	defer g.broker.lock.Unlock()
+	g.paused = true
+}
+
+func (g *memoryConsumerGroup) Resume() {
+	g.broker.lock.Lock()
+	defer g.broker.lock.Unlock()
+	g.paused = false
+	g.broker.cond.Broadcast()
+}
+
+func (g *memoryConsumerGroup) Close() error {
+	g.broker.lock.Lock()
+	g.closed = true
+	g.broker.cond.Broadcast()
+	g.broker.lock.Unlock()
+	g.cancel()
+	return nil
+}
+
+type memoryClaim struct {
+	topic    string
+	broker   *memoryBroker
+	messages chan *Message
+}
+
+func (c *memoryClaim) Topic() string              { return c.topic }
+func (c *memoryClaim) Partition() int32           { return 0 }
+func (c *memoryClaim) HighWaterMarkOffset() int64 { return c.broker.highWaterMark(c.topic) }
+func (c *memoryClaim) Messages() <-chan *Message  { return c.messages }
@@ -0,0 +1,275 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mq
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+)
+
+// recordHandler hands every message of its claims to the test, marking it unless mark is false.
+type recordHandler struct {
+	mark     bool
+	messages chan *Message
+}
+
+func newRecordHandler(mark bool) *recordHandler {
+	return &recordHandler{mark: mark, messages: make(chan *Message, 100)}
+}
+
+func (h *recordHandler) ConsumeClaim(sess ConsumerGroupSession, claim ConsumerGroupClaim) error {
+	for msg := range claim.Messages() {
+		h.messages <- msg
+		if h.mark {
+			sess.MarkMessage(msg)
+		}
+	}
+	return nil
+}
+
+func (h *recordHandler) receive(t *testing.T, n int) []*Message {
+	t.Helper()
+	var msgs []*Message
+	for len(msgs) < n {
+		select {
+		case msg := <-h.messages:
+			msgs = append(msgs, msg)
+		case <-time.After(time.Second * 3):
+			t.Fatalf("received %d messages, want %d", len(msgs), n)
+		}
+	}
+	return msgs
+}
+
+func (h *recordHandler) expectNone(t *testing.T) {
+	t.Helper()
+	select {
+	case msg := <-h.messages:
+		t.Fatalf("unexpected message %s at offset %d", msg.Key, msg.Offset)
+	case <-time.After(time.Millisecond * 100):
+	}
+}
+
+func start(group ConsumerGroup, handler ConsumerGroupHandler) chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		group.RegisterHandleAndConsumer(handler)
+	}()
+	return done
+}
+
+func stop(t *testing.T, group ConsumerGroup, done chan struct{}) {
+	t.Helper()
+	_ = group.Close()
+	select {
+	case <-done:
+	case <-time.After(time.Second * 3):
+		t.Fatal("RegisterHandleAndConsumer did not return after Close")
+	}
+}
+
+func testCtx(operationID string) context.Context {
+	return mcontext.WithMustInfoCtx([]string{operationID, "opUser", "1", "conn"})
+}
+
+func send(t *testing.T, p Producer, ctx context.Context, key string, seq int64) {
+	t.Helper()
+	if _, _, err := p.SendMessage(ctx, key, &sdkws.MsgData{ClientMsgID: key, Seq: seq}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMemorySendAndConsume(t *testing.T) {
+	broker := newMemoryBroker()
+	producer := newMemoryProducer(broker, "topic")
+	group := newMemoryConsumerGroup(broker, []string{"topic"}, "group")
+	handler := newRecordHandler(true)
+	done := start(group, handler)
+	defer stop(t, group, done)
+
+	send(t, producer, testCtx("op1"), "k1", 1)
+	send(t, producer, testCtx("op2"), "k2", 2)
+	msgs := handler.receive(t, 2)
+	for i, msg := range msgs {
+		if msg.Offset != int64(i) || msg.Topic != "topic" {
+			t.Fatalf("message %d at %s offset %d", i, msg.Topic, msg.Offset)
+		}
+		var data sdkws.MsgData
+		if err := proto.Unmarshal(msg.Value, &data); err != nil {
+			t.Fatal(err)
+		}
+		if data.Seq != int64(i+1) || string(msg.Key) != data.ClientMsgID {
+			t.Fatalf("message %d is %v with key %s", i, &data, msg.Key)
+		}
+	}
+	if operationID := mcontext.GetOperationID(GetContextFromMsg(msgs[1])); operationID != "op2" {
+		t.Fatalf("operationID %q, want op2", operationID)
+	}
+}
+
+func TestMemoryExtraHeaders(t *testing.T) {
+	broker := newMemoryBroker()
+	producer := newMemoryProducer(broker, "topic")
+	group := newMemoryConsumerGroup(broker, []string{"topic"}, "group")
+	handler := newRecordHandler(true)
+	done := start(group, handler)
+	defer stop(t, group, done)
+
+	extra := Header{Key: []byte("extra"), Value: []byte("value")}
+	if _, _, err := producer.SendMessage(testCtx("op"), "k", &sdkws.MsgData{Seq: 1}, extra); err != nil {
+		t.Fatal(err)
+	}
+	msg := handler.receive(t, 1)[0]
+	last := msg.Headers[len(msg.Headers)-1]
+	if string(last.Key) != "extra" || string(last.Value) != "value" {
+		t.Fatalf("last header %s=%s, want extra=value", last.Key, last.Value)
+	}
+	if string(msg.Headers[0].Key) != constant.OperationID {
+		t.Fatalf("first header %s, want the context headers first", msg.Headers[0].Key)
+	}
+}
+
+func TestMemorySendInvalid(t *testing.T) {
+	producer := newMemoryProducer(newMemoryBroker(), "topic")
+	if _, _, err := producer.SendMessage(testCtx("op"), "", &sdkws.MsgData{Seq: 1}); err == nil {
+		t.Fatal("empty key is accepted")
+	}
+	if _, _, err := producer.SendMessage(context.Background(), "k", &sdkws.MsgData{Seq: 1}); err == nil {
+		t.Fatal("context without operationID is accepted")
+	}
+}
+
+func TestMemoryGroupsFanOut(t *testing.T) {
+	broker := newMemoryBroker()
+	producer := newMemoryProducer(broker, "topic")
+	group1 := newMemoryConsumerGroup(broker, []string{"topic"}, "group1")
+	group2 := newMemoryConsumerGroup(broker, []string{"topic"}, "group2")
+	handler1, handler2 := newRecordHandler(true), newRecordHandler(true)
+	done1, done2 := start(group1, handler1), start(group2, handler2)
+	defer stop(t, group1, done1)
+	defer stop(t, group2, done2)
+
+	for i := int64(0); i < 10; i++ {
+		send(t, producer, testCtx("op"), "k", i)
+	}
+	handler1.receive(t, 10)
+	handler2.receive(t, 10)
+	handler1.expectNone(t)
+	handler2.expectNone(t)
+}
+
+func TestMemoryNewGroupStartsAtNewest(t *testing.T) {
+	broker := newMemoryBroker()
+	producer := newMemoryProducer(broker, "topic")
+	send(t, producer, testCtx("op"), "old", 1)
+
+	group := newMemoryConsumerGroup(broker, []string{"topic"}, "group")
+	handler := newRecordHandler(true)
+	done := start(group, handler)
+	defer stop(t, group, done)
+	send(t, producer, testCtx("op"), "new", 2)
+	if msg := handler.receive(t, 1)[0]; string(msg.Key) != "new" {
+		t.Fatalf("received %s, want new", msg.Key)
+	}
+	handler.expectNone(t)
+}
+
+func TestMemoryRedeliverUnmarked(t *testing.T) {
+	broker := newMemoryBroker()
+	producer := newMemoryProducer(broker, "topic")
+	group := newMemoryConsumerGroup(broker, []string{"topic"}, "group")
+	marking := newRecordHandler(true)
+	done := start(group, marking)
+	send(t, producer, testCtx("op"), "k", 1)
+	marking.receive(t, 1)
+	stop(t, group, done)
+
+	// the messages a stopped consumer never marked go to the next consumer of the group
+	group = newMemoryConsumerGroup(broker, []string{"topic"}, "group")
+	notMarking := newRecordHandler(false)
+	done = start(group, notMarking)
+	send(t, producer, testCtx("op"), "k", 2)
+	send(t, producer, testCtx("op"), "k", 3)
+	notMarking.receive(t, 2)
+	stop(t, group, done)
+
+	group = newMemoryConsumerGroup(broker, []string{"topic"}, "group")
+	handler := newRecordHandler(true)
+	done = start(group, handler)
+	defer stop(t, group, done)
+	msgs := handler.receive(t, 2)
+	if msgs[0].Offset != 1 || msgs[1].Offset != 2 {
+		t.Fatalf("redelivered offsets %d and %d, want 1 and 2", msgs[0].Offset, msgs[1].Offset)
+	}
+	handler.expectNone(t)
+}
+
+func TestMemoryMultipleTopics(t *testing.T) {
+	broker := newMemoryBroker()
+	group := newMemoryConsumerGroup(broker, []string{"topic1", "topic2"}, "group")
+	handler := newRecordHandler(true)
+	done := start(group, handler)
+	defer stop(t, group, done)
+
+	send(t, newMemoryProducer(broker, "topic1"), testCtx("op"), "k1", 1)
+	send(t, newMemoryProducer(broker, "topic2"), testCtx("op"), "k2", 1)
+	topics := make(map[string]bool)
+	for _, msg := range handler.receive(t, 2) {
+		topics[msg.Topic] = true
+	}
+	if !topics["topic1"] || !topics["topic2"] {
+		t.Fatalf("received from %v, want topic1 and topic2", topics)
+	}
+}
+
+func TestNewWithMemoryBackend(t *testing.T) {
+	// decoding allocates config.Config when no config was loaded
+	if err := yaml.Unmarshal([]byte("mq:\n  type: "+TypeKafka), &config.Config); err != nil {
+		t.Fatal(err)
+	}
+	UseMemoryBackend()
+
+	group := NewConsumerGroup([]string{"TestNewWithMemoryType"}, "group")
+	handler := newRecordHandler(true)
+	done := start(group, handler)
+	defer stop(t, group, done)
+	send(t, NewProducer("TestNewWithMemoryType"), testCtx("op"), "k", 1)
+	handler.receive(t, 1)
+}
+
+func TestMemoryPauseResume(t *testing.T) {
+	broker := newMemoryBroker()
+	producer := newMemoryProducer(broker, "topic")
+	group := newMemoryConsumerGroup(broker, []string{"topic"}, "group")
+	handler := newRecordHandler(true)
+	done := start(group, handler)
+	defer stop(t, group, done)
+
+	group.Pause()
+	send(t, producer, testCtx("op"), "k", 1)
+	handler.expectNone(t)
+	group.Resume()
+	handler.receive(t, 1)
+}
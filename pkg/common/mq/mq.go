@@ -0,0 +1,142 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mq
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+
+	"github.com/IBM/sarama"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/OpenIMSDK/tools/utils"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/kafka"
+)
+
+// Backends selected by mq.type in the config.
+const (
+	TypeKafka = "kafka"
+	TypeRedis = "redis"
+)
+
+// memoryBackend routes every producer and consumer group to the in-process broker whatever mq.type says.
+var memoryBackend atomic.Bool
+
+// UseMemoryBackend keeps the messages inside the process, producers and consumers have to run in the same process.
+// It is for tests only, a deployment runs msg, msgtransfer and push as separate processes.
+func UseMemoryBackend() {
+	memoryBackend.Store(true)
+}
+
+var errEmptyMsg = errors.New("binary msg is empty")
+
+// Header a message header, the kafka record header is reused so that every backend carries the context the same way.
+type Header = sarama.RecordHeader
+
+// Message a message read from a topic.
+type Message struct {
+	Topic     string
+	Partition int32
+	Offset    int64
+	Key       []byte
+	Value     []byte
+	Headers   []*Header
+
+	// id the entry id of the redis stream
+	id string
+}
+
+type Producer interface {
+	// SendMessage sends msg with the context carried in the headers, extraHeaders are appended after them.
+	SendMessage(ctx context.Context, key string, msg proto.Message, extraHeaders ...Header) (int32, int64, error)
+}
+
+type ConsumerGroupSession interface {
+	Context() context.Context
+	// MarkMessage acknowledges msg, it is not delivered to the group again.
+	MarkMessage(msg *Message)
+}
+
+type ConsumerGroupClaim interface {
+	Topic() string
+	Partition() int32
+	HighWaterMarkOffset() int64
+	// Messages is closed when the claim ends.
+	Messages() <-chan *Message
+}
+
+type ConsumerGroupHandler interface {
+	ConsumeClaim(sess ConsumerGroupSession, claim ConsumerGroupClaim) error
+}
+
+type ConsumerGroup interface {
+	// RegisterHandleAndConsumer consumes the topics of the group with handler until the group is closed.
+	RegisterHandleAndConsumer(handler ConsumerGroupHandler)
+	// Pause stops fetching messages until Resume, the messages already fetched are still delivered.
+	Pause()
+	Resume()
+	Close() error
+}
+
+// NewProducer Initialize the producer of topic on the backend of the config.
+func NewProducer(topic string) Producer {
+	if memoryBackend.Load() {
+		return newMemoryProducer(defaultMemoryBroker, topic)
+	}
+	switch strings.ToLower(config.Config.MQ.Type) {
+	case TypeRedis:
+		return newRedisProducer(mustRedis(), topic, config.Config.MQ.Redis.MaxLen)
+	default:
+		return kafka.NewKafkaProducer(config.Config.Kafka.Addr, topic)
+	}
+}
+
+// NewConsumerGroup Initialize the consumer group of topics on the backend of the config,
+// a new group starts from the newest message.
+func NewConsumerGroup(topics []string, groupID string) ConsumerGroup {
+	if memoryBackend.Load() {
+		return newMemoryConsumerGroup(defaultMemoryBroker, topics, groupID)
+	}
+	switch strings.ToLower(config.Config.MQ.Type) {
+	case TypeRedis:
+		return newRedisConsumerGroup(mustRedis(), topics, groupID)
+	default:
+		return newKafkaConsumerGroup(topics, groupID)
+	}
+}
+
+func GetContextFromMsg(msg *Message) context.Context {
+	return kafka.GetContextWithMQHeader(msg.Headers)
+}
+
+// encodeMessage marshals msg and builds its headers for the backends that do not go through kafka.Producer.
+func encodeMessage(ctx context.Context, key string, msg proto.Message, extraHeaders []Header) ([]byte, []Header, error) {
+	bMsg, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, nil, utils.Wrap(err, "mq proto Marshal err")
+	}
+	if key == "" || len(bMsg) == 0 {
+		return nil, nil, utils.Wrap(errEmptyMsg, "")
+	}
+	header, err := kafka.GetMQHeaderWithContext(ctx)
+	if err != nil {
+		return nil, nil, utils.Wrap(err, "")
+	}
+	return bMsg, append(header, extraHeaders...), nil
+}
@@ -0,0 +1,252 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/utils"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+)
+
+const (
+	redisFieldKey     = "key"
+	redisFieldValue   = "value"
+	redisFieldHeaders = "headers"
+
+	redisReadCount = 100
+	redisReadBlock = time.Second
+	// entries a consumer read but never acknowledged, e.g. because its process is gone, are claimed again after redisClaimMinIdle
+	redisClaimMinIdle  = 5 * time.Minute
+	redisClaimInterval = time.Minute
+	// how often a paused consumer checks whether it was resumed
+	redisPausedInterval = 100 * time.Millisecond
+)
+
+func mustRedis() redis.UniversalClient {
+	rdb, err := cache.NewRedis()
+	if err != nil {
+		panic(err.Error())
+	}
+	return rdb
+}
+
+// redisProducer appends to the redis stream named after the topic, streams have no partitions and the returned
+// partition and offset are always 0. A maxLen above 0 trims the stream approximately, unread entries included.
+type redisProducer struct {
+	rdb    redis.UniversalClient
+	topic  string
+	maxLen int64
+}
+
+func newRedisProducer(rdb redis.UniversalClient, topic string, maxLen int64) *redisProducer {
+	return &redisProducer{rdb: rdb, topic: topic, maxLen: maxLen}
+}
+
+func (p *redisProducer) SendMessage(ctx context.Context, key string, msg proto.Message, extraHeaders ...Header) (int32, int64, error) {
+	log.ZDebug(ctx, "SendMessage", "msg", msg, "topic", p.topic, "key", key)
+	bMsg, headers, err := encodeMessage(ctx, key, msg, extraHeaders)
+	if err != nil {
+		return 0, 0, err
+	}
+	bHeaders, err := json.Marshal(headers)
+	if err != nil {
+		return 0, 0, utils.Wrap(err, "")
+	}
+	err = p.rdb.XAdd(ctx, &redis.XAddArgs{
+		Stream: p.topic,
+		MaxLen: p.maxLen,
+		Approx: true,
+		Values: map[string]any{redisFieldKey: key, redisFieldValue: bMsg, redisFieldHeaders: bHeaders},
+	}).Err()
+	if err != nil {
+		log.ZWarn(ctx, "redis stream XAdd error", err, "topic", p.topic)
+	}
+	return 0, 0, utils.Wrap(err, "")
+}
+
+// redisConsumerGroup reads the streams of the topics as a redis consumer group, every process is a consumer of it.
+// Entries are spread over the consumers regardless of their key, so messages of a conversation are only consumed
+// in order while a single process consumes the group. The seqs stay unique as BatchInsertChat2Cache allocates them atomically.
+type redisConsumerGroup struct {
+	rdb      redis.UniversalClient
+	topics   []string
+	groupID  string
+	consumer string
+	ctx      context.Context
+	cancel   context.CancelFunc
+	paused   atomic.Bool
+}
+
+func newRedisConsumerGroup(rdb redis.UniversalClient, topics []string, groupID string) *redisConsumerGroup {
+	g := &redisConsumerGroup{rdb: rdb, topics: topics, groupID: groupID}
+	g.ctx, g.cancel = context.WithCancel(context.Background())
+	hostname, _ := os.Hostname()
+	g.consumer = fmt.Sprintf("%s-%d", hostname, os.Getpid())
+	for _, topic := range topics {
+		err := rdb.XGroupCreateMkStream(g.ctx, topic, groupID, "$").Err()
+		if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+			panic(err.Error())
+		}
+	}
+	return g
+}
+
+func (g *redisConsumerGroup) RegisterHandleAndConsumer(handler ConsumerGroupHandler) {
+	log.ZDebug(context.Background(), "register consumer group", "groupID", g.groupID, "consumer", g.consumer)
+	var wg sync.WaitGroup
+	for _, topic := range g.topics {
+		wg.Add(1)
+		go func(topic string) {
+			defer wg.Done()
+			claim := &redisClaim{topic: topic, messages: make(chan *Message)}
+			go g.read(topic, claim.messages)
+			if err := handler.ConsumeClaim(g, claim); err != nil {
+				log.ZError(g.ctx, "redis stream ConsumeClaim error", err, "topic", topic, "groupID", g.groupID)
+			}
+		}(topic)
+	}
+	wg.Wait()
+}
+
+func (g *redisConsumerGroup) read(topic string, messages chan<- *Message) {
+	defer close(messages)
+	var (
+		claimStart = "0-0"
+		lastClaim  time.Time
+	)
+	for g.ctx.Err() == nil {
+		if g.paused.Load() {
+			time.Sleep(redisPausedInterval)
+			continue
+		}
+		var (
+			entries []redis.XMessage
+			err     error
+		)
+		if time.Since(lastClaim) > redisClaimInterval {
+			entries, claimStart, err = g.rdb.XAutoClaim(g.ctx, &redis.XAutoClaimArgs{
+				Stream:   topic,
+				Group:    g.groupID,
+				MinIdle:  redisClaimMinIdle,
+				Start:    claimStart,
+				Count:    redisReadCount,
+				Consumer: g.consumer,
+			}).Result()
+			if err != nil || claimStart == "0-0" {
+				claimStart = "0-0"
+				lastClaim = time.Now()
+			}
+		} else {
+			var streams []redis.XStream
+			streams, err = g.rdb.XReadGroup(g.ctx, &redis.XReadGroupArgs{
+				Group:    g.groupID,
+				Consumer: g.consumer,
+				Streams:  []string{topic, ">"},
+				Count:    redisReadCount,
+				Block:    redisReadBlock,
+			}).Result()
+			if errors.Is(err, redis.Nil) {
+				continue
+			}
+			for _, stream := range streams {
+				entries = append(entries, stream.Messages...)
+			}
+		}
+		if err != nil {
+			if g.ctx.Err() != nil {
+				return
+			}
+			log.ZError(g.ctx, "redis stream read error", err, "topic", topic, "groupID", g.groupID)
+			time.Sleep(time.Second)
+			continue
+		}
+		for _, entry := range entries {
+			msg, err := redisEntryToMessage(topic, entry)
+			if err != nil {
+				log.ZError(g.ctx, "redis stream entry is invalid, it is dropped", err, "topic", topic, "id", entry.ID)
+				g.ack(topic, entry.ID)
+				continue
+			}
+			select {
+			case messages <- msg:
+			case <-g.ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+func redisEntryToMessage(topic string, entry redis.XMessage) (*Message, error) {
+	key, _ := entry.Values[redisFieldKey].(string)
+	value, _ := entry.Values[redisFieldValue].(string)
+	msg := &Message{Topic: topic, Key: []byte(key), Value: []byte(value), id: entry.ID}
+	if headers, ok := entry.Values[redisFieldHeaders].(string); ok {
+		if err := json.Unmarshal([]byte(headers), &msg.Headers); err != nil {
+			return nil, utils.Wrap(err, "")
+		}
+	}
+	return msg, nil
+}
+
+func (g *redisConsumerGroup) ack(topic, id string) {
+	if err := g.rdb.XAck(g.ctx, topic, g.groupID, id).Err(); err != nil {
+		log.ZWarn(g.ctx, "redis stream XAck error", err, "topic", topic, "id", id)
+	}
+}
+
+func (g *redisConsumerGroup) Context() context.Context {
+	return g.ctx
+}
+
+func (g *redisConsumerGroup) MarkMessage(msg *Message) {
+	g.ack(msg.Topic, msg.id)
+}
+
+func (g *redisConsumerGroup) Pause() {
+	g.paused.Store(true)
+}
+
+func (g *redisConsumerGroup) Resume() {
+	g.paused.Store(false)
+}
+
+func (g *redisConsumerGroup) Close() error {
+	g.cancel()
+	return nil
+}
+
+type redisClaim struct {
+	topic    string
+	messages chan *Message
+}
+
+func (c *redisClaim) Topic() string              { return c.topic }
+func (c *redisClaim) Partition() int32           { return 0 }
+func (c *redisClaim) HighWaterMarkOffset() int64 { return 0 }
+func (c *redisClaim) Messages() <-chan *Message  { return c.messages }
@@ -25,4 +25,12 @@ var (
 		Name: "seq_set_failed_total",
 		Help: "The number of failed set seq",
 	})
+	MsgTransferQueueDepthGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "msg_transfer_queue_depth",
+		Help: "The number of msgs waiting in each msg transfer worker",
+	}, []string{"worker"})
+	MsgTransferConsumerPausedGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "msg_transfer_consumer_paused",
+		Help: "Whether msg transfer paused consuming because too many msgs are pending",
+	})
 )
@@ -142,6 +142,11 @@ func IsNotification(conversationID string) bool {
 	return strings.HasPrefix(conversationID, "n_")
 }
 
+// IsBadgeCounted reports whether unread messages of the conversation count toward the app icon badge.
+func IsBadgeCounted(conversationID string, recvMsgOpt int32) bool {
+	return recvMsgOpt == constant.ReceiveMessage && !IsNotification(conversationID)
+}
+
 func IsNotificationByMsg(msg *sdkws.MsgData) bool {
 	return !Options(msg.Options).IsNotNotification()
 }
@@ -25,12 +25,14 @@ import (
 	"github.com/OpenIMSDK/tools/errs"
 
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcext"
 )
 
 type Conversation struct {
-	Client pbconversation.ConversationClient
-	conn   grpc.ClientConnInterface
-	discov discoveryregistry.SvcDiscoveryRegistry
+	Client    pbconversation.ConversationClient
+	ExtClient rpcext.ConversationExtClient
+	conn      grpc.ClientConnInterface
+	discov    discoveryregistry.SvcDiscoveryRegistry
 }
 
 func NewConversation(discov discoveryregistry.SvcDiscoveryRegistry) *Conversation {
@@ -39,7 +41,7 @@ func NewConversation(discov discoveryregistry.SvcDiscoveryRegistry) *Conversatio
 		panic(err)
 	}
 	client := pbconversation.NewConversationClient(conn)
-	return &Conversation{discov: discov, conn: conn, Client: client}
+	return &Conversation{discov: discov, conn: conn, Client: client, ExtClient: rpcext.NewConversationExtClient(conn)}
 }
 
 type ConversationRpcClient Conversation
@@ -131,3 +133,11 @@ func (c *ConversationRpcClient) GetConversations(
 	}
 	return resp.Conversations, nil
 }
+
+func (c *ConversationRpcClient) GetSnoozedUserIDs(ctx context.Context, conversationID string, userIDs []string) ([]string, error) {
+	resp, err := c.ExtClient.GetSnoozedUserIDs(ctx, &rpcext.GetSnoozedUserIDsReq{ConversationID: conversationID, UserIDs: userIDs})
+	if err != nil {
+		return nil, err
+	}
+	return resp.UserIDs, nil
+}
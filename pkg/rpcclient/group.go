@@ -28,12 +28,14 @@ import (
 	"github.com/OpenIMSDK/tools/utils"
 
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcext"
 )
 
 type Group struct {
-	conn   grpc.ClientConnInterface
-	Client group.GroupClient
-	discov discoveryregistry.SvcDiscoveryRegistry
+	conn      grpc.ClientConnInterface
+	Client    group.GroupClient
+	ExtClient rpcext.GroupExtClient
+	discov    discoveryregistry.SvcDiscoveryRegistry
 }
 
 func NewGroup(discov discoveryregistry.SvcDiscoveryRegistry) *Group {
@@ -42,7 +44,7 @@ func NewGroup(discov discoveryregistry.SvcDiscoveryRegistry) *Group {
 		panic(err)
 	}
 	client := group.NewGroupClient(conn)
-	return &Group{discov: discov, conn: conn, Client: client}
+	return &Group{discov: discov, conn: conn, Client: client, ExtClient: rpcext.NewGroupExtClient(conn)}
 }
 
 type GroupRpcClient Group
@@ -201,6 +203,19 @@ func (g *GroupRpcClient) GetGroupMemberCache(
 	return resp.Member, nil
 }
 
+// GetGroupMemberTagUserIDs the members carrying any of the tags.
+func (g *GroupRpcClient) GetGroupMemberTagUserIDs(ctx context.Context, groupID string, tags []string) ([]string, error) {
+	resp, err := g.ExtClient.GetGroupMemberTags(ctx, &rpcext.GetGroupMemberTagsReq{GroupID: groupID, Tags: tags})
+	if err != nil {
+		return nil, err
+	}
+	var userIDs []string
+	for _, tag := range resp.Tags {
+		userIDs = append(userIDs, tag.UserIDs...)
+	}
+	return utils.Distinct(userIDs), nil
+}
+
 func (g *GroupRpcClient) DismissGroup(ctx context.Context, groupID string) error {
 	_, err := g.Client.DismissGroup(ctx, &group.DismissGroupReq{
 		GroupID:      groupID,
@@ -315,7 +315,13 @@ func (g *GroupNotificationSender) GroupInfoSetNameNotification(ctx context.Conte
 	return g.Notification(ctx, mcontext.GetOpUserID(ctx), tips.Group.GroupID, constant.GroupInfoSetNameNotification, tips)
 }
 
-func (g *GroupNotificationSender) GroupInfoSetAnnouncementNotification(ctx context.Context, tips *sdkws.GroupInfoSetAnnouncementTips) (err error) {
+// GroupAnnouncementTips the GroupInfoSetAnnouncementTips with the id of the announcement, the clients which don't know it ignore the field.
+type GroupAnnouncementTips struct {
+	*sdkws.GroupInfoSetAnnouncementTips
+	AnnouncementID string `json:"announcementID"`
+}
+
+func (g *GroupNotificationSender) GroupInfoSetAnnouncementNotification(ctx context.Context, tips *sdkws.GroupInfoSetAnnouncementTips, announcementID string) (err error) {
 	defer log.ZDebug(ctx, "return")
 	defer func() {
 		if err != nil {
@@ -325,7 +331,8 @@ func (g *GroupNotificationSender) GroupInfoSetAnnouncementNotification(ctx conte
 	if err := g.fillOpUser(ctx, &tips.OpUser, tips.Group.GroupID); err != nil {
 		return err
 	}
-	return g.Notification(ctx, mcontext.GetOpUserID(ctx), tips.Group.GroupID, constant.GroupInfoSetAnnouncementNotification, tips, rpcclient.WithRpcGetUserName())
+	return g.Notification(ctx, mcontext.GetOpUserID(ctx), tips.Group.GroupID, constant.GroupInfoSetAnnouncementNotification,
+		&GroupAnnouncementTips{GroupInfoSetAnnouncementTips: tips, AnnouncementID: announcementID}, rpcclient.WithRpcGetUserName())
 }
 
 func (g *GroupNotificationSender) JoinGroupApplicationNotification(ctx context.Context, req *pbgroup.JoinGroupReq) (err error) {
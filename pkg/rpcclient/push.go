@@ -23,12 +23,14 @@ import (
 	"github.com/OpenIMSDK/tools/discoveryregistry"
 
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcext"
 )
 
 type Push struct {
-	conn   grpc.ClientConnInterface
-	Client push.PushMsgServiceClient
-	discov discoveryregistry.SvcDiscoveryRegistry
+	conn      grpc.ClientConnInterface
+	Client    push.PushMsgServiceClient
+	ExtClient rpcext.PushExtClient
+	discov    discoveryregistry.SvcDiscoveryRegistry
 }
 
 func NewPush(discov discoveryregistry.SvcDiscoveryRegistry) *Push {
@@ -37,9 +39,10 @@ func NewPush(discov discoveryregistry.SvcDiscoveryRegistry) *Push {
 		panic(err)
 	}
 	return &Push{
-		discov: discov,
-		conn:   conn,
-		Client: push.NewPushMsgServiceClient(conn),
+		discov:    discov,
+		conn:      conn,
+		Client:    push.NewPushMsgServiceClient(conn),
+		ExtClient: rpcext.NewPushExtClient(conn),
 	}
 }
 
@@ -29,13 +29,15 @@ import (
 	"github.com/OpenIMSDK/tools/utils"
 
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcext"
 )
 
 // User represents a structure holding connection details for the User RPC client.
 type User struct {
-	conn   grpc.ClientConnInterface
-	Client user.UserClient
-	Discov discoveryregistry.SvcDiscoveryRegistry
+	conn      grpc.ClientConnInterface
+	Client    user.UserClient
+	ExtClient rpcext.UserExtClient
+	Discov    discoveryregistry.SvcDiscoveryRegistry
 }
 
 // NewUser initializes and returns a User instance based on the provided service discovery registry.
@@ -45,7 +47,7 @@ func NewUser(discov discoveryregistry.SvcDiscoveryRegistry) *User {
 		panic(err)
 	}
 	client := user.NewUserClient(conn)
-	return &User{Discov: discov, Client: client, conn: conn}
+	return &User{Discov: discov, Client: client, ExtClient: rpcext.NewUserExtClient(conn), conn: conn}
 }
 
 // UserRpcClient represents the structure for a User RPC client.
@@ -179,3 +181,14 @@ func (u *UserRpcClient) SetUserStatus(ctx context.Context, userID string, status
 	})
 	return err
 }
+
+// GetDoNotDisturbMap retrieves the quiet hours of the users indexed by their user IDs, users without quiet hours are absent.
+func (u *UserRpcClient) GetDoNotDisturbMap(ctx context.Context, userIDs []string) (map[string]*rpcext.DoNotDisturb, error) {
+	resp, err := u.ExtClient.FindDoNotDisturb(ctx, &rpcext.GetDoNotDisturbReq{UserIDs: userIDs})
+	if err != nil {
+		return nil, err
+	}
+	return utils.SliceToMap(resp.DoNotDisturbs, func(e *rpcext.DoNotDisturb) string {
+		return e.UserID
+	}), nil
+}
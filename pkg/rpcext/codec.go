@@ -0,0 +1,81 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcext
+
+import (
+	"context"
+	"encoding/json"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+)
+
+// CodecName is the grpc content-subtype used by the extension services.
+const CodecName = "json"
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+func (jsonCodec) Name() string {
+	return CodecName
+}
+
+// invoke calls the unary extension method over cc.
+func invoke[Resp, Req any](ctx context.Context, cc grpc.ClientConnInterface, method string, req *Req, opts ...grpc.CallOption) (*Resp, error) {
+	out := new(Resp)
+	opts = append([]grpc.CallOption{grpc.CallContentSubtype(CodecName)}, opts...)
+	if err := cc.Invoke(ctx, method, req, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func fullMethod(serviceName, methodName string) string {
+	return "/" + serviceName + "/" + methodName
+}
+
+// unaryMethod adapts a typed server method, usually a method expression on the server interface, to a grpc.MethodDesc.
+func unaryMethod[S, Req, Resp any](serviceName, methodName string, fn func(srv S, ctx context.Context, req *Req) (*Resp, error)) grpc.MethodDesc {
+	fullMethod := fullMethod(serviceName, methodName)
+	handler := func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+		in := new(Req)
+		if err := dec(in); err != nil {
+			return nil, err
+		}
+		if interceptor == nil {
+			return fn(srv.(S), ctx, in)
+		}
+		info := &grpc.UnaryServerInfo{
+			Server:     srv,
+			FullMethod: fullMethod,
+		}
+		handler := func(ctx context.Context, req any) (any, error) {
+			return fn(srv.(S), ctx, req.(*Req))
+		}
+		return interceptor(ctx, in, info, handler)
+	}
+	return grpc.MethodDesc{MethodName: methodName, Handler: handler}
+}
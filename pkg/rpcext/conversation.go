@@ -0,0 +1,101 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcext
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc"
+)
+
+const conversationExtServiceName = "OpenIMServer.conversation.ConversationExt"
+
+type SetConversationSnoozeReq struct {
+	OwnerUserID    string `json:"ownerUserID"`
+	ConversationID string `json:"conversationID"`
+	// SnoozeUntil unix milliseconds, 0 cancels the snooze
+	SnoozeUntil int64 `json:"snoozeUntil"`
+}
+
+func (x *SetConversationSnoozeReq) Check() error {
+	if x.OwnerUserID == "" {
+		return errors.New("ownerUserID is empty")
+	}
+	if x.ConversationID == "" {
+		return errors.New("conversationID is empty")
+	}
+	if x.SnoozeUntil < 0 {
+		return errors.New("snoozeUntil is invalid")
+	}
+	return nil
+}
+
+type SetConversationSnoozeResp struct{}
+
+type GetSnoozedUserIDsReq struct {
+	ConversationID string   `json:"conversationID"`
+	UserIDs        []string `json:"userIDs"`
+}
+
+func (x *GetSnoozedUserIDsReq) Check() error {
+	if x.ConversationID == "" {
+		return errors.New("conversationID is empty")
+	}
+	return nil
+}
+
+type GetSnoozedUserIDsResp struct {
+	UserIDs []string `json:"userIDs"`
+}
+
+// ConversationExtClient is the client API for the ConversationExt service.
+type ConversationExtClient interface {
+	SetConversationSnooze(ctx context.Context, in *SetConversationSnoozeReq, opts ...grpc.CallOption) (*SetConversationSnoozeResp, error)
+	GetSnoozedUserIDs(ctx context.Context, in *GetSnoozedUserIDsReq, opts ...grpc.CallOption) (*GetSnoozedUserIDsResp, error)
+}
+
+type conversationExtClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewConversationExtClient(cc grpc.ClientConnInterface) ConversationExtClient {
+	return &conversationExtClient{cc}
+}
+
+func (c *conversationExtClient) SetConversationSnooze(ctx context.Context, in *SetConversationSnoozeReq, opts ...grpc.CallOption) (*SetConversationSnoozeResp, error) {
+	return invoke[SetConversationSnoozeResp](ctx, c.cc, fullMethod(conversationExtServiceName, "SetConversationSnooze"), in, opts...)
+}
+
+func (c *conversationExtClient) GetSnoozedUserIDs(ctx context.Context, in *GetSnoozedUserIDsReq, opts ...grpc.CallOption) (*GetSnoozedUserIDsResp, error) {
+	return invoke[GetSnoozedUserIDsResp](ctx, c.cc, fullMethod(conversationExtServiceName, "GetSnoozedUserIDs"), in, opts...)
+}
+
+// ConversationExtServer is the server API for the ConversationExt service.
+type ConversationExtServer interface {
+	SetConversationSnooze(context.Context, *SetConversationSnoozeReq) (*SetConversationSnoozeResp, error)
+	GetSnoozedUserIDs(context.Context, *GetSnoozedUserIDsReq) (*GetSnoozedUserIDsResp, error)
+}
+
+func RegisterConversationExtServer(s *grpc.Server, srv ConversationExtServer) {
+	s.RegisterService(&grpc.ServiceDesc{
+		ServiceName: conversationExtServiceName,
+		HandlerType: (*ConversationExtServer)(nil),
+		Methods: []grpc.MethodDesc{
+			unaryMethod(conversationExtServiceName, "SetConversationSnooze", ConversationExtServer.SetConversationSnooze),
+			unaryMethod(conversationExtServiceName, "GetSnoozedUserIDs", ConversationExtServer.GetSnoozedUserIDs),
+		},
+	}, srv)
+}
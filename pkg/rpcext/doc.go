@@ -0,0 +1,18 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rpcext contains the RPC services that are not part of the generated OpenIMSDK/protocol
+// definitions. Requests and responses are plain structs encoded with a JSON codec, so they can be
+// served by the same grpc.Server and reached through the same connection as the protobuf services.
+package rpcext // import "github.com/openimsdk/open-im-server/v3/pkg/rpcext"
@@ -0,0 +1,1016 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcext
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/OpenIMSDK/protocol/sdkws"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+)
+
+const groupExtServiceName = "OpenIMServer.group.GroupExt"
+
+// The bits of GroupRole.Permissions.
+const (
+	GroupPermissionKick         = authverify.GroupPermissionKick
+	GroupPermissionMute         = authverify.GroupPermissionMute
+	GroupPermissionEditInfo     = authverify.GroupPermissionEditInfo
+	GroupPermissionPin          = authverify.GroupPermissionPin
+	GroupPermissionRevokeOther  = authverify.GroupPermissionRevokeOther
+	GroupPermissionApproveJoin  = authverify.GroupPermissionApproveJoin
+	GroupPermissionAtAll        = authverify.GroupPermissionAtAll
+	GroupPermissionViewAuditLog = authverify.GroupPermissionViewAuditLog
+)
+
+// Custom roles use the role levels between ordinary members (20) and admins (60).
+const (
+	GroupCustomRoleMinLevel = authverify.GroupCustomRoleMinLevel
+	GroupCustomRoleMaxLevel = authverify.GroupCustomRoleMaxLevel
+)
+
+// GroupInviteLink ExpireTime and CreateTime are unix milliseconds, MaxUses 0 means unlimited.
+type GroupInviteLink struct {
+	Code            string `json:"code"`
+	GroupID         string `json:"groupID"`
+	CreatorUserID   string `json:"creatorUserID"`
+	MaxUses         int32  `json:"maxUses"`
+	UsedCount       int32  `json:"usedCount"`
+	RequireApproval bool   `json:"requireApproval"`
+	Revoked         bool   `json:"revoked"`
+	ExpireTime      int64  `json:"expireTime"`
+	CreateTime      int64  `json:"createTime"`
+}
+
+type CreateGroupInviteLinkReq struct {
+	GroupID string `json:"groupID"`
+	// ExpireTime unix milliseconds
+	ExpireTime      int64 `json:"expireTime"`
+	MaxUses         int32 `json:"maxUses"`
+	RequireApproval bool  `json:"requireApproval"`
+}
+
+func (x *CreateGroupInviteLinkReq) Check() error {
+	if x.GroupID == "" {
+		return errors.New("groupID is empty")
+	}
+	if x.ExpireTime <= 0 {
+		return errors.New("expireTime is invalid")
+	}
+	if x.MaxUses < 0 {
+		return errors.New("maxUses is invalid")
+	}
+	return nil
+}
+
+type CreateGroupInviteLinkResp struct {
+	Link *GroupInviteLink `json:"link"`
+}
+
+// GetGroupInviteLinksReq Valid only returns the links which can still be redeemed.
+type GetGroupInviteLinksReq struct {
+	GroupID    string                   `json:"groupID"`
+	Valid      bool                     `json:"valid"`
+	Pagination *sdkws.RequestPagination `json:"pagination"`
+}
+
+func (x *GetGroupInviteLinksReq) Check() error {
+	if x.GroupID == "" {
+		return errors.New("groupID is empty")
+	}
+	if x.Pagination == nil {
+		return errors.New("pagination is empty")
+	}
+	return nil
+}
+
+type GetGroupInviteLinksResp struct {
+	Total int64              `json:"total"`
+	Links []*GroupInviteLink `json:"links"`
+}
+
+type RevokeGroupInviteLinkReq struct {
+	Code string `json:"code"`
+}
+
+func (x *RevokeGroupInviteLinkReq) Check() error {
+	if x.Code == "" {
+		return errors.New("code is empty")
+	}
+	return nil
+}
+
+type RevokeGroupInviteLinkResp struct{}
+
+// RedeemGroupInviteLinkReq an empty UserID redeems the link for the caller.
+type RedeemGroupInviteLinkReq struct {
+	Code       string `json:"code"`
+	UserID     string `json:"userID"`
+	ReqMessage string `json:"reqMessage"`
+}
+
+func (x *RedeemGroupInviteLinkReq) Check() error {
+	if x.Code == "" {
+		return errors.New("code is empty")
+	}
+	return nil
+}
+
+// RedeemGroupInviteLinkResp Pending is true when a group request waits for the approval of the group admins.
+type RedeemGroupInviteLinkResp struct {
+	GroupID string `json:"groupID"`
+	Pending bool   `json:"pending"`
+}
+
+// GroupRole a custom role, members get it through SetGroupMemberInfo with RoleLevel.
+// Its members may always @all like ordinary members, whether Permissions holds the bit or not.
+type GroupRole struct {
+	GroupID     string `json:"groupID"`
+	RoleLevel   int32  `json:"roleLevel"`
+	Name        string `json:"name"`
+	Permissions int64  `json:"permissions"`
+	CreateTime  int64  `json:"createTime"`
+	UpdateTime  int64  `json:"updateTime"`
+}
+
+func checkGroupRoleLevel(roleLevel int32) error {
+	if !authverify.IsGroupCustomRole(roleLevel) {
+		return errors.New("roleLevel is not a custom role level")
+	}
+	return nil
+}
+
+// SetGroupRoleReq creates the role or overwrites it.
+type SetGroupRoleReq struct {
+	GroupID     string `json:"groupID"`
+	RoleLevel   int32  `json:"roleLevel"`
+	Name        string `json:"name"`
+	Permissions int64  `json:"permissions"`
+}
+
+func (x *SetGroupRoleReq) Check() error {
+	if x.GroupID == "" {
+		return errors.New("groupID is empty")
+	}
+	if err := checkGroupRoleLevel(x.RoleLevel); err != nil {
+		return err
+	}
+	if x.Name == "" {
+		return errors.New("name is empty")
+	}
+	if x.Permissions&^authverify.GroupPermissionAll != 0 {
+		return errors.New("permissions is invalid")
+	}
+	return nil
+}
+
+type SetGroupRoleResp struct{}
+
+type DeleteGroupRoleReq struct {
+	GroupID   string `json:"groupID"`
+	RoleLevel int32  `json:"roleLevel"`
+}
+
+func (x *DeleteGroupRoleReq) Check() error {
+	if x.GroupID == "" {
+		return errors.New("groupID is empty")
+	}
+	return checkGroupRoleLevel(x.RoleLevel)
+}
+
+type DeleteGroupRoleResp struct{}
+
+type GetGroupRolesReq struct {
+	GroupID string `json:"groupID"`
+}
+
+func (x *GetGroupRolesReq) Check() error {
+	if x.GroupID == "" {
+		return errors.New("groupID is empty")
+	}
+	return nil
+}
+
+type GetGroupRolesResp struct {
+	Roles []*GroupRole `json:"roles"`
+}
+
+// GroupAuditLog Before and After are json objects of the changed values, CreateTime is unix milliseconds.
+type GroupAuditLog struct {
+	ID             int64  `json:"id"`
+	GroupID        string `json:"groupID"`
+	Action         string `json:"action"`
+	OperatorUserID string `json:"operatorUserID"`
+	TargetUserID   string `json:"targetUserID"`
+	Before         string `json:"before"`
+	After          string `json:"after"`
+	CreateTime     int64  `json:"createTime"`
+}
+
+// GetGroupAuditLogsReq an empty Action or TargetUserID matches all.
+type GetGroupAuditLogsReq struct {
+	GroupID      string                   `json:"groupID"`
+	Action       string                   `json:"action"`
+	TargetUserID string                   `json:"targetUserID"`
+	Pagination   *sdkws.RequestPagination `json:"pagination"`
+}
+
+func (x *GetGroupAuditLogsReq) Check() error {
+	if x.GroupID == "" {
+		return errors.New("groupID is empty")
+	}
+	if x.Pagination == nil {
+		return errors.New("pagination is empty")
+	}
+	return nil
+}
+
+type GetGroupAuditLogsResp struct {
+	Total int64            `json:"total"`
+	Logs  []*GroupAuditLog `json:"logs"`
+}
+
+const (
+	GroupDirectoryOrderMemberCount = "memberCount"
+	GroupDirectoryOrderActivity    = "activity"
+)
+
+// GroupDirectoryEntry ActiveTime is the unix milliseconds of the newest group message, refreshed periodically.
+type GroupDirectoryEntry struct {
+	GroupID          string   `json:"groupID"`
+	GroupName        string   `json:"groupName"`
+	FaceURL          string   `json:"faceURL"`
+	Introduction     string   `json:"introduction"`
+	Category         string   `json:"category"`
+	Tags             []string `json:"tags"`
+	Description      string   `json:"description"`
+	MemberCount      uint32   `json:"memberCount"`
+	NeedVerification int32    `json:"needVerification"`
+	ActiveTime       int64    `json:"activeTime"`
+}
+
+// SetGroupDirectoryReq Discoverable false removes the group from the directory.
+type SetGroupDirectoryReq struct {
+	GroupID      string   `json:"groupID"`
+	Discoverable bool     `json:"discoverable"`
+	Category     string   `json:"category"`
+	Tags         []string `json:"tags"`
+	Description  string   `json:"description"`
+}
+
+func (x *SetGroupDirectoryReq) Check() error {
+	if x.GroupID == "" {
+		return errors.New("groupID is empty")
+	}
+	if !x.Discoverable {
+		return nil
+	}
+	if x.Category == "" {
+		return errors.New("category is empty")
+	}
+	for _, tag := range x.Tags {
+		if tag == "" || strings.Contains(tag, ",") {
+			return errors.New("tag is invalid")
+		}
+	}
+	return nil
+}
+
+type SetGroupDirectoryResp struct{}
+
+// SearchGroupDirectoryReq empty Keyword, Category and Tag match all, OrderBy defaults to memberCount.
+type SearchGroupDirectoryReq struct {
+	Keyword    string                   `json:"keyword"`
+	Category   string                   `json:"category"`
+	Tag        string                   `json:"tag"`
+	OrderBy    string                   `json:"orderBy"`
+	Pagination *sdkws.RequestPagination `json:"pagination"`
+}
+
+func (x *SearchGroupDirectoryReq) Check() error {
+	switch x.OrderBy {
+	case "", GroupDirectoryOrderMemberCount, GroupDirectoryOrderActivity:
+	default:
+		return errors.New("orderBy is invalid")
+	}
+	if x.Pagination == nil {
+		return errors.New("pagination is empty")
+	}
+	return nil
+}
+
+type SearchGroupDirectoryResp struct {
+	Total  int64                  `json:"total"`
+	Groups []*GroupDirectoryEntry `json:"groups"`
+}
+
+// JoinDirectoryGroupReq joins a listed group, groups which need verification get a group request.
+type JoinDirectoryGroupReq struct {
+	GroupID    string `json:"groupID"`
+	ReqMessage string `json:"reqMessage"`
+}
+
+func (x *JoinDirectoryGroupReq) Check() error {
+	if x.GroupID == "" {
+		return errors.New("groupID is empty")
+	}
+	return nil
+}
+
+// JoinDirectoryGroupResp Pending is true when a group request waits for the approval of the group admins.
+type JoinDirectoryGroupResp struct {
+	Pending bool `json:"pending"`
+}
+
+// QuitAllGroupsReq removes a deleted user from all joined groups, only for the app manager.
+type QuitAllGroupsReq struct {
+	UserID string `json:"userID"`
+}
+
+func (x *QuitAllGroupsReq) Check() error {
+	if x.UserID == "" {
+		return errors.New("userID is empty")
+	}
+	return nil
+}
+
+// QuitAllGroupsResp FailedGroupIDs the groups the user is still in, e.g. the owner can't quit without a succession policy.
+type QuitAllGroupsResp struct {
+	FailedGroupIDs []string `json:"failedGroupIDs"`
+}
+
+const (
+	GroupMemberImportActionAdd    = "add"
+	GroupMemberImportActionRemove = "remove"
+)
+
+// ImportGroupMembersReq ObjectName is a CSV or JSON object uploaded through /object.
+// The CSV has a header row naming the columns userID, action, roleLevel and nickname, only userID is required.
+// The JSON is {"members":[{"userID":"","action":"","roleLevel":0,"nickname":""}]}.
+// The action defaults to add, DryRun only validates the rows and changes nothing.
+type ImportGroupMembersReq struct {
+	GroupID    string `json:"groupID"`
+	ObjectName string `json:"objectName"`
+	DryRun     bool   `json:"dryRun"`
+}
+
+func (x *ImportGroupMembersReq) Check() error {
+	if x.GroupID == "" {
+		return errors.New("groupID is empty")
+	}
+	if x.ObjectName == "" {
+		return errors.New("objectName is empty")
+	}
+	return nil
+}
+
+// GroupMemberImportFailure Row counts the members of the file from 1, the CSV header row is not counted.
+type GroupMemberImportFailure struct {
+	Row    int32  `json:"row"`
+	UserID string `json:"userID"`
+	Reason string `json:"reason"`
+}
+
+// ImportGroupMembersResp the rows of a dry run are counted as if they were applied.
+type ImportGroupMembersResp struct {
+	Total    int32                       `json:"total"`
+	Added    int32                       `json:"added"`
+	Removed  int32                       `json:"removed"`
+	Failures []*GroupMemberImportFailure `json:"failures"`
+}
+
+type ExportGroupMembersReq struct {
+	GroupID string `json:"groupID"`
+}
+
+func (x *ExportGroupMembersReq) Check() error {
+	if x.GroupID == "" {
+		return errors.New("groupID is empty")
+	}
+	return nil
+}
+
+// ExportGroupMembersResp URL downloads a CSV in the format of ImportGroupMembersReq.
+type ExportGroupMembersResp struct {
+	URL        string `json:"url"`
+	ExpireTime int64  `json:"expireTime"`
+}
+
+// GroupAnnouncement Acknowledged tells if the requesting user acknowledged the announcement.
+type GroupAnnouncement struct {
+	AnnouncementID string `json:"announcementID"`
+	GroupID        string `json:"groupID"`
+	Content        string `json:"content"`
+	CreatorUserID  string `json:"creatorUserID"`
+	UpdaterUserID  string `json:"updaterUserID"`
+	Pinned         bool   `json:"pinned"`
+	Acknowledged   bool   `json:"acknowledged"`
+	CreateTime     int64  `json:"createTime"`
+	UpdateTime     int64  `json:"updateTime"`
+}
+
+type CreateGroupAnnouncementReq struct {
+	GroupID string `json:"groupID"`
+	Content string `json:"content"`
+	Pinned  bool   `json:"pinned"`
+}
+
+func (x *CreateGroupAnnouncementReq) Check() error {
+	if x.GroupID == "" {
+		return errors.New("groupID is empty")
+	}
+	if x.Content == "" {
+		return errors.New("content is empty")
+	}
+	return nil
+}
+
+type CreateGroupAnnouncementResp struct {
+	Announcement *GroupAnnouncement `json:"announcement"`
+}
+
+// UpdateGroupAnnouncementReq nil fields are kept, a changed content has to be acknowledged again.
+type UpdateGroupAnnouncementReq struct {
+	AnnouncementID string  `json:"announcementID"`
+	Content        *string `json:"content"`
+	Pinned         *bool   `json:"pinned"`
+}
+
+func (x *UpdateGroupAnnouncementReq) Check() error {
+	if x.AnnouncementID == "" {
+		return errors.New("announcementID is empty")
+	}
+	if x.Content == nil && x.Pinned == nil {
+		return errors.New("nothing to update")
+	}
+	if x.Content != nil && *x.Content == "" {
+		return errors.New("content is empty")
+	}
+	return nil
+}
+
+type UpdateGroupAnnouncementResp struct {
+	Announcement *GroupAnnouncement `json:"announcement"`
+}
+
+type DeleteGroupAnnouncementReq struct {
+	AnnouncementID string `json:"announcementID"`
+}
+
+func (x *DeleteGroupAnnouncementReq) Check() error {
+	if x.AnnouncementID == "" {
+		return errors.New("announcementID is empty")
+	}
+	return nil
+}
+
+type DeleteGroupAnnouncementResp struct{}
+
+// GetGroupAnnouncementsReq the pinned announcements first, then the newest first.
+type GetGroupAnnouncementsReq struct {
+	GroupID    string                   `json:"groupID"`
+	Pagination *sdkws.RequestPagination `json:"pagination"`
+}
+
+func (x *GetGroupAnnouncementsReq) Check() error {
+	if x.GroupID == "" {
+		return errors.New("groupID is empty")
+	}
+	if x.Pagination == nil {
+		return errors.New("pagination is empty")
+	}
+	return nil
+}
+
+type GetGroupAnnouncementsResp struct {
+	Total         int64                `json:"total"`
+	Announcements []*GroupAnnouncement `json:"announcements"`
+}
+
+type AckGroupAnnouncementReq struct {
+	AnnouncementID string `json:"announcementID"`
+}
+
+func (x *AckGroupAnnouncementReq) Check() error {
+	if x.AnnouncementID == "" {
+		return errors.New("announcementID is empty")
+	}
+	return nil
+}
+
+type AckGroupAnnouncementResp struct{}
+
+// GetGroupAnnouncementUnreadMembersReq the current members who haven't acknowledged the announcement.
+type GetGroupAnnouncementUnreadMembersReq struct {
+	AnnouncementID string                   `json:"announcementID"`
+	Pagination     *sdkws.RequestPagination `json:"pagination"`
+}
+
+func (x *GetGroupAnnouncementUnreadMembersReq) Check() error {
+	if x.AnnouncementID == "" {
+		return errors.New("announcementID is empty")
+	}
+	if x.Pagination == nil {
+		return errors.New("pagination is empty")
+	}
+	return nil
+}
+
+type GetGroupAnnouncementUnreadMembersResp struct {
+	Total   int64    `json:"total"`
+	UserIDs []string `json:"userIDs"`
+}
+
+// GroupMemberTagAtPrefix a message mentions the members carrying a tag by putting GroupMemberTagAtPrefix+tag
+// into AtUserIDList, the server replaces it with the user ids of the members.
+const GroupMemberTagAtPrefix = "@tag:"
+
+func checkGroupMemberTag(tag string) error {
+	if tag == "" {
+		return errors.New("tag is empty")
+	}
+	if len(tag) > 64 {
+		return errors.New("tag is too long")
+	}
+	if strings.ContainsAny(tag, " \t\r\n") {
+		return errors.New("tag contains whitespace")
+	}
+	return nil
+}
+
+type GroupMemberTag struct {
+	Tag     string   `json:"tag"`
+	UserIDs []string `json:"userIDs"`
+}
+
+// SetGroupMemberTagReq UserIDs replace the members carrying the tag, the tag is created if it is new.
+type SetGroupMemberTagReq struct {
+	GroupID string   `json:"groupID"`
+	Tag     string   `json:"tag"`
+	UserIDs []string `json:"userIDs"`
+}
+
+func (x *SetGroupMemberTagReq) Check() error {
+	if x.GroupID == "" {
+		return errors.New("groupID is empty")
+	}
+	if len(x.UserIDs) == 0 {
+		return errors.New("userIDs is empty")
+	}
+	return checkGroupMemberTag(x.Tag)
+}
+
+type SetGroupMemberTagResp struct{}
+
+type DeleteGroupMemberTagReq struct {
+	GroupID string `json:"groupID"`
+	Tag     string `json:"tag"`
+}
+
+func (x *DeleteGroupMemberTagReq) Check() error {
+	if x.GroupID == "" {
+		return errors.New("groupID is empty")
+	}
+	return checkGroupMemberTag(x.Tag)
+}
+
+type DeleteGroupMemberTagResp struct{}
+
+// GetGroupMemberTagsReq empty Tags returns all tags of the group.
+type GetGroupMemberTagsReq struct {
+	GroupID string   `json:"groupID"`
+	Tags    []string `json:"tags"`
+}
+
+func (x *GetGroupMemberTagsReq) Check() error {
+	if x.GroupID == "" {
+		return errors.New("groupID is empty")
+	}
+	return nil
+}
+
+// GetGroupMemberTagsResp UserIDs only holds the current members of the group.
+type GetGroupMemberTagsResp struct {
+	Tags []*GroupMemberTag `json:"tags"`
+}
+
+const (
+	GroupMuteScheduleDaily = "daily"
+	GroupMuteScheduleOnce  = "once"
+)
+
+// GroupMuteSchedule StartMinute and EndMinute are the minutes of the day in TimeZone for a daily window,
+// StartTime and EndTime are unix milliseconds for a one-off window.
+type GroupMuteSchedule struct {
+	ScheduleID    string `json:"scheduleID"`
+	GroupID       string `json:"groupID"`
+	Type          string `json:"type"`
+	StartMinute   int32  `json:"startMinute"`
+	EndMinute     int32  `json:"endMinute"`
+	TimeZone      string `json:"timeZone"`
+	StartTime     int64  `json:"startTime"`
+	EndTime       int64  `json:"endTime"`
+	Active        bool   `json:"active"`
+	CreatorUserID string `json:"creatorUserID"`
+	CreateTime    int64  `json:"createTime"`
+}
+
+// CreateGroupMuteScheduleReq a daily window ending before it starts spans midnight, empty TimeZone is the server time zone.
+type CreateGroupMuteScheduleReq struct {
+	GroupID     string `json:"groupID"`
+	Type        string `json:"type"`
+	StartMinute int32  `json:"startMinute"`
+	EndMinute   int32  `json:"endMinute"`
+	TimeZone    string `json:"timeZone"`
+	StartTime   int64  `json:"startTime"`
+	EndTime     int64  `json:"endTime"`
+}
+
+func (x *CreateGroupMuteScheduleReq) Check() error {
+	if x.GroupID == "" {
+		return errors.New("groupID is empty")
+	}
+	switch x.Type {
+	case GroupMuteScheduleDaily:
+		if x.StartMinute < 0 || x.StartMinute >= 24*60 || x.EndMinute < 0 || x.EndMinute >= 24*60 {
+			return errors.New("startMinute and endMinute must be in [0, 1440)")
+		}
+		if x.StartMinute == x.EndMinute {
+			return errors.New("startMinute equals endMinute")
+		}
+		if _, err := time.LoadLocation(x.TimeZone); err != nil {
+			return errors.New("invalid timeZone " + x.TimeZone)
+		}
+	case GroupMuteScheduleOnce:
+		if x.StartTime <= 0 || x.EndTime <= x.StartTime {
+			return errors.New("endTime must be after startTime")
+		}
+		if x.EndTime <= time.Now().UnixMilli() {
+			return errors.New("endTime has passed")
+		}
+	default:
+		return errors.New("invalid type " + x.Type)
+	}
+	return nil
+}
+
+type CreateGroupMuteScheduleResp struct {
+	Schedule *GroupMuteSchedule `json:"schedule"`
+}
+
+type DeleteGroupMuteScheduleReq struct {
+	ScheduleID string `json:"scheduleID"`
+}
+
+func (x *DeleteGroupMuteScheduleReq) Check() error {
+	if x.ScheduleID == "" {
+		return errors.New("scheduleID is empty")
+	}
+	return nil
+}
+
+type DeleteGroupMuteScheduleResp struct{}
+
+type GetGroupMuteSchedulesReq struct {
+	GroupID string `json:"groupID"`
+}
+
+func (x *GetGroupMuteSchedulesReq) Check() error {
+	if x.GroupID == "" {
+		return errors.New("groupID is empty")
+	}
+	return nil
+}
+
+// GetGroupMuteSchedulesResp Muted whether a schedule covers the current time.
+type GetGroupMuteSchedulesResp struct {
+	Schedules []*GroupMuteSchedule `json:"schedules"`
+	Muted     bool                 `json:"muted"`
+}
+
+const maxGroupJoinQuestions = 10
+
+// GroupJoinQuestion AnswerPattern is a regular expression the whole answer has to match, empty accepts any answer.
+// The applicant of a group with questions puts the answers into ReqMessage as a json array of strings, in the order of the questions.
+type GroupJoinQuestion struct {
+	Question      string `json:"question"`
+	AnswerPattern string `json:"answerPattern"`
+}
+
+// GroupJoinRule a join request matching any of the enabled rules joins the group without an approval.
+type GroupJoinRule struct {
+	GroupID   string               `json:"groupID"`
+	Questions []*GroupJoinQuestion `json:"questions"`
+	// ApproveByAnswers approve when every answer matches the pattern of its question
+	ApproveByAnswers bool `json:"approveByAnswers"`
+	// ExPattern approve when the whole Ex of the applicant matches the regular expression, empty disables the rule
+	ExPattern string `json:"exPattern"`
+	// ApproveAdminInvited approve the applicants coming from an invite link created by a member allowed to approve joins
+	ApproveAdminInvited bool `json:"approveAdminInvited"`
+	// MinFriendMembers approve when the applicant is friends with at least this many members, 0 disables the rule
+	MinFriendMembers int32  `json:"minFriendMembers"`
+	UpdateUserID     string `json:"updateUserID"`
+	UpdateTime       int64  `json:"updateTime"`
+}
+
+// SetGroupJoinRuleReq overwrites the rules of the group, an empty request removes them.
+type SetGroupJoinRuleReq struct {
+	GroupID             string               `json:"groupID"`
+	Questions           []*GroupJoinQuestion `json:"questions"`
+	ApproveByAnswers    bool                 `json:"approveByAnswers"`
+	ExPattern           string               `json:"exPattern"`
+	ApproveAdminInvited bool                 `json:"approveAdminInvited"`
+	MinFriendMembers    int32                `json:"minFriendMembers"`
+}
+
+func (x *SetGroupJoinRuleReq) Check() error {
+	if x.GroupID == "" {
+		return errors.New("groupID is empty")
+	}
+	if len(x.Questions) > maxGroupJoinQuestions {
+		return errors.New("too many questions")
+	}
+	for _, question := range x.Questions {
+		if question == nil || question.Question == "" {
+			return errors.New("question is empty")
+		}
+		if len(question.Question) > 256 || len(question.AnswerPattern) > 256 {
+			return errors.New("question is too long")
+		}
+		if _, err := regexp.Compile(question.AnswerPattern); err != nil {
+			return errors.New("invalid answerPattern " + question.AnswerPattern)
+		}
+	}
+	if x.ApproveByAnswers && len(x.Questions) == 0 {
+		return errors.New("approveByAnswers without questions")
+	}
+	if len(x.ExPattern) > 512 {
+		return errors.New("exPattern is too long")
+	}
+	if _, err := regexp.Compile(x.ExPattern); err != nil {
+		return errors.New("invalid exPattern " + x.ExPattern)
+	}
+	if x.MinFriendMembers < 0 {
+		return errors.New("minFriendMembers is negative")
+	}
+	return nil
+}
+
+type SetGroupJoinRuleResp struct{}
+
+type GetGroupJoinRuleReq struct {
+	GroupID string `json:"groupID"`
+}
+
+func (x *GetGroupJoinRuleReq) Check() error {
+	if x.GroupID == "" {
+		return errors.New("groupID is empty")
+	}
+	return nil
+}
+
+// GetGroupJoinRuleResp only the owner and the admins see the rules and the answer patterns,
+// the applicants get the questions alone.
+type GetGroupJoinRuleResp struct {
+	Rule *GroupJoinRule `json:"rule"`
+}
+
+// GroupExtClient is the client API for the GroupExt service.
+type GroupExtClient interface {
+	CreateGroupInviteLink(ctx context.Context, in *CreateGroupInviteLinkReq, opts ...grpc.CallOption) (*CreateGroupInviteLinkResp, error)
+	GetGroupInviteLinks(ctx context.Context, in *GetGroupInviteLinksReq, opts ...grpc.CallOption) (*GetGroupInviteLinksResp, error)
+	RevokeGroupInviteLink(ctx context.Context, in *RevokeGroupInviteLinkReq, opts ...grpc.CallOption) (*RevokeGroupInviteLinkResp, error)
+	RedeemGroupInviteLink(ctx context.Context, in *RedeemGroupInviteLinkReq, opts ...grpc.CallOption) (*RedeemGroupInviteLinkResp, error)
+	SetGroupRole(ctx context.Context, in *SetGroupRoleReq, opts ...grpc.CallOption) (*SetGroupRoleResp, error)
+	DeleteGroupRole(ctx context.Context, in *DeleteGroupRoleReq, opts ...grpc.CallOption) (*DeleteGroupRoleResp, error)
+	GetGroupRoles(ctx context.Context, in *GetGroupRolesReq, opts ...grpc.CallOption) (*GetGroupRolesResp, error)
+	GetGroupAuditLogs(ctx context.Context, in *GetGroupAuditLogsReq, opts ...grpc.CallOption) (*GetGroupAuditLogsResp, error)
+	SetGroupDirectory(ctx context.Context, in *SetGroupDirectoryReq, opts ...grpc.CallOption) (*SetGroupDirectoryResp, error)
+	SearchGroupDirectory(ctx context.Context, in *SearchGroupDirectoryReq, opts ...grpc.CallOption) (*SearchGroupDirectoryResp, error)
+	JoinDirectoryGroup(ctx context.Context, in *JoinDirectoryGroupReq, opts ...grpc.CallOption) (*JoinDirectoryGroupResp, error)
+	QuitAllGroups(ctx context.Context, in *QuitAllGroupsReq, opts ...grpc.CallOption) (*QuitAllGroupsResp, error)
+	ImportGroupMembers(ctx context.Context, in *ImportGroupMembersReq, opts ...grpc.CallOption) (*ImportGroupMembersResp, error)
+	ExportGroupMembers(ctx context.Context, in *ExportGroupMembersReq, opts ...grpc.CallOption) (*ExportGroupMembersResp, error)
+	CreateGroupAnnouncement(ctx context.Context, in *CreateGroupAnnouncementReq, opts ...grpc.CallOption) (*CreateGroupAnnouncementResp, error)
+	UpdateGroupAnnouncement(ctx context.Context, in *UpdateGroupAnnouncementReq, opts ...grpc.CallOption) (*UpdateGroupAnnouncementResp, error)
+	DeleteGroupAnnouncement(ctx context.Context, in *DeleteGroupAnnouncementReq, opts ...grpc.CallOption) (*DeleteGroupAnnouncementResp, error)
+	GetGroupAnnouncements(ctx context.Context, in *GetGroupAnnouncementsReq, opts ...grpc.CallOption) (*GetGroupAnnouncementsResp, error)
+	AckGroupAnnouncement(ctx context.Context, in *AckGroupAnnouncementReq, opts ...grpc.CallOption) (*AckGroupAnnouncementResp, error)
+	GetGroupAnnouncementUnreadMembers(ctx context.Context, in *GetGroupAnnouncementUnreadMembersReq, opts ...grpc.CallOption) (*GetGroupAnnouncementUnreadMembersResp, error)
+	SetGroupMemberTag(ctx context.Context, in *SetGroupMemberTagReq, opts ...grpc.CallOption) (*SetGroupMemberTagResp, error)
+	DeleteGroupMemberTag(ctx context.Context, in *DeleteGroupMemberTagReq, opts ...grpc.CallOption) (*DeleteGroupMemberTagResp, error)
+	GetGroupMemberTags(ctx context.Context, in *GetGroupMemberTagsReq, opts ...grpc.CallOption) (*GetGroupMemberTagsResp, error)
+	CreateGroupMuteSchedule(ctx context.Context, in *CreateGroupMuteScheduleReq, opts ...grpc.CallOption) (*CreateGroupMuteScheduleResp, error)
+	DeleteGroupMuteSchedule(ctx context.Context, in *DeleteGroupMuteScheduleReq, opts ...grpc.CallOption) (*DeleteGroupMuteScheduleResp, error)
+	GetGroupMuteSchedules(ctx context.Context, in *GetGroupMuteSchedulesReq, opts ...grpc.CallOption) (*GetGroupMuteSchedulesResp, error)
+	SetGroupJoinRule(ctx context.Context, in *SetGroupJoinRuleReq, opts ...grpc.CallOption) (*SetGroupJoinRuleResp, error)
+	GetGroupJoinRule(ctx context.Context, in *GetGroupJoinRuleReq, opts ...grpc.CallOption) (*GetGroupJoinRuleResp, error)
+}
+
+type groupExtClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewGroupExtClient(cc grpc.ClientConnInterface) GroupExtClient {
+	return &groupExtClient{cc}
+}
+
+func (c *groupExtClient) CreateGroupInviteLink(ctx context.Context, in *CreateGroupInviteLinkReq, opts ...grpc.CallOption) (*CreateGroupInviteLinkResp, error) {
+	return invoke[CreateGroupInviteLinkResp](ctx, c.cc, fullMethod(groupExtServiceName, "CreateGroupInviteLink"), in, opts...)
+}
+
+func (c *groupExtClient) GetGroupInviteLinks(ctx context.Context, in *GetGroupInviteLinksReq, opts ...grpc.CallOption) (*GetGroupInviteLinksResp, error) {
+	return invoke[GetGroupInviteLinksResp](ctx, c.cc, fullMethod(groupExtServiceName, "GetGroupInviteLinks"), in, opts...)
+}
+
+func (c *groupExtClient) RevokeGroupInviteLink(ctx context.Context, in *RevokeGroupInviteLinkReq, opts ...grpc.CallOption) (*RevokeGroupInviteLinkResp, error) {
+	return invoke[RevokeGroupInviteLinkResp](ctx, c.cc, fullMethod(groupExtServiceName, "RevokeGroupInviteLink"), in, opts...)
+}
+
+func (c *groupExtClient) RedeemGroupInviteLink(ctx context.Context, in *RedeemGroupInviteLinkReq, opts ...grpc.CallOption) (*RedeemGroupInviteLinkResp, error) {
+	return invoke[RedeemGroupInviteLinkResp](ctx, c.cc, fullMethod(groupExtServiceName, "RedeemGroupInviteLink"), in, opts...)
+}
+
+func (c *groupExtClient) SetGroupRole(ctx context.Context, in *SetGroupRoleReq, opts ...grpc.CallOption) (*SetGroupRoleResp, error) {
+	return invoke[SetGroupRoleResp](ctx, c.cc, fullMethod(groupExtServiceName, "SetGroupRole"), in, opts...)
+}
+
+func (c *groupExtClient) DeleteGroupRole(ctx context.Context, in *DeleteGroupRoleReq, opts ...grpc.CallOption) (*DeleteGroupRoleResp, error) {
+	return invoke[DeleteGroupRoleResp](ctx, c.cc, fullMethod(groupExtServiceName, "DeleteGroupRole"), in, opts...)
+}
+
+func (c *groupExtClient) GetGroupRoles(ctx context.Context, in *GetGroupRolesReq, opts ...grpc.CallOption) (*GetGroupRolesResp, error) {
+	return invoke[GetGroupRolesResp](ctx, c.cc, fullMethod(groupExtServiceName, "GetGroupRoles"), in, opts...)
+}
+
+func (c *groupExtClient) GetGroupAuditLogs(ctx context.Context, in *GetGroupAuditLogsReq, opts ...grpc.CallOption) (*GetGroupAuditLogsResp, error) {
+	return invoke[GetGroupAuditLogsResp](ctx, c.cc, fullMethod(groupExtServiceName, "GetGroupAuditLogs"), in, opts...)
+}
+
+func (c *groupExtClient) SetGroupDirectory(ctx context.Context, in *SetGroupDirectoryReq, opts ...grpc.CallOption) (*SetGroupDirectoryResp, error) {
+	return invoke[SetGroupDirectoryResp](ctx, c.cc, fullMethod(groupExtServiceName, "SetGroupDirectory"), in, opts...)
+}
+
+func (c *groupExtClient) SearchGroupDirectory(ctx context.Context, in *SearchGroupDirectoryReq, opts ...grpc.CallOption) (*SearchGroupDirectoryResp, error) {
+	return invoke[SearchGroupDirectoryResp](ctx, c.cc, fullMethod(groupExtServiceName, "SearchGroupDirectory"), in, opts...)
+}
+
+func (c *groupExtClient) JoinDirectoryGroup(ctx context.Context, in *JoinDirectoryGroupReq, opts ...grpc.CallOption) (*JoinDirectoryGroupResp, error) {
+	return invoke[JoinDirectoryGroupResp](ctx, c.cc, fullMethod(groupExtServiceName, "JoinDirectoryGroup"), in, opts...)
+}
+
+func (c *groupExtClient) QuitAllGroups(ctx context.Context, in *QuitAllGroupsReq, opts ...grpc.CallOption) (*QuitAllGroupsResp, error) {
+	return invoke[QuitAllGroupsResp](ctx, c.cc, fullMethod(groupExtServiceName, "QuitAllGroups"), in, opts...)
+}
+
+func (c *groupExtClient) ImportGroupMembers(ctx context.Context, in *ImportGroupMembersReq, opts ...grpc.CallOption) (*ImportGroupMembersResp, error) {
+	return invoke[ImportGroupMembersResp](ctx, c.cc, fullMethod(groupExtServiceName, "ImportGroupMembers"), in, opts...)
+}
+
+func (c *groupExtClient) ExportGroupMembers(ctx context.Context, in *ExportGroupMembersReq, opts ...grpc.CallOption) (*ExportGroupMembersResp, error) {
+	return invoke[ExportGroupMembersResp](ctx, c.cc, fullMethod(groupExtServiceName, "ExportGroupMembers"), in, opts...)
+}
+
+func (c *groupExtClient) CreateGroupAnnouncement(ctx context.Context, in *CreateGroupAnnouncementReq, opts ...grpc.CallOption) (*CreateGroupAnnouncementResp, error) {
+	return invoke[CreateGroupAnnouncementResp](ctx, c.cc, fullMethod(groupExtServiceName, "CreateGroupAnnouncement"), in, opts...)
+}
+
+func (c *groupExtClient) UpdateGroupAnnouncement(ctx context.Context, in *UpdateGroupAnnouncementReq, opts ...grpc.CallOption) (*UpdateGroupAnnouncementResp, error) {
+	return invoke[UpdateGroupAnnouncementResp](ctx, c.cc, fullMethod(groupExtServiceName, "UpdateGroupAnnouncement"), in, opts...)
+}
+
+func (c *groupExtClient) DeleteGroupAnnouncement(ctx context.Context, in *DeleteGroupAnnouncementReq, opts ...grpc.CallOption) (*DeleteGroupAnnouncementResp, error) {
+	return invoke[DeleteGroupAnnouncementResp](ctx, c.cc, fullMethod(groupExtServiceName, "DeleteGroupAnnouncement"), in, opts...)
+}
+
+func (c *groupExtClient) GetGroupAnnouncements(ctx context.Context, in *GetGroupAnnouncementsReq, opts ...grpc.CallOption) (*GetGroupAnnouncementsResp, error) {
+	return invoke[GetGroupAnnouncementsResp](ctx, c.cc, fullMethod(groupExtServiceName, "GetGroupAnnouncements"), in, opts...)
+}
+
+func (c *groupExtClient) AckGroupAnnouncement(ctx context.Context, in *AckGroupAnnouncementReq, opts ...grpc.CallOption) (*AckGroupAnnouncementResp, error) {
+	return invoke[AckGroupAnnouncementResp](ctx, c.cc, fullMethod(groupExtServiceName, "AckGroupAnnouncement"), in, opts...)
+}
+
+func (c *groupExtClient) GetGroupAnnouncementUnreadMembers(ctx context.Context, in *GetGroupAnnouncementUnreadMembersReq, opts ...grpc.CallOption) (*GetGroupAnnouncementUnreadMembersResp, error) {
+	return invoke[GetGroupAnnouncementUnreadMembersResp](ctx, c.cc, fullMethod(groupExtServiceName, "GetGroupAnnouncementUnreadMembers"), in, opts...)
+}
+
+func (c *groupExtClient) SetGroupMemberTag(ctx context.Context, in *SetGroupMemberTagReq, opts ...grpc.CallOption) (*SetGroupMemberTagResp, error) {
+	return invoke[SetGroupMemberTagResp](ctx, c.cc, fullMethod(groupExtServiceName, "SetGroupMemberTag"), in, opts...)
+}
+
+func (c *groupExtClient) DeleteGroupMemberTag(ctx context.Context, in *DeleteGroupMemberTagReq, opts ...grpc.CallOption) (*DeleteGroupMemberTagResp, error) {
+	return invoke[DeleteGroupMemberTagResp](ctx, c.cc, fullMethod(groupExtServiceName, "DeleteGroupMemberTag"), in, opts...)
+}
+
+func (c *groupExtClient) GetGroupMemberTags(ctx context.Context, in *GetGroupMemberTagsReq, opts ...grpc.CallOption) (*GetGroupMemberTagsResp, error) {
+	return invoke[GetGroupMemberTagsResp](ctx, c.cc, fullMethod(groupExtServiceName, "GetGroupMemberTags"), in, opts...)
+}
+
+func (c *groupExtClient) CreateGroupMuteSchedule(ctx context.Context, in *CreateGroupMuteScheduleReq, opts ...grpc.CallOption) (*CreateGroupMuteScheduleResp, error) {
+	return invoke[CreateGroupMuteScheduleResp](ctx, c.cc, fullMethod(groupExtServiceName, "CreateGroupMuteSchedule"), in, opts...)
+}
+
+func (c *groupExtClient) DeleteGroupMuteSchedule(ctx context.Context, in *DeleteGroupMuteScheduleReq, opts ...grpc.CallOption) (*DeleteGroupMuteScheduleResp, error) {
+	return invoke[DeleteGroupMuteScheduleResp](ctx, c.cc, fullMethod(groupExtServiceName, "DeleteGroupMuteSchedule"), in, opts...)
+}
+
+func (c *groupExtClient) GetGroupMuteSchedules(ctx context.Context, in *GetGroupMuteSchedulesReq, opts ...grpc.CallOption) (*GetGroupMuteSchedulesResp, error) {
+	return invoke[GetGroupMuteSchedulesResp](ctx, c.cc, fullMethod(groupExtServiceName, "GetGroupMuteSchedules"), in, opts...)
+}
+
+func (c *groupExtClient) SetGroupJoinRule(ctx context.Context, in *SetGroupJoinRuleReq, opts ...grpc.CallOption) (*SetGroupJoinRuleResp, error) {
+	return invoke[SetGroupJoinRuleResp](ctx, c.cc, fullMethod(groupExtServiceName, "SetGroupJoinRule"), in, opts...)
+}
+
+func (c *groupExtClient) GetGroupJoinRule(ctx context.Context, in *GetGroupJoinRuleReq, opts ...grpc.CallOption) (*GetGroupJoinRuleResp, error) {
+	return invoke[GetGroupJoinRuleResp](ctx, c.cc, fullMethod(groupExtServiceName, "GetGroupJoinRule"), in, opts...)
+}
+
+// GroupExtServer is the server API for the GroupExt service.
+type GroupExtServer interface {
+	CreateGroupInviteLink(context.Context, *CreateGroupInviteLinkReq) (*CreateGroupInviteLinkResp, error)
+	GetGroupInviteLinks(context.Context, *GetGroupInviteLinksReq) (*GetGroupInviteLinksResp, error)
+	RevokeGroupInviteLink(context.Context, *RevokeGroupInviteLinkReq) (*RevokeGroupInviteLinkResp, error)
+	RedeemGroupInviteLink(context.Context, *RedeemGroupInviteLinkReq) (*RedeemGroupInviteLinkResp, error)
+	SetGroupRole(context.Context, *SetGroupRoleReq) (*SetGroupRoleResp, error)
+	DeleteGroupRole(context.Context, *DeleteGroupRoleReq) (*DeleteGroupRoleResp, error)
+	GetGroupRoles(context.Context, *GetGroupRolesReq) (*GetGroupRolesResp, error)
+	GetGroupAuditLogs(context.Context, *GetGroupAuditLogsReq) (*GetGroupAuditLogsResp, error)
+	SetGroupDirectory(context.Context, *SetGroupDirectoryReq) (*SetGroupDirectoryResp, error)
+	SearchGroupDirectory(context.Context, *SearchGroupDirectoryReq) (*SearchGroupDirectoryResp, error)
+	JoinDirectoryGroup(context.Context, *JoinDirectoryGroupReq) (*JoinDirectoryGroupResp, error)
+	QuitAllGroups(context.Context, *QuitAllGroupsReq) (*QuitAllGroupsResp, error)
+	ImportGroupMembers(context.Context, *ImportGroupMembersReq) (*ImportGroupMembersResp, error)
+	ExportGroupMembers(context.Context, *ExportGroupMembersReq) (*ExportGroupMembersResp, error)
+	CreateGroupAnnouncement(context.Context, *CreateGroupAnnouncementReq) (*CreateGroupAnnouncementResp, error)
+	UpdateGroupAnnouncement(context.Context, *UpdateGroupAnnouncementReq) (*UpdateGroupAnnouncementResp, error)
+	DeleteGroupAnnouncement(context.Context, *DeleteGroupAnnouncementReq) (*DeleteGroupAnnouncementResp, error)
+	GetGroupAnnouncements(context.Context, *GetGroupAnnouncementsReq) (*GetGroupAnnouncementsResp, error)
+	AckGroupAnnouncement(context.Context, *AckGroupAnnouncementReq) (*AckGroupAnnouncementResp, error)
+	GetGroupAnnouncementUnreadMembers(context.Context, *GetGroupAnnouncementUnreadMembersReq) (*GetGroupAnnouncementUnreadMembersResp, error)
+	SetGroupMemberTag(context.Context, *SetGroupMemberTagReq) (*SetGroupMemberTagResp, error)
+	DeleteGroupMemberTag(context.Context, *DeleteGroupMemberTagReq) (*DeleteGroupMemberTagResp, error)
+	GetGroupMemberTags(context.Context, *GetGroupMemberTagsReq) (*GetGroupMemberTagsResp, error)
+	CreateGroupMuteSchedule(context.Context, *CreateGroupMuteScheduleReq) (*CreateGroupMuteScheduleResp, error)
+	DeleteGroupMuteSchedule(context.Context, *DeleteGroupMuteScheduleReq) (*DeleteGroupMuteScheduleResp, error)
+	GetGroupMuteSchedules(context.Context, *GetGroupMuteSchedulesReq) (*GetGroupMuteSchedulesResp, error)
+	SetGroupJoinRule(context.Context, *SetGroupJoinRuleReq) (*SetGroupJoinRuleResp, error)
+	GetGroupJoinRule(context.Context, *GetGroupJoinRuleReq) (*GetGroupJoinRuleResp, error)
+}
+
+func RegisterGroupExtServer(s *grpc.Server, srv GroupExtServer) {
+	s.RegisterService(&grpc.ServiceDesc{
+		ServiceName: groupExtServiceName,
+		HandlerType: (*GroupExtServer)(nil),
+		Methods: []grpc.MethodDesc{
+			unaryMethod(groupExtServiceName, "CreateGroupInviteLink", GroupExtServer.CreateGroupInviteLink),
+			unaryMethod(groupExtServiceName, "GetGroupInviteLinks", GroupExtServer.GetGroupInviteLinks),
+			unaryMethod(groupExtServiceName, "RevokeGroupInviteLink", GroupExtServer.RevokeGroupInviteLink),
+			unaryMethod(groupExtServiceName, "RedeemGroupInviteLink", GroupExtServer.RedeemGroupInviteLink),
+			unaryMethod(groupExtServiceName, "SetGroupRole", GroupExtServer.SetGroupRole),
+			unaryMethod(groupExtServiceName, "DeleteGroupRole", GroupExtServer.DeleteGroupRole),
+			unaryMethod(groupExtServiceName, "GetGroupRoles", GroupExtServer.GetGroupRoles),
+			unaryMethod(groupExtServiceName, "GetGroupAuditLogs", GroupExtServer.GetGroupAuditLogs),
+			unaryMethod(groupExtServiceName, "SetGroupDirectory", GroupExtServer.SetGroupDirectory),
+			unaryMethod(groupExtServiceName, "SearchGroupDirectory", GroupExtServer.SearchGroupDirectory),
+			unaryMethod(groupExtServiceName, "JoinDirectoryGroup", GroupExtServer.JoinDirectoryGroup),
+			unaryMethod(groupExtServiceName, "QuitAllGroups", GroupExtServer.QuitAllGroups),
+			unaryMethod(groupExtServiceName, "ImportGroupMembers", GroupExtServer.ImportGroupMembers),
+			unaryMethod(groupExtServiceName, "ExportGroupMembers", GroupExtServer.ExportGroupMembers),
+			unaryMethod(groupExtServiceName, "CreateGroupAnnouncement", GroupExtServer.CreateGroupAnnouncement),
+			unaryMethod(groupExtServiceName, "UpdateGroupAnnouncement", GroupExtServer.UpdateGroupAnnouncement),
+			unaryMethod(groupExtServiceName, "DeleteGroupAnnouncement", GroupExtServer.DeleteGroupAnnouncement),
+			unaryMethod(groupExtServiceName, "GetGroupAnnouncements", GroupExtServer.GetGroupAnnouncements),
+			unaryMethod(groupExtServiceName, "AckGroupAnnouncement", GroupExtServer.AckGroupAnnouncement),
+			unaryMethod(groupExtServiceName, "GetGroupAnnouncementUnreadMembers", GroupExtServer.GetGroupAnnouncementUnreadMembers),
+			unaryMethod(groupExtServiceName, "SetGroupMemberTag", GroupExtServer.SetGroupMemberTag),
+			unaryMethod(groupExtServiceName, "DeleteGroupMemberTag", GroupExtServer.DeleteGroupMemberTag),
+			unaryMethod(groupExtServiceName, "GetGroupMemberTags", GroupExtServer.GetGroupMemberTags),
+			unaryMethod(groupExtServiceName, "CreateGroupMuteSchedule", GroupExtServer.CreateGroupMuteSchedule),
+			unaryMethod(groupExtServiceName, "DeleteGroupMuteSchedule", GroupExtServer.DeleteGroupMuteSchedule),
+			unaryMethod(groupExtServiceName, "GetGroupMuteSchedules", GroupExtServer.GetGroupMuteSchedules),
+			unaryMethod(groupExtServiceName, "SetGroupJoinRule", GroupExtServer.SetGroupJoinRule),
+			unaryMethod(groupExtServiceName, "GetGroupJoinRule", GroupExtServer.GetGroupJoinRule),
+		},
+	}, srv)
+}
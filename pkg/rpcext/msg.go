@@ -0,0 +1,401 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcext
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc"
+
+	"github.com/OpenIMSDK/protocol/sdkws"
+)
+
+const msgExtServiceName = "OpenIMServer.msg.MsgExt"
+
+const (
+	ExportFormatJSONL = "jsonl"
+	ExportFormatCSV   = "csv"
+	ExportFormatHTML  = "html"
+)
+
+const (
+	ExportJobRunning   = "running"
+	ExportJobSucceeded = "succeeded"
+	ExportJobFailed    = "failed"
+)
+
+// CreateExportJobReq exports the messages of the conversation UserID sees, sent between StartTime and EndTime
+// in unix milliseconds, an EndTime of 0 means now.
+type CreateExportJobReq struct {
+	UserID         string `json:"userID"`
+	ConversationID string `json:"conversationID"`
+	StartTime      int64  `json:"startTime"`
+	EndTime        int64  `json:"endTime"`
+	Format         string `json:"format"`
+}
+
+func (x *CreateExportJobReq) Check() error {
+	if x.UserID == "" {
+		return errors.New("userID is empty")
+	}
+	if x.ConversationID == "" {
+		return errors.New("conversationID is empty")
+	}
+	if x.EndTime != 0 && x.StartTime > x.EndTime {
+		return errors.New("startTime is after endTime")
+	}
+	switch x.Format {
+	case ExportFormatJSONL, ExportFormatCSV, ExportFormatHTML:
+	default:
+		return errors.New("format must be jsonl, csv or html")
+	}
+	return nil
+}
+
+type CreateExportJobResp struct {
+	JobID string `json:"jobID"`
+}
+
+type GetExportJobReq struct {
+	JobID string `json:"jobID"`
+}
+
+func (x *GetExportJobReq) Check() error {
+	if x.JobID == "" {
+		return errors.New("jobID is empty")
+	}
+	return nil
+}
+
+// ExportJob Progress goes from 0 to 100, URL and ExpireTime are set once the job succeeded.
+type ExportJob struct {
+	JobID          string `json:"jobID"`
+	UserID         string `json:"userID"`
+	ConversationID string `json:"conversationID"`
+	Format         string `json:"format"`
+	Status         string `json:"status"`
+	Progress       int32  `json:"progress"`
+	MsgNum         int64  `json:"msgNum"`
+	URL            string `json:"url"`
+	ExpireTime     int64  `json:"expireTime"`
+	ErrMsg         string `json:"errMsg"`
+	CreateTime     int64  `json:"createTime"`
+}
+
+type GetExportJobResp struct {
+	Job *ExportJob `json:"job"`
+}
+
+const (
+	LegalHoldTargetUser         = 1
+	LegalHoldTargetConversation = 2
+)
+
+func checkLegalHoldTarget(targetType int32, targetID string) error {
+	if targetType != LegalHoldTargetUser && targetType != LegalHoldTargetConversation {
+		return errors.New("targetType must be 1(user) or 2(conversation)")
+	}
+	if targetID == "" {
+		return errors.New("targetID is empty")
+	}
+	return nil
+}
+
+// LegalHold while a hold is active the held messages are not deleted, a user hold covers all conversations of the user.
+type LegalHold struct {
+	TargetType     int32  `json:"targetType"`
+	TargetID       string `json:"targetID"`
+	Reason         string `json:"reason"`
+	OperatorUserID string `json:"operatorUserID"`
+	CreateTime     int64  `json:"createTime"`
+}
+
+// LegalHoldLog Action is set or release.
+type LegalHoldLog struct {
+	ID             int64  `json:"id"`
+	TargetType     int32  `json:"targetType"`
+	TargetID       string `json:"targetID"`
+	Action         string `json:"action"`
+	Reason         string `json:"reason"`
+	OperatorUserID string `json:"operatorUserID"`
+	CreateTime     int64  `json:"createTime"`
+}
+
+type SetLegalHoldReq struct {
+	TargetType int32  `json:"targetType"`
+	TargetID   string `json:"targetID"`
+	Reason     string `json:"reason"`
+}
+
+func (x *SetLegalHoldReq) Check() error {
+	if err := checkLegalHoldTarget(x.TargetType, x.TargetID); err != nil {
+		return err
+	}
+	if x.Reason == "" {
+		return errors.New("reason is empty")
+	}
+	return nil
+}
+
+type SetLegalHoldResp struct{}
+
+type ReleaseLegalHoldReq struct {
+	TargetType int32  `json:"targetType"`
+	TargetID   string `json:"targetID"`
+	Reason     string `json:"reason"`
+}
+
+func (x *ReleaseLegalHoldReq) Check() error {
+	if err := checkLegalHoldTarget(x.TargetType, x.TargetID); err != nil {
+		return err
+	}
+	if x.Reason == "" {
+		return errors.New("reason is empty")
+	}
+	return nil
+}
+
+type ReleaseLegalHoldResp struct{}
+
+// SearchLegalHoldsReq TargetType 0 means all types.
+type SearchLegalHoldsReq struct {
+	TargetType int32                    `json:"targetType"`
+	Pagination *sdkws.RequestPagination `json:"pagination"`
+}
+
+func (x *SearchLegalHoldsReq) Check() error {
+	if x.Pagination == nil {
+		return errors.New("pagination is empty")
+	}
+	return nil
+}
+
+type SearchLegalHoldsResp struct {
+	Total int64        `json:"total"`
+	Holds []*LegalHold `json:"holds"`
+}
+
+// SearchLegalHoldLogsReq TargetType 0 and an empty TargetID mean all targets.
+type SearchLegalHoldLogsReq struct {
+	TargetType int32                    `json:"targetType"`
+	TargetID   string                   `json:"targetID"`
+	Pagination *sdkws.RequestPagination `json:"pagination"`
+}
+
+func (x *SearchLegalHoldLogsReq) Check() error {
+	if x.Pagination == nil {
+		return errors.New("pagination is empty")
+	}
+	return nil
+}
+
+type SearchLegalHoldLogsResp struct {
+	Total int64           `json:"total"`
+	Logs  []*LegalHoldLog `json:"logs"`
+}
+
+const (
+	RetentionScopeConversation     = 1
+	RetentionScopeGroup            = 2
+	RetentionScopeConversationType = 3
+)
+
+// RetentionPolicy messages older than RetainDays are deleted, 0 keeps them forever.
+// ScopeID is a conversation id, a group id or a session type such as "4" for notification conversations.
+type RetentionPolicy struct {
+	ScopeType      int32  `json:"scopeType"`
+	ScopeID        string `json:"scopeID"`
+	RetainDays     int32  `json:"retainDays"`
+	OperatorUserID string `json:"operatorUserID"`
+	UpdateTime     int64  `json:"updateTime"`
+}
+
+func checkRetentionScope(scopeType int32, scopeID string) error {
+	switch scopeType {
+	case RetentionScopeConversation, RetentionScopeGroup:
+		if scopeID == "" {
+			return errors.New("scopeID is empty")
+		}
+	case RetentionScopeConversationType:
+		switch scopeID {
+		case "1", "2", "3", "4":
+		default:
+			return errors.New("scopeID of a conversation type scope must be a session type 1-4")
+		}
+	default:
+		return errors.New("scopeType must be 1(conversation), 2(group) or 3(conversation type)")
+	}
+	return nil
+}
+
+type SetRetentionPolicyReq struct {
+	ScopeType  int32  `json:"scopeType"`
+	ScopeID    string `json:"scopeID"`
+	RetainDays int32  `json:"retainDays"`
+}
+
+func (x *SetRetentionPolicyReq) Check() error {
+	if err := checkRetentionScope(x.ScopeType, x.ScopeID); err != nil {
+		return err
+	}
+	if x.RetainDays < 0 {
+		return errors.New("retainDays is negative")
+	}
+	return nil
+}
+
+type SetRetentionPolicyResp struct{}
+
+type DeleteRetentionPolicyReq struct {
+	ScopeType int32  `json:"scopeType"`
+	ScopeID   string `json:"scopeID"`
+}
+
+func (x *DeleteRetentionPolicyReq) Check() error {
+	return checkRetentionScope(x.ScopeType, x.ScopeID)
+}
+
+type DeleteRetentionPolicyResp struct{}
+
+// SearchRetentionPoliciesReq ScopeType 0 means all types.
+type SearchRetentionPoliciesReq struct {
+	ScopeType  int32                    `json:"scopeType"`
+	Pagination *sdkws.RequestPagination `json:"pagination"`
+}
+
+func (x *SearchRetentionPoliciesReq) Check() error {
+	if x.Pagination == nil {
+		return errors.New("pagination is empty")
+	}
+	return nil
+}
+
+type SearchRetentionPoliciesResp struct {
+	Total    int64              `json:"total"`
+	Policies []*RetentionPolicy `json:"policies"`
+}
+
+type GetEffectiveRetentionPolicyReq struct {
+	ConversationID string `json:"conversationID"`
+}
+
+func (x *GetEffectiveRetentionPolicyReq) Check() error {
+	if x.ConversationID == "" {
+		return errors.New("conversationID is empty")
+	}
+	return nil
+}
+
+// GetEffectiveRetentionPolicyResp Policy is nil when the global DefaultRetainDays applies.
+type GetEffectiveRetentionPolicyResp struct {
+	Policy            *RetentionPolicy `json:"policy"`
+	DefaultRetainDays int32            `json:"defaultRetainDays"`
+}
+
+// MsgExtClient is the client API for the MsgExt service.
+type MsgExtClient interface {
+	CreateExportJob(ctx context.Context, in *CreateExportJobReq, opts ...grpc.CallOption) (*CreateExportJobResp, error)
+	GetExportJob(ctx context.Context, in *GetExportJobReq, opts ...grpc.CallOption) (*GetExportJobResp, error)
+	SetLegalHold(ctx context.Context, in *SetLegalHoldReq, opts ...grpc.CallOption) (*SetLegalHoldResp, error)
+	ReleaseLegalHold(ctx context.Context, in *ReleaseLegalHoldReq, opts ...grpc.CallOption) (*ReleaseLegalHoldResp, error)
+	SearchLegalHolds(ctx context.Context, in *SearchLegalHoldsReq, opts ...grpc.CallOption) (*SearchLegalHoldsResp, error)
+	SearchLegalHoldLogs(ctx context.Context, in *SearchLegalHoldLogsReq, opts ...grpc.CallOption) (*SearchLegalHoldLogsResp, error)
+	SetRetentionPolicy(ctx context.Context, in *SetRetentionPolicyReq, opts ...grpc.CallOption) (*SetRetentionPolicyResp, error)
+	DeleteRetentionPolicy(ctx context.Context, in *DeleteRetentionPolicyReq, opts ...grpc.CallOption) (*DeleteRetentionPolicyResp, error)
+	SearchRetentionPolicies(ctx context.Context, in *SearchRetentionPoliciesReq, opts ...grpc.CallOption) (*SearchRetentionPoliciesResp, error)
+	GetEffectiveRetentionPolicy(ctx context.Context, in *GetEffectiveRetentionPolicyReq, opts ...grpc.CallOption) (*GetEffectiveRetentionPolicyResp, error)
+}
+
+type msgExtClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMsgExtClient(cc grpc.ClientConnInterface) MsgExtClient {
+	return &msgExtClient{cc}
+}
+
+func (c *msgExtClient) CreateExportJob(ctx context.Context, in *CreateExportJobReq, opts ...grpc.CallOption) (*CreateExportJobResp, error) {
+	return invoke[CreateExportJobResp](ctx, c.cc, fullMethod(msgExtServiceName, "CreateExportJob"), in, opts...)
+}
+
+func (c *msgExtClient) GetExportJob(ctx context.Context, in *GetExportJobReq, opts ...grpc.CallOption) (*GetExportJobResp, error) {
+	return invoke[GetExportJobResp](ctx, c.cc, fullMethod(msgExtServiceName, "GetExportJob"), in, opts...)
+}
+
+func (c *msgExtClient) SetLegalHold(ctx context.Context, in *SetLegalHoldReq, opts ...grpc.CallOption) (*SetLegalHoldResp, error) {
+	return invoke[SetLegalHoldResp](ctx, c.cc, fullMethod(msgExtServiceName, "SetLegalHold"), in, opts...)
+}
+
+func (c *msgExtClient) ReleaseLegalHold(ctx context.Context, in *ReleaseLegalHoldReq, opts ...grpc.CallOption) (*ReleaseLegalHoldResp, error) {
+	return invoke[ReleaseLegalHoldResp](ctx, c.cc, fullMethod(msgExtServiceName, "ReleaseLegalHold"), in, opts...)
+}
+
+func (c *msgExtClient) SearchLegalHolds(ctx context.Context, in *SearchLegalHoldsReq, opts ...grpc.CallOption) (*SearchLegalHoldsResp, error) {
+	return invoke[SearchLegalHoldsResp](ctx, c.cc, fullMethod(msgExtServiceName, "SearchLegalHolds"), in, opts...)
+}
+
+func (c *msgExtClient) SearchLegalHoldLogs(ctx context.Context, in *SearchLegalHoldLogsReq, opts ...grpc.CallOption) (*SearchLegalHoldLogsResp, error) {
+	return invoke[SearchLegalHoldLogsResp](ctx, c.cc, fullMethod(msgExtServiceName, "SearchLegalHoldLogs"), in, opts...)
+}
+
+func (c *msgExtClient) SetRetentionPolicy(ctx context.Context, in *SetRetentionPolicyReq, opts ...grpc.CallOption) (*SetRetentionPolicyResp, error) {
+	return invoke[SetRetentionPolicyResp](ctx, c.cc, fullMethod(msgExtServiceName, "SetRetentionPolicy"), in, opts...)
+}
+
+func (c *msgExtClient) DeleteRetentionPolicy(ctx context.Context, in *DeleteRetentionPolicyReq, opts ...grpc.CallOption) (*DeleteRetentionPolicyResp, error) {
+	return invoke[DeleteRetentionPolicyResp](ctx, c.cc, fullMethod(msgExtServiceName, "DeleteRetentionPolicy"), in, opts...)
+}
+
+func (c *msgExtClient) SearchRetentionPolicies(ctx context.Context, in *SearchRetentionPoliciesReq, opts ...grpc.CallOption) (*SearchRetentionPoliciesResp, error) {
+	return invoke[SearchRetentionPoliciesResp](ctx, c.cc, fullMethod(msgExtServiceName, "SearchRetentionPolicies"), in, opts...)
+}
+
+func (c *msgExtClient) GetEffectiveRetentionPolicy(ctx context.Context, in *GetEffectiveRetentionPolicyReq, opts ...grpc.CallOption) (*GetEffectiveRetentionPolicyResp, error) {
+	return invoke[GetEffectiveRetentionPolicyResp](ctx, c.cc, fullMethod(msgExtServiceName, "GetEffectiveRetentionPolicy"), in, opts...)
+}
+
+// MsgExtServer is the server API for the MsgExt service.
+type MsgExtServer interface {
+	CreateExportJob(context.Context, *CreateExportJobReq) (*CreateExportJobResp, error)
+	GetExportJob(context.Context, *GetExportJobReq) (*GetExportJobResp, error)
+	SetLegalHold(context.Context, *SetLegalHoldReq) (*SetLegalHoldResp, error)
+	ReleaseLegalHold(context.Context, *ReleaseLegalHoldReq) (*ReleaseLegalHoldResp, error)
+	SearchLegalHolds(context.Context, *SearchLegalHoldsReq) (*SearchLegalHoldsResp, error)
+	SearchLegalHoldLogs(context.Context, *SearchLegalHoldLogsReq) (*SearchLegalHoldLogsResp, error)
+	SetRetentionPolicy(context.Context, *SetRetentionPolicyReq) (*SetRetentionPolicyResp, error)
+	DeleteRetentionPolicy(context.Context, *DeleteRetentionPolicyReq) (*DeleteRetentionPolicyResp, error)
+	SearchRetentionPolicies(context.Context, *SearchRetentionPoliciesReq) (*SearchRetentionPoliciesResp, error)
+	GetEffectiveRetentionPolicy(context.Context, *GetEffectiveRetentionPolicyReq) (*GetEffectiveRetentionPolicyResp, error)
+}
+
+func RegisterMsgExtServer(s *grpc.Server, srv MsgExtServer) {
+	s.RegisterService(&grpc.ServiceDesc{
+		ServiceName: msgExtServiceName,
+		HandlerType: (*MsgExtServer)(nil),
+		Methods: []grpc.MethodDesc{
+			unaryMethod(msgExtServiceName, "CreateExportJob", MsgExtServer.CreateExportJob),
+			unaryMethod(msgExtServiceName, "GetExportJob", MsgExtServer.GetExportJob),
+			unaryMethod(msgExtServiceName, "SetLegalHold", MsgExtServer.SetLegalHold),
+			unaryMethod(msgExtServiceName, "ReleaseLegalHold", MsgExtServer.ReleaseLegalHold),
+			unaryMethod(msgExtServiceName, "SearchLegalHolds", MsgExtServer.SearchLegalHolds),
+			unaryMethod(msgExtServiceName, "SearchLegalHoldLogs", MsgExtServer.SearchLegalHoldLogs),
+			unaryMethod(msgExtServiceName, "SetRetentionPolicy", MsgExtServer.SetRetentionPolicy),
+			unaryMethod(msgExtServiceName, "DeleteRetentionPolicy", MsgExtServer.DeleteRetentionPolicy),
+			unaryMethod(msgExtServiceName, "SearchRetentionPolicies", MsgExtServer.SearchRetentionPolicies),
+			unaryMethod(msgExtServiceName, "GetEffectiveRetentionPolicy", MsgExtServer.GetEffectiveRetentionPolicy),
+		},
+	}, srv)
+}
@@ -0,0 +1,97 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcext
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc"
+
+	"github.com/OpenIMSDK/protocol/sdkws"
+)
+
+const pushExtServiceName = "OpenIMServer.push.PushExt"
+
+// PushRecord one offline push attempt, Code is 0 when the vendor accepted the push.
+type PushRecord struct {
+	Vendor      string `json:"vendor"`
+	UserID      string `json:"userID"`
+	ClientMsgID string `json:"clientMsgID"`
+	Code        int    `json:"code"`
+	ErrMsg      string `json:"errMsg"`
+	// Latency milliseconds the vendor call took
+	Latency    int64 `json:"latency"`
+	CreateTime int64 `json:"createTime"`
+}
+
+// SearchPushRecordsReq StartTime and EndTime are unix milliseconds, an EndTime of 0 means now.
+type SearchPushRecordsReq struct {
+	UserID      string                   `json:"userID"`
+	ClientMsgID string                   `json:"clientMsgID"`
+	StartTime   int64                    `json:"startTime"`
+	EndTime     int64                    `json:"endTime"`
+	Pagination  *sdkws.RequestPagination `json:"pagination"`
+}
+
+func (x *SearchPushRecordsReq) Check() error {
+	if x.UserID == "" && x.ClientMsgID == "" {
+		return errors.New("userID and clientMsgID are empty")
+	}
+	if x.EndTime != 0 && x.StartTime > x.EndTime {
+		return errors.New("startTime is after endTime")
+	}
+	if x.Pagination == nil {
+		return errors.New("pagination is empty")
+	}
+	return nil
+}
+
+type SearchPushRecordsResp struct {
+	Total   int64         `json:"total"`
+	Records []*PushRecord `json:"records"`
+}
+
+// PushExtClient is the client API for the PushExt service.
+type PushExtClient interface {
+	SearchPushRecords(ctx context.Context, in *SearchPushRecordsReq, opts ...grpc.CallOption) (*SearchPushRecordsResp, error)
+}
+
+type pushExtClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPushExtClient(cc grpc.ClientConnInterface) PushExtClient {
+	return &pushExtClient{cc}
+}
+
+func (c *pushExtClient) SearchPushRecords(ctx context.Context, in *SearchPushRecordsReq, opts ...grpc.CallOption) (*SearchPushRecordsResp, error) {
+	return invoke[SearchPushRecordsResp](ctx, c.cc, fullMethod(pushExtServiceName, "SearchPushRecords"), in, opts...)
+}
+
+// PushExtServer is the server API for the PushExt service.
+type PushExtServer interface {
+	SearchPushRecords(context.Context, *SearchPushRecordsReq) (*SearchPushRecordsResp, error)
+}
+
+func RegisterPushExtServer(s *grpc.Server, srv PushExtServer) {
+	s.RegisterService(&grpc.ServiceDesc{
+		ServiceName: pushExtServiceName,
+		HandlerType: (*PushExtServer)(nil),
+		Methods: []grpc.MethodDesc{
+			unaryMethod(pushExtServiceName, "SearchPushRecords", PushExtServer.SearchPushRecords),
+		},
+	}, srv)
+}
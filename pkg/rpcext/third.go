@@ -0,0 +1,77 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcext
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc"
+)
+
+const thirdExtServiceName = "OpenIMServer.third.ThirdExt"
+
+// VoipUpdateTokenReq registers the call push token of a device, a PushKit VoIP token on iOS
+// and the FCM token on Android. ExpireTime is in seconds.
+type VoipUpdateTokenReq struct {
+	Account    string `json:"account"`
+	PlatformID int32  `json:"platformID"`
+	VoipToken  string `json:"voipToken"`
+	ExpireTime int64  `json:"expireTime"`
+}
+
+func (x *VoipUpdateTokenReq) Check() error {
+	if x.Account == "" {
+		return errors.New("account is empty")
+	}
+	if x.VoipToken == "" {
+		return errors.New("voipToken is empty")
+	}
+	return nil
+}
+
+type VoipUpdateTokenResp struct{}
+
+// ThirdExtClient is the client API for the ThirdExt service.
+type ThirdExtClient interface {
+	VoipUpdateToken(ctx context.Context, in *VoipUpdateTokenReq, opts ...grpc.CallOption) (*VoipUpdateTokenResp, error)
+}
+
+type thirdExtClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewThirdExtClient(cc grpc.ClientConnInterface) ThirdExtClient {
+	return &thirdExtClient{cc}
+}
+
+func (c *thirdExtClient) VoipUpdateToken(ctx context.Context, in *VoipUpdateTokenReq, opts ...grpc.CallOption) (*VoipUpdateTokenResp, error) {
+	return invoke[VoipUpdateTokenResp](ctx, c.cc, fullMethod(thirdExtServiceName, "VoipUpdateToken"), in, opts...)
+}
+
+// ThirdExtServer is the server API for the ThirdExt service.
+type ThirdExtServer interface {
+	VoipUpdateToken(context.Context, *VoipUpdateTokenReq) (*VoipUpdateTokenResp, error)
+}
+
+func RegisterThirdExtServer(s *grpc.Server, srv ThirdExtServer) {
+	s.RegisterService(&grpc.ServiceDesc{
+		ServiceName: thirdExtServiceName,
+		HandlerType: (*ThirdExtServer)(nil),
+		Methods: []grpc.MethodDesc{
+			unaryMethod(thirdExtServiceName, "VoipUpdateToken", ThirdExtServer.VoipUpdateToken),
+		},
+	}, srv)
+}
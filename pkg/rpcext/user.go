@@ -0,0 +1,156 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcext
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+const userExtServiceName = "OpenIMServer.user.UserExt"
+
+// DoNotDisturb daily quiet hours of a user, StartTime and EndTime are "HH:MM" in TimeZone (IANA name, empty means UTC).
+// A window whose StartTime is after its EndTime spans midnight.
+type DoNotDisturb struct {
+	UserID    string `json:"userID"`
+	Enable    bool   `json:"enable"`
+	StartTime string `json:"startTime"`
+	EndTime   string `json:"endTime"`
+	TimeZone  string `json:"timeZone"`
+}
+
+// InQuietHours reports whether t falls within the quiet hours.
+func (x *DoNotDisturb) InQuietHours(t time.Time) bool {
+	if x == nil || !x.Enable {
+		return false
+	}
+	start, err := parseClock(x.StartTime)
+	if err != nil {
+		return false
+	}
+	end, err := parseClock(x.EndTime)
+	if err != nil {
+		return false
+	}
+	loc, err := time.LoadLocation(x.TimeZone)
+	if err != nil {
+		return false
+	}
+	t = t.In(loc)
+	now := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	if start <= end {
+		return now >= start && now < end
+	}
+	return now >= start || now < end
+}
+
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+type SetDoNotDisturbReq struct {
+	DoNotDisturb *DoNotDisturb `json:"doNotDisturb"`
+}
+
+func (x *SetDoNotDisturbReq) Check() error {
+	if x.DoNotDisturb == nil {
+		return errors.New("doNotDisturb is empty")
+	}
+	if x.DoNotDisturb.UserID == "" {
+		return errors.New("userID is empty")
+	}
+	if _, err := parseClock(x.DoNotDisturb.StartTime); err != nil {
+		return errors.New("startTime is invalid, want HH:MM")
+	}
+	if _, err := parseClock(x.DoNotDisturb.EndTime); err != nil {
+		return errors.New("endTime is invalid, want HH:MM")
+	}
+	if _, err := time.LoadLocation(x.DoNotDisturb.TimeZone); err != nil {
+		return errors.New("timeZone is invalid")
+	}
+	return nil
+}
+
+type SetDoNotDisturbResp struct{}
+
+type GetDoNotDisturbReq struct {
+	UserIDs []string `json:"userIDs"`
+}
+
+func (x *GetDoNotDisturbReq) Check() error {
+	if x.UserIDs == nil {
+		return errors.New("userIDs is empty")
+	}
+	return nil
+}
+
+type GetDoNotDisturbResp struct {
+	DoNotDisturbs []*DoNotDisturb `json:"doNotDisturbs"`
+}
+
+// UserExtClient is the client API for the UserExt service.
+type UserExtClient interface {
+	SetDoNotDisturb(ctx context.Context, in *SetDoNotDisturbReq, opts ...grpc.CallOption) (*SetDoNotDisturbResp, error)
+	GetDoNotDisturb(ctx context.Context, in *GetDoNotDisturbReq, opts ...grpc.CallOption) (*GetDoNotDisturbResp, error)
+	FindDoNotDisturb(ctx context.Context, in *GetDoNotDisturbReq, opts ...grpc.CallOption) (*GetDoNotDisturbResp, error)
+}
+
+type userExtClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewUserExtClient(cc grpc.ClientConnInterface) UserExtClient {
+	return &userExtClient{cc}
+}
+
+func (c *userExtClient) SetDoNotDisturb(ctx context.Context, in *SetDoNotDisturbReq, opts ...grpc.CallOption) (*SetDoNotDisturbResp, error) {
+	return invoke[SetDoNotDisturbResp](ctx, c.cc, fullMethod(userExtServiceName, "SetDoNotDisturb"), in, opts...)
+}
+
+func (c *userExtClient) GetDoNotDisturb(ctx context.Context, in *GetDoNotDisturbReq, opts ...grpc.CallOption) (*GetDoNotDisturbResp, error) {
+	return invoke[GetDoNotDisturbResp](ctx, c.cc, fullMethod(userExtServiceName, "GetDoNotDisturb"), in, opts...)
+}
+
+func (c *userExtClient) FindDoNotDisturb(ctx context.Context, in *GetDoNotDisturbReq, opts ...grpc.CallOption) (*GetDoNotDisturbResp, error) {
+	return invoke[GetDoNotDisturbResp](ctx, c.cc, fullMethod(userExtServiceName, "FindDoNotDisturb"), in, opts...)
+}
+
+// UserExtServer is the server API for the UserExt service.
+type UserExtServer interface {
+	SetDoNotDisturb(context.Context, *SetDoNotDisturbReq) (*SetDoNotDisturbResp, error)
+	// GetDoNotDisturb the quiet hours of users the caller may access
+	GetDoNotDisturb(context.Context, *GetDoNotDisturbReq) (*GetDoNotDisturbResp, error)
+	// FindDoNotDisturb the quiet hours of any users, for the other services only, it has no http route
+	FindDoNotDisturb(context.Context, *GetDoNotDisturbReq) (*GetDoNotDisturbResp, error)
+}
+
+func RegisterUserExtServer(s *grpc.Server, srv UserExtServer) {
+	s.RegisterService(&grpc.ServiceDesc{
+		ServiceName: userExtServiceName,
+		HandlerType: (*UserExtServer)(nil),
+		Methods: []grpc.MethodDesc{
+			unaryMethod(userExtServiceName, "SetDoNotDisturb", UserExtServer.SetDoNotDisturb),
+			unaryMethod(userExtServiceName, "GetDoNotDisturb", UserExtServer.GetDoNotDisturb),
+			unaryMethod(userExtServiceName, "FindDoNotDisturb", UserExtServer.FindDoNotDisturb),
+		},
+	}, srv)
+}
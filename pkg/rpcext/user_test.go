@@ -0,0 +1,54 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcext
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDoNotDisturbInQuietHours(t *testing.T) {
+	shanghai := time.FixedZone("CST", 8*3600)
+	at := func(hour, min int, loc *time.Location) time.Time {
+		return time.Date(2023, 11, 6, hour, min, 0, 0, loc)
+	}
+	tests := []struct {
+		name string
+		dnd  *DoNotDisturb
+		t    time.Time
+		want bool
+	}{
+		{name: "nil", t: at(12, 0, time.UTC), want: false},
+		{name: "disabled", dnd: &DoNotDisturb{StartTime: "00:00", EndTime: "23:59"}, t: at(12, 0, time.UTC), want: false},
+		{name: "same day inside", dnd: &DoNotDisturb{Enable: true, StartTime: "12:00", EndTime: "14:00"}, t: at(13, 0, time.UTC), want: true},
+		{name: "same day start is inclusive", dnd: &DoNotDisturb{Enable: true, StartTime: "12:00", EndTime: "14:00"}, t: at(12, 0, time.UTC), want: true},
+		{name: "same day end is exclusive", dnd: &DoNotDisturb{Enable: true, StartTime: "12:00", EndTime: "14:00"}, t: at(14, 0, time.UTC), want: false},
+		{name: "midnight span before midnight", dnd: &DoNotDisturb{Enable: true, StartTime: "22:00", EndTime: "07:00"}, t: at(23, 30, time.UTC), want: true},
+		{name: "midnight span after midnight", dnd: &DoNotDisturb{Enable: true, StartTime: "22:00", EndTime: "07:00"}, t: at(6, 59, time.UTC), want: true},
+		{name: "midnight span daytime", dnd: &DoNotDisturb{Enable: true, StartTime: "22:00", EndTime: "07:00"}, t: at(7, 0, time.UTC), want: false},
+		{name: "timezone converts utc", dnd: &DoNotDisturb{Enable: true, StartTime: "22:00", EndTime: "07:00", TimeZone: "Asia/Shanghai"}, t: at(15, 0, time.UTC), want: true},
+		{name: "timezone daytime", dnd: &DoNotDisturb{Enable: true, StartTime: "22:00", EndTime: "07:00", TimeZone: "Asia/Shanghai"}, t: at(2, 0, time.UTC), want: false},
+		{name: "time in another zone", dnd: &DoNotDisturb{Enable: true, StartTime: "12:00", EndTime: "14:00"}, t: at(21, 0, shanghai), want: true},
+		{name: "invalid timezone", dnd: &DoNotDisturb{Enable: true, StartTime: "00:00", EndTime: "23:59", TimeZone: "Mars/Olympus"}, t: at(12, 0, time.UTC), want: false},
+		{name: "invalid clock", dnd: &DoNotDisturb{Enable: true, StartTime: "24:00", EndTime: "07:00"}, t: at(1, 0, time.UTC), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.dnd.InQuietHours(tt.t); got != tt.want {
+				t.Errorf("InQuietHours() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -104,7 +104,7 @@ openim::common::dependency_name() {
         mysql
         redis
         zookeeper
-        kafka
+        $([[ "${MQ_TYPE}" == "redis" ]] || echo kafka) # no kafka when messages go through redis streams
         mongodb
         minio
     )
@@ -126,7 +126,7 @@ openim::common::dependency_port() {
     ${MYSQL_PORT} # MySQL port 
     ${REDIS_PORT} # Redis port
     ${ZOOKEEPER_PORT} # Zookeeper port
-    ${KAFKA_PORT} # Kafka port
+    $([[ "${MQ_TYPE}" == "redis" ]] || echo ${KAFKA_PORT}) # Kafka port
     ${MONGO_PORT} # MongoDB port
     ${MINIO_PORT} # MinIO port
   )
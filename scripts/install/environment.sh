@@ -190,6 +190,7 @@ def "MONGO_USERNAME" "${OPENIM_USER}"                 # MongoDB的用户名
 # MongoDB的密码
 readonly MONGO_PASSWORD=${MONGO_PASSWORD:-"${PASSWORD}"}
 def "MONGO_MAX_POOL_SIZE" "100"                # 最大连接池大小
+def "MONGO_MSG_LAYOUT" "array"                 # 消息存储结构, array 或 single
 
 ###################### Object 配置信息 ######################
 # app要能访问到此ip和端口或域名
@@ -237,11 +238,17 @@ def "KAFKA_ADDRESS" "${DOCKER_BRIDGE_GATEWAY}"              # `Kafka` 的地址
 def "KAFKA_LATESTMSG_REDIS_TOPIC" "latestMsgToRedis"        # `Kafka` 的最新消息到Redis的主题
 def "KAFKA_OFFLINEMSG_MONGO_TOPIC" "offlineMsgToMongoMysql" # `Kafka` 的离线消息到Mongo的主题
 def "KAFKA_MSG_PUSH_TOPIC" "msgToPush"                      # `Kafka` 的消息到推送的主题
+def "KAFKA_MSG_DEAD_LETTER_TOPIC" "msgDeadLetter"           # `Kafka` 的写入失败消息的死信主题
+def "KAFKA_MSG_MODIFY_TOPIC" "msgToModify"                  # `Kafka` 的消息撤回和删除更新的主题
 def "KAFKA_CONSUMERGROUPID_REDIS" "redis"                   # `Kafka` 的消费组ID到Redis
 def "KAFKA_CONSUMERGROUPID_MONGO" "mongo"                   # `Kafka` 的消费组ID到Mongo
 def "KAFKA_CONSUMERGROUPID_MYSQL" "mysql"                   # `Kafka` 的消费组ID到MySql
 def "KAFKA_CONSUMERGROUPID_PUSH" "push"                     # `Kafka` 的消费组ID到推送
 
+###################### MQ 配置信息 ######################
+def "MQ_TYPE" "kafka"                                       # 消息队列的类型，kafka 或 redis
+def "MQ_REDIS_MAX_LEN" "0"                                  # 使用 redis 时每个 stream 的最大长度，0 表示不裁剪
+
 ###################### openim-web 配置信息 ######################
 def "OPENIM_WEB_PORT" "11001"                       # openim-web的端口
 def "OPENIM_WEB_ADDRESS" "${DOCKER_BRIDGE_GATEWAY}" # openim-web的地址
@@ -353,6 +360,20 @@ def "JPNS_APP_KEY" ""                 # JPNS应用密钥
 def "JPNS_MASTER_SECRET" ""           # JPNS主密钥
 def "JPNS_PUSH_URL" ""                # JPNS推送URL
 def "JPNS_PUSH_INTENT" ""             # JPNS推送意图
+def "PUSH_DND_ENABLE" "true"          # 是否启用免打扰时段
+def "PUSH_DND_MODE" "suppress"        # 免打扰模式 suppress/silent
+def "PUSH_DND_AT_BYPASS" "true"       # @消息是否绕过免打扰
+def "PUSH_DND_SIGNAL_BYPASS" "true"   # 信令消息是否绕过免打扰
+def "PUSH_COALESCE_ENABLE" "false"    # 是否合并大群离线推送
+def "PUSH_COALESCE_WINDOW" "10"       # 合并推送窗口(秒)
+def "PUSH_AUDIT_ENABLE" "true"        # 是否记录离线推送日志
+def "PUSH_AUDIT_RETENTION" "7"        # 推送日志保留天数
+def "PUSH_VOIP_ENABLE" "false"        # 是否启用音视频来电推送
+def "PUSH_VOIP_TTL" "30"              # 来电推送有效期(秒)
+def "PUSH_VOIP_KEY_FILE" "AuthKey.p8" # APNs鉴权密钥文件
+def "PUSH_VOIP_KEY_ID" ""             # APNs密钥ID
+def "PUSH_VOIP_TEAM_ID" ""            # Apple开发者团队ID
+def "PUSH_VOIP_BUNDLE_ID" ""          # iOS应用BundleID
 def "MANAGER_USERID_1" "openIM123456" # 管理员ID 1
 def "MANAGER_USERID_2" "openIM654321" # 管理员ID 2
 def "MANAGER_USERID_3" "openIMAdmin"  # 管理员ID 3
@@ -362,6 +383,10 @@ def "NICKNAME_3" "system3"            # 昵称3
 def "MULTILOGIN_POLICY" "1"           # 多登录策略
 def "CHAT_PERSISTENCE_MYSQL" "true"   # 聊天持久化MySQL
 def "MSG_CACHE_TIMEOUT" "86400"       # 消息缓存超时
+def "TRANSFER_WORKER_NUM" "100"       # msgtransfer的工作协程数
+def "TRANSFER_MAX_BATCH_SIZE" "100"   # 单个会话一次处理的最大消息数，最大 100
+def "TRANSFER_MAX_PENDING" "50000"    # 暂停消费的待处理消息数
+def "TRANSFER_DEDUPE_TIMEOUT" "86400" # 按clientMsgID记住seq的秒数
 def "GROUP_MSG_READ_RECEIPT" "true"   # 群消息已读回执启用
 def "SINGLE_MSG_READ_RECEIPT" "true"  # 单一消息已读回执启用
 def "RETAIN_CHAT_RECORDS" "365"       # 保留聊天记录
@@ -369,6 +394,16 @@ def "RETAIN_CHAT_RECORDS" "365"       # 保留聊天记录
 readonly CHAT_RECORDS_CLEAR_TIME=${CHAT_RECORDS_CLEAR_TIME:-'0 2 * * 3'}
 # 消息销毁时间
 readonly MSG_DESTRUCT_TIME=${MSG_DESTRUCT_TIME:-'0 2 * * *'}
+# 群组目录活跃度刷新时间
+readonly GROUP_DIR_REFRESH_TIME=${GROUP_DIR_REFRESH_TIME:-'*/10 * * * *'}
+# 群组定时禁言检查时间
+readonly GROUP_MUTE_SCHED_TIME=${GROUP_MUTE_SCHED_TIME:-'* * * * *'}
+def "GROUP_OWNER_SUCCESSION" "oldestAdmin" # 群主退群或注销后的继任策略
+def "MSG_ARCHIVE_ENABLE" "false"      # 是否将旧消息归档到对象存储
+# 消息归档时间
+readonly MSG_ARCHIVE_CRON_TIME=${MSG_ARCHIVE_CRON_TIME:-'0 3 * * *'}
+def "MSG_ARCHIVE_DAYS" "180"          # 超过多少天的消息被归档
+def "MSG_ARCHIVE_CACHE_TIME" "3600"   # 归档消息的缓存秒数
 # 密钥
 readonly SECRET=${SECRET:-"${PASSWORD}"}
 def "TOKEN_EXPIRE" "90"         # Token到期时间
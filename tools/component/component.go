@@ -284,6 +284,10 @@ func checkZookeeper() error {
 }
 
 func checkKafka() error {
+	// messages go through redis streams, there is no kafka to check
+	if strings.EqualFold(config.Config.MQ.Type, "redis") {
+		return nil
+	}
 	var kafkaClient sarama.Client
 	defer func() {
 		if kafkaClient != nil {